@@ -0,0 +1,111 @@
+// Package app reúne a composição que depende de várias camadas. Fica em
+// internal para que pacotes de pkg (config, validator) não importem o domínio
+package app
+
+import (
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/config"
+)
+
+// Configure valida as regras da configuração que dependem do domínio e as
+// aplica: limite de nome e papéis customizados. Deve ser chamado logo após
+// config.Load, antes de montar os casos de uso
+func Configure(cfg *config.Config) error {
+	if err := Validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	user.SetMaxNameLength(cfg.Users.MaxNameLength)
+
+	if err := user.RegisterRoles(cfg.Security.CustomRoles...); err != nil {
+		return fmt.Errorf("failed to register custom roles: %w", err)
+	}
+
+	// A matriz pode citar papéis customizados, então é validada após o registro
+	if _, err := user.ParseUpdatePolicy(cfg.Users.UpdatePermissions); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
+
+// Validate verifica as regras da configuração que dependem do domínio sem
+// alterar nenhum estado global. config.Config.Validate cobre o restante
+func Validate(cfg *config.Config) error {
+	if err := validateSecretStrength(cfg); err != nil {
+		return err
+	}
+	if err := user.ValidatePasswordPeppers(cfg.Security.PasswordPepperVersion, cfg.Security.PasswordPeppers); err != nil {
+		return err
+	}
+	if _, err := auth.SigningMethodByName(cfg.Security.JWTAlgorithm); err != nil {
+		return err
+	}
+	if cfg.Security.ResetTokenBytes != 0 && cfg.Security.ResetTokenBytes < auth.MinResetTokenBytes {
+		return fmt.Errorf("invalid reset token length %d: must be at least %d bytes", cfg.Security.ResetTokenBytes, auth.MinResetTokenBytes)
+	}
+
+	// O token viaja no header Authorization; um orçamento maior que o limite de
+	// headers produziria tokens que o próprio servidor rejeita com 431
+	tokenBytes := cfg.Security.JWTMaxBytes
+	if tokenBytes == 0 {
+		tokenBytes = auth.DefaultMaxTokenBytes
+	}
+	if maxHeader := cfg.Server.EffectiveMaxHeaderBytes(); tokenBytes >= maxHeader {
+		return fmt.Errorf("jwt max bytes (%d) must be smaller than server max header bytes (%d)", tokenBytes, maxHeader)
+	}
+
+	for _, role := range cfg.Security.CustomRoles {
+		if err := user.ValidateRoleName(role); err != nil {
+			return fmt.Errorf("invalid custom role: %w", err)
+		}
+	}
+	if _, err := user.NewEmailDomainRules(cfg.Users.AllowedEmailDomains, cfg.Users.BlockedEmailDomains); err != nil {
+		return err
+	}
+
+	if cfg.Auth.Backend == config.AuthBackendLDAP {
+		for group, role := range cfg.Auth.LDAP.RoleMapping {
+			if err := user.ValidateRoleName(role); err != nil {
+				return fmt.Errorf("invalid role for ldap group %q: %w", group, err)
+			}
+		}
+		if cfg.Auth.LDAP.DefaultRole != "" {
+			if err := user.ValidateRoleName(cfg.Auth.LDAP.DefaultRole); err != nil {
+				return fmt.Errorf("invalid ldap default role: %w", err)
+			}
+		}
+	}
+
+	for _, role := range cfg.Security.RateLimitExemptRoles {
+		if err := user.ValidateRoleName(role); err != nil {
+			return fmt.Errorf("invalid rate limit exempt role: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateSecretStrength recusa segredos JWT fracos em production (ou com
+// security.require_strong_secret). Development e testing aceitam segredos curtos
+func validateSecretStrength(cfg *config.Config) error {
+	if !cfg.IsProduction() && !cfg.Security.RequireStrongSecret {
+		return nil
+	}
+
+	if len(cfg.Security.JWTKeys) == 0 {
+		if err := auth.ValidateSecretStrength(cfg.Security.JWTSecret); err != nil {
+			return fmt.Errorf("invalid jwt secret (set APP_JWT_SECRET): %w", err)
+		}
+		return nil
+	}
+	for kid, secret := range cfg.Security.JWTKeys {
+		if err := auth.ValidateSecretStrength(secret); err != nil {
+			return fmt.Errorf("invalid jwt key %q: %w", kid, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureRegistersCustomRoles(t *testing.T) {
+	t.Cleanup(user.ResetRoles)
+	t.Cleanup(func() { user.SetMaxNameLength(0) })
+
+	cfg := &config.Config{}
+	cfg.Security.CustomRoles = []string{"auditor"}
+	cfg.Users.MaxNameLength = 40
+	cfg.Users.UpdatePermissions = map[string]map[string]string{"auditor": {"name": "self"}}
+
+	require.NoError(t, Configure(cfg))
+	assert.True(t, user.IsValidRole("auditor"))
+	assert.Equal(t, 40, user.MaxNameLength())
+}
+
+func TestValidateRejectsDomainRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config.Config)
+	}{
+		{"invalid custom role", func(c *config.Config) { c.Security.CustomRoles = []string{"Has Space"} }},
+		{"invalid exempt role", func(c *config.Config) { c.Security.RateLimitExemptRoles = []string{"1role"} }},
+		{"unknown jwt algorithm", func(c *config.Config) { c.Security.JWTAlgorithm = "none" }},
+		{"short reset token", func(c *config.Config) { c.Security.ResetTokenBytes = auth.MinResetTokenBytes - 1 }},
+		{"weak secret in production", func(c *config.Config) {
+			c.Environment = "production"
+			c.Security.JWTSecret = "short"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Security.JWTSecret = "dev-secret"
+			require.NoError(t, Validate(cfg))
+
+			tt.modify(cfg)
+			assert.Error(t, Validate(cfg))
+			assert.Error(t, Configure(cfg))
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"go-api-boilerplate/internal/domain/auth"
+	domainRepo "go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/handlers"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/infrastructure/ldap"
+	"go-api-boilerplate/internal/infrastructure/metrics"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/internal/infrastructure/webhooks"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/pkg/config"
+	"go-api-boilerplate/pkg/database"
+	"go-api-boilerplate/pkg/worker"
+)
+
+// TokenStateSource fornece o estado dos usuários conferido na validação dos
+// tokens; PostgresUserRepository a implementa
+type TokenStateSource interface {
+	auth.TokenVersionSource
+	auth.AccountStatusSource
+}
+
+// NewUserRepository cria o repositório de usuários do PostgreSQL. Com
+// database.singleflight_reads, ele é envolvido por
+// repository.NewSingleflightUserRepository, que compartilha as leituras
+// concorrentes de um mesmo usuário por ID ou email
+func NewUserRepository(cfg *config.Config, db *sql.DB, opts ...repository.PostgresOption) domainRepo.UserRepository {
+	users := repository.NewPostgresUserRepository(db, opts...)
+	if cfg.Database.SingleflightReads {
+		users = repository.NewSingleflightUserRepository(users)
+	}
+	return users
+}
+
+// JWTOptions traduz a configuração de security nas opções do JWTService:
+// algoritmo, audiências, limites de tamanho (jwt_max_bytes,
+// jwt_max_permissions), revogação em massa com cache (token_version_cache_ttl)
+// e, com check_account_status, a verificação de conta ativa com cache
+// (account_status_cache_ttl). Sessões e blacklist são acrescentadas pela
+// aplicação, que cria os stores
+func JWTOptions(cfg *config.Config, users TokenStateSource, log *slog.Logger) ([]auth.JWTOption, error) {
+	method, err := auth.SigningMethodByName(cfg.Security.JWTAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []auth.JWTOption{
+		auth.WithSigningMethod(method),
+		auth.WithTokenLimits(cfg.Security.JWTMaxBytes, cfg.Security.JWTMaxPermissions),
+		auth.WithLogger(log),
+		auth.WithTokenVersions(auth.NewCachedTokenVersions(users, cfg.Security.TokenVersionCacheTTL)),
+	}
+	if cfg.Security.JWTAudience != "" || len(cfg.Security.JWTIssuedAudiences) > 0 {
+		opts = append(opts, auth.WithAudience(cfg.Security.JWTAudience, cfg.Security.JWTIssuedAudiences...))
+	}
+	if cfg.Security.CheckAccountStatus {
+		opts = append(opts, auth.WithAccountStatus(auth.NewCachedAccountStatus(users, cfg.Security.AccountStatusCacheTTL)))
+	}
+	return opts, nil
+}
+
+// NewResetTokenIssuer cria o emissor dos tokens de redefinição de senha passado
+// a usecase.WithPasswordReset, com o tamanho (reset_token_bytes), a validade
+// (reset_token_ttl) e o máximo de confirmações (reset_token_max_attempts) de
+// security; valores 0 usam os padrões de auth. c é a fonte de tempo da
+// expiração; nil usa clock.System
+func NewResetTokenIssuer(cfg *config.Config, c clock.Clock) (*auth.ResetTokenIssuer, error) {
+	return auth.NewResetTokenIssuer(cfg.Security.ResetTokenBytes, cfg.Security.ResetTokenTTL, c,
+		auth.WithResetMaxAttempts(cfg.Security.ResetTokenMaxAttempts))
+}
+
+// NewVerificationTokenIssuer cria o emissor dos links de verificação de email
+// passado a usecase.WithEmailVerification: mesmo tamanho dos tokens de
+// redefinição (reset_token_bytes), com a validade de verification_token_ttl
+func NewVerificationTokenIssuer(cfg *config.Config, c clock.Clock) (*auth.ResetTokenIssuer, error) {
+	return auth.NewResetTokenIssuer(cfg.Security.ResetTokenBytes, cfg.Security.VerificationTokenTTL, c)
+}
+
+// UseCaseOptions traduz a configuração de users e security nas opções do
+// UserUseCase: limites de exportação e de resultados, política de exclusão,
+// domínios de email, matriz de atualização, nomes de usuário
+// (usernames_enabled), bootstrap do primeiro admin, expiração de senhas e
+// rebaixamento de admins inativos. Deve ser chamado após Configure, que
+// registra os papéis customizados citados na matriz. Integrações (logger,
+// métricas, eventos, verificação de email, sessões) são acrescentadas pela
+// aplicação
+func UseCaseOptions(cfg *config.Config) ([]usecase.Option, error) {
+	domains, err := user.NewEmailDomainRules(cfg.Users.AllowedEmailDomains, cfg.Users.BlockedEmailDomains)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := user.ParseUpdatePolicy(cfg.Users.UpdatePermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []usecase.Option{
+		usecase.WithExportLimits(cfg.Users.ExportBatchSize, cfg.Users.ExportMaxRows),
+		usecase.WithResultLimits(cfg.Users.MaxBatchGetIDs, cfg.Users.MaxSearchLimit),
+		usecase.WithDeletionPolicy(cfg.Users.DeletionPolicy),
+		usecase.WithEmailDomainRules(domains),
+		usecase.WithUpdatePolicy(policy),
+	}
+	if cfg.Users.UsernamesEnabled {
+		opts = append(opts, usecase.WithUsernames())
+	}
+	if cfg.Users.AdminBootstrapEnabled {
+		opts = append(opts, usecase.WithAdminBootstrap())
+	}
+	if cfg.Security.PasswordExpirationEnabled {
+		opts = append(opts, usecase.WithPasswordMaxAge(cfg.Security.PasswordMaxAge))
+	}
+	if downgrade := cfg.Users.AdminDowngrade; downgrade.Enabled {
+		opts = append(opts, usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{
+			InactiveAfter: downgrade.InactiveAfter,
+			DryRun:        downgrade.DryRun,
+		}))
+	}
+	return opts, nil
+}
+
+// AuthenticatorOptions traduz auth.backend nas opções de login do UserUseCase.
+// Com ldap, as credenciais passam a ser verificadas no diretório de auth.ldap
+// (ldap.Client) por um ldap.Authenticator, que provisiona o usuário em users no
+// primeiro login com o role de role_mapping. Com local não há opções
+func AuthenticatorOptions(cfg *config.Config, users domainRepo.UserRepository) ([]usecase.Option, error) {
+	if cfg.Auth.Backend != config.AuthBackendLDAP {
+		return nil, nil
+	}
+
+	directory, err := ldap.NewClient(ldap.Config{
+		URL:          cfg.Auth.LDAP.URL,
+		BindDN:       cfg.Auth.LDAP.BindDN,
+		BindPassword: cfg.Auth.LDAP.BindPassword,
+		BaseDN:       cfg.Auth.LDAP.BaseDN,
+		UserFilter:   cfg.Auth.LDAP.UserFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	authenticator, err := ldap.NewAuthenticator(directory, users, cfg.Auth.LDAP.RoleMapping, cfg.Auth.LDAP.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+	return []usecase.Option{usecase.WithAuthenticator(authenticator)}, nil
+}
+
+// HandlerOptions traduz a configuração nas opções do UserHandler: formato das
+// datas, JSON estrito, limite do corpo do login e o limite de reenvio do link de
+// verificação (verification_resend_limit por verification_resend_window, com
+// handlers.DefaultVerificationResendLimit e DefaultVerificationResendWindow
+// para valores 0) e o limite da redefinição de senha (password_reset_limit por
+// password_reset_window, com os padrões de handlers para valores 0). Os
+// limiters são em memória; com várias réplicas acrescente
+// WithVerificationResendLimiter e WithPasswordResetLimiter com limiters
+// compartilhados
+func HandlerOptions(cfg *config.Config) ([]handlers.HandlerOption, error) {
+	format, err := handlers.ParseTimestampFormat(cfg.Server.TimestampFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	resendLimit := cfg.Security.VerificationResendLimit
+	if resendLimit == 0 {
+		resendLimit = handlers.DefaultVerificationResendLimit
+	}
+	resendWindow := cfg.Security.VerificationResendWindow
+	if resendWindow == 0 {
+		resendWindow = handlers.DefaultVerificationResendWindow
+	}
+
+	resetLimit := cfg.Security.PasswordResetLimit
+	if resetLimit == 0 {
+		resetLimit = handlers.DefaultPasswordResetLimit
+	}
+	resetWindow := cfg.Security.PasswordResetWindow
+	if resetWindow == 0 {
+		resetWindow = handlers.DefaultPasswordResetWindow
+	}
+
+	return []handlers.HandlerOption{
+		handlers.WithTimestampFormat(format),
+		handlers.WithStrictJSON(cfg.Server.StrictJSON),
+		handlers.WithMaxLoginBodyBytes(cfg.Security.LoginMaxBodyBytes),
+		handlers.WithVerificationResendLimiter(middleware.NewMemoryRateLimiter(resendLimit, resendWindow)),
+		handlers.WithPasswordResetLimiter(middleware.NewMemoryRateLimiter(resetLimit, resetWindow)),
+	}, nil
+}
+
+// NewWebhookDispatcher cria o dispatcher de webhooks com a configuração de
+// webhooks e o mesmo header de ID de correlação da API
+// (server.request_id_header). Close deve ser chamado no desligamento
+func NewWebhookDispatcher(cfg *config.Config, log *slog.Logger) *webhooks.Dispatcher {
+	return webhooks.NewDispatcher(cfg.Webhooks, log, webhooks.WithRequestIDHeader(cfg.Server.RequestIDHeader))
+}
+
+// StartDatabaseWorkers inicia no manager o probe de saúde
+// (database.health_check_interval) e a amostragem do pool
+// (database.pool_stats_interval), ambos alimentando as métricas do banco.
+// Intervalos 0 desabilitam cada um
+func StartDatabaseWorkers(workers *worker.Manager, cfg *config.Config, db *sql.DB, log *slog.Logger) {
+	metrics.RegisterDatabaseMetrics()
+
+	if cfg.Database.HealthCheckInterval > 0 {
+		workers.Go("db-health-probe", func(ctx context.Context) {
+			database.RunHealthProbe(ctx, db, cfg.Database.HealthCheckInterval, log, metrics.DatabaseHealthCheck)
+		})
+	}
+	if cfg.Database.PoolStatsInterval > 0 {
+		workers.Go("db-pool-stats", func(ctx context.Context) {
+			database.RunPoolStatsSampler(ctx, db, cfg.Database.PoolStatsInterval, metrics.DatabasePoolStats)
+		})
+	}
+}
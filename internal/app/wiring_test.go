@@ -0,0 +1,293 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/handlers"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/pkg/config"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenState é uma fonte fixa de versão de token e status de conta
+type tokenState struct {
+	version int
+	active  bool
+}
+
+func (s *tokenState) GetTokenVersion(context.Context, string) (int, error) { return s.version, nil }
+func (s *tokenState) IsActive(context.Context, string) (bool, error)       { return s.active, nil }
+
+func TestJWTOptionsApplyConfig(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	newService := func(t *testing.T, cfg *config.Config, state *tokenState) auth.JWTService {
+		opts, err := JWTOptions(cfg, state, log)
+		require.NoError(t, err)
+		return auth.NewJWTService("test-secret", time.Hour, opts...)
+	}
+
+	t.Run("account status is checked only when enabled", func(t *testing.T) {
+		state := &tokenState{version: 1, active: false}
+
+		cfg := &config.Config{}
+		token, err := newService(t, cfg, state).GenerateToken("1", "a@b.com", "user")
+		require.NoError(t, err)
+		_, err = newService(t, cfg, state).ValidateToken(token)
+		assert.NoError(t, err)
+
+		cfg.Security.CheckAccountStatus = true
+		_, err = newService(t, cfg, state).ValidateToken(token)
+		assert.ErrorIs(t, err, auth.ErrInactiveAccount)
+	})
+
+	t.Run("token versions are checked", func(t *testing.T) {
+		state := &tokenState{version: 1, active: true}
+		cfg := &config.Config{}
+		token, err := newService(t, cfg, state).GenerateToken("1", "a@b.com", "user")
+		require.NoError(t, err)
+
+		state.version = 2
+		_, err = newService(t, cfg, state).ValidateToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("jwt max bytes limits issued tokens", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Security.JWTMaxBytes = 64
+		_, err := newService(t, cfg, &tokenState{version: 1, active: true}).GenerateToken("1", "a@b.com", "user")
+		assert.ErrorIs(t, err, auth.ErrTokenTooLarge)
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Security.JWTAlgorithm = "RS256"
+		_, err := JWTOptions(cfg, &tokenState{}, log)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewUserRepositoryAppliesSingleflightReads(t *testing.T) {
+	cfg := &config.Config{}
+	assert.IsType(t, &repository.PostgresUserRepository{}, NewUserRepository(cfg, nil))
+
+	cfg.Database.SingleflightReads = true
+	assert.IsType(t, &repository.SingleflightUserRepository{}, NewUserRepository(cfg, nil))
+}
+
+func TestUseCaseOptionsApplyConfig(t *testing.T) {
+	newUseCase := func(t *testing.T, cfg *config.Config) *usecase.UserUseCase {
+		opts, err := UseCaseOptions(cfg)
+		require.NoError(t, err)
+		return usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}, opts...)
+	}
+
+	uc := newUseCase(t, &config.Config{})
+	assert.False(t, uc.UsernamesEnabled())
+	assert.False(t, uc.AdminBootstrapEnabled())
+	assert.False(t, uc.PasswordExpirationEnabled())
+	assert.False(t, uc.AdminDowngradeEnabled())
+
+	cfg := &config.Config{}
+	cfg.Users.UsernamesEnabled = true
+	cfg.Users.AdminBootstrapEnabled = true
+	cfg.Users.AdminDowngrade = config.AdminDowngradeConfig{Enabled: true, InactiveAfter: 90 * 24 * time.Hour}
+	cfg.Security.PasswordExpirationEnabled = true
+	cfg.Security.PasswordMaxAge = 90 * 24 * time.Hour
+	uc = newUseCase(t, cfg)
+	assert.True(t, uc.UsernamesEnabled())
+	assert.True(t, uc.AdminBootstrapEnabled())
+	assert.True(t, uc.PasswordExpirationEnabled())
+	assert.True(t, uc.AdminDowngradeEnabled())
+
+	cfg = &config.Config{}
+	cfg.Users.AllowedEmailDomains = []string{"*"}
+	_, err := UseCaseOptions(cfg)
+	assert.Error(t, err)
+}
+
+func TestAuthenticatorOptionsApplyBackend(t *testing.T) {
+	opts, err := AuthenticatorOptions(&config.Config{}, &mocks.UserRepository{})
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	// Um endereço sem servidor: o login precisa falhar no diretório, sem tocar a senha local
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	cfg := &config.Config{}
+	cfg.Auth.Backend = config.AuthBackendLDAP
+	cfg.Auth.LDAP = config.LDAPConfig{URL: "ldap://" + address, BaseDN: "dc=example,dc=com"}
+	opts, err = AuthenticatorOptions(cfg, &mocks.UserRepository{})
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, opts...)
+	_, err = uc.AuthenticateUser(context.Background(), usecase.AuthenticateUserInput{Email: "ana@example.com", Password: "secret"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ldap")
+	repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+
+	t.Run("invalid ldap config", func(t *testing.T) {
+		cfg := &config.Config{}
+		cfg.Auth.Backend = config.AuthBackendLDAP
+		cfg.Auth.LDAP = config.LDAPConfig{URL: "http://ldap.example.com", BaseDN: "dc=example,dc=com"}
+		_, err := AuthenticatorOptions(cfg, &mocks.UserRepository{})
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenIssuersApplyConfig(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	cfg := &config.Config{}
+	cfg.Security.ResetTokenBytes = 48
+	cfg.Security.ResetTokenTTL = 15 * time.Minute
+	issuer, err := NewResetTokenIssuer(cfg, fake)
+	require.NoError(t, err)
+
+	plain, stored, err := issuer.Issue()
+	require.NoError(t, err)
+	assert.Len(t, plain, base64.RawURLEncoding.EncodedLen(48))
+	assert.Equal(t, fake.Now().Add(15*time.Minute), stored.ExpiresAt)
+
+	issuer, err = NewResetTokenIssuer(&config.Config{}, fake)
+	require.NoError(t, err)
+	plain, stored, err = issuer.Issue()
+	require.NoError(t, err)
+	assert.Len(t, plain, base64.RawURLEncoding.EncodedLen(auth.DefaultResetTokenBytes))
+	assert.Equal(t, fake.Now().Add(auth.DefaultResetTokenTTL), stored.ExpiresAt)
+
+	cfg.Security.VerificationTokenTTL = 24 * time.Hour
+	issuer, err = NewVerificationTokenIssuer(cfg, fake)
+	require.NoError(t, err)
+	plain, stored, err = issuer.Issue()
+	require.NoError(t, err)
+	assert.Len(t, plain, base64.RawURLEncoding.EncodedLen(48))
+	assert.Equal(t, fake.Now().Add(24*time.Hour), stored.ExpiresAt)
+
+	// reset_token_max_attempts esgota o token na última confirmação errada
+	cfg.Security.ResetTokenMaxAttempts = 2
+	issuer, err = NewResetTokenIssuer(cfg, fake)
+	require.NoError(t, err)
+	_, stored, err = issuer.Issue()
+	require.NoError(t, err)
+	counter := &attemptCounter{}
+	assert.ErrorIs(t, issuer.Confirm(context.Background(), "guess", stored, counter), auth.ErrResetTokenInvalid)
+	assert.ErrorIs(t, issuer.Confirm(context.Background(), "guess", stored, counter), auth.ErrResetTokenExhausted)
+
+	cfg.Security.ResetTokenBytes = auth.MinResetTokenBytes - 1
+	_, err = NewResetTokenIssuer(cfg, fake)
+	assert.Error(t, err)
+	_, err = NewVerificationTokenIssuer(cfg, fake)
+	assert.Error(t, err)
+}
+
+// attemptCounter conta as confirmações em memória
+type attemptCounter struct{ n int }
+
+func (c *attemptCounter) IncrementResetAttempts(context.Context, string) (int, error) {
+	c.n++
+	return c.n, nil
+}
+
+func TestNewWebhookDispatcherUsesRequestIDHeader(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	cfg := &config.Config{}
+	cfg.Server.RequestIDHeader = "X-Correlation-ID"
+	cfg.Webhooks.URLs = []string{receiver.URL}
+	dispatcher := NewWebhookDispatcher(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	event := user.NewEvent(user.EventUserUpdated, &user.User{ID: "42"})
+	event.RequestID = "req-123"
+	dispatcher.Publish(context.Background(), event)
+
+	select {
+	case req := <-received:
+		assert.Equal(t, "req-123", req.Header.Get("X-Correlation-ID"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+	require.NoError(t, dispatcher.Close(context.Background()))
+}
+
+func TestHandlerOptionsApplyResendLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resend := func(router *gin.Engine) int {
+		req := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", strings.NewReader(`{"email":"ana@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	newRouter := func(t *testing.T, cfg *config.Config) *gin.Engine {
+		opts, err := HandlerOptions(cfg)
+		require.NoError(t, err)
+		h := handlers.NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}), opts...)
+		router := gin.New()
+		router.POST("/auth/verify/resend", h.ResendVerification)
+		return router
+	}
+
+	t.Run("configured limit", func(t *testing.T) {
+		router := newRouter(t, &config.Config{Security: config.SecurityConfig{VerificationResendLimit: 1, VerificationResendWindow: time.Hour}})
+		assert.Equal(t, http.StatusOK, resend(router))
+		assert.Equal(t, http.StatusTooManyRequests, resend(router))
+	})
+
+	t.Run("zero uses the default limit", func(t *testing.T) {
+		router := newRouter(t, &config.Config{})
+		for i := 0; i < handlers.DefaultVerificationResendLimit; i++ {
+			assert.Equal(t, http.StatusOK, resend(router))
+		}
+		assert.Equal(t, http.StatusTooManyRequests, resend(router))
+	})
+
+	t.Run("password reset limit", func(t *testing.T) {
+		opts, err := HandlerOptions(&config.Config{Security: config.SecurityConfig{PasswordResetLimit: 1, PasswordResetWindow: time.Hour}})
+		require.NoError(t, err)
+		h := handlers.NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}), opts...)
+		router := gin.New()
+		router.POST("/auth/password/reset", h.RequestPasswordReset)
+		reset := func() int {
+			req := httptest.NewRequest(http.MethodPost, "/auth/password/reset", strings.NewReader(`{"email":"ana@example.com"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+		assert.Equal(t, http.StatusOK, reset())
+		assert.Equal(t, http.StatusTooManyRequests, reset())
+	})
+
+	t.Run("invalid timestamp format", func(t *testing.T) {
+		_, err := HandlerOptions(&config.Config{Server: config.ServerConfig{TimestampFormat: "iso"}})
+		assert.Error(t, err)
+	})
+}
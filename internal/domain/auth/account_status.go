@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// ErrInactiveAccount indica que o dono do token foi desativado ou excluído
+var ErrInactiveAccount = errors.New("account is not active")
+
+// DefaultAccountStatusCacheTTL é a janela de consistência padrão da verificação de conta ativa
+const DefaultAccountStatusCacheTTL = 5 * time.Second
+
+// AccountStatusSource informa se um usuário está ativo. Usuários excluídos
+// devem retornar user.ErrUserNotFound. Implementado pelo repositório de usuários
+type AccountStatusSource interface {
+	IsActive(ctx context.Context, userID string) (bool, error)
+}
+
+// WithAccountStatus faz a validação rejeitar tokens de usuários desativados ou
+// excluídos, consultando a fonte a cada requisição
+func WithAccountStatus(source AccountStatusSource) JWTOption {
+	return func(j *jwtService) {
+		j.accounts = source
+	}
+}
+
+// checkAccountStatus rejeita tokens de contas inativas ou excluídas.
+// Falhas na consulta rejeitam o token (fail-closed)
+func (j *jwtService) checkAccountStatus(claims *Claims) error {
+	if j.accounts == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blacklistTimeout)
+	defer cancel()
+
+	active, err := j.accounts.IsActive(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return ErrInactiveAccount
+		}
+		return fmt.Errorf("%w: failed to check account status: %v", ErrInvalidToken, err)
+	}
+	if !active {
+		return ErrInactiveAccount
+	}
+
+	return nil
+}
+
+// CachedAccountStatus decora um AccountStatusSource com cache local por usuário.
+// Uma conta desativada ou excluída ainda pode ser aceita por até ttl em cada
+// instância; contas excluídas ficam em cache como inativas
+type CachedAccountStatus struct {
+	source AccountStatusSource
+	cache  *ttlCache[bool]
+}
+
+var _ AccountStatusSource = (*CachedAccountStatus)(nil)
+
+// NewCachedAccountStatus cria o cache; ttl <= 0 usa DefaultAccountStatusCacheTTL
+func NewCachedAccountStatus(source AccountStatusSource, ttl time.Duration) *CachedAccountStatus {
+	if ttl <= 0 {
+		ttl = DefaultAccountStatusCacheTTL
+	}
+	return &CachedAccountStatus{
+		source: source,
+		cache:  newTTLCache[bool](ttl),
+	}
+}
+
+// IsActive retorna o status em cache ou consulta a fonte quando expirado.
+// Erros diferentes de user.ErrUserNotFound não são armazenados em cache
+func (c *CachedAccountStatus) IsActive(ctx context.Context, userID string) (bool, error) {
+	if active, ok := c.cache.get(userID); ok {
+		return active, nil
+	}
+
+	active, err := c.source.IsActive(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, user.ErrUserNotFound) {
+			return false, err
+		}
+		active = false
+	}
+
+	c.cache.set(userID, active)
+	return active, nil
+}
+
+// Invalidate descarta o status em cache do usuário
+func (c *CachedAccountStatus) Invalidate(userID string) {
+	c.cache.delete(userID)
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccounts mapeia userID para is_active; ausentes são tratados como excluídos
+type fakeAccounts struct {
+	active map[string]bool
+	err    error
+	calls  int
+}
+
+func (f *fakeAccounts) IsActive(_ context.Context, userID string) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+	active, ok := f.active[userID]
+	if !ok {
+		return false, user.ErrUserNotFound
+	}
+	return active, nil
+}
+
+func TestAccountStatusRejectsInactiveAndDeletedUsers(t *testing.T) {
+	accounts := &fakeAccounts{active: map[string]bool{"active": true, "inactive": false}}
+	service := NewJWTService("secret", time.Hour, WithAccountStatus(accounts))
+
+	for userID, want := range map[string]error{
+		"active":   nil,
+		"inactive": ErrInactiveAccount,
+		"deleted":  ErrInactiveAccount,
+	} {
+		token, err := service.GenerateToken(userID, userID+"@example.com", "user")
+		require.NoError(t, err)
+
+		_, err = service.ValidateToken(token)
+		if want == nil {
+			assert.NoError(t, err, userID)
+		} else {
+			assert.ErrorIs(t, err, want, userID)
+		}
+	}
+}
+
+func TestAccountStatusFailsClosed(t *testing.T) {
+	accounts := &fakeAccounts{err: errors.New("connection refused")}
+	service := NewJWTService("secret", time.Hour, WithAccountStatus(accounts))
+
+	token, err := service.GenerateToken("1", "a@example.com", "user")
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestCachedAccountStatus(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeAccounts{active: map[string]bool{"1": true}}
+	cache := NewCachedAccountStatus(source, time.Minute)
+	now := time.Now()
+	cache.cache.now = func() time.Time { return now }
+
+	active, err := cache.IsActive(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	// Dentro da janela a desativação ainda não é vista
+	source.active["1"] = false
+	active, _ = cache.IsActive(ctx, "1")
+	assert.True(t, active)
+	assert.Equal(t, 1, source.calls)
+
+	now = now.Add(time.Minute)
+	active, _ = cache.IsActive(ctx, "1")
+	assert.False(t, active)
+
+	// Usuários excluídos ficam em cache como inativos
+	active, err = cache.IsActive(ctx, "deleted")
+	require.NoError(t, err)
+	assert.False(t, active)
+	calls := source.calls
+	_, _ = cache.IsActive(ctx, "deleted")
+	assert.Equal(t, calls, source.calls)
+
+	// Erros não são armazenados em cache
+	source.err = errors.New("timeout")
+	_, err = cache.IsActive(ctx, "other")
+	assert.Error(t, err)
+	source.err = nil
+	source.active["other"] = true
+	active, err = cache.IsActive(ctx, "other")
+	require.NoError(t, err)
+	assert.True(t, active)
+}
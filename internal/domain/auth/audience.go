@@ -0,0 +1,48 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// WithAudience define a audiência deste serviço: ValidateToken só aceita tokens
+// cuja claim aud a contenha. Tokens emitidos sem audiências explícitas recebem
+// issued ou, se vazio, apenas audience
+func WithAudience(audience string, issued ...string) JWTOption {
+	return func(j *jwtService) {
+		j.audience = audience
+		j.issuedAudiences = issued
+	}
+}
+
+// WithTokenAudiences emite um token aceito pelos serviços listados (claim aud),
+// substituindo as audiências padrão do serviço
+func WithTokenAudiences(audiences ...string) TokenOption {
+	return func(o *tokenOptions) {
+		o.audiences = audiences
+	}
+}
+
+// audienceFor resolve a claim aud de uma emissão; nil omite a claim
+func (j *jwtService) audienceFor(options tokenOptions) jwt.ClaimStrings {
+	switch {
+	case len(options.audiences) > 0:
+		return options.audiences
+	case len(j.issuedAudiences) > 0:
+		return j.issuedAudiences
+	case j.audience != "":
+		return jwt.ClaimStrings{j.audience}
+	default:
+		return nil
+	}
+}
+
+// parserOptions monta as opções de validação; com audiência configurada, a
+// claim aud é obrigatória e deve contê-la
+func (j *jwtService) parserOptions() []jwt.ParserOption {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{j.method.Alg()}),
+		jwt.WithTimeFunc(j.clock.Now),
+	}
+	if j.audience != "" {
+		opts = append(opts, jwt.WithAudience(j.audience))
+	}
+	return opts
+}
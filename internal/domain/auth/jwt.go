@@ -1,78 +1,412 @@
 package auth
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
+	"go-api-boilerplate/pkg/clock"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("token expired")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrExpiredToken     = errors.New("token expired")
+	ErrUnknownKeyID     = errors.New("unknown signing key id")
+	ErrRevokedToken     = errors.New("token revoked")
+	ErrTokenNotYetValid = errors.New("token not valid yet")
+
+	ErrUnsupportedAlgorithm = errors.New("unsupported jwt signing algorithm")
 )
 
+// blacklistTimeout limita a consulta ao blacklist durante a validação
+const blacklistTimeout = 2 * time.Second
+
+// DefaultKeyID é o kid usado quando o serviço é criado com uma única chave
+const DefaultKeyID = "default"
+
 // Claims representa as claims do JWT
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	// Permissions é opcional e limitada por WithTokenLimits
+	Permissions []string `json:"permissions,omitempty"`
+	// TokenVersion é a versão dos tokens do usuário na emissão; tokens com versão
+	// anterior à atual são rejeitados quando WithTokenVersions está configurado
+	TokenVersion int `json:"token_version"`
 	jwt.RegisteredClaims
 }
 
 // JWTService define os contratos para autenticação JWT
 type JWTService interface {
-	GenerateToken(userID, email, role string) (string, error)
+	GenerateToken(userID, email, role string, opts ...TokenOption) (string, error)
 	ValidateToken(tokenString string) (*Claims, error)
+	RevokeToken(ctx context.Context, tokenString string) error
+	// ExpiresIn retorna a validade dos tokens emitidos
+	ExpiresIn() time.Duration
+}
+
+// TokenBlacklist armazena os jti de tokens revogados até sua expiração.
+// Implementações compartilhadas (ex.: Redis) propagam a revogação entre réplicas
+type TokenBlacklist interface {
+	Revoke(ctx context.Context, jti string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// TokenVersionSource fornece a versão atual dos tokens de um usuário.
+// Implementado pelo repositório de usuários
+type TokenVersionSource interface {
+	GetTokenVersion(ctx context.Context, userID string) (int, error)
+}
+
+// JWTOption configura dependências opcionais do JWTService
+type JWTOption func(*jwtService)
+
+// WithBlacklist define o armazenamento de tokens revogados
+func WithBlacklist(blacklist TokenBlacklist) JWTOption {
+	return func(j *jwtService) {
+		j.blacklist = blacklist
+	}
+}
+
+// WithTokenVersions habilita a revogação em massa por usuário: a versão atual é
+// embutida na emissão e conferida na validação
+func WithTokenVersions(source TokenVersionSource) JWTOption {
+	return func(j *jwtService) {
+		j.versions = source
+	}
+}
+
+// WithSigningMethod define o algoritmo HMAC usado para assinar e o único aceito na validação
+func WithSigningMethod(method *jwt.SigningMethodHMAC) JWTOption {
+	return func(j *jwtService) {
+		j.method = method
+	}
+}
+
+// WithClock define a fonte de tempo da emissão e da validação (expiração)
+func WithClock(c clock.Clock) JWTOption {
+	return func(j *jwtService) {
+		j.clock = c
+	}
+}
+
+// SigningMethodByName resolve um algoritmo HMAC suportado (HS256, HS384, HS512); vazio usa HS256
+func SigningMethodByName(name string) (*jwt.SigningMethodHMAC, error) {
+	switch name {
+	case "", jwt.SigningMethodHS256.Alg():
+		return jwt.SigningMethodHS256, nil
+	case jwt.SigningMethodHS384.Alg():
+		return jwt.SigningMethodHS384, nil
+	case jwt.SigningMethodHS512.Alg():
+		return jwt.SigningMethodHS512, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, name)
+	}
 }
 
 // jwtService implementa JWTService
 type jwtService struct {
-	secretKey []byte
+	activeKID string
+	keys      map[string][]byte
 	expiresIn time.Duration
+	method    *jwt.SigningMethodHMAC
+	blacklist TokenBlacklist
+	versions  TokenVersionSource
+	accounts  AccountStatusSource
+
+	sessions    SessionStore
+	maxSessions int
+
+	audience        string
+	issuedAudiences []string
+
+	permissions    PermissionsFunc
+	maxBytes       int
+	maxPermissions int
+	logger         *slog.Logger
+	clock          clock.Clock
 }
 
-// NewJWTService cria uma nova instância de JWTService
-func NewJWTService(secretKey string, expiresIn time.Duration) JWTService {
-	return &jwtService{
-		secretKey: []byte(secretKey),
+// NewJWTService cria uma nova instância de JWTService com uma única chave
+func NewJWTService(secretKey string, expiresIn time.Duration, opts ...JWTOption) JWTService {
+	j := &jwtService{
+		activeKID: DefaultKeyID,
+		keys:      map[string][]byte{DefaultKeyID: []byte(secretKey)},
 		expiresIn: expiresIn,
+		method:    jwt.SigningMethodHS256,
+
+		maxBytes:       DefaultMaxTokenBytes,
+		maxPermissions: DefaultMaxPermissions,
+		logger:         slog.Default(),
+		clock:          clock.System,
+	}
+
+	for _, opt := range opts {
+		opt(j)
 	}
+
+	return j
 }
 
-// GenerateToken gera um novo token JWT
-func (j *jwtService) GenerateToken(userID, email, role string) (string, error) {
+// NewJWTServiceWithKeys cria um JWTService com suporte a rotação de chaves.
+// Tokens são assinados com a chave activeKID; as demais chaves continuam
+// aceitas na validação, permitindo que tokens antigos expirem naturalmente
+func NewJWTServiceWithKeys(activeKID string, keys map[string]string, expiresIn time.Duration, opts ...JWTOption) (JWTService, error) {
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q not found", ErrUnknownKeyID, activeKID)
+	}
+
+	keySet := make(map[string][]byte, len(keys))
+	for kid, secret := range keys {
+		if secret == "" {
+			return nil, fmt.Errorf("jwt key %q has an empty secret", kid)
+		}
+		keySet[kid] = []byte(secret)
+	}
+
+	j := &jwtService{
+		activeKID: activeKID,
+		keys:      keySet,
+		expiresIn: expiresIn,
+		method:    jwt.SigningMethodHS256,
+
+		maxBytes:       DefaultMaxTokenBytes,
+		maxPermissions: DefaultMaxPermissions,
+		logger:         slog.Default(),
+		clock:          clock.System,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	return j, nil
+}
+
+// ExpiresIn implementa JWTService
+func (j *jwtService) ExpiresIn() time.Duration {
+	return j.expiresIn
+}
+
+// GenerateToken gera um novo token JWT. Se o token exceder o tamanho máximo,
+// as permissões são removidas; se ainda assim exceder, retorna ErrTokenTooLarge.
+// Com WithSessions, o token só é retornado depois de registrado como sessão
+func (j *jwtService) GenerateToken(userID, email, role string, opts ...TokenOption) (string, error) {
+	var options tokenOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	now := j.clock.Now()
+	validFrom := options.validFrom(now)
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.expiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(validFrom.Add(j.expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(validFrom),
+			Audience:  j.audienceFor(options),
 		},
 	}
+	if j.permissions != nil {
+		claims.Permissions = j.limitPermissions(userID, j.permissions(role))
+	}
+	if j.versions != nil {
+		version, err := j.currentTokenVersion(userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to load token version: %w", err)
+		}
+		claims.TokenVersion = version
+	}
+
+	tokenString, err := j.signWithinBudget(claims)
+	if err != nil {
+		return "", err
+	}
+	if err := j.trackSession(claims, now, options); err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// signWithinBudget assina as claims respeitando o tamanho máximo do token
+func (j *jwtService) signWithinBudget(claims *Claims) (string, error) {
+	tokenString, err := j.sign(claims)
+	if err != nil {
+		return "", err
+	}
+	if len(tokenString) <= j.maxBytes {
+		return tokenString, nil
+	}
+
+	j.logger.Warn("jwt exceeds size budget",
+		"user_id", claims.UserID,
+		"size", len(tokenString),
+		"max_bytes", j.maxBytes,
+	)
+	if len(claims.Permissions) > 0 {
+		claims.Permissions = nil
+		if tokenString, err = j.sign(claims); err != nil {
+			return "", err
+		}
+		if len(tokenString) <= j.maxBytes {
+			return tokenString, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %d bytes (max %d)", ErrTokenTooLarge, len(tokenString), j.maxBytes)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretKey)
+// sign assina as claims com a chave ativa
+func (j *jwtService) sign(claims *Claims) (string, error) {
+	token := jwt.NewWithClaims(j.method, claims)
+	token.Header["kid"] = j.activeKID
+	return token.SignedString(j.keys[j.activeKID])
 }
 
-// ValidateToken valida um token JWT
+// ValidateToken valida um token JWT. Apenas o algoritmo configurado é aceito,
+// rejeitando tokens com alg "none" ou algoritmos assimétricos forjados.
+// Com WithAudience, tokens sem essa audiência na claim aud são inválidos
 func (j *jwtService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.keyFunc, j.parserOptions()...)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
 		}
+		if errors.Is(err, jwt.ErrTokenNotValidYet) {
+			return nil, ErrTokenNotYetValid
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
 		return nil, ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+	if err := j.checkRevoked(claims); err != nil {
+		return nil, err
+	}
+
+	if err := j.checkSession(claims); err != nil {
+		return nil, err
+	}
+
+	if err := j.checkTokenVersion(claims); err != nil {
+		return nil, err
+	}
+
+	if err := j.checkAccountStatus(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// RevokeToken revoga um token válido até sua expiração. Com WithSessions, a
+// sessão do token também é encerrada
+func (j *jwtService) RevokeToken(ctx context.Context, tokenString string) error {
+	if j.blacklist == nil && j.sessions == nil {
+		return errors.New("token blacklist not configured")
+	}
+
+	claims, err := j.ValidateToken(tokenString)
+	if err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return ErrInvalidToken
+	}
+
+	if err := j.removeSession(ctx, claims); err != nil {
+		return err
+	}
+	if j.blacklist == nil {
+		return nil
+	}
+
+	ttl := claims.ExpiresAt.Time.Sub(j.clock.Now())
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := j.blacklist.Revoke(ctx, claims.ID, ttl); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	return nil
+}
+
+// checkRevoked consulta o blacklist, quando configurado.
+// Falhas na consulta rejeitam o token (fail-closed)
+func (j *jwtService) checkRevoked(claims *Claims) error {
+	if j.blacklist == nil || claims.ID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blacklistTimeout)
+	defer cancel()
+
+	revoked, err := j.blacklist.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check blacklist: %v", ErrInvalidToken, err)
+	}
+	if revoked {
+		return ErrRevokedToken
+	}
+
+	return nil
+}
+
+// checkTokenVersion rejeita tokens emitidos antes da última revogação em massa
+// do usuário. Falhas na consulta rejeitam o token (fail-closed)
+func (j *jwtService) checkTokenVersion(claims *Claims) error {
+	if j.versions == nil {
+		return nil
+	}
+
+	current, err := j.currentTokenVersion(claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check token version: %v", ErrInvalidToken, err)
+	}
+	if claims.TokenVersion < current {
+		return ErrRevokedToken
+	}
+
+	return nil
+}
+
+// currentTokenVersion consulta a versão atual com o mesmo timeout do blacklist
+func (j *jwtService) currentTokenVersion(userID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), blacklistTimeout)
+	defer cancel()
+
+	return j.versions.GetTokenVersion(ctx, userID)
+}
+
+// keyFunc seleciona a chave de verificação pelo kid do header.
+// Tokens sem kid (emitidos antes da rotação) usam a chave ativa
+func (j *jwtService) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return j.keys[j.activeKID], nil
+	}
+
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
 	}
 
-	return nil, ErrInvalidToken
+	return key, nil
 }
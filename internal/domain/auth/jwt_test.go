@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRotation(t *testing.T) {
+	oldService, err := NewJWTServiceWithKeys("v1", map[string]string{"v1": "old-secret"}, time.Hour)
+	require.NoError(t, err)
+
+	rotated, err := NewJWTServiceWithKeys("v2", map[string]string{
+		"v1": "old-secret",
+		"v2": "new-secret",
+	}, time.Hour)
+	require.NoError(t, err)
+
+	t.Run("stamps active kid", func(t *testing.T) {
+		tokenString, err := rotated.GenerateToken("1", "a@b.com", "user")
+		require.NoError(t, err)
+
+		token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+		require.NoError(t, err)
+		assert.Equal(t, "v2", token.Header["kid"])
+	})
+
+	t.Run("accepts token signed with previous key", func(t *testing.T) {
+		tokenString, err := oldService.GenerateToken("1", "a@b.com", "user")
+		require.NoError(t, err)
+
+		claims, err := rotated.ValidateToken(tokenString)
+		require.NoError(t, err)
+		assert.Equal(t, "1", claims.UserID)
+	})
+
+	t.Run("rejects token after previous key is retired", func(t *testing.T) {
+		tokenString, err := oldService.GenerateToken("1", "a@b.com", "user")
+		require.NoError(t, err)
+
+		retired, err := NewJWTServiceWithKeys("v2", map[string]string{"v2": "new-secret"}, time.Hour)
+		require.NoError(t, err)
+
+		_, err = retired.ValidateToken(tokenString)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("rejects unknown active kid", func(t *testing.T) {
+		_, err := NewJWTServiceWithKeys("v3", map[string]string{"v1": "old-secret"}, time.Hour)
+		assert.ErrorIs(t, err, ErrUnknownKeyID)
+	})
+}
+
+func TestValidateTokenRejectsUnexpectedAlgorithms(t *testing.T) {
+	service := NewJWTService("test-secret", time.Hour)
+	claims := &Claims{
+		UserID: "1",
+		Email:  "a@b.com",
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	t.Run("none", func(t *testing.T) {
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = service.ValidateToken(tokenString)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+		require.NoError(t, err)
+
+		_, err = service.ValidateToken(tokenString)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other HMAC with the same secret", func(t *testing.T) {
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+
+		_, err = service.ValidateToken(tokenString)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("configured algorithm", func(t *testing.T) {
+		hs512 := NewJWTService("test-secret", time.Hour, WithSigningMethod(jwt.SigningMethodHS512))
+		tokenString, err := hs512.GenerateToken("1", "a@b.com", "user")
+		require.NoError(t, err)
+
+		_, err = hs512.ValidateToken(tokenString)
+		assert.NoError(t, err)
+		_, err = service.ValidateToken(tokenString)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestSigningMethodByName(t *testing.T) {
+	method, err := SigningMethodByName("")
+	require.NoError(t, err)
+	assert.Equal(t, "HS256", method.Alg())
+
+	_, err = SigningMethodByName("RS256")
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	_, err = SigningMethodByName("none")
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestTokenLimits(t *testing.T) {
+	manyPermissions := func(string) []string {
+		perms := make([]string, 100)
+		for i := range perms {
+			perms[i] = fmt.Sprintf("users:permission:%03d", i)
+		}
+		return perms
+	}
+	discard := WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	t.Run("caps permissions", func(t *testing.T) {
+		service := NewJWTService("secret", time.Hour, discard, WithPermissions(manyPermissions), WithTokenLimits(8192, 5))
+		tokenString, err := service.GenerateToken("1", "a@b.com", "admin")
+		require.NoError(t, err)
+
+		claims, err := service.ValidateToken(tokenString)
+		require.NoError(t, err)
+		assert.Len(t, claims.Permissions, 5)
+	})
+
+	t.Run("drops permissions over byte budget", func(t *testing.T) {
+		service := NewJWTService("secret", time.Hour, discard, WithPermissions(manyPermissions), WithTokenLimits(600, 100))
+		tokenString, err := service.GenerateToken("1", "a@b.com", "admin")
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(tokenString), 600)
+
+		claims, err := service.ValidateToken(tokenString)
+		require.NoError(t, err)
+		assert.Empty(t, claims.Permissions)
+	})
+
+	t.Run("rejects token that cannot fit", func(t *testing.T) {
+		service := NewJWTService("secret", time.Hour, discard, WithTokenLimits(50, 0))
+		_, err := service.GenerateToken("1", "a@b.com", "admin")
+		assert.ErrorIs(t, err, ErrTokenTooLarge)
+	})
+}
+
+// fakeVersions simula a coluna token_version
+type fakeVersions map[string]int
+
+func (f fakeVersions) GetTokenVersion(_ context.Context, userID string) (int, error) {
+	return f[userID], nil
+}
+
+func TestTokenVersionBumpRevokesOldTokens(t *testing.T) {
+	versions := fakeVersions{"1": 0, "2": 0}
+	service := NewJWTService("secret", time.Hour, WithTokenVersions(versions))
+
+	oldToken, err := service.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+	otherUser, err := service.GenerateToken("2", "b@b.com", "user")
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(oldToken)
+	require.NoError(t, err)
+
+	// Revogação em massa do usuário 1
+	versions["1"]++
+
+	_, err = service.ValidateToken(oldToken)
+	assert.ErrorIs(t, err, ErrRevokedToken)
+
+	_, err = service.ValidateToken(otherUser)
+	assert.NoError(t, err, "other users' sessions are unaffected")
+
+	newToken, err := service.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+	claims, err := service.ValidateToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, 1, claims.TokenVersion)
+}
+
+func TestTokenExpiryWithFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	service := NewJWTService("secret", time.Hour, WithClock(fake))
+
+	tokenString, err := service.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(tokenString)
+	require.NoError(t, err)
+	assert.True(t, fake.Now().Add(time.Hour).Equal(claims.ExpiresAt.Time))
+
+	fake.Advance(59 * time.Minute)
+	_, err = service.ValidateToken(tokenString)
+	assert.NoError(t, err)
+
+	fake.Advance(time.Minute)
+	_, err = service.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestTokenNotBeforeWithFakeClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	service := NewJWTService("secret", time.Hour, WithClock(fake))
+	start := fake.Now().Add(24 * time.Hour)
+
+	tokenString, err := service.GenerateToken("1", "a@b.com", "user", WithNotBefore(start))
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrTokenNotYetValid)
+
+	fake.Set(start)
+	claims, err := service.ValidateToken(tokenString)
+	require.NoError(t, err)
+	assert.True(t, start.Equal(claims.NotBefore.Time))
+	assert.True(t, start.Add(time.Hour).Equal(claims.ExpiresAt.Time), "expiry counts from nbf")
+
+	fake.Advance(time.Hour)
+	_, err = service.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestMultipleAudiences(t *testing.T) {
+	issuer := NewJWTService("secret", time.Hour, WithAudience("users-api"))
+	billing := NewJWTService("secret", time.Hour, WithAudience("billing"))
+	reports := NewJWTService("secret", time.Hour, WithAudience("reports"))
+
+	tokenString, err := issuer.GenerateToken("1", "a@b.com", "user", WithTokenAudiences("users-api", "billing"))
+	require.NoError(t, err)
+
+	claims, err := billing.ValidateToken(tokenString)
+	require.NoError(t, err, "billing is among the token audiences")
+	assert.Equal(t, jwt.ClaimStrings{"users-api", "billing"}, claims.Audience)
+
+	_, err = issuer.ValidateToken(tokenString)
+	assert.NoError(t, err)
+
+	_, err = reports.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrInvalidToken, "reports is not among the token audiences")
+
+	defaultAud, err := issuer.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+	_, err = billing.ValidateToken(defaultAud)
+	assert.ErrorIs(t, err, ErrInvalidToken, "tokens default to the issuer's own audience")
+
+	noAud, err := NewJWTService("secret", time.Hour).GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+	_, err = issuer.ValidateToken(noAud)
+	assert.ErrorIs(t, err, ErrInvalidToken, "aud is required once an audience is configured")
+}
+
+func TestIssuedAudiencesFromConfig(t *testing.T) {
+	issuer := NewJWTService("secret", time.Hour, WithAudience("users-api", "users-api", "billing"))
+	billing := NewJWTService("secret", time.Hour, WithAudience("billing"))
+
+	tokenString, err := issuer.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+
+	_, err = billing.ValidateToken(tokenString)
+	assert.NoError(t, err)
+}
+
+func TestTokenNotBeforeDelay(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	service := NewJWTService("secret", time.Hour, WithClock(fake))
+
+	tokenString, err := service.GenerateToken("1", "a@b.com", "user", WithNotBeforeDelay(10*time.Minute))
+	require.NoError(t, err)
+
+	fake.Advance(9 * time.Minute)
+	_, err = service.ValidateToken(tokenString)
+	assert.ErrorIs(t, err, ErrTokenNotYetValid)
+
+	fake.Advance(time.Minute)
+	_, err = service.ValidateToken(tokenString)
+	assert.NoError(t, err)
+
+	past, err := service.GenerateToken("1", "a@b.com", "user", WithNotBefore(fake.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	_, err = service.ValidateToken(past)
+	assert.NoError(t, err, "past nbf is clamped to issuance")
+}
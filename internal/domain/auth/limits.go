@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// ErrTokenTooLarge indica que o token excede o orçamento de bytes mesmo após
+// remover as claims opcionais
+var ErrTokenTooLarge = errors.New("token exceeds maximum size")
+
+// Limites padrão de tamanho do token. Ficam bem abaixo dos limites típicos de
+// header (8 KiB) de proxies e servidores, evitando erros 431 no caminho
+const (
+	DefaultMaxTokenBytes  = 4096
+	DefaultMaxPermissions = 32
+)
+
+// PermissionsFunc resolve as permissões embutidas no token para um papel
+type PermissionsFunc func(role string) []string
+
+// WithPermissions embute no token as permissões resolvidas para o papel do usuário
+func WithPermissions(resolve PermissionsFunc) JWTOption {
+	return func(j *jwtService) {
+		j.permissions = resolve
+	}
+}
+
+// WithTokenLimits define o tamanho máximo do token em bytes e o número máximo
+// de permissões embutidas. Valores <= 0 usam os padrões
+func WithTokenLimits(maxBytes, maxPermissions int) JWTOption {
+	return func(j *jwtService) {
+		if maxBytes > 0 {
+			j.maxBytes = maxBytes
+		}
+		if maxPermissions > 0 {
+			j.maxPermissions = maxPermissions
+		}
+	}
+}
+
+// WithLogger define o logger usado para avisos de limites de claims
+func WithLogger(logger *slog.Logger) JWTOption {
+	return func(j *jwtService) {
+		j.logger = logger
+	}
+}
+
+// limitPermissions corta a lista de permissões no máximo configurado
+func (j *jwtService) limitPermissions(userID string, permissions []string) []string {
+	if len(permissions) <= j.maxPermissions {
+		return permissions
+	}
+
+	j.logger.Warn("jwt permissions trimmed",
+		"user_id", userID,
+		"permissions", len(permissions),
+		"max_permissions", j.maxPermissions,
+	)
+	return permissions[:j.maxPermissions]
+}
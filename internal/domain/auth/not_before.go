@@ -0,0 +1,43 @@
+package auth
+
+import "time"
+
+// TokenOption ajusta a emissão de um token específico
+type TokenOption func(*tokenOptions)
+
+// tokenOptions acumula os ajustes de uma emissão
+type tokenOptions struct {
+	notBefore time.Time
+	delay     time.Duration
+	audiences []string
+	userAgent string
+	ip        string
+}
+
+// WithNotBefore emite um token que só passa a valer em t (claim nbf). A
+// expiração é contada a partir de t, não da emissão; instantes no passado
+// são ignorados
+func WithNotBefore(t time.Time) TokenOption {
+	return func(o *tokenOptions) {
+		o.notBefore = t
+	}
+}
+
+// WithNotBeforeDelay emite um token que só passa a valer após d a partir da emissão
+func WithNotBeforeDelay(d time.Duration) TokenOption {
+	return func(o *tokenOptions) {
+		o.delay = d
+	}
+}
+
+// validFrom resolve o início da validade; nunca antes de now
+func (o tokenOptions) validFrom(now time.Time) time.Time {
+	start := now
+	if o.delay > 0 {
+		start = now.Add(o.delay)
+	}
+	if o.notBefore.After(start) {
+		start = o.notBefore
+	}
+	return start
+}
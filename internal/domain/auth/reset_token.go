@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+)
+
+// Erros da verificação de tokens de redefinição de senha
+var (
+	ErrResetTokenInvalid = errors.New("reset token is invalid")
+	ErrResetTokenExpired = errors.New("reset token has expired")
+	// ErrResetTokenExhausted indica que o token foi invalidado por excesso de tentativas
+	ErrResetTokenExhausted = errors.New("reset token exceeded the maximum number of attempts")
+)
+
+// Padrões e limites dos tokens de redefinição de senha
+const (
+	DefaultResetTokenTTL   = time.Hour
+	DefaultResetTokenBytes = 32
+	// DefaultResetTokenMaxAttempts é o número de confirmações erradas toleradas
+	DefaultResetTokenMaxAttempts = 5
+	// MinResetTokenBytes garante ao menos 128 bits de entropia
+	MinResetTokenBytes = 16
+)
+
+// ResetToken é o que deve ser persistido de um token de redefinição: apenas o
+// hash, nunca o valor enviado ao usuário
+type ResetToken struct {
+	Hash      string
+	ExpiresAt time.Time
+	// Attempts conta as confirmações; é persistido junto do hash e incrementado
+	// pelo armazenamento (ResetAttemptCounter)
+	Attempts int
+}
+
+// ResetAttemptCounter registra as tentativas de confirmação no armazenamento.
+// O incremento precisa ser atômico, por exemplo
+//
+//	UPDATE password_reset_tokens SET attempts = attempts + 1
+//	WHERE token_hash = $1 RETURNING attempts
+//
+// para que confirmações concorrentes não compartilhem a mesma contagem
+type ResetAttemptCounter interface {
+	// IncrementResetAttempts incrementa as tentativas do token com o hash e
+	// retorna o novo total
+	IncrementResetAttempts(ctx context.Context, hash string) (int, error)
+}
+
+// ResetTokenIssuer gera e verifica tokens de redefinição de senha
+type ResetTokenIssuer struct {
+	length      int
+	ttl         time.Duration
+	maxAttempts int
+	clock       clock.Clock
+}
+
+// ResetTokenOption configura o ResetTokenIssuer
+type ResetTokenOption func(*ResetTokenIssuer)
+
+// WithResetMaxAttempts define quantas confirmações erradas um token tolera
+// antes de ser invalidado. Valores <= 0 usam DefaultResetTokenMaxAttempts
+func WithResetMaxAttempts(n int) ResetTokenOption {
+	return func(i *ResetTokenIssuer) {
+		if n > 0 {
+			i.maxAttempts = n
+		}
+	}
+}
+
+// NewResetTokenIssuer cria um emissor com tokens de length bytes aleatórios
+// válidos por ttl. Valores <= 0 usam os padrões; length abaixo de
+// MinResetTokenBytes é rejeitado. c é a fonte de tempo da expiração; nil usa clock.System
+func NewResetTokenIssuer(length int, ttl time.Duration, c clock.Clock, opts ...ResetTokenOption) (*ResetTokenIssuer, error) {
+	if length <= 0 {
+		length = DefaultResetTokenBytes
+	}
+	if length < MinResetTokenBytes {
+		return nil, fmt.Errorf("reset token length %d is below the minimum of %d bytes", length, MinResetTokenBytes)
+	}
+	if ttl <= 0 {
+		ttl = DefaultResetTokenTTL
+	}
+
+	if c == nil {
+		c = clock.System
+	}
+
+	i := &ResetTokenIssuer{length: length, ttl: ttl, maxAttempts: DefaultResetTokenMaxAttempts, clock: c}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i, nil
+}
+
+// Issue gera um token com crypto/rand. Retorna o valor a enviar ao usuário e
+// o ResetToken a persistir
+func (i *ResetTokenIssuer) Issue() (string, ResetToken, error) {
+	raw := make([]byte, i.length)
+	if _, err := rand.Read(raw); err != nil {
+		return "", ResetToken{}, fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	plain := base64.RawURLEncoding.EncodeToString(raw)
+	return plain, ResetToken{
+		Hash:      HashResetToken(plain),
+		ExpiresAt: i.clock.Now().Add(i.ttl),
+	}, nil
+}
+
+// Verify confere o token recebido com o persistido, em tempo constante, e
+// rejeita tokens expirados
+func (i *ResetTokenIssuer) Verify(plain string, stored ResetToken) error {
+	if subtle.ConstantTimeCompare([]byte(HashResetToken(plain)), []byte(stored.Hash)) != 1 {
+		return ErrResetTokenInvalid
+	}
+	if !i.clock.Now().Before(stored.ExpiresAt) {
+		return ErrResetTokenExpired
+	}
+	return nil
+}
+
+// Confirm registra a tentativa em attempts antes de comparar o token, de modo
+// que requisições concorrentes nunca fazem mais comparações que o máximo.
+// Além do máximo, ou quando a última tentativa permitida erra, retorna
+// ErrResetTokenExhausted mesmo para o token correto; o chamador deve então
+// descartar o token
+func (i *ResetTokenIssuer) Confirm(ctx context.Context, plain string, stored ResetToken, attempts ResetAttemptCounter) error {
+	n, err := attempts.IncrementResetAttempts(ctx, stored.Hash)
+	if err != nil {
+		return fmt.Errorf("failed to record reset token attempt: %w", err)
+	}
+	if n > i.maxAttempts {
+		return ErrResetTokenExhausted
+	}
+
+	err = i.Verify(plain, stored)
+	if errors.Is(err, ErrResetTokenInvalid) && n >= i.maxAttempts {
+		return ErrResetTokenExhausted
+	}
+	return err
+}
+
+// HashResetToken calcula o hash persistido de um token. SHA-256 basta: o token
+// já tem entropia alta, então não precisa de um hash lento como bcrypt
+func HashResetToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
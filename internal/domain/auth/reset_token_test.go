@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetTokenIssuer(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+	issuer, err := NewResetTokenIssuer(24, 15*time.Minute, fake)
+	require.NoError(t, err)
+
+	plain, stored, err := issuer.Issue()
+	require.NoError(t, err)
+
+	raw, err := base64.RawURLEncoding.DecodeString(plain)
+	require.NoError(t, err)
+	assert.Len(t, raw, 24)
+	assert.NotEqual(t, plain, stored.Hash)
+	assert.Equal(t, HashResetToken(plain), stored.Hash)
+	assert.Equal(t, now.Add(15*time.Minute), stored.ExpiresAt)
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, issuer.Verify(plain, stored))
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		assert.ErrorIs(t, issuer.Verify(plain+"x", stored), ErrResetTokenInvalid)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		fake.Advance(15 * time.Minute)
+		defer fake.Set(now)
+
+		assert.ErrorIs(t, issuer.Verify(plain, stored), ErrResetTokenExpired)
+	})
+}
+
+func TestNewResetTokenIssuerLimits(t *testing.T) {
+	_, err := NewResetTokenIssuer(MinResetTokenBytes-1, time.Hour, nil)
+	assert.Error(t, err)
+
+	issuer, err := NewResetTokenIssuer(0, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultResetTokenBytes, issuer.length)
+	assert.Equal(t, DefaultResetTokenTTL, issuer.ttl)
+}
+
+// attemptCounter é um ResetAttemptCounter em memória
+type attemptCounter struct {
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (c *attemptCounter) IncrementResetAttempts(_ context.Context, hash string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.attempts == nil {
+		c.attempts = make(map[string]int)
+	}
+	c.attempts[hash]++
+	return c.attempts[hash], nil
+}
+
+func TestResetTokenConfirmLimitsAttempts(t *testing.T) {
+	ctx := context.Background()
+	issuer, err := NewResetTokenIssuer(0, time.Hour, nil, WithResetMaxAttempts(3))
+	require.NoError(t, err)
+
+	plain, stored, err := issuer.Issue()
+	require.NoError(t, err)
+	counter := &attemptCounter{}
+
+	assert.ErrorIs(t, issuer.Confirm(ctx, "wrong", stored, counter), ErrResetTokenInvalid)
+	assert.ErrorIs(t, issuer.Confirm(ctx, "wrong", stored, counter), ErrResetTokenInvalid)
+	assert.ErrorIs(t, issuer.Confirm(ctx, "wrong", stored, counter), ErrResetTokenExhausted)
+
+	assert.ErrorIs(t, issuer.Confirm(ctx, plain, stored, counter), ErrResetTokenExhausted, "correct token is rejected once exhausted")
+	assert.Equal(t, 4, counter.attempts[stored.Hash])
+}
+
+func TestResetTokenConfirmAcceptsBeforeLimit(t *testing.T) {
+	ctx := context.Background()
+	issuer, err := NewResetTokenIssuer(0, time.Hour, nil)
+	require.NoError(t, err)
+
+	plain, stored, err := issuer.Issue()
+	require.NoError(t, err)
+	counter := &attemptCounter{}
+
+	for i := 0; i < DefaultResetTokenMaxAttempts-1; i++ {
+		assert.ErrorIs(t, issuer.Confirm(ctx, "wrong", stored, counter), ErrResetTokenInvalid)
+	}
+	assert.NoError(t, issuer.Confirm(ctx, plain, stored, counter))
+}
+
+func TestResetTokenConfirmConcurrentGuesses(t *testing.T) {
+	ctx := context.Background()
+	issuer, err := NewResetTokenIssuer(0, time.Hour, nil, WithResetMaxAttempts(3))
+	require.NoError(t, err)
+
+	_, stored, err := issuer.Issue()
+	require.NoError(t, err)
+	counter := &attemptCounter{}
+
+	// Todas as goroutines partem do mesmo token lido do armazenamento; só as
+	// três primeiras tentativas registradas chegam a comparar o palpite
+	var wg sync.WaitGroup
+	var compared atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errors.Is(issuer.Confirm(ctx, "wrong", stored, counter), ErrResetTokenInvalid) {
+				compared.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), compared.Load(), "the third wrong guess exhausts the token")
+	assert.Equal(t, 20, counter.attempts[stored.Hash])
+}
+
+func TestResetTokenConfirmCounterFailure(t *testing.T) {
+	issuer, err := NewResetTokenIssuer(0, time.Hour, nil)
+	require.NoError(t, err)
+
+	plain, stored, err := issuer.Issue()
+	require.NoError(t, err)
+
+	err = issuer.Confirm(context.Background(), plain, stored, failingCounter{})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrResetTokenInvalid)
+}
+
+// failingCounter simula uma falha do armazenamento
+type failingCounter struct{}
+
+func (failingCounter) IncrementResetAttempts(context.Context, string) (int, error) {
+	return 0, errors.New("storage unavailable")
+}
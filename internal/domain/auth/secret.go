@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MinSecretBytes é o tamanho mínimo de um segredo HMAC forte: 256 bits, o
+// tamanho da saída do HS256
+const MinSecretBytes = 32
+
+// minSecretDistinctBytes rejeita segredos longos porém repetitivos ("aaaa...")
+const minSecretDistinctBytes = 8
+
+// ErrWeakSecret indica um segredo de assinatura fácil de quebrar por força bruta
+var ErrWeakSecret = errors.New("weak jwt secret")
+
+// placeholderSecrets são valores de exemplo que nunca devem assinar tokens reais
+var placeholderSecrets = map[string]struct{}{
+	"your-secret-key-change-in-production": {},
+}
+
+// ValidateSecretStrength verifica se o segredo HMAC tem ao menos
+// MinSecretBytes, variedade mínima de caracteres e não é um valor de exemplo.
+// Gere segredos com, por exemplo, `openssl rand -base64 48`
+func ValidateSecretStrength(secret string) error {
+	if len(secret) < MinSecretBytes {
+		return fmt.Errorf("%w: must be at least %d bytes, got %d", ErrWeakSecret, MinSecretBytes, len(secret))
+	}
+	if _, ok := placeholderSecrets[secret]; ok {
+		return fmt.Errorf("%w: the example secret from the documentation cannot be used", ErrWeakSecret)
+	}
+
+	distinct := make(map[byte]struct{}, minSecretDistinctBytes)
+	for i := 0; i < len(secret) && len(distinct) < minSecretDistinctBytes; i++ {
+		distinct[secret[i]] = struct{}{}
+	}
+	if len(distinct) < minSecretDistinctBytes {
+		return fmt.Errorf("%w: too few distinct characters", ErrWeakSecret)
+	}
+	return nil
+}
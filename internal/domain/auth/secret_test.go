@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSecretStrength(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		valid  bool
+	}{
+		{"random base64", "q3Jx8N0vTz1bLw9cR5mYkP2hGd7sAeU4fVnXoWiKj6E=", true},
+		{"too short", "abc", false},
+		{"one byte short", strings.Repeat("a1b2c3d4", 4)[:MinSecretBytes-1], false},
+		{"repetitive", strings.Repeat("ab", 32), false},
+		{"documentation placeholder", "your-secret-key-change-in-production", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecretStrength(tt.secret)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrWeakSecret)
+			}
+		})
+	}
+}
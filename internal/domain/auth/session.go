@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrSessionNotFound indica uma sessão inexistente, expirada ou de outro usuário
+var ErrSessionNotFound = errors.New("session not found")
+
+// maxUserAgentLength limita o user-agent guardado em cada sessão
+const maxUserAgentLength = 512
+
+// Session é um token emitido e ainda não revogado, identificado pelo jti.
+// UserAgent e IP identificam o dispositivo do login e somem junto com a sessão
+type Session struct {
+	ID        string
+	UserID    string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	UserAgent string
+	IP        string
+}
+
+// SessionStore guarda as sessões ativas de cada usuário até expirarem.
+// Implementações compartilhadas (ex.: Redis) valem para todas as réplicas
+type SessionStore interface {
+	Add(ctx context.Context, session Session) error
+	// List retorna as sessões não expiradas do usuário, da mais antiga para a mais recente
+	List(ctx context.Context, userID string) ([]Session, error)
+	Exists(ctx context.Context, userID, id string) (bool, error)
+	// Remove retorna ErrSessionNotFound se a sessão não pertence ao usuário
+	Remove(ctx context.Context, userID, id string) error
+	RemoveAll(ctx context.Context, userID string) error
+}
+
+// WithSessions registra cada token emitido como sessão e faz a validação
+// rejeitar tokens cuja sessão foi removida. Com maxSessions > 0, emitir além do
+// limite revoga as sessões mais antigas do usuário
+func WithSessions(store SessionStore, maxSessions int) JWTOption {
+	return func(j *jwtService) {
+		j.sessions = store
+		j.maxSessions = maxSessions
+	}
+}
+
+// WithSessionMetadata guarda o user-agent e o IP do login na sessão do token
+// emitido (ver WithSessions); user-agents longos são truncados
+func WithSessionMetadata(userAgent, ip string) TokenOption {
+	return func(o *tokenOptions) {
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+		}
+		o.userAgent = userAgent
+		o.ip = ip
+	}
+}
+
+// trackSession registra a sessão do token emitido e aplica o limite por usuário.
+// issuedAt tem a precisão do relógio, para ordenar logins no mesmo segundo
+func (j *jwtService) trackSession(claims *Claims, issuedAt time.Time, options tokenOptions) error {
+	if j.sessions == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blacklistTimeout)
+	defer cancel()
+
+	session := Session{
+		ID:        claims.ID,
+		UserID:    claims.UserID,
+		IssuedAt:  issuedAt,
+		ExpiresAt: claims.ExpiresAt.Time,
+		UserAgent: options.userAgent,
+		IP:        options.ip,
+	}
+	if err := j.sessions.Add(ctx, session); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	if j.maxSessions <= 0 {
+		return nil
+	}
+
+	active, err := j.sessions.List(ctx, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	excess := len(active) - j.maxSessions
+	for _, s := range active {
+		if excess <= 0 {
+			break
+		}
+		// A sessão recém-criada nunca é a revogada
+		if s.ID == session.ID {
+			continue
+		}
+		if err := j.sessions.Remove(ctx, s.UserID, s.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return fmt.Errorf("failed to revoke oldest session: %w", err)
+		}
+		excess--
+	}
+
+	return nil
+}
+
+// checkSession rejeita tokens cuja sessão foi revogada ou expirou.
+// Falhas na consulta rejeitam o token (fail-closed)
+func (j *jwtService) checkSession(claims *Claims) error {
+	if j.sessions == nil {
+		return nil
+	}
+	if claims.ID == "" {
+		return ErrRevokedToken
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blacklistTimeout)
+	defer cancel()
+
+	exists, err := j.sessions.Exists(ctx, claims.UserID, claims.ID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check session: %v", ErrInvalidToken, err)
+	}
+	if !exists {
+		return ErrRevokedToken
+	}
+
+	return nil
+}
+
+// removeSession encerra a sessão de um token revogado; sessões já removidas são ignoradas
+func (j *jwtService) removeSession(ctx context.Context, claims *Claims) error {
+	if j.sessions == nil {
+		return nil
+	}
+
+	if err := j.sessions.Remove(ctx, claims.UserID, claims.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTokenVersionCacheTTL é a janela de consistência padrão da revogação em massa
+const DefaultTokenVersionCacheTTL = 10 * time.Second
+
+// CachedTokenVersions decora um TokenVersionSource com cache local por usuário.
+// Evita uma consulta ao banco por requisição autenticada, ao custo de uma janela
+// de consistência: após um incremento de token_version, tokens antigos ainda
+// podem ser aceitos por até ttl em cada instância
+type CachedTokenVersions struct {
+	source TokenVersionSource
+	cache  *ttlCache[int]
+}
+
+var _ TokenVersionSource = (*CachedTokenVersions)(nil)
+
+// NewCachedTokenVersions cria o cache; ttl <= 0 usa DefaultTokenVersionCacheTTL
+func NewCachedTokenVersions(source TokenVersionSource, ttl time.Duration) *CachedTokenVersions {
+	if ttl <= 0 {
+		ttl = DefaultTokenVersionCacheTTL
+	}
+	return &CachedTokenVersions{
+		source: source,
+		cache:  newTTLCache[int](ttl),
+	}
+}
+
+// GetTokenVersion retorna a versão em cache ou consulta a fonte quando expirada.
+// Erros não são armazenados em cache
+func (c *CachedTokenVersions) GetTokenVersion(ctx context.Context, userID string) (int, error) {
+	if version, ok := c.cache.get(userID); ok {
+		return version, nil
+	}
+
+	version, err := c.source.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	c.cache.set(userID, version)
+	return version, nil
+}
+
+// Invalidate descarta a versão em cache do usuário, encerrando a janela de
+// consistência nesta instância (ex.: logo após uma revogação local)
+func (c *CachedTokenVersions) Invalidate(userID string) {
+	c.cache.delete(userID)
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingVersions conta as consultas à fonte
+type countingVersions struct {
+	version int
+	calls   int
+}
+
+func (c *countingVersions) GetTokenVersion(context.Context, string) (int, error) {
+	c.calls++
+	return c.version, nil
+}
+
+func TestCachedTokenVersions(t *testing.T) {
+	ctx := context.Background()
+	source := &countingVersions{}
+	cache := NewCachedTokenVersions(source, time.Minute)
+	now := time.Now()
+	cache.cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		v, err := cache.GetTokenVersion(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, v)
+	}
+	assert.Equal(t, 1, source.calls, "lookups within the TTL are served from cache")
+
+	// Dentro da janela de consistência o valor antigo ainda é retornado
+	source.version = 1
+	v, _ := cache.GetTokenVersion(ctx, "1")
+	assert.Equal(t, 0, v)
+
+	// Após o TTL a fonte é consultada novamente
+	now = now.Add(time.Minute)
+	v, _ = cache.GetTokenVersion(ctx, "1")
+	assert.Equal(t, 1, v)
+	assert.Equal(t, 2, source.calls)
+
+	// Invalidate encerra a janela imediatamente
+	source.version = 2
+	cache.Invalidate("1")
+	v, _ = cache.GetTokenVersion(ctx, "1")
+	assert.Equal(t, 2, v)
+}
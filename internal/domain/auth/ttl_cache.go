@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlCacheSweep é o tamanho a partir do qual entradas expiradas são removidas
+const ttlCacheSweep = 10000
+
+// ttlCache guarda valores por usuário durante um TTL fixo
+type ttlCache[V any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]ttlEntry[V]
+}
+
+// ttlEntry é um valor e o instante em que expira
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// newTTLCache cria um cache com o TTL informado
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]ttlEntry[V]),
+	}
+}
+
+// get retorna o valor se presente e não expirado
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || !c.now().Before(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// set armazena o valor até now + ttl
+func (c *ttlCache[V]) set(key string, value V) {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= ttlCacheSweep {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = ttlEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// delete descarta o valor da chave
+func (c *ttlCache[V]) delete(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
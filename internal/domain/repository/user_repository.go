@@ -2,21 +2,45 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go-api-boilerplate/internal/domain/user"
 )
 
+// RoleUpdateResult classifica os IDs de uma atualização de roles em lote,
+// preservando a ordem de entrada
+type RoleUpdateResult struct {
+	// Updated são os usuários cujo role foi alterado
+	Updated []string
+	// Unchanged são os usuários que já tinham o role
+	Unchanged []string
+	// NotFound são os IDs inexistentes ou inválidos
+	NotFound []string
+}
+
 // UserRepository define os contratos para persistência de usuários
 type UserRepository interface {
 	// Create cria um novo usuário no repositório
 	Create(ctx context.Context, user *user.User) error
 
+	// CreateFirstAdmin cria o usuário (com role admin) somente se ainda não
+	// existir nenhum admin; caso contrário retorna ErrAdminAlreadyExists. A
+	// verificação e a inserção são atômicas entre chamadas concorrentes
+	CreateFirstAdmin(ctx context.Context, user *user.User) error
+
 	// GetByID busca um usuário pelo ID
 	GetByID(ctx context.Context, id string) (*user.User, error)
 
+	// GetByIDs busca vários usuários pelo ID, em qualquer ordem. IDs inexistentes
+	// ou inválidos são omitidos do resultado
+	GetByIDs(ctx context.Context, ids []string) ([]*user.User, error)
+
 	// GetByEmail busca um usuário pelo email
 	GetByEmail(ctx context.Context, email string) (*user.User, error)
 
+	// GetByUsername busca um usuário pelo nome de usuário, sem diferenciar maiúsculas
+	GetByUsername(ctx context.Context, username string) (*user.User, error)
+
 	// Update atualiza um usuário existente
 	Update(ctx context.Context, user *user.User) error
 
@@ -26,12 +50,146 @@ type UserRepository interface {
 	// List retorna uma lista de usuários com paginação
 	List(ctx context.Context, offset, limit int) ([]*user.User, error)
 
+	// ListActive retorna uma lista paginada apenas com usuários ativos
+	ListActive(ctx context.Context, offset, limit int) ([]*user.User, error)
+
+	// Search retorna uma página de usuários cujo nome ou email contém query
+	Search(ctx context.Context, query string, includeInactive bool, offset, limit int) ([]*user.User, error)
+
+	// CountSearch retorna o total de usuários que correspondem a query
+	CountSearch(ctx context.Context, query string, includeInactive bool) (int64, error)
+
 	// Count retorna o total de usuários
 	Count(ctx context.Context) (int64, error)
 
+	// CountActive retorna o total de usuários ativos
+	CountActive(ctx context.Context) (int64, error)
+
+	// ListAfterID retorna até limit usuários com ID maior que afterID, em ordem de
+	// ID (paginação por keyset). afterID vazio começa do início
+	ListAfterID(ctx context.Context, afterID string, limit int) ([]*user.User, error)
+
+	// Iterate percorre todos os usuários em lotes de batchSize, em ordem de ID,
+	// chamando fn para cada lote. Para no primeiro erro de fn, retornando-o
+	Iterate(ctx context.Context, batchSize int, fn func([]*user.User) error) error
+
+	// CountByRole retorna o total de usuários por papel, incluindo papéis
+	// válidos sem nenhum usuário (contagem zero)
+	CountByRole(ctx context.Context) (map[user.Role]int64, error)
+
+	// CountCreatedBetween retorna o total de usuários criados no intervalo [from, to]
+	CountCreatedBetween(ctx context.Context, from, to time.Time) (int64, error)
+
+	// ListCreatedBetween retorna uma página de usuários criados no intervalo [from, to]
+	ListCreatedBetween(ctx context.Context, from, to time.Time, offset, limit int) ([]*user.User, error)
+
+	// ReplacePassword grava o hash de senha e PasswordChangedAt de u apenas se
+	// o hash persistido ainda for previousHash. Retorna false, sem erro, quando
+	// a senha foi trocada por outra operação (ou o usuário não existe mais)
+	ReplacePassword(ctx context.Context, u *user.User, previousHash string) (bool, error)
+
+	// RecordLogin grava at como o momento do último login do usuário
+	RecordLogin(ctx context.Context, id string, at time.Time) error
+
+	// CountDormant retorna o total de usuários sem login desde since, incluindo
+	// os que nunca entraram
+	CountDormant(ctx context.Context, since time.Time) (int64, error)
+
+	// ListDormant retorna uma página de usuários sem login desde since, dos que
+	// nunca entraram aos de login mais antigo
+	ListDormant(ctx context.Context, since time.Time, offset, limit int) ([]*user.User, error)
+
+	// ListInactiveByRole retorna os usuários ativos do papel sem atividade desde
+	// since, da atividade mais antiga para a mais recente. A atividade é o último
+	// login ou, para quem nunca entrou, a criação da conta
+	ListInactiveByRole(ctx context.Context, role user.Role, since time.Time) ([]*user.User, error)
+
+	// CountActiveByRole retorna o total de usuários ativos do papel
+	CountActiveByRole(ctx context.Context, role user.Role) (int64, error)
+
+	// DowngradeInactive muda de from para to o papel dos usuários de ids que
+	// continuam sem atividade desde since, invalidando seus tokens na mesma
+	// operação. Quem entrou, mudou de papel ou foi desativado depois da
+	// listagem é ignorado.
+	// Retorna os IDs alterados
+	DowngradeInactive(ctx context.Context, ids []string, from, to user.Role, since time.Time) ([]string, error)
+
+	// Anonymize grava os dados anonimizados do usuário, desativando a conta,
+	// invalidando seus tokens e removendo os tokens de verificação de email e de
+	// redefinição de senha pendentes e os vínculos com identidades externas na
+	// mesma operação
+	Anonymize(ctx context.Context, u *user.User) error
+
+	// IncrementTokenVersion incrementa a versão dos tokens do usuário, invalidando
+	// todas as sessões emitidas, e retorna a nova versão
+	IncrementTokenVersion(ctx context.Context, id string) (int, error)
+
+	// GetTokenVersion retorna a versão atual dos tokens do usuário
+	GetTokenVersion(ctx context.Context, id string) (int, error)
+
+	// IsActive informa se o usuário está ativo; retorna ErrUserNotFound se
+	// ele tiver sido excluído
+	IsActive(ctx context.Context, id string) (bool, error)
+
+	// UpdateRoles define o role de vários usuários em uma única transação,
+	// incrementando o token_version de quem teve o role alterado
+	UpdateRoles(ctx context.Context, ids []string, role user.Role) (*RoleUpdateResult, error)
+
+	// PreviewUpdateRoles reporta o efeito de UpdateRoles sem persistir alterações
+	PreviewUpdateRoles(ctx context.Context, ids []string, role user.Role) (*RoleUpdateResult, error)
+
 	// ExistsByEmail verifica se existe um usuário com o email fornecido
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 
+	// ExistsByUsername verifica se o nome de usuário já está em uso, sem diferenciar maiúsculas
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+
+	// ExistingEmails retorna, em qualquer ordem, quais dos emails fornecidos já
+	// estão cadastrados; a comparação é exata, então normalize antes
+	ExistingEmails(ctx context.Context, emails []string) ([]string, error)
+
 	// ExistsByID verifica se existe um usuário com o ID fornecido
 	ExistsByID(ctx context.Context, id string) (bool, error)
-} 
\ No newline at end of file
+
+	// GetByIdentity busca o usuário vinculado à identidade externa (issuer e
+	// subject do provedor OIDC)
+	GetByIdentity(ctx context.Context, issuer, subject string) (*user.User, error)
+
+	// LinkIdentity vincula uma identidade externa ao usuário; retorna
+	// ErrIdentityAlreadyLinked se ela já estiver vinculada
+	LinkIdentity(ctx context.Context, userID, issuer, subject string) error
+
+	// ReplaceVerificationToken grava um token de verificação de email, descartando
+	// na mesma transação os tokens ainda não usados do usuário
+	ReplaceVerificationToken(ctx context.Context, token *user.VerificationToken) error
+
+	// ConsumeVerificationToken remove e retorna o token com o hash informado;
+	// retorna ErrVerificationTokenInvalid se ele não existir. Não verifica a expiração
+	ConsumeVerificationToken(ctx context.Context, hash string) (*user.VerificationToken, error)
+
+	// MarkEmailVerified marca o email do usuário como verificado em verifiedAt,
+	// desde que email ainda seja o email atual; caso contrário retorna ErrUserNotFound
+	MarkEmailVerified(ctx context.Context, userID, email string, verifiedAt time.Time) error
+
+	// ChangeEmail troca o email do usuário de oldEmail para newEmail, já
+	// verificado em verifiedAt. Retorna ErrUserNotFound se oldEmail não for mais
+	// o email atual e ErrUserAlreadyExists se newEmail já estiver em uso
+	ChangeEmail(ctx context.Context, userID, oldEmail, newEmail string, verifiedAt time.Time) error
+
+	// ReplacePasswordResetToken grava o token de redefinição de senha do
+	// usuário, descartando na mesma transação o token anterior
+	ReplacePasswordResetToken(ctx context.Context, token *user.PasswordResetToken) error
+
+	// GetPasswordResetToken retorna o token de redefinição pendente do usuário;
+	// retorna ErrPasswordResetTokenNotFound se não houver. Não verifica a expiração
+	GetPasswordResetToken(ctx context.Context, userID string) (*user.PasswordResetToken, error)
+
+	// IncrementResetAttempts incrementa atomicamente as tentativas do token com
+	// o hash e retorna o novo total (implementa auth.ResetAttemptCounter);
+	// retorna ErrPasswordResetTokenNotFound se ele não existir mais
+	IncrementResetAttempts(ctx context.Context, hash string) (int, error)
+
+	// ConsumePasswordResetToken remove o token com o hash; retorna
+	// ErrPasswordResetTokenNotFound se ele já tiver sido usado ou descartado
+	ConsumePasswordResetToken(ctx context.Context, hash string) error
+}
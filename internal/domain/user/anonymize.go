@@ -0,0 +1,32 @@
+package user
+
+// Valores gravados no lugar dos dados pessoais de uma conta anonimizada
+const (
+	AnonymizedName        = "Deleted User"
+	AnonymizedEmailDomain = "anonymized.invalid"
+	// unusablePassword não é um hash bcrypt válido, então nenhuma senha confere
+	unusablePassword = "!"
+)
+
+// AnonymizedEmail gera o email substituto de uma conta anonimizada. Deriva do
+// ID para respeitar a unicidade de email; o domínio .invalid nunca é roteável
+func AnonymizedEmail(id string) string {
+	return "deleted-" + id + "@" + AnonymizedEmailDomain
+}
+
+// Anonymize remove os dados pessoais mantendo a linha (e o ID) para integridade
+// referencial: email e nome são substituídos, o nome de usuário é removido, a
+// senha deixa de conferir e a conta é desativada
+func (u *User) Anonymize() {
+	u.Email = AnonymizedEmail(u.ID)
+	u.Name = AnonymizedName
+	u.Username = ""
+	u.Password = unusablePassword
+	u.IsActive = false
+	u.UpdatedAt = now()
+}
+
+// IsAnonymized indica se a conta já teve os dados pessoais removidos
+func (u *User) IsAnonymized() bool {
+	return u.Email == AnonymizedEmail(u.ID)
+}
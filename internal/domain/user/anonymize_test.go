@@ -0,0 +1,26 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymize(t *testing.T) {
+	u, err := NewUser("person@example.com", "password123", "Real Person", RoleUser)
+	require.NoError(t, err)
+	u.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+	require.NoError(t, u.SetUsername("real_person"))
+	require.False(t, u.IsAnonymized())
+
+	u.Anonymize()
+
+	assert.Equal(t, "deleted-8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60@anonymized.invalid", u.Email)
+	assert.Equal(t, AnonymizedName, u.Name)
+	assert.Empty(t, u.Username)
+	assert.False(t, u.IsActive)
+	assert.False(t, u.CheckPassword("password123"))
+	assert.True(t, u.IsAnonymized())
+	assert.NoError(t, ValidateName(u.Name))
+}
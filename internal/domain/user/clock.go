@@ -0,0 +1,32 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+)
+
+// entityClock é a fonte de tempo dos timestamps das entidades e eventos
+var entityClock = struct {
+	mu    sync.RWMutex
+	clock clock.Clock
+}{clock: clock.System}
+
+// SetClock define a fonte de tempo de CreatedAt, UpdatedAt e dos eventos.
+// Deve ser chamado na inicialização ou em testes; nil restaura clock.System
+func SetClock(c clock.Clock) {
+	if c == nil {
+		c = clock.System
+	}
+	entityClock.mu.Lock()
+	defer entityClock.mu.Unlock()
+	entityClock.clock = c
+}
+
+// now retorna o instante atual segundo o relógio configurado
+func now() time.Time {
+	entityClock.mu.RLock()
+	defer entityClock.mu.RUnlock()
+	return entityClock.clock.Now()
+}
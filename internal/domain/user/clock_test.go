@@ -0,0 +1,54 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityTimestampsUseClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	SetClock(fake)
+	t.Cleanup(func() { SetClock(nil) })
+
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+	assert.Equal(t, start, u.CreatedAt)
+	assert.Equal(t, start, u.UpdatedAt)
+
+	fake.Advance(time.Hour)
+	require.NoError(t, u.UpdateName("Ana Maria"))
+	assert.Equal(t, start, u.CreatedAt)
+	assert.Equal(t, start.Add(time.Hour), u.UpdatedAt)
+
+	assert.Equal(t, start.Add(time.Hour), NewEvent(EventUserUpdated, u).OccurredAt)
+}
+
+func TestPasswordExpiredFollowsChangePassword(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	maxAge := 90 * 24 * time.Hour
+	fake := clock.NewFake(start)
+	SetClock(fake)
+	t.Cleanup(func() { SetClock(nil) })
+
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+	assert.False(t, u.PasswordExpired(start.Add(maxAge-time.Second), maxAge))
+	assert.True(t, u.PasswordExpired(start.Add(maxAge), maxAge))
+	assert.False(t, u.PasswordExpired(start.Add(maxAge), 0))
+
+	fake.Advance(maxAge)
+	require.NoError(t, u.ChangePassword("new-password456"))
+	assert.False(t, u.PasswordExpired(fake.Now(), maxAge))
+	assert.Equal(t, fake.Now(), u.UpdatedAt)
+
+	// Contas externas não têm senha local e nunca expiram
+	external, err := NewExternalUser("bia@example.com", "Bia", RoleUser)
+	require.NoError(t, err)
+	assert.False(t, external.PasswordExpired(start.Add(10*maxAge), maxAge))
+}
@@ -0,0 +1,35 @@
+package user
+
+import "fmt"
+
+// DomainError acrescenta contexto (campo e valor envolvidos) a um erro
+// sentinela do domínio. Unwrap devolve o sentinela, então comparações com
+// errors.Is (ex.: ErrUserNotFound) continuam funcionando
+type DomainError struct {
+	Err   error
+	Field string
+	Value string
+}
+
+// NewDomainError envolve err com o campo e o valor que causaram o erro
+func NewDomainError(err error, field, value string) *DomainError {
+	return &DomainError{Err: err, Field: field, Value: value}
+}
+
+// Error implementa error
+func (e *DomainError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Err, e.Detail())
+}
+
+// Unwrap expõe o erro sentinela
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// Detail descreve o contexto no formato "campo=valor"
+func (e *DomainError) Detail() string {
+	return fmt.Sprintf("%s=%s", e.Field, e.Value)
+}
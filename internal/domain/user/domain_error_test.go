@@ -0,0 +1,25 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainError(t *testing.T) {
+	err := NewDomainError(ErrUserNotFound, "id", "123")
+
+	assert.Equal(t, "user not found: id=123", err.Error())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.NotErrorIs(t, err, ErrUserAlreadyExists)
+
+	wrapped := fmt.Errorf("failed to load: %w", err)
+	var domainErr *DomainError
+	assert.True(t, errors.As(wrapped, &domainErr))
+	assert.Equal(t, "id", domainErr.Field)
+	assert.ErrorIs(t, wrapped, ErrUserNotFound)
+
+	assert.Equal(t, "user not found", NewDomainError(ErrUserNotFound, "", "").Error())
+}
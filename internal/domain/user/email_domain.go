@@ -0,0 +1,109 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmailDomainNotAllowed indica um email cujo domínio não é aceito no cadastro
+var ErrEmailDomainNotAllowed = errors.New("email domain not allowed")
+
+// EmailDomainRules restringe os domínios de email aceitos no cadastro. Padrões
+// são domínios exatos ("example.com") ou curingas de subdomínio
+// ("*.example.com", que não inclui o próprio example.com). O bloqueio tem
+// precedência; com Allowed vazio todos os domínios não bloqueados são aceitos
+type EmailDomainRules struct {
+	Allowed []string
+	Blocked []string
+}
+
+// NewEmailDomainRules normaliza e valida os padrões de domínio
+func NewEmailDomainRules(allowed, blocked []string) (EmailDomainRules, error) {
+	rules := EmailDomainRules{}
+	for _, pattern := range allowed {
+		normalized, err := normalizeDomainPattern(pattern)
+		if err != nil {
+			return EmailDomainRules{}, err
+		}
+		rules.Allowed = append(rules.Allowed, normalized)
+	}
+	for _, pattern := range blocked {
+		normalized, err := normalizeDomainPattern(pattern)
+		if err != nil {
+			return EmailDomainRules{}, err
+		}
+		rules.Blocked = append(rules.Blocked, normalized)
+	}
+	return rules, nil
+}
+
+// ValidateDomainPattern verifica se um padrão de domínio é aceitável
+func ValidateDomainPattern(pattern string) error {
+	_, err := normalizeDomainPattern(pattern)
+	return err
+}
+
+// Check retorna ErrEmailDomainNotAllowed se o domínio do email (já
+// normalizado ou não) for bloqueado ou estiver fora da lista permitida
+func (r EmailDomainRules) Check(email string) error {
+	domain := EmailDomain(email)
+	if domain == "" {
+		return ErrEmailDomainNotAllowed
+	}
+
+	for _, pattern := range r.Blocked {
+		if matchDomain(pattern, domain) {
+			return ErrEmailDomainNotAllowed
+		}
+	}
+
+	if len(r.Allowed) == 0 {
+		return nil
+	}
+	for _, pattern := range r.Allowed {
+		if matchDomain(pattern, domain) {
+			return nil
+		}
+	}
+	return ErrEmailDomainNotAllowed
+}
+
+// EmailDomain retorna o domínio normalizado de um email, ou vazio se não houver
+func EmailDomain(email string) string {
+	email = NormalizeEmail(email)
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return email[at+1:]
+}
+
+// matchDomain compara o domínio com um padrão exato ou "*.sufixo"
+func matchDomain(pattern, domain string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return strings.HasSuffix(domain, suffix)
+	}
+	return domain == pattern
+}
+
+// normalizeDomainPattern converte o padrão para minúsculas e rejeita formatos inválidos
+func normalizeDomainPattern(pattern string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(pattern))
+	domain := strings.TrimPrefix(normalized, "*.")
+	if domain == "" || strings.ContainsAny(domain, "@* ") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return "", fmt.Errorf("invalid email domain pattern %q", pattern)
+	}
+	return normalized, nil
+}
+
+// ErrDisposableEmail indica um email de provedor descartável/temporário
+var ErrDisposableEmail = errors.New("disposable email addresses are not allowed")
+
+// EmailPolicy decide se um email pode ser usado no cadastro. Implementações
+// rejeitam retornando um erro que envolve ErrDisposableEmail; outros erros
+// indicam falha da própria verificação
+type EmailPolicy interface {
+	Check(ctx context.Context, email string) error
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailDomainRules(t *testing.T) {
+	rules, err := NewEmailDomainRules([]string{"Example.com", "*.corp.example.org"}, []string{"blocked.corp.example.org"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		email   string
+		allowed bool
+	}{
+		{"ana@example.com", true},
+		{"Ana@EXAMPLE.COM", true},
+		{"ana@mail.example.com", false},
+		{"ana@eu.corp.example.org", true},
+		{"ana@a.b.corp.example.org", true},
+		{"ana@corp.example.org", false},
+		{"ana@blocked.corp.example.org", false},
+		{"ana@evilcorp.example.org", false},
+		{"ana@gmail.com", false},
+		{"not-an-email", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			err := rules.Check(tt.email)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrEmailDomainNotAllowed)
+			}
+		})
+	}
+}
+
+func TestEmailDomainRulesDefaults(t *testing.T) {
+	assert.NoError(t, EmailDomainRules{}.Check("ana@anything.io"))
+
+	rules, err := NewEmailDomainRules(nil, []string{"*.tempmail.com"})
+	require.NoError(t, err)
+	assert.NoError(t, rules.Check("ana@gmail.com"))
+	assert.ErrorIs(t, rules.Check("ana@x.tempmail.com"), ErrEmailDomainNotAllowed)
+}
+
+func TestNewEmailDomainRulesRejectsInvalidPatterns(t *testing.T) {
+	for _, pattern := range []string{"", "*.", "ana@example.com", "*example.com", "example.com."} {
+		_, err := NewEmailDomainRules([]string{pattern}, nil)
+		assert.Error(t, err, pattern)
+	}
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifica o tipo de evento do ciclo de vida do usuário
+type EventType string
+
+const (
+	EventUserCreated EventType = "user.created"
+	EventUserUpdated EventType = "user.updated"
+	EventUserDeleted EventType = "user.deleted"
+)
+
+// Event representa uma mudança no ciclo de vida de um usuário
+type Event struct {
+	Type       EventType
+	UserID     string
+	User       *User // nil em eventos de exclusão
+	OccurredAt time.Time
+	// RequestID é o ID de correlação da requisição que originou o evento,
+	// repassado a webhooks e streams; vazio fora de requisições HTTP
+	RequestID string
+}
+
+// NewEvent cria um evento com uma cópia do usuário, para que assinantes
+// assíncronos não observem alterações posteriores da entidade
+func NewEvent(eventType EventType, u *User) Event {
+	event := Event{Type: eventType, OccurredAt: now().UTC()}
+	if u != nil {
+		snapshot := *u
+		event.User = &snapshot
+		event.UserID = u.ID
+	}
+	return event
+}
+
+// EventPublisher recebe eventos de usuário. Implementações não devem bloquear
+// a requisição que originou o evento
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
+}
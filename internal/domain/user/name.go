@@ -0,0 +1,99 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxNameLength é o tamanho máximo padrão de um nome, em caracteres
+const DefaultMaxNameLength = 255
+
+// Regras de validação de nome reportadas em ValidationError.Rule
+const (
+	RuleRequired    = "required"
+	RuleMaxLength   = "max_length"
+	RuleControlChar = "no_control_chars"
+	RuleCharset     = "charset"
+)
+
+// ErrInvalidName indica um nome que viola alguma regra de validação
+var ErrInvalidName = errors.New("invalid name")
+
+// ValidationError descreve qual regra um campo violou. Err permite comparar
+// com errors.Is (ex.: ErrEmptyName, ErrInvalidName)
+type ValidationError struct {
+	Field   string
+	Rule    string
+	Message string
+	Err     error
+}
+
+// Error implementa error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s failed %s: %s", e.Field, e.Rule, e.Message)
+}
+
+// Unwrap expõe o erro sentinela da regra
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// maxNameLength é o limite em vigor, ajustado na inicialização por SetMaxNameLength
+var maxNameLength atomic.Int64
+
+func init() {
+	maxNameLength.Store(DefaultMaxNameLength)
+}
+
+// SetMaxNameLength ajusta o tamanho máximo de nomes; n <= 0 restaura o padrão.
+// Deve ser chamado na inicialização, antes de atender requisições
+func SetMaxNameLength(n int) {
+	if n <= 0 {
+		n = DefaultMaxNameLength
+	}
+	maxNameLength.Store(int64(n))
+}
+
+// MaxNameLength retorna o tamanho máximo de nomes em vigor
+func MaxNameLength() int {
+	return int(maxNameLength.Load())
+}
+
+// ValidateName verifica um nome já normalizado: não vazio, dentro do limite de
+// tamanho, sem caracteres de controle e composto apenas por letras, marcas
+// (acentos combinantes), espaços, hífens e apóstrofos
+func ValidateName(name string) error {
+	if name == "" {
+		return &ValidationError{Field: "name", Rule: RuleRequired, Message: "cannot be empty", Err: ErrEmptyName}
+	}
+
+	if max := MaxNameLength(); utf8.RuneCountInString(name) > max {
+		return &ValidationError{Field: "name", Rule: RuleMaxLength, Message: fmt.Sprintf("must be at most %d characters", max), Err: ErrInvalidName}
+	}
+
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return &ValidationError{Field: "name", Rule: RuleControlChar, Message: "must not contain control characters", Err: ErrInvalidName}
+		}
+	}
+
+	for _, r := range name {
+		if !isNameRune(r) {
+			return &ValidationError{Field: "name", Rule: RuleCharset, Message: fmt.Sprintf("contains disallowed character %q", r), Err: ErrInvalidName}
+		}
+	}
+
+	return nil
+}
+
+// isNameRune informa se r é permitido em nomes
+func isNameRune(r rune) bool {
+	switch r {
+	case ' ', '-', '\'', '’':
+		return true
+	}
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"Ana", ""},
+		{"José da Silva", ""},
+		{"Zoë O'Brien-Smith", ""},
+		{"Mário Žák", ""},
+		{"Ме́ри", ""}, // marca combinante (acento agudo)
+		{"山田 太郎", ""},
+		{"", RuleRequired},
+		{"Ana\x00", RuleControlChar},
+		{"Robert'); DROP TABLE users;--", RuleCharset},
+		{"Ana 2", RuleCharset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.name)
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "name", validationErr.Field)
+			assert.Equal(t, tt.rule, validationErr.Rule)
+		})
+	}
+}
+
+func TestNameMaxLengthIsConfigurable(t *testing.T) {
+	defer SetMaxNameLength(0)
+
+	long := strings.Repeat("á", DefaultMaxNameLength)
+	assert.NoError(t, ValidateName(long), "length counts characters, not bytes")
+	assert.ErrorIs(t, ValidateName(long+"a"), ErrInvalidName)
+
+	SetMaxNameLength(3)
+	_, err := NewUser("ana@example.com", "password123", "Anna", RoleUser)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, RuleMaxLength, validationErr.Rule)
+}
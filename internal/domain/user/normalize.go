@@ -0,0 +1,15 @@
+package user
+
+import "strings"
+
+// NormalizeName remove espaços das extremidades e colapsa espaços internos
+// repetidos: "  Ana   Maria " vira "Ana Maria"
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeEmail remove espaços das extremidades e converte para minúsculas,
+// evitando duplicatas que diferem apenas na capitalização
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,38 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserNormalizesNameAndEmail(t *testing.T) {
+	u, err := NewUser("A@B.com", "password123", "  Ana  ", RoleUser)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ana", u.Name)
+	assert.Equal(t, "a@b.com", u.Email)
+}
+
+func TestUpdateNormalizesNameAndEmail(t *testing.T) {
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+
+	require.NoError(t, u.UpdateName("  Ana \t  Maria "))
+	assert.Equal(t, "Ana Maria", u.Name)
+
+	require.NoError(t, u.UpdateEmail(" Ana.Maria@Example.COM "))
+	assert.Equal(t, "ana.maria@example.com", u.Email)
+}
+
+func TestBlankNamesAreRejectedAfterTrimming(t *testing.T) {
+	_, err := NewUser("ana@example.com", "password123", "   ", RoleUser)
+	assert.ErrorIs(t, err, ErrEmptyName)
+
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+	assert.ErrorIs(t, u.UpdateName("\t \n"), ErrEmptyName)
+	assert.Equal(t, "Ana", u.Name)
+	assert.ErrorIs(t, u.UpdateEmail("  "), ErrEmptyEmail)
+}
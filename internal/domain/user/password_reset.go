@@ -0,0 +1,27 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPasswordResetTokenNotFound indica que não há token de redefinição de
+// senha pendente (nunca emitido, já usado ou substituído)
+var ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+
+// PasswordResetToken é um token de redefinição de senha persistido: apenas o
+// hash, nunca o valor enviado ao usuário. Cada usuário tem no máximo um token
+// pendente; Attempts conta as confirmações já registradas
+type PasswordResetToken struct {
+	Hash      string
+	UserID    string
+	ExpiresAt time.Time
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// HasLocalPassword informa se a conta entra com senha local. Usuários de
+// provedores externos e contas anonimizadas têm a senha inutilizável
+func (u *User) HasLocalPassword() bool {
+	return u.Password != unusablePassword
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// pepperPrefix marca hashes com pepper: "$pepper$<versão>" seguido do hash bcrypt
+// ("$2a$..."). Hashes sem o prefixo são bcrypt puros, anteriores ao pepper
+const pepperPrefix = "$pepper$"
+
+// ErrInvalidPepper indica uma configuração de pepper inválida
+var ErrInvalidPepper = errors.New("invalid password pepper")
+
+// passwordPeppers guarda os peppers por versão e a versão usada em novos hashes
+var passwordPeppers = struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}{}
+
+// SetPasswordPeppers define os segredos aplicados (HMAC-SHA256) à senha antes do
+// bcrypt, por versão. Novos hashes usam current; versões anteriores devem
+// permanecer em peppers enquanto houver hashes gravados com elas. current vazio
+// desabilita o pepper em novos hashes. Deve ser chamado na inicialização
+func SetPasswordPeppers(current string, peppers map[string]string) error {
+	if err := ValidatePasswordPeppers(current, peppers); err != nil {
+		return err
+	}
+
+	keys := make(map[string][]byte, len(peppers))
+	for version, secret := range peppers {
+		keys[version] = []byte(secret)
+	}
+
+	passwordPeppers.mu.Lock()
+	defer passwordPeppers.mu.Unlock()
+	passwordPeppers.current = current
+	passwordPeppers.keys = keys
+	return nil
+}
+
+// ValidatePasswordPeppers verifica versões e segredos sem aplicá-los
+func ValidatePasswordPeppers(current string, peppers map[string]string) error {
+	for version, secret := range peppers {
+		if version == "" || strings.Contains(version, "$") {
+			return fmt.Errorf("%w: version %q must be non-empty and cannot contain '$'", ErrInvalidPepper, version)
+		}
+		if secret == "" {
+			return fmt.Errorf("%w: version %q has an empty secret", ErrInvalidPepper, version)
+		}
+	}
+	if _, ok := peppers[current]; current != "" && !ok {
+		return fmt.Errorf("%w: current version %q not found", ErrInvalidPepper, current)
+	}
+	return nil
+}
+
+// currentPepper retorna a versão e o segredo usados em novos hashes
+func currentPepper() (string, []byte) {
+	passwordPeppers.mu.RLock()
+	defer passwordPeppers.mu.RUnlock()
+	if passwordPeppers.current == "" {
+		return "", nil
+	}
+	return passwordPeppers.current, passwordPeppers.keys[passwordPeppers.current]
+}
+
+// pepperByVersion retorna o segredo de uma versão configurada
+func pepperByVersion(version string) ([]byte, bool) {
+	passwordPeppers.mu.RLock()
+	defer passwordPeppers.mu.RUnlock()
+	key, ok := passwordPeppers.keys[version]
+	return key, ok
+}
+
+// applyPepper deriva a entrada do bcrypt; o resultado em base64 tem tamanho
+// fixo, abaixo do limite de 72 bytes do bcrypt
+func applyPepper(password string, pepper []byte) []byte {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(password))
+	return []byte(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+// splitPepperedHash separa a versão do pepper e o hash bcrypt; versão vazia
+// indica um hash sem pepper
+func splitPepperedHash(stored string) (version, hash string) {
+	rest, ok := strings.CutPrefix(stored, pepperPrefix)
+	if !ok {
+		return "", stored
+	}
+	i := strings.Index(rest, "$")
+	if i <= 0 {
+		return "", stored
+	}
+	return rest[:i], rest[i:]
+}
+
+// PasswordPepperVersion retorna a versão do pepper usada no hash da senha
+// (vazio para hashes sem pepper)
+func (u *User) PasswordPepperVersion() string {
+	version, _ := splitPepperedHash(u.Password)
+	return version
+}
+
+// PasswordNeedsRehash indica se o hash não usa o pepper atual. Após um login
+// bem-sucedido, SetPassword com a senha informada migra o hash na rotação
+func (u *User) PasswordNeedsRehash() bool {
+	if u.Password == unusablePassword {
+		return false
+	}
+	current, _ := currentPepper()
+	return u.PasswordPepperVersion() != current
+}
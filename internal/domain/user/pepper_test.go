@@ -0,0 +1,54 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPepper(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, SetPasswordPeppers("", nil)) })
+
+	legacy, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+	assert.Empty(t, legacy.PasswordPepperVersion())
+
+	require.NoError(t, SetPasswordPeppers("v1", map[string]string{"v1": "pepper-one"}))
+
+	u, err := NewUser("bia@example.com", "password123", "Bia", RoleUser)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", u.PasswordPepperVersion())
+	assert.True(t, strings.HasPrefix(u.Password, "$pepper$v1$2a$"))
+	assert.True(t, u.CheckPassword("password123"))
+	assert.False(t, u.CheckPassword("wrong123"))
+	assert.False(t, u.PasswordNeedsRehash())
+
+	assert.True(t, legacy.CheckPassword("password123"), "hashes without pepper still verify")
+	assert.True(t, legacy.PasswordNeedsRehash())
+
+	t.Run("rotation keeps previous versions verifiable", func(t *testing.T) {
+		require.NoError(t, SetPasswordPeppers("v2", map[string]string{"v1": "pepper-one", "v2": "pepper-two"}))
+
+		assert.True(t, u.CheckPassword("password123"))
+		assert.True(t, u.PasswordNeedsRehash())
+
+		require.NoError(t, u.SetPassword("password123"))
+		assert.Equal(t, "v2", u.PasswordPepperVersion())
+		assert.True(t, u.CheckPassword("password123"))
+	})
+
+	t.Run("hash without its pepper never verifies", func(t *testing.T) {
+		require.NoError(t, SetPasswordPeppers("v3", map[string]string{"v3": "pepper-three"}))
+		assert.False(t, u.CheckPassword("password123"))
+	})
+}
+
+func TestValidatePasswordPeppers(t *testing.T) {
+	assert.NoError(t, ValidatePasswordPeppers("", nil))
+	assert.NoError(t, ValidatePasswordPeppers("v1", map[string]string{"v1": "secret"}))
+	assert.ErrorIs(t, ValidatePasswordPeppers("v2", map[string]string{"v1": "secret"}), ErrInvalidPepper)
+	assert.ErrorIs(t, ValidatePasswordPeppers("v1", map[string]string{"v1": ""}), ErrInvalidPepper)
+	assert.ErrorIs(t, ValidatePasswordPeppers("", map[string]string{"v$1": "secret"}), ErrInvalidPepper)
+}
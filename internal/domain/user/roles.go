@@ -0,0 +1,101 @@
+package user
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// builtinRoles são os papéis embutidos, sempre válidos
+var builtinRoles = []Role{RoleAdmin, RoleUser, RoleGuest}
+
+// roleNamePattern restringe nomes de papéis customizados
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// roleRegistry é a fonte única do conjunto de papéis válidos
+var roleRegistry = struct {
+	mu    sync.RWMutex
+	roles []Role
+}{roles: append([]Role(nil), builtinRoles...)}
+
+// ValidRoles retorna o conjunto atual de papéis válidos, na ordem de registro
+func ValidRoles() []Role {
+	roleRegistry.mu.RLock()
+	defer roleRegistry.mu.RUnlock()
+	return append([]Role(nil), roleRegistry.roles...)
+}
+
+// RoleNames retorna os nomes dos papéis válidos, na ordem de ValidRoles. É a
+// forma usada para injetar o conjunto em pacotes que não importam o domínio
+// (ex.: validator.WithRoles)
+func RoleNames() []string {
+	roles := ValidRoles()
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return names
+}
+
+// IsValidRole verifica se o papel pertence ao conjunto de papéis válidos
+func IsValidRole(role Role) bool {
+	roleRegistry.mu.RLock()
+	defer roleRegistry.mu.RUnlock()
+	return containsRole(roleRegistry.roles, role)
+}
+
+// ParseRole converte uma string em Role, retornando ErrInvalidRole se desconhecido
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if !IsValidRole(role) {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// ValidateRoleName verifica se um nome de papel customizado é aceitável
+func ValidateRoleName(name string) error {
+	if !roleNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q must match %s", ErrInvalidRole, name, roleNamePattern.String())
+	}
+	return nil
+}
+
+// RegisterRoles estende o conjunto de papéis válidos com papéis customizados.
+// Deve ser chamado na inicialização, antes de atender requisições. Papéis já
+// registrados são ignorados
+func RegisterRoles(names ...string) error {
+	for _, name := range names {
+		if err := ValidateRoleName(name); err != nil {
+			return err
+		}
+	}
+
+	roleRegistry.mu.Lock()
+	defer roleRegistry.mu.Unlock()
+	for _, name := range names {
+		role := Role(name)
+		if !containsRole(roleRegistry.roles, role) {
+			roleRegistry.roles = append(roleRegistry.roles, role)
+		}
+	}
+	return nil
+}
+
+// ResetRoles descarta os papéis customizados, voltando aos papéis embutidos.
+// Usado em testes para desfazer RegisterRoles
+func ResetRoles() {
+	roleRegistry.mu.Lock()
+	defer roleRegistry.mu.Unlock()
+	roleRegistry.roles = append([]Role(nil), builtinRoles...)
+}
+
+// containsRole verifica se role está em roles
+func containsRole(roles []Role, role Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Campos de usuário controlados pela matriz de atualização
+const (
+	FieldName     = "name"
+	FieldEmail    = "email"
+	FieldUsername = "username"
+	FieldRole     = "role"
+	FieldIsActive = "is_active"
+)
+
+// updatableFields são os campos aceitos na matriz, na ordem em que são verificados
+var updatableFields = []string{FieldName, FieldEmail, FieldUsername, FieldRole, FieldIsActive}
+
+// UpdateScope define de quais usuários um papel pode alterar um campo
+type UpdateScope string
+
+// Escopos da matriz de atualização. Campos ausentes da matriz valem UpdateScopeNone
+const (
+	UpdateScopeNone UpdateScope = "none"
+	UpdateScopeSelf UpdateScope = "self"
+	UpdateScopeAny  UpdateScope = "any"
+)
+
+// ErrFieldUpdateForbidden indica uma alteração que o papel do autor não permite
+var ErrFieldUpdateForbidden = errors.New("field update not allowed")
+
+// FieldPermissionError nomeia o campo que o papel não pode alterar. Field vazio
+// indica que o papel não pode atualizar outros usuários
+type FieldPermissionError struct {
+	Role  Role
+	Field string
+	Self  bool
+}
+
+// Error implementa error
+func (e *FieldPermissionError) Error() string {
+	switch {
+	case e.Field == "":
+		return fmt.Sprintf("role %q cannot update other users", e.Role)
+	case e.Self:
+		return fmt.Sprintf("role %q cannot change its own %q", e.Role, e.Field)
+	default:
+		return fmt.Sprintf("role %q cannot change %q of other users", e.Role, e.Field)
+	}
+}
+
+// Unwrap expõe ErrFieldUpdateForbidden
+func (e *FieldPermissionError) Unwrap() error {
+	return ErrFieldUpdateForbidden
+}
+
+// UpdatePolicy é a matriz de atualização: para cada papel do autor, o escopo
+// de cada campo. Papéis ausentes não alteram nenhum campo
+type UpdatePolicy map[Role]map[string]UpdateScope
+
+// DefaultUpdatePolicy retorna a matriz padrão: admins alteram qualquer campo de
+// qualquer usuário; usuários alteram apenas o próprio nome e username. O email
+// do próprio usuário muda pelo fluxo com verificação (POST /me/email)
+func DefaultUpdatePolicy() UpdatePolicy {
+	return UpdatePolicy{
+		RoleAdmin: {
+			FieldName:     UpdateScopeAny,
+			FieldEmail:    UpdateScopeAny,
+			FieldUsername: UpdateScopeAny,
+			FieldRole:     UpdateScopeAny,
+			FieldIsActive: UpdateScopeAny,
+		},
+		RoleUser: {
+			FieldName:     UpdateScopeSelf,
+			FieldUsername: UpdateScopeSelf,
+		},
+	}
+}
+
+// CanUpdate informa se role pode alterar field; self indica que o alvo é o
+// próprio autor
+func (p UpdatePolicy) CanUpdate(role Role, field string, self bool) bool {
+	switch p[role][field] {
+	case UpdateScopeAny:
+		return true
+	case UpdateScopeSelf:
+		return self
+	default:
+		return false
+	}
+}
+
+// Authorize verifica os campos alterados, retornando um FieldPermissionError
+// para o primeiro campo não permitido. Atualizar outro usuário exige ao menos
+// um campo com escopo any, mesmo sem campos alterados
+func (p UpdatePolicy) Authorize(role Role, self bool, fields []string) error {
+	for _, field := range fields {
+		if !p.CanUpdate(role, field, self) {
+			return &FieldPermissionError{Role: role, Field: field, Self: self}
+		}
+	}
+
+	if !self && !p.canUpdateOthers(role) {
+		return &FieldPermissionError{Role: role}
+	}
+
+	return nil
+}
+
+// canUpdateOthers informa se role altera algum campo de outros usuários
+func (p UpdatePolicy) canUpdateOthers(role Role) bool {
+	for _, scope := range p[role] {
+		if scope == UpdateScopeAny {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseUpdatePolicy monta a matriz a partir da configuração (papel -> campo ->
+// escopo). Cada papel informado substitui a linha padrão dele; os demais
+// mantêm DefaultUpdatePolicy. Papéis customizados precisam estar registrados
+func ParseUpdatePolicy(raw map[string]map[string]string) (UpdatePolicy, error) {
+	policy := DefaultUpdatePolicy()
+
+	for roleName, fields := range raw {
+		role, err := ParseRole(roleName)
+		if err != nil {
+			return nil, fmt.Errorf("update permissions: %w: %q", err, roleName)
+		}
+
+		row := make(map[string]UpdateScope, len(fields))
+		for field, scope := range fields {
+			if !isUpdatableField(field) {
+				return nil, fmt.Errorf("update permissions: unknown field %q for role %q (use one of %v)", field, roleName, updatableFields)
+			}
+			switch s := UpdateScope(scope); s {
+			case UpdateScopeNone, UpdateScopeSelf, UpdateScopeAny:
+				row[field] = s
+			default:
+				return nil, fmt.Errorf("update permissions: invalid scope %q for %s.%s (use none, self or any)", scope, roleName, field)
+			}
+		}
+		policy[role] = row
+	}
+
+	return policy, nil
+}
+
+// isUpdatableField informa se field pertence à matriz
+func isUpdatableField(field string) bool {
+	for _, f := range updatableFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
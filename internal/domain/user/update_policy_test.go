@@ -0,0 +1,85 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultUpdatePolicy(t *testing.T) {
+	policy := DefaultUpdatePolicy()
+
+	tests := []struct {
+		role    Role
+		field   string
+		self    bool
+		allowed bool
+	}{
+		{RoleAdmin, FieldRole, false, true},
+		{RoleAdmin, FieldIsActive, false, true},
+		{RoleAdmin, FieldRole, true, true},
+		{RoleUser, FieldName, true, true},
+		{RoleUser, FieldUsername, true, true},
+		{RoleUser, FieldName, false, false},
+		{RoleUser, FieldEmail, true, false},
+		{RoleUser, FieldRole, true, false},
+		{RoleUser, FieldIsActive, true, false},
+		{RoleGuest, FieldName, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+tt.field, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, policy.CanUpdate(tt.role, tt.field, tt.self))
+		})
+	}
+}
+
+func TestUpdatePolicyAuthorize(t *testing.T) {
+	policy := DefaultUpdatePolicy()
+
+	assert.NoError(t, policy.Authorize(RoleUser, true, []string{FieldName}))
+	assert.NoError(t, policy.Authorize(RoleUser, true, nil))
+	assert.NoError(t, policy.Authorize(RoleAdmin, false, nil))
+
+	// O primeiro campo não permitido é nomeado no erro
+	err := policy.Authorize(RoleUser, true, []string{FieldName, FieldRole})
+	require.ErrorIs(t, err, ErrFieldUpdateForbidden)
+	var permissionErr *FieldPermissionError
+	require.True(t, errors.As(err, &permissionErr))
+	assert.Equal(t, FieldRole, permissionErr.Field)
+	assert.Equal(t, `role "user" cannot change its own "role"`, err.Error())
+
+	err = policy.Authorize(RoleUser, false, nil)
+	require.ErrorIs(t, err, ErrFieldUpdateForbidden)
+	assert.Equal(t, `role "user" cannot update other users`, err.Error())
+}
+
+func TestParseUpdatePolicy(t *testing.T) {
+	policy, err := ParseUpdatePolicy(map[string]map[string]string{
+		"guest": {"name": "self"},
+		"user":  {"email": "self"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, policy.CanUpdate(RoleGuest, FieldName, true))
+	// A linha informada substitui a padrão por inteiro
+	assert.True(t, policy.CanUpdate(RoleUser, FieldEmail, true))
+	assert.False(t, policy.CanUpdate(RoleUser, FieldName, true))
+	assert.True(t, policy.CanUpdate(RoleAdmin, FieldRole, false))
+
+	empty, err := ParseUpdatePolicy(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUpdatePolicy(), empty)
+
+	invalid := []map[string]map[string]string{
+		{"owner": {"name": "self"}},
+		{"user": {"password": "self"}},
+		{"user": {"name": "everyone"}},
+	}
+	for _, raw := range invalid {
+		_, err := ParseUpdatePolicy(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -9,11 +9,21 @@ import (
 
 // Erros personalizados do domínio
 var (
-	ErrInvalidRole        = errors.New("invalid role")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidPassword    = errors.New("invalid password")
-	ErrUserDeactivated    = errors.New("user account is deactivated")
+	ErrInvalidRole       = errors.New("invalid role")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrInvalidPassword   = errors.New("invalid password")
+	ErrUserDeactivated   = errors.New("user account is deactivated")
+	ErrEmptyName         = errors.New("name cannot be empty")
+	ErrEmptyEmail        = errors.New("email cannot be empty")
+	// ErrIdentityAlreadyLinked indica que a identidade externa já pertence a um usuário
+	ErrIdentityAlreadyLinked = errors.New("external identity already linked")
+	// ErrAdminAlreadyExists indica que já existe um admin, o que encerra a
+	// criação do primeiro admin pela API
+	ErrAdminAlreadyExists = errors.New("an admin already exists")
+	// ErrPasswordExpired indica uma senha correta, mas vencida pela política de
+	// expiração; o login só é concluído após a troca
+	ErrPasswordExpired = errors.New("password expired, must be changed")
 )
 
 // User representa a entidade de usuário no domínio
@@ -26,26 +36,40 @@ type User struct {
 	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// TokenVersion é embutida nos JWTs; incrementá-la invalida todas as sessões
+	TokenVersion int `json:"-"`
+	// EmailVerifiedAt é o momento da verificação do email; nil enquanto não verificado
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// Username é o nome de usuário opcional (ver SetUsername); vazio quando não definido
+	Username string `json:"username,omitempty"`
+	// LastLoginAt é o momento do último login bem-sucedido; nil se nunca entrou
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	// PasswordChangedAt é o momento da última troca de senha; nil nunca expira
+	// (ex.: usuários de provedores externos, sem senha local)
+	PasswordChangedAt *time.Time `json:"-"`
 }
 
 // Role representa o papel/permissão do usuário
 type Role string
 
 const (
-	RoleAdmin  Role = "admin"
-	RoleUser   Role = "user"
-	RoleGuest  Role = "guest"
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+	RoleGuest Role = "guest"
 )
 
-// NewUser cria uma nova instância de User
+// NewUser cria uma nova instância de User com nome e email normalizados
 func NewUser(email, password, name string, role Role) (*User, error) {
+	createdAt := now()
 	user := &User{
-		Email:     email,
-		Name:      name,
+		Email:     NormalizeEmail(email),
+		Name:      NormalizeName(name),
 		Role:      role,
 		IsActive:  true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+
+		PasswordChangedAt: &createdAt,
 	}
 
 	if err := user.SetPassword(password); err != nil {
@@ -59,7 +83,32 @@ func NewUser(email, password, name string, role Role) (*User, error) {
 	return user, nil
 }
 
-// SetPassword define a senha do usuário com hash bcrypt
+// NewExternalUser cria o registro local de um usuário autenticado por um
+// provedor externo (ex.: LDAP). A senha local é inutilizável, então o login só
+// acontece pelo provedor. O email já foi confirmado pelo provedor, então o
+// usuário nasce verificado
+func NewExternalUser(email, name string, role Role) (*User, error) {
+	createdAt := now()
+	user := &User{
+		Email:           NormalizeEmail(email),
+		Name:            NormalizeName(name),
+		Password:        unusablePassword,
+		Role:            role,
+		IsActive:        true,
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+		EmailVerifiedAt: &createdAt,
+	}
+
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetPassword define a senha do usuário com hash bcrypt, aplicando antes o
+// pepper atual quando configurado (SetPasswordPeppers)
 func (u *User) SetPassword(password string) error {
 	if password == "" {
 		return errors.New("password cannot be empty")
@@ -69,95 +118,149 @@ func (u *User) SetPassword(password string) error {
 		return errors.New("password must be at least 6 characters long")
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	input := []byte(password)
+	version, pepper := currentPepper()
+	if pepper != nil {
+		input = applyPepper(password, pepper)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword(input, bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
 	u.Password = string(hashedPassword)
+	if pepper != nil {
+		u.Password = pepperPrefix + version + u.Password
+	}
 	return nil
 }
 
-// CheckPassword verifica se a senha fornecida corresponde à senha do usuário
+// CheckPassword verifica se a senha fornecida corresponde à senha do usuário,
+// usando o pepper da versão registrada no hash
 func (u *User) CheckPassword(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
+	version, hash := splitPepperedHash(u.Password)
+	input := []byte(password)
+	if version != "" {
+		pepper, ok := pepperByVersion(version)
+		if !ok {
+			return false
+		}
+		input = applyPepper(password, pepper)
+	}
+
+	err := compareHashAndPassword([]byte(hash), input)
 	return err == nil
 }
 
+// compareHashAndPassword é a comparação bcrypt usada por CheckPassword e
+// SimulatePasswordCheck; os testes a substituem para medir o custo com um relógio falso
+var compareHashAndPassword = bcrypt.CompareHashAndPassword
+
+// dummyPasswordHash é um hash bcrypt fixo, com o custo de SetPassword, usado
+// por SimulatePasswordCheck
+const dummyPasswordHash = "$2a$10$nQ7VJnSUkYF3yzoiHS2ip.4viHhJCalw5z6mrhsfJJ0hEpL9nklKK"
+
+// SimulatePasswordCheck executa uma comparação bcrypt descartável. Chamada
+// quando o usuário não existe, iguala o tempo de resposta ao de uma senha
+// errada e evita que o login revele quais emails estão cadastrados
+func SimulatePasswordCheck(password string) {
+	_ = compareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+}
+
 // Validate valida os campos da entidade User
 func (u *User) Validate() error {
 	if u.Email == "" {
-		return errors.New("email cannot be empty")
+		return ErrEmptyEmail
 	}
 
-	if u.Name == "" {
-		return errors.New("name cannot be empty")
+	if err := ValidateName(u.Name); err != nil {
+		return err
 	}
 
 	if u.Password == "" {
 		return errors.New("password cannot be empty")
 	}
 
-	if !isValidRole(u.Role) {
+	if !IsValidRole(u.Role) {
 		return errors.New("invalid role")
 	}
 
 	return nil
 }
 
-// UpdateName atualiza o nome do usuário
+// ChangePassword define uma nova senha escolhida pelo usuário, reiniciando o
+// prazo de expiração. Para apenas regravar o hash (ex.: troca de pepper), use
+// SetPassword
+func (u *User) ChangePassword(password string) error {
+	if err := u.SetPassword(password); err != nil {
+		return err
+	}
+
+	changedAt := now()
+	u.PasswordChangedAt = &changedAt
+	u.UpdatedAt = changedAt
+	return nil
+}
+
+// PasswordExpired informa se, em at, a senha tem maxAge ou mais. maxAge <= 0
+// desabilita a expiração
+func (u *User) PasswordExpired(at time.Time, maxAge time.Duration) bool {
+	if maxAge <= 0 || u.PasswordChangedAt == nil {
+		return false
+	}
+	return !at.Before(u.PasswordChangedAt.Add(maxAge))
+}
+
+// UpdateName atualiza o nome do usuário, normalizado por NormalizeName
 func (u *User) UpdateName(name string) error {
-	if name == "" {
-		return errors.New("name cannot be empty")
+	name = NormalizeName(name)
+	if err := ValidateName(name); err != nil {
+		return err
 	}
 
 	u.Name = name
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = now()
 	return nil
 }
 
-// UpdateEmail atualiza o email do usuário
+// UpdateEmail atualiza o email do usuário, normalizado por NormalizeEmail.
+// Um email diferente do atual volta a ser não verificado
 func (u *User) UpdateEmail(email string) error {
+	email = NormalizeEmail(email)
 	if email == "" {
-		return errors.New("email cannot be empty")
+		return ErrEmptyEmail
 	}
 
+	if email != u.Email {
+		u.EmailVerifiedAt = nil
+	}
 	u.Email = email
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = now()
 	return nil
 }
 
 // UpdateRole atualiza o papel do usuário
 func (u *User) UpdateRole(role Role) error {
-	if !isValidRole(role) {
+	if !IsValidRole(role) {
 		return errors.New("invalid role")
 	}
 
 	u.Role = role
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = now()
 	return nil
 }
 
 // Activate ativa o usuário
 func (u *User) Activate() {
 	u.IsActive = true
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = now()
 }
 
 // Deactivate desativa o usuário
 func (u *User) Deactivate() {
 	u.IsActive = false
-	u.UpdatedAt = time.Now()
-}
-
-// isValidRole verifica se o papel é válido
-func isValidRole(role Role) bool {
-	switch role {
-	case RoleAdmin, RoleUser, RoleGuest:
-		return true
-	default:
-		return false
-	}
+	u.UpdatedAt = now()
 }
 
 // IsAdmin verifica se o usuário é administrador
@@ -168,4 +271,9 @@ func (u *User) IsAdmin() bool {
 // IsActive verifica se o usuário está ativo
 func (u *User) IsActiveUser() bool {
 	return u.IsActive
-} 
\ No newline at end of file
+}
+
+// IsEmailVerified informa se o email atual do usuário foi verificado
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestSimulatePasswordCheckMatchesPasswordCost garante que o hash fixo acompanha
+// o custo dos hashes reais; com custos diferentes o tempo volta a revelar emails
+func TestSimulatePasswordCheckMatchesPasswordCost(t *testing.T) {
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+
+	realCost, err := bcrypt.Cost([]byte(u.Password))
+	require.NoError(t, err)
+	dummyCost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	require.NoError(t, err)
+	assert.Equal(t, realCost, dummyCost)
+}
+
+// TestSimulatePasswordCheckTakesWrongPasswordTime compara, com um relógio falso,
+// o custo de um email inexistente com o de uma senha errada: cada comparação
+// avança o relógio conforme o custo bcrypt do hash comparado
+func TestSimulatePasswordCheckTakesWrongPasswordTime(t *testing.T) {
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+
+	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	original := compareHashAndPassword
+	t.Cleanup(func() { compareHashAndPassword = original })
+	compareHashAndPassword = func(hash, password []byte) error {
+		cost, err := bcrypt.Cost(hash)
+		require.NoError(t, err)
+		fake.Advance(time.Duration(1<<cost) * time.Microsecond)
+		return bcrypt.ErrMismatchedHashAndPassword
+	}
+
+	elapsed := func(fn func()) time.Duration {
+		start := fake.Now()
+		fn()
+		return fake.Now().Sub(start)
+	}
+
+	wrongPassword := elapsed(func() { u.CheckPassword("wrong-password") })
+	unknownEmail := elapsed(func() { SimulatePasswordCheck("wrong-password") })
+
+	require.NotZero(t, wrongPassword)
+	assert.Equal(t, wrongPassword, unknownEmail, "unknown emails must cost the same as wrong passwords")
+}
+
+func TestUpdateEmailResetsVerification(t *testing.T) {
+	u, err := NewExternalUser("ana@example.com", "Ana", RoleUser)
+	require.NoError(t, err)
+	require.True(t, u.IsEmailVerified())
+
+	// O mesmo email (após normalização) mantém a verificação
+	require.NoError(t, u.UpdateEmail("ANA@example.com"))
+	assert.True(t, u.IsEmailVerified())
+
+	require.NoError(t, u.UpdateEmail("ana.souza@example.com"))
+	assert.False(t, u.IsEmailVerified())
+}
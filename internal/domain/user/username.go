@@ -0,0 +1,78 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limites de tamanho de um nome de usuário, em caracteres
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 30
+)
+
+// RuleMinLength é a regra de tamanho mínimo reportada em ValidationError.Rule
+const RuleMinLength = "min_length"
+
+// ErrInvalidUsername indica um nome de usuário que viola alguma regra de validação
+var ErrInvalidUsername = errors.New("invalid username")
+
+// NormalizeUsername remove espaços das extremidades. A caixa é mantida; a
+// unicidade e as buscas não diferenciam maiúsculas
+func NormalizeUsername(username string) string {
+	return strings.TrimSpace(username)
+}
+
+// ValidateUsername verifica um nome de usuário já normalizado: entre
+// MinUsernameLength e MaxUsernameLength caracteres, apenas letras ASCII,
+// dígitos e "_". Sem "@", nunca é confundido com um email no login
+func ValidateUsername(username string) error {
+	if username == "" {
+		return &ValidationError{Field: "username", Rule: RuleRequired, Message: "cannot be empty", Err: ErrInvalidUsername}
+	}
+
+	if n := utf8.RuneCountInString(username); n < MinUsernameLength {
+		return &ValidationError{Field: "username", Rule: RuleMinLength, Message: fmt.Sprintf("must be at least %d characters", MinUsernameLength), Err: ErrInvalidUsername}
+	} else if n > MaxUsernameLength {
+		return &ValidationError{Field: "username", Rule: RuleMaxLength, Message: fmt.Sprintf("must be at most %d characters", MaxUsernameLength), Err: ErrInvalidUsername}
+	}
+
+	for _, r := range username {
+		if !isUsernameRune(r) {
+			return &ValidationError{Field: "username", Rule: RuleCharset, Message: fmt.Sprintf("contains disallowed character %q (use letters, digits and underscores)", r), Err: ErrInvalidUsername}
+		}
+	}
+
+	return nil
+}
+
+// isUsernameRune informa se r é permitido em nomes de usuário
+func isUsernameRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// SetUsername define o nome de usuário, normalizado por NormalizeUsername
+func (u *User) SetUsername(username string) error {
+	username = NormalizeUsername(username)
+	if err := ValidateUsername(username); err != nil {
+		return err
+	}
+
+	u.Username = username
+	u.UpdatedAt = now()
+	return nil
+}
+
+// ClearUsername remove o nome de usuário; o login volta a aceitar só o email
+func (u *User) ClearUsername() {
+	u.Username = ""
+	u.UpdatedAt = now()
+}
+
+// IsLoginUsername informa se o identificador do login é um nome de usuário
+// (e não um email)
+func IsLoginUsername(identifier string) bool {
+	return !strings.Contains(identifier, "@")
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		rule     string
+	}{
+		{"ana", ""},
+		{"Ana_Silva_2", ""},
+		{strings.Repeat("a", MaxUsernameLength), ""},
+		{"", RuleRequired},
+		{"ab", RuleMinLength},
+		{strings.Repeat("a", MaxUsernameLength+1), RuleMaxLength},
+		{"ana.silva", RuleCharset},
+		{"ana@example.com", RuleCharset},
+		{"joão", RuleCharset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			err := ValidateUsername(tt.username)
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "username", validationErr.Field)
+			assert.Equal(t, tt.rule, validationErr.Rule)
+			assert.ErrorIs(t, err, ErrInvalidUsername)
+		})
+	}
+}
+
+func TestSetUsernameNormalizes(t *testing.T) {
+	u, err := NewUser("ana@example.com", "password123", "Ana", RoleUser)
+	require.NoError(t, err)
+
+	require.NoError(t, u.SetUsername("  Ana_Silva "))
+	assert.Equal(t, "Ana_Silva", u.Username, "trims spaces and keeps the case")
+
+	assert.Error(t, u.SetUsername("a b"))
+	assert.Equal(t, "Ana_Silva", u.Username, "an invalid username leaves the current one")
+}
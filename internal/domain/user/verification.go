@@ -0,0 +1,31 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// Erros da verificação de email
+var (
+	ErrVerificationTokenInvalid = errors.New("verification token is invalid")
+	ErrVerificationTokenExpired = errors.New("verification token has expired")
+)
+
+// VerificationToken é um token de verificação de email persistido: apenas o
+// hash, nunca o valor enviado ao usuário. Email é o endereço para o qual o link
+// foi enviado; a verificação só vale enquanto ele for o email do usuário
+type VerificationToken struct {
+	Hash   string
+	UserID string
+	Email  string
+	// ReplacesEmail é o email atual em uma troca de email: confirmar o token
+	// substitui ReplacesEmail por Email. Vazio na verificação do email atual
+	ReplacesEmail string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+// IsEmailChange informa se o token confirma uma troca de email
+func (t *VerificationToken) IsEmailChange() bool {
+	return t.ReplacesEmail != ""
+}
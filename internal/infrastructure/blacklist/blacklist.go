@@ -0,0 +1,22 @@
+package blacklist
+
+import (
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// New cria o blacklist configurado em security.token_blacklist
+func New(cfg *config.Config) auth.TokenBlacklist {
+	if cfg.Security.TokenBlacklist == "redis" {
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		return NewRedisBlacklist(client)
+	}
+
+	return NewMemoryBlacklist()
+}
@@ -0,0 +1,83 @@
+package blacklist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokedTokenRejectedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	newInstance := func() auth.JWTService {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return auth.NewJWTService("shared-secret", time.Hour, auth.WithBlacklist(NewRedisBlacklist(client)))
+	}
+
+	instanceA := newInstance()
+	instanceB := newInstance()
+
+	token, err := instanceA.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+
+	_, err = instanceB.ValidateToken(token)
+	require.NoError(t, err)
+
+	require.NoError(t, instanceA.RevokeToken(context.Background(), token))
+
+	_, err = instanceB.ValidateToken(token)
+	assert.ErrorIs(t, err, auth.ErrRevokedToken)
+
+	// A chave expira junto com o token
+	server.FastForward(time.Hour + time.Second)
+	assert.False(t, server.Exists("jwt:revoked:"+mustJTI(t, token)))
+}
+
+func TestMemoryBlacklist(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBlacklist()
+
+	require.NoError(t, b.Revoke(ctx, "jti-1", time.Hour))
+	require.NoError(t, b.Revoke(ctx, "jti-2", -time.Second))
+
+	revoked, err := b.IsRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = b.IsRevoked(ctx, "jti-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestMemoryBlacklistExpiresWithClock(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	b := NewMemoryBlacklist(WithClock(fake))
+
+	require.NoError(t, b.Revoke(ctx, "jti-1", time.Minute))
+	fake.Advance(59 * time.Second)
+	revoked, err := b.IsRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	fake.Advance(2 * time.Second)
+	revoked, err = b.IsRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.False(t, revoked, "the revocation ends with the token lifetime")
+}
+
+// mustJTI extrai o jti de um token ainda não revogado usando um serviço sem blacklist
+func mustJTI(t *testing.T, token string) string {
+	t.Helper()
+	claims, err := auth.NewJWTService("shared-secret", time.Hour).ValidateToken(token)
+	require.NoError(t, err)
+	return claims.ID
+}
@@ -0,0 +1,90 @@
+package blacklist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/clock"
+)
+
+// pruneInterval define a frequência mínima da limpeza de entradas expiradas
+const pruneInterval = time.Minute
+
+// MemoryBlacklist implementa auth.TokenBlacklist em memória.
+// Adequado para uma única instância; revogações não são vistas por outras réplicas
+type MemoryBlacklist struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	lastPrune time.Time
+	clock     clock.Clock
+}
+
+var _ auth.TokenBlacklist = (*MemoryBlacklist)(nil)
+
+// MemoryOption configura comportamentos opcionais do MemoryBlacklist
+type MemoryOption func(*MemoryBlacklist)
+
+// WithClock define a fonte de tempo da expiração das revogações; nil mantém
+// clock.System
+func WithClock(c clock.Clock) MemoryOption {
+	return func(b *MemoryBlacklist) {
+		if c != nil {
+			b.clock = c
+		}
+	}
+}
+
+// NewMemoryBlacklist cria uma nova instância de MemoryBlacklist
+func NewMemoryBlacklist(opts ...MemoryOption) *MemoryBlacklist {
+	b := &MemoryBlacklist{
+		entries: make(map[string]time.Time),
+		clock:   clock.System,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.lastPrune = b.clock.Now()
+
+	return b
+}
+
+// Revoke marca o jti como revogado até o fim do ttl
+func (b *MemoryBlacklist) Revoke(ctx context.Context, jti string, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	b.entries[jti] = now.Add(ttl)
+
+	if now.Sub(b.lastPrune) >= pruneInterval {
+		for id, expiresAt := range b.entries {
+			if now.After(expiresAt) {
+				delete(b.entries, id)
+			}
+		}
+		b.lastPrune = now
+	}
+
+	return nil
+}
+
+// IsRevoked verifica se o jti foi revogado e ainda não expirou
+func (b *MemoryBlacklist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiresAt, ok := b.entries[jti]
+	if !ok {
+		return false, nil
+	}
+
+	if b.clock.Now().After(expiresAt) {
+		delete(b.entries, jti)
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package blacklist
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix isola as chaves do blacklist no Redis
+const keyPrefix = "jwt:revoked:"
+
+// RedisBlacklist implementa auth.TokenBlacklist no Redis, compartilhado entre réplicas.
+// Cada jti expira automaticamente junto com o token via TTL da chave
+type RedisBlacklist struct {
+	client redis.UniversalClient
+}
+
+var _ auth.TokenBlacklist = (*RedisBlacklist)(nil)
+
+// NewRedisBlacklist cria uma nova instância de RedisBlacklist
+func NewRedisBlacklist(client redis.UniversalClient) *RedisBlacklist {
+	return &RedisBlacklist{client: client}
+}
+
+// Revoke marca o jti como revogado até o fim do ttl
+func (b *RedisBlacklist) Revoke(ctx context.Context, jti string, ttl time.Duration) error {
+	if err := b.client.Set(ctx, keyPrefix+jti, 1, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store revoked token: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked verifica se o jti foi revogado
+func (b *RedisBlacklist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := b.client.Exists(ctx, keyPrefix+jti).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+
+	return n > 0, nil
+}
@@ -5,18 +5,48 @@
 package db
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type EmailVerificationToken struct {
+	TokenHash     string         `json:"token_hash"`
+	UserID        uuid.UUID      `json:"user_id"`
+	Email         string         `json:"email"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	ReplacesEmail sql.NullString `json:"replaces_email"`
+}
+
+type PasswordResetToken struct {
+	TokenHash string    `json:"token_hash"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Attempts  int32     `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	IsActive  bool      `json:"is_active"`
+	ID                uuid.UUID      `json:"id"`
+	Email             string         `json:"email"`
+	Password          string         `json:"password"`
+	Name              string         `json:"name"`
+	Role              string         `json:"role"`
+	IsActive          bool           `json:"is_active"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	TokenVersion      int32          `json:"token_version"`
+	EmailVerifiedAt   sql.NullTime   `json:"email_verified_at"`
+	Username          sql.NullString `json:"username"`
+	LastLoginAt       sql.NullTime   `json:"last_login_at"`
+	PasswordChangedAt sql.NullTime   `json:"password_changed_at"`
+}
+
+type UserIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	UserID    uuid.UUID `json:"user_id"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -6,20 +6,65 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error)
+	ChangeUserEmail(ctx context.Context, arg ChangeUserEmailParams) (int64, error)
+	ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (int64, error)
+	CountActiveUsers(ctx context.Context) (int64, error)
+	CountActiveUsersByRole(ctx context.Context, role string) (int64, error)
+	CountDormantUsers(ctx context.Context, since sql.NullTime) (int64, error)
+	CountSearchUsers(ctx context.Context, arg CountSearchUsersParams) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
+	CountUsersByRole(ctx context.Context) ([]CountUsersByRoleRow, error)
+	CountUsersCreatedBetween(ctx context.Context, arg CountUsersCreatedBetweenParams) (int64, error)
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id uuid.UUID) error
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error
+	DeleteEmailVerificationTokensByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeletePasswordResetTokensByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteUserIdentitiesByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Condicional: quem entrou ou mudou de papel depois da listagem fica de fora
+	DowngradeInactiveUsers(ctx context.Context, arg DowngradeInactiveUsersParams) ([]uuid.UUID, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	ExistsByID(ctx context.Context, id uuid.UUID) (bool, error)
+	ExistsByRole(ctx context.Context, role string) (bool, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	GetPasswordResetTokenByUser(ctx context.Context, userID uuid.UUID) (PasswordResetToken, error)
+	GetTokenVersion(ctx context.Context, id uuid.UUID) (int32, error)
+	GetUserActiveStatus(ctx context.Context, id uuid.UUID) (bool, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
+	GetUserRolesForUpdate(ctx context.Context, ids []uuid.UUID) ([]GetUserRolesForUpdateRow, error)
+	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	IncrementPasswordResetAttempts(ctx context.Context, tokenHash string) (int32, error)
+	IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int32, error)
+	ListActiveUsers(ctx context.Context, arg ListActiveUsersParams) ([]User, error)
+	ListDormantUsers(ctx context.Context, arg ListDormantUsersParams) ([]User, error)
+	ListExistingEmails(ctx context.Context, emails []string) ([]string, error)
+	// Sem login, a atividade é a criação da conta: um admin recém-criado não é inativo
+	ListInactiveUsersByRole(ctx context.Context, arg ListInactiveUsersByRoleParams) ([]User, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListUsersAfterID(ctx context.Context, arg ListUsersAfterIDParams) ([]User, error)
+	ListUsersCreatedBetween(ctx context.Context, arg ListUsersCreatedBetweenParams) ([]User, error)
+	// Serializa a criação do primeiro admin até o fim da transação
+	LockFirstAdminBootstrap(ctx context.Context) error
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error)
+	// Grava apenas se o hash ainda for o lido, sem sobrescrever uma troca concorrente
+	ReplaceUserPassword(ctx context.Context, arg ReplaceUserPasswordParams) (int64, error)
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	UpdateLastLogin(ctx context.Context, arg UpdateLastLoginParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,11 +7,114 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const anonymizeUser = `-- name: AnonymizeUser :one
+UPDATE users SET
+    email = $2,
+    password = $3,
+    name = $4,
+    username = NULL,
+    is_active = FALSE,
+    token_version = token_version + 1,
+    updated_at = $5
+WHERE id = $1
+RETURNING id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at
+`
+
+type AnonymizeUserParams struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Password  string    `json:"password"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, anonymizeUser,
+		arg.ID,
+		arg.Email,
+		arg.Password,
+		arg.Name,
+		arg.UpdatedAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Password,
+		&i.Name,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Username,
+		&i.LastLoginAt,
+		&i.PasswordChangedAt,
+	)
+	return i, err
+}
+
+const countActiveUsers = `-- name: CountActiveUsers :one
+SELECT COUNT(*) FROM users WHERE is_active = true
+`
+
+func (q *Queries) CountActiveUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countActiveUsersByRole = `-- name: CountActiveUsersByRole :one
+SELECT COUNT(*) FROM users
+WHERE role = $1 AND is_active
+`
+
+func (q *Queries) CountActiveUsersByRole(ctx context.Context, role string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveUsersByRole, role)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countDormantUsers = `-- name: CountDormantUsers :one
+SELECT COUNT(*) FROM users
+WHERE last_login_at IS NULL OR last_login_at < $1
+`
+
+func (q *Queries) CountDormantUsers(ctx context.Context, since sql.NullTime) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDormantUsers, since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE (name ILIKE $1 OR email ILIKE $1)
+  AND (is_active = true OR $2::boolean)
+`
+
+type CountSearchUsersParams struct {
+	Pattern         string `json:"pattern"`
+	IncludeInactive bool   `json:"include_inactive"`
+}
+
+func (q *Queries) CountSearchUsers(ctx context.Context, arg CountSearchUsersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchUsers, arg.Pattern, arg.IncludeInactive)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUsers = `-- name: CountUsers :one
 SELECT COUNT(*) FROM users
 `
@@ -23,22 +126,75 @@ func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countUsersByRole = `-- name: CountUsersByRole :many
+SELECT role, COUNT(*) AS count FROM users
+GROUP BY role
+`
+
+type CountUsersByRoleRow struct {
+	Role  string `json:"role"`
+	Count int64  `json:"count"`
+}
+
+func (q *Queries) CountUsersByRole(ctx context.Context) ([]CountUsersByRoleRow, error) {
+	rows, err := q.db.QueryContext(ctx, countUsersByRole)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountUsersByRoleRow{}
+	for rows.Next() {
+		var i CountUsersByRoleRow
+		if err := rows.Scan(&i.Role, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countUsersCreatedBetween = `-- name: CountUsersCreatedBetween :one
+SELECT COUNT(*) FROM users
+WHERE created_at >= $1 AND created_at <= $2
+`
+
+type CountUsersCreatedBetweenParams struct {
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+func (q *Queries) CountUsersCreatedBetween(ctx context.Context, arg CountUsersCreatedBetweenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsersCreatedBetween, arg.FromTime, arg.ToTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-    email, password, name, role, is_active, created_at, updated_at
+    email, password, name, role, is_active, created_at, updated_at, email_verified_at, username, password_changed_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, email, password, name, role, is_active, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at
 `
 
 type CreateUserParams struct {
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Email             string         `json:"email"`
+	Password          string         `json:"password"`
+	Name              string         `json:"name"`
+	Role              string         `json:"role"`
+	IsActive          bool           `json:"is_active"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	EmailVerifiedAt   sql.NullTime   `json:"email_verified_at"`
+	Username          sql.NullString `json:"username"`
+	PasswordChangedAt sql.NullTime   `json:"password_changed_at"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -50,6 +206,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.IsActive,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.EmailVerifiedAt,
+		arg.Username,
+		arg.PasswordChangedAt,
 	)
 	var i User
 	err := row.Scan(
@@ -61,21 +220,77 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Username,
+		&i.LastLoginAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
+const deleteUser = `-- name: DeleteUser :execrows
 DELETE FROM users WHERE id = $1
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const downgradeInactiveUsers = `-- name: DowngradeInactiveUsers :many
+UPDATE users SET
+    role = $1,
+    token_version = token_version + 1,
+    updated_at = NOW()
+WHERE id = ANY($2::uuid[])
+    AND role = $3
+    AND is_active
+    AND COALESCE(last_login_at, created_at) < $4
+RETURNING id
+`
+
+type DowngradeInactiveUsersParams struct {
+	NewRole string      `json:"new_role"`
+	Ids     []uuid.UUID `json:"ids"`
+	Role    string      `json:"role"`
+	Since   time.Time   `json:"since"`
+}
+
+// Condicional: quem entrou ou mudou de papel depois da listagem fica de fora
+func (q *Queries) DowngradeInactiveUsers(ctx context.Context, arg DowngradeInactiveUsersParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, downgradeInactiveUsers,
+		arg.NewRole,
+		pq.Array(arg.Ids),
+		arg.Role,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const existsByEmail = `-- name: ExistsByEmail :one
-SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)
+SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1))
 `
 
 func (q *Queries) ExistsByEmail(ctx context.Context, email string) (bool, error) {
@@ -85,6 +300,137 @@ func (q *Queries) ExistsByEmail(ctx context.Context, email string) (bool, error)
 	return exists, err
 }
 
+const existsByUsername = `-- name: ExistsByUsername :one
+SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(username) = LOWER($1))
+`
+
+func (q *Queries) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, existsByUsername, username)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const existsByRole = `-- name: ExistsByRole :one
+SELECT EXISTS(SELECT 1 FROM users WHERE role = $1)
+`
+
+func (q *Queries) ExistsByRole(ctx context.Context, role string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, existsByRole, role)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listDormantUsers = `-- name: ListDormantUsers :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users
+WHERE last_login_at IS NULL OR last_login_at < $1
+ORDER BY last_login_at ASC NULLS FIRST, id
+LIMIT $2 OFFSET $3
+`
+
+type ListDormantUsersParams struct {
+	Since  sql.NullTime `json:"since"`
+	Limit  int32        `json:"limit"`
+	Offset int32        `json:"offset"`
+}
+
+func (q *Queries) ListDormantUsers(ctx context.Context, arg ListDormantUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listDormantUsers, arg.Since, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInactiveUsersByRole = `-- name: ListInactiveUsersByRole :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users
+WHERE role = $1 AND is_active AND COALESCE(last_login_at, created_at) < $2
+ORDER BY COALESCE(last_login_at, created_at), id
+`
+
+type ListInactiveUsersByRoleParams struct {
+	Role  string    `json:"role"`
+	Since time.Time `json:"since"`
+}
+
+// Sem login, a atividade é a criação da conta: um admin recém-criado não é inativo
+func (q *Queries) ListInactiveUsersByRole(ctx context.Context, arg ListInactiveUsersByRoleParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listInactiveUsersByRole, arg.Role, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockFirstAdminBootstrap = `-- name: LockFirstAdminBootstrap :exec
+SELECT pg_advisory_xact_lock(hashtext('users:first_admin_bootstrap'))
+`
+
+// Serializa a criação do primeiro admin até o fim da transação
+func (q *Queries) LockFirstAdminBootstrap(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, lockFirstAdminBootstrap)
+	return err
+}
+
 const existsByID = `-- name: ExistsByID :one
 SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)
 `
@@ -96,8 +442,32 @@ func (q *Queries) ExistsByID(ctx context.Context, id uuid.UUID) (bool, error) {
 	return exists, err
 }
 
+const getTokenVersion = `-- name: GetTokenVersion :one
+SELECT token_version FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetTokenVersion(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getTokenVersion, id)
+	var token_version int32
+	err := row.Scan(&token_version)
+	return token_version, err
+}
+
+const getUserActiveStatus = `-- name: GetUserActiveStatus :one
+SELECT is_active FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserActiveStatus(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserActiveStatus, id)
+	var is_active bool
+	err := row.Scan(&is_active)
+	return is_active, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, password, name, role, is_active, created_at, updated_at FROM users WHERE email = $1
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users WHERE LOWER(email) = LOWER($1)
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -112,12 +482,42 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Username,
+		&i.LastLoginAt,
+		&i.PasswordChangedAt,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users WHERE LOWER(username) = LOWER($1)
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Password,
+		&i.Name,
+		&i.Role,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Username,
+		&i.LastLoginAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, password, name, role, is_active, created_at, updated_at FROM users WHERE id = $1
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -132,12 +532,185 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Username,
+		&i.LastLoginAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
 
+const getUserRolesForUpdate = `-- name: GetUserRolesForUpdate :many
+SELECT id, role FROM users
+WHERE id = ANY($1::uuid[])
+FOR UPDATE
+`
+
+type GetUserRolesForUpdateRow struct {
+	ID   uuid.UUID `json:"id"`
+	Role string    `json:"role"`
+}
+
+func (q *Queries) GetUserRolesForUpdate(ctx context.Context, ids []uuid.UUID) ([]GetUserRolesForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserRolesForUpdate, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUserRolesForUpdateRow{}
+	for rows.Next() {
+		var i GetUserRolesForUpdateRow
+		if err := rows.Scan(&i.ID, &i.Role); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementTokenVersion = `-- name: IncrementTokenVersion :one
+UPDATE users SET
+    token_version = token_version + 1,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING token_version
+`
+
+func (q *Queries) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementTokenVersion, id)
+	var token_version int32
+	err := row.Scan(&token_version)
+	return token_version, err
+}
+
+const listActiveUsers = `-- name: ListActiveUsers :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users 
+WHERE is_active = true
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListActiveUsersParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListActiveUsers(ctx context.Context, arg ListActiveUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExistingEmails = `-- name: ListExistingEmails :many
+SELECT LOWER(email)::text FROM users
+WHERE LOWER(email) = ANY($1::text[])
+`
+
+func (q *Queries) ListExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listExistingEmails, pq.Array(emails))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, email, password, name, role, is_active, created_at, updated_at FROM users 
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users 
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -165,6 +738,201 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersAfterID = `-- name: ListUsersAfterID :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUsersAfterIDParams struct {
+	ID    uuid.UUID `json:"id"`
+	Limit int32     `json:"limit"`
+}
+
+func (q *Queries) ListUsersAfterID(ctx context.Context, arg ListUsersAfterIDParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersAfterID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersCreatedBetween = `-- name: ListUsersCreatedBetween :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users
+WHERE created_at >= $1 AND created_at <= $2
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListUsersCreatedBetweenParams struct {
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+	Limit    int32     `json:"limit"`
+	Offset   int32     `json:"offset"`
+}
+
+func (q *Queries) ListUsersCreatedBetween(ctx context.Context, arg ListUsersCreatedBetweenParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersCreatedBetween,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replaceUserPassword = `-- name: ReplaceUserPassword :execrows
+UPDATE users SET
+    password = $1,
+    password_changed_at = $2
+WHERE id = $3 AND password = $4
+`
+
+type ReplaceUserPasswordParams struct {
+	Password          string       `json:"password"`
+	PasswordChangedAt sql.NullTime `json:"password_changed_at"`
+	ID                uuid.UUID    `json:"id"`
+	PreviousPassword  string       `json:"previous_password"`
+}
+
+// Grava apenas se o hash ainda for o lido, sem sobrescrever uma troca concorrente
+func (q *Queries) ReplaceUserPassword(ctx context.Context, arg ReplaceUserPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, replaceUserPassword,
+		arg.Password,
+		arg.PasswordChangedAt,
+		arg.ID,
+		arg.PreviousPassword,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at FROM users
+WHERE (name ILIKE $1 OR email ILIKE $1)
+  AND (is_active = true OR $2::boolean)
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type SearchUsersParams struct {
+	Pattern         string `json:"pattern"`
+	IncludeInactive bool   `json:"include_inactive"`
+	Limit           int32  `json:"limit"`
+	Offset          int32  `json:"offset"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.Pattern,
+		arg.IncludeInactive,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Password,
+			&i.Name,
+			&i.Role,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TokenVersion,
+			&i.EmailVerifiedAt,
+			&i.Username,
+			&i.LastLoginAt,
+			&i.PasswordChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -179,26 +947,49 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const updateLastLogin = `-- name: UpdateLastLogin :exec
+UPDATE users SET last_login_at = $1
+WHERE id = $2
+`
+
+type UpdateLastLoginParams struct {
+	LastLoginAt sql.NullTime `json:"last_login_at"`
+	ID          uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) UpdateLastLogin(ctx context.Context, arg UpdateLastLoginParams) error {
+	_, err := q.db.ExecContext(ctx, updateLastLogin, arg.LastLoginAt, arg.ID)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users SET
     email = COALESCE($2, email),
+    -- Um novo email precisa ser verificado novamente
+    email_verified_at = CASE WHEN COALESCE($2, email) = email THEN email_verified_at END,
     password = COALESCE($3, password),
     name = COALESCE($4, name),
     role = COALESCE($5, role),
+    -- Uma troca de role invalida os tokens emitidos com o role anterior
+    token_version = CASE WHEN COALESCE($5, role) = role THEN token_version ELSE token_version + 1 END,
     is_active = COALESCE($6, is_active),
-    updated_at = $7
+    updated_at = $7,
+    username = $8,
+    password_changed_at = $9
 WHERE id = $1
-RETURNING id, email, password, name, role, is_active, created_at, updated_at
+RETURNING id, email, password, name, role, is_active, created_at, updated_at, token_version, email_verified_at, username, last_login_at, password_changed_at
 `
 
 type UpdateUserParams struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	IsActive  bool      `json:"is_active"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                uuid.UUID      `json:"id"`
+	Email             string         `json:"email"`
+	Password          string         `json:"password"`
+	Name              string         `json:"name"`
+	Role              string         `json:"role"`
+	IsActive          bool           `json:"is_active"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	Username          sql.NullString `json:"username"`
+	PasswordChangedAt sql.NullTime   `json:"password_changed_at"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -210,6 +1001,8 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Role,
 		arg.IsActive,
 		arg.UpdatedAt,
+		arg.Username,
+		arg.PasswordChangedAt,
 	)
 	var i User
 	err := row.Scan(
@@ -221,6 +1014,32 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TokenVersion,
+		&i.EmailVerifiedAt,
+		&i.Username,
+		&i.LastLoginAt,
+		&i.PasswordChangedAt,
 	)
 	return i, err
 }
+
+const updateUserRoles = `-- name: UpdateUserRoles :execrows
+UPDATE users SET
+    role = $1,
+    token_version = token_version + 1,
+    updated_at = NOW()
+WHERE id = ANY($2::uuid[])
+`
+
+type UpdateUserRolesParams struct {
+	Role string      `json:"role"`
+	Ids  []uuid.UUID `json:"ids"`
+}
+
+func (q *Queries) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserRoles, arg.Role, pq.Array(arg.Ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
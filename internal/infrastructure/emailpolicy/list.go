@@ -0,0 +1,76 @@
+package emailpolicy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// List é uma user.EmailPolicy que rejeita domínios descartáveis de uma lista.
+// Um domínio listado também cobre seus subdomínios
+type List struct {
+	domains map[string]struct{}
+}
+
+// NewList cria a política a partir dos domínios informados
+func NewList(domains []string) *List {
+	l := &List{domains: make(map[string]struct{}, len(domains))}
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			l.domains[domain] = struct{}{}
+		}
+	}
+	return l
+}
+
+// LoadFile lê uma lista com um domínio por linha; linhas vazias e iniciadas
+// por # são ignoradas. extra é acrescentado aos domínios do arquivo
+func LoadFile(path string, extra ...string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open disposable email list: %w", err)
+	}
+	defer f.Close()
+
+	domains := append([]string(nil), extra...)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read disposable email list: %w", err)
+	}
+
+	return NewList(domains), nil
+}
+
+// Len retorna quantos domínios a lista contém
+func (l *List) Len() int {
+	return len(l.domains)
+}
+
+// Check implementa user.EmailPolicy
+func (l *List) Check(_ context.Context, email string) error {
+	domain := user.EmailDomain(email)
+	for domain != "" {
+		if _, ok := l.domains[domain]; ok {
+			return user.NewDomainError(user.ErrDisposableEmail, "domain", user.EmailDomain(email))
+		}
+		// Sobe para o domínio pai: a.b.com -> b.com
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		domain = parent
+	}
+	return nil
+}
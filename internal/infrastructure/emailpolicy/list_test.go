@@ -0,0 +1,36 @@
+package emailpolicy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCheck(t *testing.T) {
+	list := NewList([]string{"Mailinator.com", " tempmail.dev "})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, list.Check(ctx, "ana@mailinator.com"), user.ErrDisposableEmail)
+	assert.ErrorIs(t, list.Check(ctx, "ana@eu.TEMPMAIL.dev"), user.ErrDisposableEmail)
+	assert.NoError(t, list.Check(ctx, "ana@example.com"))
+	assert.NoError(t, list.Check(ctx, "ana@notmailinator.com"))
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# descartáveis\nmailinator.com\n\nyopmail.com\n"), 0o600))
+
+	list, err := LoadFile(path, "tempmail.dev")
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.Len())
+	assert.ErrorIs(t, list.Check(context.Background(), "ana@yopmail.com"), user.ErrDisposableEmail)
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
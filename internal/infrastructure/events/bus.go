@@ -0,0 +1,38 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// Bus distribui eventos de usuário para vários assinantes em processo
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []user.EventPublisher
+}
+
+// NewBus cria um barramento de eventos vazio
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registra um assinante. Assinantes são chamados de forma síncrona
+// e devem repassar o trabalho pesado para goroutines próprias
+func (b *Bus) Subscribe(subscriber user.EventPublisher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+// Publish entrega o evento a todos os assinantes
+func (b *Bus) Publish(ctx context.Context, event user.Event) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		s.Publish(ctx, event)
+	}
+}
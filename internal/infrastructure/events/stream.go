@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// DefaultStreamBuffer é o número de eventos enfileirados por assinante antes de descartar
+const DefaultStreamBuffer = 64
+
+// Stream repassa eventos de usuário para assinantes de longa duração (ex.: SSE).
+// Cada assinante tem um buffer limitado; eventos excedentes são descartados
+// para que consumidores lentos nunca bloqueiem quem publica
+type Stream struct {
+	mu          sync.RWMutex
+	subscribers map[chan user.Event]struct{}
+	buffer      int
+	dropped     atomic.Int64
+}
+
+// NewStream cria um Stream com o buffer por assinante informado
+func NewStream(buffer int) *Stream {
+	if buffer <= 0 {
+		buffer = DefaultStreamBuffer
+	}
+	return &Stream{
+		subscribers: make(map[chan user.Event]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Subscribe registra um assinante e retorna seu canal e a função de cancelamento
+func (s *Stream) Subscribe() (<-chan user.Event, func()) {
+	ch := make(chan user.Event, s.buffer)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, ch)
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish entrega o evento a cada assinante sem bloquear
+func (s *Stream) Publish(_ context.Context, event user.Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped retorna quantos eventos foram descartados por assinantes lentos
+func (s *Stream) Dropped() int64 {
+	return s.dropped.Load()
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamSlowConsumerDoesNotBlockPublisher(t *testing.T) {
+	stream := NewStream(2)
+	bus := NewBus()
+	bus.Subscribe(stream)
+
+	events, unsubscribe := stream.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			bus.Publish(context.Background(), user.NewEvent(user.EventUserDeleted, nil))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publisher blocked by slow consumer")
+	}
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(8), stream.Dropped())
+}
+
+func TestStreamUnsubscribeClosesChannel(t *testing.T) {
+	stream := NewStream(1)
+	events, unsubscribe := stream.Subscribe()
+	unsubscribe()
+	unsubscribe() // idempotente
+
+	_, open := <-events
+	assert.False(t, open)
+
+	// Publicar após o cancelamento não deve causar pânico
+	stream.Publish(context.Background(), user.NewEvent(user.EventUserCreated, &user.User{ID: "1"}))
+}
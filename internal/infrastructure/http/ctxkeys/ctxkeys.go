@@ -0,0 +1,93 @@
+// Package ctxkeys define o contrato dos valores guardados no contexto do Gin
+// por middlewares e lidos por handlers, evitando chaves como strings soltas
+package ctxkeys
+
+import (
+	"go-api-boilerplate/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Chaves dos valores no contexto do Gin; acessadas apenas pelas funções abaixo
+const (
+	userIDKey    = "userID"
+	userEmailKey = "userEmail"
+	userRoleKey  = "userRole"
+	tokenKey     = "token"
+	sessionIDKey = "sessionID"
+	requestIDKey = "request_id"
+)
+
+// SetUserID guarda o ID do usuário autenticado
+func SetUserID(c *gin.Context, id string) {
+	c.Set(userIDKey, id)
+}
+
+// UserID retorna o ID do usuário autenticado, se houver
+func UserID(c *gin.Context) (string, bool) {
+	return getString(c, userIDKey)
+}
+
+// SetUserEmail guarda o email do usuário autenticado
+func SetUserEmail(c *gin.Context, email string) {
+	c.Set(userEmailKey, email)
+}
+
+// UserEmail retorna o email do usuário autenticado, se houver
+func UserEmail(c *gin.Context) (string, bool) {
+	return getString(c, userEmailKey)
+}
+
+// SetUserRole guarda o papel do usuário autenticado
+func SetUserRole(c *gin.Context, role string) {
+	c.Set(userRoleKey, role)
+}
+
+// UserRole retorna o papel do usuário autenticado, se houver
+func UserRole(c *gin.Context) (string, bool) {
+	return getString(c, userRoleKey)
+}
+
+// SetToken guarda o JWT bruto da requisição autenticada
+func SetToken(c *gin.Context, token string) {
+	c.Set(tokenKey, token)
+}
+
+// Token retorna o JWT bruto da requisição autenticada, se houver
+func Token(c *gin.Context) (string, bool) {
+	return getString(c, tokenKey)
+}
+
+// SetSessionID guarda o jti do token da requisição autenticada
+func SetSessionID(c *gin.Context, id string) {
+	c.Set(sessionIDKey, id)
+}
+
+// SessionID retorna o jti do token da requisição autenticada, se houver
+func SessionID(c *gin.Context) (string, bool) {
+	return getString(c, sessionIDKey)
+}
+
+// SetRequestID guarda o ID de correlação da requisição, também no contexto da
+// requisição (requestid.FromContext), de onde chega a eventos, webhooks e logs
+func SetRequestID(c *gin.Context, id string) {
+	c.Set(requestIDKey, id)
+	if c.Request != nil {
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+	}
+}
+
+// RequestID retorna o ID de correlação da requisição, se houver
+func RequestID(c *gin.Context) (string, bool) {
+	return getString(c, requestIDKey)
+}
+
+// getString lê um valor string; valores de outro tipo contam como ausentes
+func getString(c *gin.Context, key string) (string, bool) {
+	value, exists := c.Get(key)
+	if !exists {
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
@@ -0,0 +1,64 @@
+package ctxkeys
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-boilerplate/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		set  func(*gin.Context, string)
+		get  func(*gin.Context) (string, bool)
+	}{
+		{"user id", SetUserID, UserID},
+		{"user email", SetUserEmail, UserEmail},
+		{"user role", SetUserRole, UserRole},
+		{"token", SetToken, Token},
+		{"request id", SetRequestID, RequestID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+			_, ok := tt.get(c)
+			assert.False(t, ok)
+
+			tt.set(c, "value")
+			value, ok := tt.get(c)
+			assert.True(t, ok)
+			assert.Equal(t, "value", value)
+		})
+	}
+}
+
+func TestSetRequestIDPropagatesToRequestContext(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	SetRequestID(c, "req-1")
+	assert.Equal(t, "req-1", requestid.FromContext(c.Request.Context()))
+}
+
+func TestAccessorsKeysAreDistinct(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	SetUserID(c, "id")
+	SetUserRole(c, "admin")
+
+	id, _ := UserID(c)
+	role, _ := UserRole(c)
+	assert.Equal(t, "id", id)
+	assert.Equal(t, "admin", role)
+
+	_, ok := UserEmail(c)
+	assert.False(t, ok)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	playground "github.com/go-playground/validator/v10"
+)
+
+// CodeUnknownField indica um campo JSON não reconhecido no modo estrito
+const CodeUnknownField = "UNKNOWN_FIELD"
+
+// CodeRequestTooLarge indica um corpo acima do limite do endpoint
+const CodeRequestTooLarge = "REQUEST_TOO_LARGE"
+
+// unknownFieldPrefix é o início da mensagem de encoding/json para campos desconhecidos
+const unknownFieldPrefix = "json: unknown field "
+
+// maxStrictJSONBytes limita o corpo lido em memória pelo modo estrito; acima
+// dele a resposta é 413, como nos endpoints com limite próprio
+const maxStrictJSONBytes = 1 << 20
+
+// bindJSON decodifica e valida o corpo JSON em req. No modo estrito campos
+// desconhecidos são rejeitados com CodeUnknownField. Em caso de erro responde
+// 400 e retorna false; falhas de validação vêm todas juntas (CodeValidationFailed)
+func (h *UserHandler) bindJSON(c *gin.Context, req interface{}) bool {
+	failures, ok := h.bindJSONCollect(c, req)
+	if !ok {
+		return false
+	}
+	if !failures.empty() {
+		respondError(c, http.StatusBadRequest, failures.response())
+		return false
+	}
+	return true
+}
+
+// bindJSONCollect é como bindJSON, mas devolve as falhas de validação sem
+// responder, para que o handler acrescente outras regras antes. Corpos
+// malformados ou com campos desconhecidos respondem 400 e retornam false
+func (h *UserHandler) bindJSONCollect(c *gin.Context, req interface{}) (*validationFailures, bool) {
+	failures := &validationFailures{}
+	err := h.decodeJSON(c, req)
+	if err == nil {
+		return failures, true
+	}
+
+	var validationErrs playground.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		failures.addBinding(validationErrs)
+		return failures, true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(c, http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error:   "Request body too large",
+			Message: fmt.Sprintf("Request body must not exceed %d bytes", tooLarge.Limit),
+			Code:    CodeRequestTooLarge,
+		})
+		return nil, false
+	}
+
+	response := ErrorResponse{
+		Error:   "Invalid request data",
+		Message: err.Error(),
+	}
+	if field, ok := unknownField(err); ok {
+		response.Message = fmt.Sprintf("Unknown field %q", field)
+		response.Code = CodeUnknownField
+		response.Details = []string{"field=" + field}
+	}
+
+	respondError(c, http.StatusBadRequest, response)
+	return nil, false
+}
+
+// decodeJSON usa o binding padrão do gin no modo leniente e um decoder com
+// DisallowUnknownFields no modo estrito, seguido da mesma validação
+func (h *UserHandler) decodeJSON(c *gin.Context, req interface{}) error {
+	if !h.strictJSON {
+		return c.ShouldBindJSON(req)
+	}
+
+	if c.Request.Body == nil {
+		return fmt.Errorf("invalid request")
+	}
+	// Um byte além do limite distingue um corpo grande demais de um no limite exato
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxStrictJSONBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxStrictJSONBytes {
+		return &http.MaxBytesError{Limit: maxStrictJSONBytes}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(req)
+}
+
+// unknownField extrai o nome do campo de um erro de campo desconhecido
+func unknownField(err error) (string, bool) {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, unknownFieldPrefix) {
+		return "", false
+	}
+	return strings.Trim(strings.TrimPrefix(msg, unknownFieldPrefix), `"`), true
+}
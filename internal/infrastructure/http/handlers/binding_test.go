@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindJSONUnknownFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// role inválido garante que o handler para antes do repositório quando o bind passa
+	const extraField = `{"email":"ana@example.com","password":"password123","name":"Ana","role":"nope","rol":"admin"}`
+	const validBody = `{"email":"ana@example.com","password":"password123","name":"Ana","role":"nope"}`
+
+	newRouter := func(strict bool) *gin.Engine {
+		h := NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}), WithStrictJSON(strict))
+		router := gin.New()
+		router.POST("/users", h.CreateUser)
+		return router
+	}
+	post := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+		return w
+	}
+
+	t.Run("strict rejects extra field", func(t *testing.T) {
+		w := post(newRouter(true), extraField)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, CodeUnknownField, resp.Code)
+		assert.Equal(t, `Unknown field "rol"`, resp.Message)
+		assert.Equal(t, []string{"field=rol"}, resp.Details)
+	})
+
+	t.Run("strict still validates known fields", func(t *testing.T) {
+		w := post(newRouter(true), `{"email":"not-an-email","password":"password123","name":"Ana","role":"user"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid request data")
+		assert.NotContains(t, w.Body.String(), CodeUnknownField)
+	})
+
+	t.Run("strict accepts known fields", func(t *testing.T) {
+		w := post(newRouter(true), validBody)
+		assert.Contains(t, w.Body.String(), "Invalid role")
+	})
+
+	t.Run("strict rejects oversized body", func(t *testing.T) {
+		body := `{"email":"ana@example.com","name":"` + strings.Repeat("a", maxStrictJSONBytes) + `"}`
+		w := post(newRouter(true), body)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), CodeRequestTooLarge)
+	})
+
+	t.Run("lenient ignores extra field", func(t *testing.T) {
+		w := post(newRouter(false), extraField)
+		assert.Contains(t, w.Body.String(), "Invalid role")
+	})
+}
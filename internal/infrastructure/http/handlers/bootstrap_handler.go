@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminBootstrapEnabled informa se a criação do primeiro admin pela API foi
+// habilitada no caso de uso (usecase.WithAdminBootstrap)
+func (h *UserHandler) AdminBootstrapEnabled() bool {
+	return h.userUseCase != nil && h.userUseCase.AdminBootstrapEnabled()
+}
+
+// BootstrapAdmin cria o primeiro admin de uma instalação nova
+// @Summary Criar o primeiro admin
+// @Description Endpoint público de uso único: cria um admin enquanto não existir nenhum. Depois disso responde 410
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body RegisterRequest true "Dados do admin"
+// @Success 201 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/bootstrap-admin [post]
+func (h *UserHandler) BootstrapAdmin(c *gin.Context) {
+	var req RegisterRequest
+	failures, ok := h.bindJSONCollect(c, &req)
+	if !ok {
+		return
+	}
+	if !failures.empty() {
+		h.addNewUserRules(c.Request.Context(), failures, req.Email, req.Name, nil)
+		respondError(c, http.StatusBadRequest, failures.response())
+		return
+	}
+
+	output, err := h.userUseCase.BootstrapAdmin(c.Request.Context(), usecase.BootstrapAdminInput{
+		Email:    req.Email,
+		Password: req.Password,
+		Name:     req.Name,
+		Username: req.Username,
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to create first admin",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, NewUserResponse(output.User, h.timestampFormat))
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// O repositório passa a ter um admin depois da primeira criação
+	repo := &mocks.UserRepository{}
+	repo.On("CountByRole", mock.Anything).Return(map[user.Role]int64{user.RoleAdmin: 0}, nil).Once()
+	repo.On("CountByRole", mock.Anything).Return(map[user.Role]int64{user.RoleAdmin: 1}, nil)
+	repo.On("ExistsByEmail", mock.Anything, "root@example.com").Return(false, nil)
+	repo.On("CreateFirstAdmin", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil).Once()
+
+	uc := usecase.NewUserUseCase(repo, auth.NewJWTService("test-secret", time.Hour), usecase.WithAdminBootstrap())
+	h := NewUserHandler(uc)
+	require.True(t, h.AdminBootstrapEnabled())
+	router := gin.New()
+	router.POST("/auth/bootstrap-admin", h.BootstrapAdmin)
+
+	post := func() *httptest.ResponseRecorder {
+		body := `{"email":"root@example.com","password":"password123","name":"Root"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/bootstrap-admin", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := post()
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	var created UserResponse
+	require.NoError(t, json.Unmarshal(first.Body.Bytes(), &created))
+	assert.Equal(t, user.RoleAdmin, created.Role)
+
+	second := post()
+	assert.Equal(t, http.StatusGone, second.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(second.Body.Bytes(), &response))
+	assert.Equal(t, CodeAdminBootstrapClosed, response.Code)
+	repo.AssertNumberOfCalls(t, "CreateFirstAdmin", 1)
+}
+
+func TestAdminBootstrapDisabledByDefault(t *testing.T) {
+	h := NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}))
+	assert.False(t, h.AdminBootstrapEnabled())
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	playground "github.com/go-playground/validator/v10"
+)
+
+// BulkCreateUsersRequest representa a criação de vários usuários. Cada item é
+// validado individualmente: itens inválidos falham sem afetar os demais
+type BulkCreateUsersRequest struct {
+	Users []CreateUserRequest `json:"users" binding:"required"`
+}
+
+// BatchGetUsersRequest representa a busca de vários usuários pelo ID
+type BatchGetUsersRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// ValidateImportRequest representa os emails de uma importação a pré-validar
+type ValidateImportRequest struct {
+	Emails []string `json:"emails" binding:"required"`
+}
+
+// BulkCreateUsers cria vários usuários, reportando o resultado de cada um
+// @Summary Criar usuários em lote
+// @Description Cria até 100 usuários. A resposta é 200 mesmo com falhas parciais;
+// @Description cada item traz seu status HTTP e, se falhou, o erro no formato padrão
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkCreateUsersRequest true "Usuários a criar"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/bulk [post]
+func (h *UserHandler) BulkCreateUsers(c *gin.Context) {
+	var req BulkCreateUsersRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+	if len(req.Users) == 0 {
+		h.respondBulkError(c, "Failed to create users", usecase.ErrEmptyBulkUsers)
+		return
+	}
+	if len(req.Users) > usecase.MaxBulkCreateUsers {
+		h.respondBulkError(c, "Failed to create users", usecase.ErrTooManyBulkUsers)
+		return
+	}
+
+	// Itens inválidos falham aqui; os válidos seguem para o caso de uso
+	items := make([]BulkItemResponse, len(req.Users))
+	var inputs []usecase.CreateUserInput
+	var positions []int
+	for i, item := range req.Users {
+		if err := binding.Validator.ValidateStruct(&item); err != nil {
+			failures := &validationFailures{}
+			var validationErrs playground.ValidationErrors
+			if errors.As(err, &validationErrs) {
+				failures.addBinding(validationErrs)
+			} else {
+				failures.add("", err.Error())
+			}
+			h.addNewUserRules(c.Request.Context(), failures, item.Email, item.Name, &item.Role)
+			items[i] = bulkItemError(i, "", http.StatusBadRequest, failures.response())
+			continue
+		}
+		role, err := h.validateRole(item.Role)
+		if err != nil {
+			items[i] = bulkItemError(i, "", http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid role",
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		inputs = append(inputs, usecase.CreateUserInput{
+			Email:    item.Email,
+			Password: item.Password,
+			Name:     item.Name,
+			Role:     role,
+			Username: item.Username,
+		})
+		positions = append(positions, i)
+	}
+
+	if len(inputs) > 0 {
+		output, err := h.userUseCase.BulkCreateUsers(c.Request.Context(), usecase.BulkCreateUsersInput{Users: inputs})
+		if err != nil {
+			h.respondBulkError(c, "Failed to create users", err)
+			return
+		}
+		for j, result := range output.Items {
+			items[positions[j]] = h.newBulkItemResponse(positions[j], result, "Failed to create user")
+		}
+	}
+
+	respondJSON(c, http.StatusOK, newBulkResponse(items))
+}
+
+// BatchGetUsers busca vários usuários pelo ID, reportando o resultado de cada um
+// @Summary Buscar usuários em lote
+// @Description Busca até users.max_batch_get_ids usuários (padrão 100) em uma consulta.
+// @Description IDs inexistentes aparecem como itens com status 404; a resposta é 200.
+// @Description Acima do limite responde 413 com code TOO_MANY_IDS
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BatchGetUsersRequest true "IDs dos usuários"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/batch-get [post]
+func (h *UserHandler) BatchGetUsers(c *gin.Context) {
+	var req BatchGetUsersRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	output, err := h.userUseCase.BatchGetUsers(c.Request.Context(), usecase.BatchGetUsersInput{IDs: req.IDs})
+	if err != nil {
+		h.respondBulkError(c, "Failed to get users", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, h.newBulkResponse(output.Items, "Failed to get user"))
+}
+
+// ValidateImport pré-valida os emails de uma importação sem criar usuários
+// @Summary Pré-validar emails de importação
+// @Description Verifica até 1000 emails com as regras de cadastro (formato, domínio,
+// @Description duplicatas na lista e emails já cadastrados). Nada é criado; cada
+// @Description email recebe valid, invalid_format, domain_blocked, already_exists ou duplicate
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ValidateImportRequest true "Emails a verificar"
+// @Success 200 {object} ValidateImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/validate-import [post]
+func (h *UserHandler) ValidateImport(c *gin.Context) {
+	var req ValidateImportRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+	if len(req.Emails) == 0 {
+		h.respondBulkError(c, "Failed to validate import", usecase.ErrEmptyImportEmails)
+		return
+	}
+	if len(req.Emails) > usecase.MaxImportEmails {
+		h.respondBulkError(c, "Failed to validate import", usecase.ErrTooManyImportEmails)
+		return
+	}
+
+	// O formato segue a mesma regra do binding de CreateUserRequest; os demais
+	// emails seguem para o caso de uso
+	engine := binding.Validator.Engine().(*playground.Validate)
+	items := make([]ImportEmailItem, len(req.Emails))
+	var emails []string
+	var positions []int
+	for i, email := range req.Emails {
+		items[i] = ImportEmailItem{Index: i, Email: user.NormalizeEmail(email)}
+		if err := engine.Var(email, "required,email"); err != nil {
+			items[i].Status = usecase.ImportEmailInvalidFormat
+			continue
+		}
+		emails = append(emails, email)
+		positions = append(positions, i)
+	}
+
+	if len(emails) > 0 {
+		output, err := h.userUseCase.ValidateImportEmails(c.Request.Context(), usecase.ValidateImportEmailsInput{Emails: emails})
+		if err != nil {
+			h.respondBulkError(c, "Failed to validate import", err)
+			return
+		}
+		for j, result := range output.Items {
+			items[positions[j]].Status = result.Status
+		}
+	}
+
+	respondJSON(c, http.StatusOK, newValidateImportResponse(items))
+}
+
+// newValidateImportResponse calcula o resumo da pré-validação
+func newValidateImportResponse(items []ImportEmailItem) ValidateImportResponse {
+	summary := ValidateImportSummary{Total: len(items)}
+	for _, item := range items {
+		if item.Status == usecase.ImportEmailValid {
+			summary.Valid++
+		} else {
+			summary.Invalid++
+		}
+	}
+	return ValidateImportResponse{Items: emptyIfNil(items), Summary: summary}
+}
+
+// respondBulkError responde um erro que impede a operação em lote inteira
+func (h *UserHandler) respondBulkError(c *gin.Context, title string, err error) {
+	status, message := h.mapErrorToHTTPStatus(err)
+	respondError(c, status, ErrorResponse{
+		Error:   title,
+		Message: message,
+		Code:    errorCode(err),
+		Details: errorDetails(err),
+	})
+}
+
+// newBulkResponse converte os resultados do caso de uso; errTitle é o campo
+// error dos itens que falharam
+func (h *UserHandler) newBulkResponse(results []usecase.BulkItemResult, errTitle string) BulkResponse {
+	items := make([]BulkItemResponse, len(results))
+	for i, result := range results {
+		items[i] = h.newBulkItemResponse(result.Index, result, errTitle)
+	}
+	return newBulkResponse(items)
+}
+
+// newBulkItemResponse converte um resultado, mapeando erros como nos endpoints individuais
+func (h *UserHandler) newBulkItemResponse(index int, result usecase.BulkItemResult, errTitle string) BulkItemResponse {
+	if result.Failed() {
+		status, message := h.mapErrorToHTTPStatus(result.Err)
+		return bulkItemError(index, result.ID, status, ErrorResponse{
+			Error:   errTitle,
+			Message: message,
+			Code:    errorCode(result.Err),
+			Details: errorDetails(result.Err),
+		})
+	}
+
+	item := BulkItemResponse{
+		Index:  index,
+		ID:     result.ID,
+		Status: http.StatusOK,
+		Result: result.Status,
+	}
+	if result.Status == usecase.BulkItemCreated {
+		item.Status = http.StatusCreated
+	}
+	if result.User != nil {
+		resp := NewUserResponse(result.User, h.timestampFormat)
+		item.User = &resp
+	}
+	return item
+}
+
+// bulkItemError monta um item que falhou
+func bulkItemError(index int, id string, status int, response ErrorResponse) BulkItemResponse {
+	response.Retryable = retryableStatus(status)
+	return BulkItemResponse{
+		Index:  index,
+		ID:     id,
+		Status: status,
+		Result: usecase.BulkItemFailed,
+		Error:  &response,
+	}
+}
+
+// newBulkResponse calcula o resumo dos itens
+func newBulkResponse(items []BulkItemResponse) BulkResponse {
+	summary := BulkSummary{Total: len(items)}
+	for _, item := range items {
+		if item.Error != nil {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+	}
+	return BulkResponse{Items: emptyIfNil(items), Summary: summary}
+}
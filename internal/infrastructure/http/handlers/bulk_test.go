@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCreateUsersPartialSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("ExistsByEmail", mock.Anything, "ana@example.com").Return(false, nil)
+	repo.On("ExistsByEmail", mock.Anything, "taken@example.com").Return(true, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*user.User).ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+	}).Return(nil)
+
+	router := gin.New()
+	router.POST("/users/bulk", h.BulkCreateUsers)
+
+	body := `{"users":[
+		{"email":"ana@example.com","password":"password123","name":"Ana","role":"user"},
+		{"email":"taken@example.com","password":"password123","name":"Bia","role":"user"},
+		{"email":"not-an-email","password":"password123","name":"Caio","role":"user"},
+		{"email":"duda@example.com","password":"password123","name":"Duda","role":"root"}
+	]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/bulk", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp BulkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, BulkSummary{Total: 4, Succeeded: 1, Failed: 3}, resp.Summary)
+	require.Len(t, resp.Items, 4)
+
+	created := resp.Items[0]
+	assert.Equal(t, http.StatusCreated, created.Status)
+	assert.Equal(t, usecase.BulkItemCreated, created.Result)
+	require.NotNil(t, created.User)
+	assert.Equal(t, "ana@example.com", created.User.Email)
+	assert.Nil(t, created.Error)
+
+	wantStatus := []int{http.StatusConflict, http.StatusBadRequest, http.StatusBadRequest}
+	for i, status := range wantStatus {
+		item := resp.Items[i+1]
+		assert.Equal(t, i+1, item.Index)
+		assert.Equal(t, status, item.Status)
+		assert.Equal(t, usecase.BulkItemFailed, item.Result)
+		require.NotNil(t, item.Error)
+	}
+	assert.Equal(t, "User already exists", resp.Items[1].Error.Message)
+	assert.Equal(t, []string{"email=taken@example.com"}, resp.Items[1].Error.Details)
+	assert.Equal(t, "Invalid role", resp.Items[3].Error.Error)
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestBatchGetUsersPartialSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const found = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+	const missing = "1b2c3d4e-5f60-4a7b-8c9d-0e1f2a3b4c5d"
+
+	h, repo, _ := newTestHandler()
+	repo.On("GetByIDs", mock.Anything, []string{found, missing}).Return([]*user.User{
+		{ID: found, Email: "ana@example.com", Name: "Ana", Role: user.RoleUser, IsActive: true},
+	}, nil)
+
+	router := gin.New()
+	router.POST("/users/batch-get", h.BatchGetUsers)
+
+	// O ID repetido antes do inexistente desloca a posição deste na entrada
+	body := `{"ids":["` + found + `","` + found + `","` + missing + `"]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/batch-get", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp BulkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, BulkSummary{Total: 2, Succeeded: 1, Failed: 1}, resp.Summary)
+	require.Len(t, resp.Items, 2)
+
+	assert.Equal(t, http.StatusOK, resp.Items[0].Status)
+	assert.Equal(t, usecase.BulkItemFound, resp.Items[0].Result)
+	require.NotNil(t, resp.Items[0].User)
+	assert.Equal(t, found, resp.Items[0].User.ID)
+
+	assert.Equal(t, 0, resp.Items[0].Index)
+
+	assert.Equal(t, http.StatusNotFound, resp.Items[1].Status)
+	assert.Equal(t, missing, resp.Items[1].ID)
+	assert.Equal(t, 2, resp.Items[1].Index)
+	require.NotNil(t, resp.Items[1].Error)
+	assert.Equal(t, []string{"id=" + missing}, resp.Items[1].Error.Details)
+
+	t.Run("empty batch is rejected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/batch-get", strings.NewReader(`{"ids":[]}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestValidateImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("ExistingEmails", mock.Anything, []string{"ana@example.com", "taken@example.com"}).
+		Return([]string{"taken@example.com"}, nil)
+
+	router := gin.New()
+	router.POST("/users/validate-import", h.ValidateImport)
+
+	body := `{"emails":["ana@example.com","not-an-email","Taken@Example.com","ana@example.com"]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/validate-import", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ValidateImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, ValidateImportSummary{Total: 4, Valid: 1, Invalid: 3}, resp.Summary)
+	assert.Equal(t, []ImportEmailItem{
+		{Index: 0, Email: "ana@example.com", Status: usecase.ImportEmailValid},
+		{Index: 1, Email: "not-an-email", Status: usecase.ImportEmailInvalidFormat},
+		{Index: 2, Email: "taken@example.com", Status: usecase.ImportEmailAlreadyExists},
+		{Index: 3, Email: "ana@example.com", Status: usecase.ImportEmailDuplicate},
+	}, resp.Items)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestValidateImportRejectsOversizedList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	router := gin.New()
+	router.POST("/users/validate-import", h.ValidateImport)
+
+	emails := make([]string, usecase.MaxImportEmails+1)
+	for i := range emails {
+		emails[i] = "a@example.com"
+	}
+	payload, err := json.Marshal(ValidateImportRequest{Emails: emails})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/validate-import", strings.NewReader(string(payload))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "ExistingEmails", mock.Anything, mock.Anything)
+}
+
+func TestResultLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mocks.UserRepository{}
+	repo.On("GetByIDs", mock.Anything, mock.Anything).Return([]*user.User{}, nil)
+	repo.On("Search", mock.Anything, "ana", false, 0, 5).Return([]*user.User{}, nil)
+	repo.On("CountSearch", mock.Anything, "ana", false).Return(int64(0), nil)
+	h := NewUserHandler(usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithResultLimits(3, 5)))
+
+	router := gin.New()
+	router.POST("/users/batch-get", h.BatchGetUsers)
+	router.GET("/users/search", h.SearchUsers)
+
+	serve := func(req *http.Request) (*httptest.ResponseRecorder, ErrorResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp ErrorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+	batchGet := func(ids ...string) *http.Request {
+		payload, err := json.Marshal(BatchGetUsersRequest{IDs: ids})
+		require.NoError(t, err)
+		return httptest.NewRequest(http.MethodPost, "/users/batch-get", strings.NewReader(string(payload)))
+	}
+
+	t.Run("batch-get at the cap", func(t *testing.T) {
+		w, _ := serve(batchGet("a", "b", "c", "a"))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("batch-get above the cap", func(t *testing.T) {
+		w, resp := serve(batchGet("a", "b", "c", "d"))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, CodeTooManyIDs, resp.Code)
+		assert.Equal(t, "At most 3 user IDs are allowed per request", resp.Message)
+	})
+
+	t.Run("search at the cap", func(t *testing.T) {
+		w, _ := serve(httptest.NewRequest(http.MethodGet, "/users/search?q=ana&limit=5", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("search above the cap", func(t *testing.T) {
+		for _, query := range []string{"limit=6", "per_page=6"} {
+			w, resp := serve(httptest.NewRequest(http.MethodGet, "/users/search?q=ana&"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Equal(t, CodeLimitTooLarge, resp.Code, query)
+		}
+	})
+
+	repo.AssertNumberOfCalls(t, "GetByIDs", 1)
+	repo.AssertNumberOfCalls(t, "Search", 1)
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sort"
+	"time"
+
+	"go-api-boilerplate/pkg/config"
+	"go-api-boilerplate/pkg/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Status possíveis de cada verificação de diagnóstico
+const (
+	DiagnosticPass    = "pass"
+	DiagnosticFail    = "fail"
+	DiagnosticSkipped = "skipped"
+)
+
+// diagnosticsTimeout limita o tempo total das verificações
+const diagnosticsTimeout = 5 * time.Second
+
+// Pinger representa uma dependência externa cuja disponibilidade pode ser verificada
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// DiagnosticsHandler expõe um autodiagnóstico consolidado da aplicação
+type DiagnosticsHandler struct {
+	db       *sql.DB
+	cfg      *config.Config
+	migrator *database.Migrator
+	notifier Pinger
+}
+
+// NewDiagnosticsHandler cria uma nova instância de DiagnosticsHandler.
+// notifier é opcional; quando nil a verificação é reportada como skipped
+func NewDiagnosticsHandler(db *sql.DB, cfg *config.Config, migrator *database.Migrator, notifier Pinger) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		db:       db,
+		cfg:      cfg,
+		migrator: migrator,
+		notifier: notifier,
+	}
+}
+
+// DiagnosticCheck representa o resultado de uma verificação individual
+type DiagnosticCheck struct {
+	Status  string                 `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// DiagnosticsResponse representa o relatório de diagnóstico
+type DiagnosticsResponse struct {
+	Status      string                     `json:"status"`
+	Environment string                     `json:"environment"`
+	Checks      map[string]DiagnosticCheck `json:"checks"`
+}
+
+// Diagnostics retorna o autodiagnóstico da aplicação
+// @Summary Diagnóstico da aplicação
+// @Description Verifica configuração, banco, pool, migrações, JWT e notificador
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} DiagnosticsResponse
+// @Failure 503 {object} DiagnosticsResponse
+// @Router /admin/diagnostics [get]
+func (h *DiagnosticsHandler) Diagnostics(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), diagnosticsTimeout)
+	defer cancel()
+
+	checks := map[string]DiagnosticCheck{
+		"database":   h.checkDatabase(ctx),
+		"pool":       h.checkPool(),
+		"migrations": h.checkMigrations(ctx),
+		"jwt":        h.checkJWT(),
+		"notifier":   h.checkNotifier(ctx),
+	}
+
+	response := DiagnosticsResponse{
+		Status:      DiagnosticPass,
+		Environment: h.cfg.Environment,
+		Checks:      checks,
+	}
+
+	status := http.StatusOK
+	for _, check := range checks {
+		if check.Status == DiagnosticFail {
+			response.Status = DiagnosticFail
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	respondJSON(c, status, response)
+}
+
+// Ready informa se a instância pode receber tráfego (banco acessível)
+// @Summary Readiness check
+// @Description Retorna 503 enquanto o banco não responde
+// @Tags system
+// @Produce json
+// @Success 200 {object} DiagnosticCheck
+// @Failure 503 {object} DiagnosticCheck
+// @Router /health/ready [get]
+func (h *DiagnosticsHandler) Ready(c *gin.Context) {
+	if err := database.HealthCheck(c.Request.Context(), h.db); err != nil {
+		respondJSON(c, http.StatusServiceUnavailable, DiagnosticCheck{Status: DiagnosticFail, Message: err.Error()})
+		return
+	}
+	respondJSON(c, http.StatusOK, DiagnosticCheck{Status: DiagnosticPass})
+}
+
+// checkDatabase verifica se o banco está acessível
+func (h *DiagnosticsHandler) checkDatabase(ctx context.Context) DiagnosticCheck {
+	start := time.Now()
+	if err := database.HealthCheck(ctx, h.db); err != nil {
+		return DiagnosticCheck{Status: DiagnosticFail, Message: err.Error()}
+	}
+
+	return DiagnosticCheck{
+		Status:  DiagnosticPass,
+		Details: map[string]interface{}{"latency_ms": time.Since(start).Milliseconds()},
+	}
+}
+
+// checkPool reporta as configurações do pool em vigor e o uso atual. Message
+// traz uma orientação de ajuste quando o pool está saturado ou já houve espera
+func (h *DiagnosticsHandler) checkPool() DiagnosticCheck {
+	stats := h.db.Stats()
+	return DiagnosticCheck{
+		Status:  DiagnosticPass,
+		Message: poolTuningHint(stats),
+		Details: map[string]interface{}{
+			"max_open_conns":    stats.MaxOpenConnections,
+			"max_idle_conns":    h.cfg.Database.MaxIdleConns,
+			"conn_max_lifetime": h.cfg.Database.ConnMaxLifetime.String(),
+			"open_connections":  stats.OpenConnections,
+			"in_use":            stats.InUse,
+			"idle":              stats.Idle,
+			"wait_count":        stats.WaitCount,
+			"wait_duration":     stats.WaitDuration.String(),
+		},
+	}
+}
+
+// poolTuningHint sugere ajustes a partir dos contadores do pool; vazio quando não há sinal
+func poolTuningHint(stats sql.DBStats) string {
+	switch {
+	case stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections:
+		return "pool saturated: all connections are in use; consider raising database.max_open_conns"
+	case stats.WaitCount > 0:
+		return "requests have waited for connections; watch db_wait_count_total and consider raising database.max_open_conns"
+	}
+	return ""
+}
+
+// checkMigrations reporta a versão atual do schema
+func (h *DiagnosticsHandler) checkMigrations(ctx context.Context) DiagnosticCheck {
+	if h.migrator == nil {
+		return DiagnosticCheck{Status: DiagnosticSkipped, Message: "migrator not configured"}
+	}
+
+	version, err := h.migrator.CurrentVersion(ctx)
+	if err != nil {
+		return DiagnosticCheck{Status: DiagnosticFail, Message: err.Error()}
+	}
+
+	return DiagnosticCheck{
+		Status:  DiagnosticPass,
+		Details: map[string]interface{}{"version": version},
+	}
+}
+
+// checkJWT verifica se o JWT está configurado, sem expor os segredos
+func (h *DiagnosticsHandler) checkJWT() DiagnosticCheck {
+	security := h.cfg.Security
+	details := map[string]interface{}{
+		"expiration": security.JWTExpiration.String(),
+	}
+
+	if len(security.JWTKeys) > 0 {
+		kids := make([]string, 0, len(security.JWTKeys))
+		for kid := range security.JWTKeys {
+			kids = append(kids, kid)
+		}
+		sort.Strings(kids)
+		details["active_kid"] = security.JWTActiveKID
+		details["key_ids"] = kids
+	} else if security.JWTSecret == "" {
+		return DiagnosticCheck{Status: DiagnosticFail, Message: "jwt secret not configured"}
+	}
+
+	return DiagnosticCheck{Status: DiagnosticPass, Details: details}
+}
+
+// checkNotifier verifica se o notificador está acessível
+func (h *DiagnosticsHandler) checkNotifier(ctx context.Context) DiagnosticCheck {
+	if h.notifier == nil {
+		return DiagnosticCheck{Status: DiagnosticSkipped, Message: "notifier not configured"}
+	}
+
+	if err := h.notifier.Ping(ctx); err != nil {
+		return DiagnosticCheck{Status: DiagnosticFail, Message: err.Error()}
+	}
+
+	return DiagnosticCheck{Status: DiagnosticPass}
+}
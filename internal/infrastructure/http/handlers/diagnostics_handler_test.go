@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+// pingerFunc adapta uma função para a interface Pinger
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+func TestDiagnostics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Porta sem servidor: o ping falha imediatamente
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=postgres dbname=none sslmode=disable connect_timeout=1")
+	require.NoError(t, err)
+	defer db.Close()
+
+	cfg := &config.Config{
+		Environment: "testing",
+		Security: config.SecurityConfig{
+			JWTSecret:     "super-secret-value",
+			JWTExpiration: time.Hour,
+		},
+	}
+	notifier := pingerFunc(func(ctx context.Context) error { return errors.New("smtp unreachable") })
+	h := NewDiagnosticsHandler(db, cfg, nil, notifier)
+
+	router := gin.New()
+	router.GET("/admin/diagnostics", h.Diagnostics)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/diagnostics", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.NotContains(t, w.Body.String(), "super-secret-value")
+
+	var response DiagnosticsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, DiagnosticFail, response.Status)
+	assert.Equal(t, "testing", response.Environment)
+	assert.Equal(t, DiagnosticFail, response.Checks["database"].Status)
+	assert.Equal(t, DiagnosticPass, response.Checks["jwt"].Status)
+	assert.Equal(t, DiagnosticSkipped, response.Checks["migrations"].Status)
+	assert.Equal(t, DiagnosticFail, response.Checks["notifier"].Status)
+}
+
+func TestReadyReportsUnavailableDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=postgres dbname=none sslmode=disable connect_timeout=1")
+	require.NoError(t, err)
+	defer db.Close()
+
+	router := gin.New()
+	router.GET("/health/ready", NewDiagnosticsHandler(db, &config.Config{}, nil, nil).Ready)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), DiagnosticFail)
+}
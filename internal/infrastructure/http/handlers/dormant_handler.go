@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"strconv"
+
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDormantUsers lista as contas sem login na janela informada
+// @Summary Listar contas inativas
+// @Description Usuários sem login nos últimos days dias, incluindo os que nunca entraram,
+// @Description dos que nunca entraram aos de login mais antigo
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param days query int true "Janela de inatividade em dias (mínimo 1)"
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param limit query int false "Limite de registros" default(10)
+// @Param page query int false "Página (alternativa a offset)" default(1)
+// @Param per_page query int false "Registros por página (alternativa a limit)" default(10)
+// @Success 200 {object} PagedUsersResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/dormant [get]
+func (h *UserHandler) ListDormantUsers(c *gin.Context) {
+	h.respondPaged(c, "Failed to list dormant users", func(p Pagination) (*usecase.PagedUsers, error) {
+		days, err := strconv.Atoi(c.Query("days"))
+		if err != nil {
+			return nil, usecase.ErrInvalidDormantDays
+		}
+		return h.userUseCase.ListDormantUsers(c.Request.Context(), usecase.ListDormantUsersInput{
+			Days:   days,
+			Offset: p.Offset,
+			Limit:  p.Limit,
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDormantUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	lastLogin := now.AddDate(0, -4, 0)
+	old := &user.User{ID: "1", Email: "old@example.com", Role: user.RoleUser, LastLoginAt: &lastLogin}
+	never := &user.User{ID: "2", Email: "never@example.com", Role: user.RoleUser}
+
+	repo := &mocks.UserRepository{}
+	since := now.AddDate(0, 0, -90)
+	repo.On("ListDormant", mock.Anything, since, 0, 10).Return([]*user.User{never, old}, nil)
+	repo.On("CountDormant", mock.Anything, since).Return(int64(2), nil)
+
+	uc := usecase.NewUserUseCase(repo, auth.NewJWTService("test-secret", time.Hour), usecase.WithClock(clock.NewFake(now)))
+	router := gin.New()
+	router.GET("/users/dormant", NewUserHandler(uc).ListDormantUsers)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/dormant"+query, nil))
+		return w
+	}
+
+	w := get("?days=90")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var page PagedUsersResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, int64(2), page.Total)
+	require.Len(t, page.Users, 2)
+	assert.Nil(t, page.Users[0].LastLoginAt)
+	require.NotNil(t, page.Users[1].LastLoginAt)
+	assert.True(t, page.Users[1].LastLoginAt.Time.Equal(lastLogin))
+
+	for _, query := range []string{"", "?days=0", "?days=abc"} {
+		w := get(query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
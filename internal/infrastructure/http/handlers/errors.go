@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError escreve o erro no formato negociado pelo header Accept: JSON por
+// padrão ou uma linha legível quando o cliente pede text/plain. Retryable é
+// derivado do status
+func respondError(c *gin.Context, status int, response ErrorResponse) {
+	response.Retryable = retryableStatus(status)
+	switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEPlain) {
+	case gin.MIMEPlain:
+		c.String(status, "%s", response.text())
+	default:
+		respondJSON(c, status, response)
+	}
+}
+
+// Códigos de erro estruturados dos erros de domínio, para que clientes possam
+// diferenciar rejeições que compartilham o mesmo status HTTP
+const (
+	CodeEmailDomainNotAllowed = "EMAIL_DOMAIN_NOT_ALLOWED"
+	CodeDisposableEmail       = "DISPOSABLE_EMAIL"
+	// Token de verificação de email inválido (ou já usado) e expirado; ambos
+	// permitem ao cliente oferecer o reenvio do link
+	CodeVerificationTokenInvalid = "VERIFICATION_TOKEN_INVALID"
+	CodeVerificationTokenExpired = "VERIFICATION_TOKEN_EXPIRED"
+	// Token de redefinição de senha inválido, expirado ou invalidado por
+	// excesso de tentativas; nos dois últimos o cliente deve pedir outro link
+	CodeResetTokenInvalid   = "RESET_TOKEN_INVALID"
+	CodeResetTokenExpired   = "RESET_TOKEN_EXPIRED"
+	CodeResetTokenExhausted = "RESET_TOKEN_EXHAUSTED"
+	// A criação do primeiro admin foi encerrada porque já existe um admin
+	CodeAdminBootstrapClosed = "ADMIN_BOOTSTRAP_CLOSED"
+	// O papel do autor não pode alterar o campo (matriz de atualização)
+	CodeFieldUpdateForbidden = "FIELD_UPDATE_FORBIDDEN"
+	// Busca em lote com mais IDs, ou página de busca maior, que o configurado
+	CodeTooManyIDs    = "TOO_MANY_IDS"
+	CodeLimitTooLarge = "LIMIT_TOO_LARGE"
+	// A senha confere, mas venceu; o login exige a troca (POST /auth/password/expired)
+	CodePasswordExpired = "PASSWORD_EXPIRED"
+)
+
+// retryableStatus classifica os status de falhas passageiras: timeouts, rate
+// limit e indisponibilidade. Erros do cliente (validação, 404, 409) e erros
+// internos não classificados não são repetíveis
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// errorCode retorna o código estruturado de um erro de domínio, ou vazio
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, user.ErrEmailDomainNotAllowed):
+		return CodeEmailDomainNotAllowed
+	case errors.Is(err, user.ErrDisposableEmail):
+		return CodeDisposableEmail
+	case errors.Is(err, user.ErrVerificationTokenInvalid):
+		return CodeVerificationTokenInvalid
+	case errors.Is(err, user.ErrVerificationTokenExpired):
+		return CodeVerificationTokenExpired
+	case errors.Is(err, auth.ErrResetTokenInvalid):
+		return CodeResetTokenInvalid
+	case errors.Is(err, auth.ErrResetTokenExpired):
+		return CodeResetTokenExpired
+	case errors.Is(err, auth.ErrResetTokenExhausted):
+		return CodeResetTokenExhausted
+	case errors.Is(err, user.ErrAdminAlreadyExists):
+		return CodeAdminBootstrapClosed
+	case errors.Is(err, user.ErrFieldUpdateForbidden):
+		return CodeFieldUpdateForbidden
+	case errors.Is(err, usecase.ErrTooManyBatchGetIDs):
+		return CodeTooManyIDs
+	case errors.Is(err, usecase.ErrSearchLimitTooLarge):
+		return CodeLimitTooLarge
+	case errors.Is(err, user.ErrPasswordExpired):
+		return CodePasswordExpired
+	default:
+		return ""
+	}
+}
+
+// errorDetails extrai o contexto de um DomainError ("campo=valor") para os
+// detalhes da resposta; erros sem contexto não geram detalhes
+func errorDetails(err error) []string {
+	var domainErr *user.DomainError
+	if errors.As(err, &domainErr) && domainErr.Field != "" {
+		return []string{domainErr.Detail()}
+	}
+	return nil
+}
+
+// text formata o erro como "Error: Message [code]" seguido de um detalhe por linha
+func (r ErrorResponse) text() string {
+	var b strings.Builder
+	b.WriteString(r.Error)
+	if r.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(r.Message)
+	}
+	if r.Code != "" {
+		b.WriteString(" [")
+		b.WriteString(r.Code)
+		b.WriteString("]")
+	}
+	b.WriteString("\n")
+	for _, detail := range r.Details {
+		b.WriteString("  - ")
+		b.WriteString(detail)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponseNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, _, _ := newTestHandler()
+	router := gin.New()
+	router.GET("/users/:id", h.GetUserByID)
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{"json by default", "", "application/json; charset=utf-8"},
+		{"json for wildcard", "*/*", "application/json; charset=utf-8"},
+		{"json when requested", "application/json", "application/json; charset=utf-8"},
+		{"text when requested", "text/plain", "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/not-a-uuid", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+
+			if tt.contentType == "text/plain; charset=utf-8" {
+				assert.Equal(t, "Invalid user ID: User ID must be a valid UUID\n", w.Body.String())
+				return
+			}
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Invalid user ID", response.Error)
+		})
+	}
+}
+
+func TestErrorResponseTextIncludesCodeAndDetails(t *testing.T) {
+	response := ErrorResponse{
+		Error:   "Invalid pagination",
+		Message: "Invalid pagination parameters",
+		Code:    CodeInvalidPagination,
+		Details: []string{"limit must be at least 1"},
+	}
+
+	assert.Equal(t, "Invalid pagination: Invalid pagination parameters ["+CodeInvalidPagination+"]\n  - limit must be at least 1\n", response.text())
+}
+
+func TestErrorResponseRetryable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const id = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	tests := []struct {
+		name      string
+		err       error
+		status    int
+		retryable bool
+	}{
+		{"serialization failure", fmt.Errorf("failed to get user: %w", &pq.Error{Code: "40001"}), http.StatusServiceUnavailable, true},
+		{"timeout", context.DeadlineExceeded, http.StatusGatewayTimeout, true},
+		{"not found", user.ErrUserNotFound, http.StatusNotFound, false},
+		{"unclassified internal error", errors.New("boom"), http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo, _ := newTestHandler()
+			repo.On("GetByID", mock.Anything, id).Return(nil, tt.err)
+
+			router := gin.New()
+			router.GET("/users/:id", h.GetUserByID)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+id, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			var response ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.retryable, response.Retryable)
+		})
+	}
+
+	t.Run("validation errors are not retryable", func(t *testing.T) {
+		h, _, _ := newTestHandler()
+		router := gin.New()
+		router.GET("/users/:id", h.GetUserByID)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/not-a-uuid", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"retryable":false`)
+	})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultHeartbeatInterval mantém conexões SSE vivas através de proxies
+const defaultHeartbeatInterval = 15 * time.Second
+
+// EventSubscriber fornece uma assinatura de eventos de usuário
+type EventSubscriber interface {
+	Subscribe() (<-chan user.Event, func())
+}
+
+// EventsHandler transmite eventos de usuário via Server-Sent Events
+type EventsHandler struct {
+	subscriber EventSubscriber
+	heartbeat  time.Duration
+}
+
+// NewEventsHandler cria uma nova instância de EventsHandler
+func NewEventsHandler(subscriber EventSubscriber) *EventsHandler {
+	return &EventsHandler{
+		subscriber: subscriber,
+		heartbeat:  defaultHeartbeatInterval,
+	}
+}
+
+// UserEventResponse representa o dado de um evento SSE
+type UserEventResponse struct {
+	Type       user.EventType `json:"type"`
+	UserID     string         `json:"user_id"`
+	User       *UserResponse  `json:"user,omitempty"`
+	OccurredAt Timestamp      `json:"occurred_at"`
+	RequestID  string         `json:"request_id,omitempty"`
+}
+
+// Stream transmite eventos de criação, atualização e exclusão de usuários
+// @Summary Stream de eventos de usuários
+// @Description Server-Sent Events com user.created, user.updated e user.deleted
+// @Tags users
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Success 200 {object} UserEventResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /users/events [get]
+func (h *EventsHandler) Stream(c *gin.Context) {
+	events, unsubscribe := h.subscriber.Subscribe()
+	defer unsubscribe()
+
+	clearWriteDeadline(c)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // desativa buffering em proxies nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(c.Writer, event); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeSSEEvent serializa um evento no formato text/event-stream
+func writeSSEEvent(w gin.ResponseWriter, event user.Event) error {
+	data := UserEventResponse{
+		Type:       event.Type,
+		UserID:     event.UserID,
+		OccurredAt: NewTimestamp(event.OccurredAt, TimestampRFC3339Nano),
+		RequestID:  event.RequestID,
+	}
+	if event.User != nil {
+		resp := NewUserResponse(event.User, TimestampRFC3339Nano)
+		data.User = &resp
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+	return err
+}
+
+// clearWriteDeadline remove o prazo de escrita (server.write_timeout) da
+// conexão atual. Streams longos terminam quando o cliente desconecta, não
+// quando o prazo da resposta expira
+func clearWriteDeadline(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSubscriber entrega um canal controlado pelo teste
+type fakeSubscriber struct {
+	events       chan user.Event
+	unsubscribed chan struct{}
+}
+
+func (f *fakeSubscriber) Subscribe() (<-chan user.Event, func()) {
+	return f.events, func() { close(f.unsubscribed) }
+}
+
+func TestEventsStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sub := &fakeSubscriber{events: make(chan user.Event, 1), unsubscribed: make(chan struct{})}
+	h := NewEventsHandler(sub)
+	h.heartbeat = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/events", nil).WithContext(ctx)
+
+	sub.events <- user.NewEvent(user.EventUserCreated, &user.User{ID: "42", Email: "a@b.com", Role: user.RoleUser})
+
+	done := make(chan struct{})
+	go func() {
+		h.Stream(c)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel() // simula a desconexão do cliente
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream did not stop after client disconnect")
+	}
+	<-sub.unsubscribed
+
+	body := w.Body.String()
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Contains(t, body, "event: user.created\ndata: {")
+	assert.Contains(t, body, `"user_id":"42"`)
+	assert.True(t, strings.Contains(body, ": heartbeat\n\n"), "expected a heartbeat")
+}
+
+func TestEventsStreamOutlivesWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sub := &fakeSubscriber{events: make(chan user.Event), unsubscribed: make(chan struct{})}
+	h := NewEventsHandler(sub)
+	h.heartbeat = 10 * time.Millisecond
+
+	router := gin.New()
+	router.GET("/api/v1/users/events", h.Stream)
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/users/events")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	// Heartbeats continuam chegando depois do prazo de escrita do servidor
+	deadline := time.Now().Add(200 * time.Millisecond)
+	buf := make([]byte, 64)
+	for time.Now().Before(deadline) {
+		_, err := resp.Body.Read(buf)
+		if !assert.NoError(t, err, "stream was cut by the write timeout") {
+			return
+		}
+	}
+}
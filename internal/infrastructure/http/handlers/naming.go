@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondJSON serializa o DTO na convenção de nomes da requisição (ver
+// middleware.FieldNamingMiddleware). É a saída JSON de todos os handlers, no
+// lugar de c.JSON
+func respondJSON(c *gin.Context, status int, obj any) {
+	middleware.RespondJSON(c, status, obj)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldNamingRoleStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.FieldNamingMiddleware(middleware.FieldNamingCamelCase))
+	router.GET("/stats", func(c *gin.Context) {
+		respondJSON(c, http.StatusOK, RoleStatsResponse{Roles: map[string]int64{"support_agent": 2}, Total: 2})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	// Nomes de papéis são dados, não campos: não mudam na conversão
+	assert.JSONEq(t, `{"roles":{"support_agent":2},"total":2}`, w.Body.String())
+}
+
+func TestFieldNaming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	u, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	u.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	tests := []struct {
+		name    string
+		naming  middleware.FieldNaming
+		accept  string
+		present string
+		absent  string
+	}{
+		{"snake_case by default", "", "", "created_at", "createdAt"},
+		{"camel_case from config", middleware.FieldNamingCamelCase, "", "createdAt", "created_at"},
+		{"accept profile selects camelCase", middleware.FieldNamingSnakeCase, `application/json; profile="camelCase"`, "createdAt", "created_at"},
+		{"accept profile overrides config", middleware.FieldNamingCamelCase, "application/json;profile=snake_case", "created_at", "createdAt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo, _ := newTestHandler()
+			repo.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+
+			router := gin.New()
+			if tt.naming != "" {
+				router.Use(middleware.FieldNamingMiddleware(tt.naming))
+			}
+			router.GET("/users/:id", h.GetUserByID)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/"+u.ID, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Contains(t, body, tt.present)
+			assert.NotContains(t, body, tt.absent)
+		})
+	}
+}
+
+func TestFieldNamingErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.FieldNamingMiddleware(middleware.FieldNamingCamelCase))
+	router.NoRoute(NoRoute)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"ROUTE_NOT_FOUND"`)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/infrastructure/oidc"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCProvider é o provedor externo usado no login OIDC (ver oidc.Provider)
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce string) (string, error)
+	Exchange(ctx context.Context, code, nonce string) (*oidc.Identity, error)
+}
+
+// Cookie que guarda state e nonce entre o redirecionamento e o callback
+const (
+	oidcStateCookie = "oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+)
+
+// WithOIDC habilita o login via provedor OIDC (rotas /auth/oidc/login e
+// /auth/oidc/callback)
+func WithOIDC(provider OIDCProvider) HandlerOption {
+	return func(h *UserHandler) {
+		h.oidc = provider
+	}
+}
+
+// OIDCEnabled informa se o login OIDC foi configurado
+func (h *UserHandler) OIDCEnabled() bool {
+	return h.oidc != nil
+}
+
+// OIDCLogin redireciona para o provedor OIDC
+// @Summary Login OIDC
+// @Description Redireciona para o provedor externo; state e nonce ficam em um cookie HttpOnly de uso único
+// @Tags auth
+// @Success 302 "Redirect to the identity provider"
+// @Failure 502 {object} ErrorResponse
+// @Router /auth/oidc/login [get]
+func (h *UserHandler) OIDCLogin(c *gin.Context) {
+	state, errState := randomToken()
+	nonce, errNonce := randomToken()
+	if errState != nil || errNonce != nil {
+		respondError(c, http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to start login",
+			Message: "Internal server error",
+		})
+		return
+	}
+
+	authURL, err := h.oidc.AuthCodeURL(c.Request.Context(), state, nonce)
+	if err != nil {
+		respondError(c, http.StatusBadGateway, ErrorResponse{
+			Error:   "Failed to start login",
+			Message: "Identity provider unavailable",
+		})
+		return
+	}
+
+	setOIDCStateCookie(c, state+"."+nonce, int(oidcStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback conclui o login OIDC: confere o state, troca o código pelo ID
+// token e emite o token da API para o usuário vinculado (ou criado)
+// @Summary Callback OIDC
+// @Description Valida o state, troca o código no provedor e retorna o token da API
+// @Tags auth
+// @Produce json
+// @Param code query string true "Código de autorização"
+// @Param state query string true "State enviado na autorização"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /auth/oidc/callback [get]
+func (h *UserHandler) OIDCCallback(c *gin.Context) {
+	stored, _ := c.Cookie(oidcStateCookie)
+	// O state é de uso único, inclusive quando o callback falha
+	setOIDCStateCookie(c, "", -1)
+
+	if c.Query("error") != "" {
+		respondError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Authentication failed",
+			Message: "Identity provider denied the login",
+		})
+		return
+	}
+
+	state, nonce, ok := strings.Cut(stored, ".")
+	if !ok || c.Query("state") == "" || subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(state)) != 1 {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Authentication failed",
+			Message: "Invalid or expired login state",
+		})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Authentication failed",
+			Message: "Authorization code is required",
+		})
+		return
+	}
+
+	identity, err := h.oidc.Exchange(c.Request.Context(), code, nonce)
+	if err != nil {
+		status, message := http.StatusBadGateway, "Identity provider unavailable"
+		if errors.Is(err, oidc.ErrExchangeFailed) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			status, message = http.StatusUnauthorized, "Invalid authorization code or ID token"
+		}
+		respondError(c, status, ErrorResponse{
+			Error:   "Authentication failed",
+			Message: message,
+		})
+		return
+	}
+
+	requestID, _ := ctxkeys.RequestID(c)
+	output, err := h.userUseCase.AuthenticateExternal(c.Request.Context(), usecase.ExternalLoginInput{
+		Issuer:        identity.Issuer,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name:          identity.Name,
+		ClientIP:      c.ClientIP(),
+		RequestID:     requestID,
+		UserAgent:     c.Request.UserAgent(),
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Authentication failed",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, NewLoginResponse(output, h.timestampFormat))
+}
+
+// setOIDCStateCookie grava (ou remove, com maxAge negativo) o cookie de state.
+// SameSite=Lax é necessário: o callback chega por um redirecionamento do provedor
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// randomToken gera um valor aleatório de 256 bits para state e nonce
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
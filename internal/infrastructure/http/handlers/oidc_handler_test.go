@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/oidc"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCProvider aceita apenas o código "valid-code" com o nonce enviado na autorização
+type fakeOIDCProvider struct {
+	nonce    string
+	identity *oidc.Identity
+	err      error
+}
+
+func (p *fakeOIDCProvider) AuthCodeURL(_ context.Context, state, nonce string) (string, error) {
+	p.nonce = nonce
+	return "https://accounts.example.com/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (p *fakeOIDCProvider) Exchange(_ context.Context, code, nonce string) (*oidc.Identity, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if code != "valid-code" || nonce != p.nonce {
+		return nil, oidc.ErrInvalidIDToken
+	}
+	return p.identity, nil
+}
+
+// oidcRouter registra as rotas OIDC de um handler com o provedor fake
+func oidcRouter(provider OIDCProvider, repo *mocks.UserRepository, jwtService *mocks.JWTService) *gin.Engine {
+	h := NewUserHandler(usecase.NewUserUseCase(repo, jwtService), WithOIDC(provider))
+	router := gin.New()
+	router.GET("/auth/oidc/login", h.OIDCLogin)
+	router.GET("/auth/oidc/callback", h.OIDCCallback)
+	return router
+}
+
+// startOIDCLogin chama o login e retorna o state da URL e o cookie gravado
+func startOIDCLogin(t *testing.T, router *gin.Engine) (string, *http.Cookie) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return location.Query().Get("state"), cookies[0]
+}
+
+func callback(router *gin.Engine, query string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?"+query, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOIDCLoginSetsStateCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := oidcRouter(&fakeOIDCProvider{}, &mocks.UserRepository{}, &mocks.JWTService{})
+
+	state, cookie := startOIDCLogin(t, router)
+	assert.NotEmpty(t, state)
+	assert.Equal(t, oidcStateCookie, cookie.Name)
+	assert.True(t, strings.HasPrefix(cookie.Value, state+"."))
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+}
+
+func TestOIDCCallbackIssuesToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	linked, err := user.NewExternalUser("ana@example.com", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	linked.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	repo := &mocks.UserRepository{}
+	jwtService := &mocks.JWTService{}
+	repo.On("GetByIdentity", mock.Anything, "https://accounts.example.com", "subject-123").Return(linked, nil)
+	jwtService.On("GenerateToken", linked.ID, linked.Email, "user").Return("signed-token", nil)
+	repo.On("RecordLogin", mock.Anything, linked.ID, mock.Anything).Return(nil)
+	jwtService.On("ExpiresIn").Return(time.Hour)
+
+	provider := &fakeOIDCProvider{identity: &oidc.Identity{
+		Issuer:        "https://accounts.example.com",
+		Subject:       "subject-123",
+		Email:         "ana@example.com",
+		EmailVerified: true,
+	}}
+	router := oidcRouter(provider, repo, jwtService)
+	state, cookie := startOIDCLogin(t, router)
+
+	w := callback(router, "code=valid-code&state="+url.QueryEscape(state), cookie)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "signed-token", resp.Token)
+
+	// O cookie de state é removido após o uso
+	cleared := w.Result().Cookies()
+	require.Len(t, cleared, 1)
+	assert.Equal(t, oidcStateCookie, cleared[0].Name)
+	assert.Negative(t, cleared[0].MaxAge)
+}
+
+func TestOIDCCallbackRejections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("state mismatch", func(t *testing.T) {
+		router := oidcRouter(&fakeOIDCProvider{}, &mocks.UserRepository{}, &mocks.JWTService{})
+		_, cookie := startOIDCLogin(t, router)
+		w := callback(router, "code=valid-code&state=forged", cookie)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing state cookie", func(t *testing.T) {
+		router := oidcRouter(&fakeOIDCProvider{}, &mocks.UserRepository{}, &mocks.JWTService{})
+		state, _ := startOIDCLogin(t, router)
+		w := callback(router, "code=valid-code&state="+url.QueryEscape(state), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("provider denied", func(t *testing.T) {
+		router := oidcRouter(&fakeOIDCProvider{}, &mocks.UserRepository{}, &mocks.JWTService{})
+		state, cookie := startOIDCLogin(t, router)
+		w := callback(router, "error=access_denied&state="+url.QueryEscape(state), cookie)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		router := oidcRouter(&fakeOIDCProvider{}, &mocks.UserRepository{}, &mocks.JWTService{})
+		state, cookie := startOIDCLogin(t, router)
+		w := callback(router, "code=stolen-code&state="+url.QueryEscape(state), cookie)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("provider unavailable", func(t *testing.T) {
+		provider := &fakeOIDCProvider{err: context.DeadlineExceeded}
+		router := oidcRouter(provider, &mocks.UserRepository{}, &mocks.JWTService{})
+		state, cookie := startOIDCLogin(t, router)
+		w := callback(router, "code=valid-code&state="+url.QueryEscape(state), cookie)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+
+	t.Run("unverified email", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		repo.On("GetByIdentity", mock.Anything, "https://accounts.example.com", "subject-123").Return(nil, user.ErrUserNotFound)
+		provider := &fakeOIDCProvider{identity: &oidc.Identity{
+			Issuer:  "https://accounts.example.com",
+			Subject: "subject-123",
+			Email:   "ana@example.com",
+		}}
+		router := oidcRouter(provider, repo, &mocks.JWTService{})
+		state, cookie := startOIDCLogin(t, router)
+		w := callback(router, "code=valid-code&state="+url.QueryEscape(state), cookie)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
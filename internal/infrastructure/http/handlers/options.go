@@ -0,0 +1,33 @@
+package handlers
+
+// HandlerOption configura comportamentos opcionais do UserHandler
+type HandlerOption func(*UserHandler)
+
+// WithTimestampFormat define o formato das datas nas respostas
+func WithTimestampFormat(format TimestampFormat) HandlerOption {
+	return func(h *UserHandler) {
+		h.timestampFormat = format
+	}
+}
+
+// DefaultMaxLoginBodyBytes limita o corpo do login; credenciais reais ocupam
+// poucas centenas de bytes
+const DefaultMaxLoginBodyBytes = 4 << 10
+
+// WithMaxLoginBodyBytes define o tamanho máximo do corpo do login; acima dele a
+// resposta é 413. Valores não positivos mantêm DefaultMaxLoginBodyBytes
+func WithMaxLoginBodyBytes(n int64) HandlerOption {
+	return func(h *UserHandler) {
+		if n > 0 {
+			h.maxLoginBytes = n
+		}
+	}
+}
+
+// WithStrictJSON rejeita campos JSON desconhecidos nos corpos das requisições
+// em vez de ignorá-los
+func WithStrictJSON(strict bool) HandlerOption {
+	return func(h *UserHandler) {
+		h.strictJSON = strict
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Códigos de erro estruturados
+const (
+	CodeInvalidPagination = "INVALID_PAGINATION"
+)
+
+// Valores padrão de paginação
+const (
+	defaultLimit = 10
+	// maxPaginationValue limita offset e limit, que chegam ao SQL como int4
+	maxPaginationValue = math.MaxInt32
+)
+
+// Pagination representa os parâmetros de paginação já normalizados em offset/limit
+type Pagination struct {
+	Offset int
+	Limit  int
+}
+
+// parsePagination lê offset/limit ou, alternativamente, page/per_page.
+// Retorna a lista de problemas encontrados, nomeando o parâmetro inválido
+func parsePagination(c *gin.Context) (Pagination, []string) {
+	_, hasOffset := c.GetQuery("offset")
+	_, hasLimit := c.GetQuery("limit")
+	_, hasPage := c.GetQuery("page")
+	_, hasPerPage := c.GetQuery("per_page")
+
+	if (hasOffset || hasLimit) && (hasPage || hasPerPage) {
+		return Pagination{}, []string{"offset/limit cannot be combined with page/per_page"}
+	}
+
+	var details []string
+	if hasPage || hasPerPage {
+		page, ok := parseQueryInt(c, "page", 1, 1)
+		if !ok {
+			details = append(details, "page must be an integer between 1 and 2147483647")
+		}
+		perPage, ok := parseQueryInt(c, "per_page", defaultLimit, 1)
+		if !ok {
+			details = append(details, "per_page must be an integer between 1 and 2147483647")
+		}
+		if len(details) > 0 {
+			return Pagination{}, details
+		}
+		// (page - 1) * perPage não pode estourar nem passar do limite do SQL
+		if page-1 > maxPaginationValue/perPage {
+			return Pagination{}, []string{"page is too large for the given per_page"}
+		}
+		return Pagination{Offset: (page - 1) * perPage, Limit: perPage}, nil
+	}
+
+	offset, ok := parseQueryInt(c, "offset", 0, 0)
+	if !ok {
+		details = append(details, "offset must be an integer between 0 and 2147483647")
+	}
+	limit, ok := parseQueryInt(c, "limit", defaultLimit, 1)
+	if !ok {
+		details = append(details, "limit must be an integer between 1 and 2147483647")
+	}
+	if len(details) > 0 {
+		return Pagination{}, details
+	}
+
+	return Pagination{Offset: offset, Limit: limit}, nil
+}
+
+// parseQueryInt lê um inteiro da query, usando o padrão quando ausente.
+// Valores acima de maxPaginationValue são inválidos
+func parseQueryInt(c *gin.Context, name string, defaultValue, minValue int) (int, bool) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return defaultValue, true
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < minValue || value > maxPaginationValue {
+		return 0, false
+	}
+
+	return value, true
+}
+
+// bindPagination lê a paginação da query; parâmetros inválidos já são
+// respondidos com 400 e retornam false
+func bindPagination(c *gin.Context) (Pagination, bool) {
+	pagination, details := parsePagination(c)
+	if len(details) > 0 {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid pagination",
+			Message: "Invalid pagination parameters",
+			Code:    CodeInvalidPagination,
+			Details: details,
+		})
+		return Pagination{}, false
+	}
+	return pagination, true
+}
+
+// respondPaged lê a paginação uma única vez, executa a consulta e escreve a
+// resposta paginada padrão. failure é o título do erro quando a consulta falha
+func (h *UserHandler) respondPaged(c *gin.Context, failure string, query func(Pagination) (*usecase.PagedUsers, error)) {
+	pagination, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+
+	output, err := query(pagination)
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   failure,
+			Message: message,
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, NewPagedUsersResponse(output, h.timestampFormat))
+}
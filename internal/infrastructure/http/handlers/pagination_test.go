@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected Pagination
+		details  []string
+	}{
+		{"defaults", "", Pagination{Offset: 0, Limit: 10}, nil},
+		{"offset and limit", "?offset=20&limit=5", Pagination{Offset: 20, Limit: 5}, nil},
+		{"page and per_page", "?page=3&per_page=25", Pagination{Offset: 50, Limit: 25}, nil},
+		{"page only", "?page=2", Pagination{Offset: 10, Limit: 10}, nil},
+		{"negative offset", "?offset=-1", Pagination{}, []string{"offset must be an integer between 0 and 2147483647"}},
+		{"invalid limit and offset", "?offset=x&limit=0", Pagination{}, []string{
+			"offset must be an integer between 0 and 2147483647",
+			"limit must be an integer between 1 and 2147483647",
+		}},
+		{"invalid page", "?page=0", Pagination{}, []string{"page must be an integer between 1 and 2147483647"}},
+		{"page overflowing the offset", "?page=4611686018427387904&per_page=100", Pagination{}, []string{"page must be an integer between 1 and 2147483647"}},
+		{"offset past the SQL range", "?page=2147483647&per_page=100", Pagination{}, []string{"page is too large for the given per_page"}},
+		{"last reachable page", "?page=21474837&per_page=100", Pagination{Offset: 2147483600, Limit: 100}, nil},
+		{"huge limit", "?limit=3000000000", Pagination{}, []string{"limit must be an integer between 1 and 2147483647"}},
+		{"conflicting params", "?offset=0&page=1", Pagination{}, []string{"offset/limit cannot be combined with page/per_page"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil)
+
+			pagination, details := parsePagination(c)
+			assert.Equal(t, tt.expected, pagination)
+			assert.Equal(t, tt.details, details)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeExpiredPasswordRequest representa a troca de uma senha vencida. Como no
+// login, username pode ser informado no lugar do email
+type ChangeExpiredPasswordRequest struct {
+	Email           string `json:"email" binding:"required_without=Username,omitempty,email"`
+	Username        string `json:"username,omitempty" binding:"required_without=Email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// PasswordExpirationEnabled informa se a expiração de senhas foi habilitada no
+// caso de uso (usecase.WithPasswordMaxAge)
+func (h *UserHandler) PasswordExpirationEnabled() bool {
+	return h.userUseCase != nil && h.userUseCase.PasswordExpirationEnabled()
+}
+
+// ChangeExpiredPassword troca a senha vencida e conclui o login
+// @Summary Trocar senha expirada
+// @Description Endpoint público para quando o login responde 403 com code PASSWORD_EXPIRED: confere a senha atual,
+// @Description define a nova, encerra as sessões anteriores e responde como /auth/login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body ChangeExpiredPasswordRequest true "Credenciais atuais e nova senha"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/password/expired [post]
+func (h *UserHandler) ChangeExpiredPassword(c *gin.Context) {
+	// Público como o login: corpos grandes são recusados antes do parse
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxLoginBytes)
+
+	var req ChangeExpiredPasswordRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	requestID, _ := ctxkeys.RequestID(c)
+	output, err := h.userUseCase.ChangeExpiredPassword(c.Request.Context(), usecase.ChangeExpiredPasswordInput{
+		Email:           req.Email,
+		Username:        req.Username,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+		ClientIP:        c.ClientIP(),
+		RequestID:       requestID,
+		UserAgent:       c.Request.UserAgent(),
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to change password",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, NewLoginResponse(output, h.timestampFormat))
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiredPasswordFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	u, err := user.NewUser("login@example.com", "password123", "Login User", user.RoleUser)
+	require.NoError(t, err)
+	u.ID = "1"
+	changed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	u.PasswordChangedAt = &changed
+
+	repo := &mocks.UserRepository{}
+	repo.On("GetByEmail", mock.Anything, u.Email).Return(u, nil)
+	repo.On("ReplacePassword", mock.Anything, u, mock.Anything).Return(true, nil)
+	repo.On("IncrementTokenVersion", mock.Anything, u.ID).Return(2, nil)
+	repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+
+	maxAge := 90 * 24 * time.Hour
+	uc := usecase.NewUserUseCase(repo, auth.NewJWTService("test-secret", time.Hour),
+		usecase.WithClock(clock.NewFake(changed.Add(maxAge))),
+		usecase.WithPasswordMaxAge(maxAge))
+	h := NewUserHandler(uc)
+	require.True(t, h.PasswordExpirationEnabled())
+
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+	router.POST("/auth/password/expired", h.ChangeExpiredPassword)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/auth/login", `{"email":"login@example.com","password":"password123"}`)
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, CodePasswordExpired, errResp.Code)
+
+	w = post("/auth/password/expired", `{"email":"login@example.com","current_password":"password123","new_password":"password123"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = post("/auth/password/expired", `{"email":"login@example.com","current_password":"password123","new_password":"new-password456"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.Token)
+
+	w = post("/auth/login", `{"email":"login@example.com","password":"new-password456"}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
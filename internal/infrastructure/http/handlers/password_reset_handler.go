@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limite padrão de pedidos e confirmações de redefinição de senha por IP
+const (
+	DefaultPasswordResetLimit  = 5
+	DefaultPasswordResetWindow = 15 * time.Minute
+)
+
+// WithPasswordResetLimiter define o limiter da redefinição de senha: pedidos
+// são consultados por IP e por email, confirmações por IP. Sem esta opção é
+// usado um limiter em memória de DefaultPasswordResetLimit requisições por
+// DefaultPasswordResetWindow
+func WithPasswordResetLimiter(limiter middleware.RateLimiter) HandlerOption {
+	return func(h *UserHandler) {
+		if limiter != nil {
+			h.resetLimiter = limiter
+		}
+	}
+}
+
+// PasswordResetEnabled informa se a redefinição de senha foi configurada no
+// caso de uso (usecase.WithPasswordReset)
+func (h *UserHandler) PasswordResetEnabled() bool {
+	return h.userUseCase != nil && h.userUseCase.PasswordResetEnabled()
+}
+
+// RequestPasswordResetRequest representa o pedido do link de redefinição
+type RequestPasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ConfirmPasswordResetRequest representa a redefinição com o token do link
+type ConfirmPasswordResetRequest struct {
+	Email       string `json:"email" binding:"required,email"`
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// resetAcceptedMessage é a resposta de todo pedido aceito, exista a conta ou não
+const resetAcceptedMessage = "If the email belongs to an account with a password, a reset link has been sent"
+
+// RequestPasswordReset envia o link de redefinição de senha
+// @Summary Pedir redefinição de senha
+// @Description Envia um link se o email pertence a uma conta ativa com senha, invalidando o anterior. A resposta é sempre 200 para não revelar quais emails estão cadastrados
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RequestPasswordResetRequest true "Email da conta"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /auth/password/reset [post]
+func (h *UserHandler) RequestPasswordReset(c *gin.Context) {
+	var req RequestPasswordResetRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	email := user.NormalizeEmail(req.Email)
+	if !h.allowPasswordReset(c, "reset-request:ip:"+c.ClientIP(), "reset-request:email:"+usecase.HashIdentifier(email)) {
+		respondError(c, http.StatusTooManyRequests, ErrorResponse{
+			Error:   "Rate limit exceeded",
+			Message: "Too many password reset emails requested, please try again later",
+		})
+		return
+	}
+
+	h.userUseCase.RequestPasswordReset(c.Request.Context(), email)
+	respondJSON(c, http.StatusOK, MessageResponse{Message: resetAcceptedMessage})
+}
+
+// ConfirmPasswordReset redefine a senha com o token do link
+// @Summary Confirmar redefinição de senha
+// @Description Define a nova senha com o token recebido e encerra todas as sessões. O token é de uso único e é
+// @Description invalidado após security.reset_token_max_attempts confirmações erradas (code RESET_TOKEN_EXHAUSTED)
+// @Tags auth
+// @Accept json
+// @Param request body ConfirmPasswordResetRequest true "Email, token do link e nova senha"
+// @Success 204 "Password reset"
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /auth/password/reset/confirm [post]
+func (h *UserHandler) ConfirmPasswordReset(c *gin.Context) {
+	// Palpites de token são limitados por IP antes de qualquer consulta
+	if !h.allowPasswordReset(c, "reset-confirm:ip:"+c.ClientIP()) {
+		respondError(c, http.StatusTooManyRequests, ErrorResponse{
+			Error:   "Rate limit exceeded",
+			Message: "Too many password reset attempts, please try again later",
+		})
+		return
+	}
+
+	var req ConfirmPasswordResetRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	err := h.userUseCase.ConfirmPasswordReset(c.Request.Context(), usecase.ConfirmPasswordResetInput{
+		Email:       req.Email,
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to reset password",
+			Message: message,
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// allowPasswordReset consulta o limiter com cada chave; todas precisam
+// permitir. Falhas do backend liberam a requisição, como o rate limit global
+func (h *UserHandler) allowPasswordReset(c *gin.Context, keys ...string) bool {
+	ctx := c.Request.Context()
+	for _, key := range keys {
+		if allowed, err := h.resetLimiter.Allow(ctx, key); err == nil && !allowed {
+			return false
+		}
+	}
+	return true
+}
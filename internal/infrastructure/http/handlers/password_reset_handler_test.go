@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingResetNotifier guarda os links de redefinição enviados
+type recordingResetNotifier struct {
+	sent []string
+}
+
+func (n *recordingResetNotifier) SendPasswordResetEmail(_ context.Context, to, _, _ string) error {
+	n.sent = append(n.sent, to)
+	return nil
+}
+
+// passwordResetRouter registra as rotas de redefinição com as opções informadas
+func passwordResetRouter(t *testing.T, repo *mocks.UserRepository, notifier *recordingResetNotifier, opts ...HandlerOption) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	issuer, err := auth.NewResetTokenIssuer(0, time.Hour, nil)
+	require.NoError(t, err)
+
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithPasswordReset(issuer, notifier))
+	h := NewUserHandler(uc, opts...)
+	require.True(t, h.PasswordResetEnabled())
+
+	router := gin.New()
+	// O pedido roda em segundo plano; a resposta só volta ao teste depois dele
+	router.Use(func(c *gin.Context) {
+		c.Next()
+		uc.Wait()
+	})
+	router.POST("/auth/password/reset", h.RequestPasswordReset)
+	router.POST("/auth/password/reset/confirm", h.ConfirmPasswordReset)
+	return router
+}
+
+func postPasswordReset(router *gin.Engine, path, body, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+const confirmResetBody = `{"email":"ana@example.com","token":"token","new_password":"new-password"}`
+
+func TestRequestPasswordResetHandler(t *testing.T) {
+	repo := &mocks.UserRepository{}
+	notifier := &recordingResetNotifier{}
+	router := passwordResetRouter(t, repo, notifier)
+
+	u := &user.User{ID: "42", Email: "ana@example.com", Name: "Ana", IsActive: true}
+	require.NoError(t, u.SetPassword("old-password"))
+	repo.On("GetByEmail", mock.Anything, "ana@example.com").Return(u, nil)
+	repo.On("GetByEmail", mock.Anything, "ghost@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("ReplacePasswordResetToken", mock.Anything, mock.Anything).Return(nil).Once()
+
+	known := postPasswordReset(router, "/auth/password/reset", `{"email":"Ana@Example.com"}`, "10.0.0.1")
+	unknown := postPasswordReset(router, "/auth/password/reset", `{"email":"ghost@example.com"}`, "10.0.0.1")
+
+	// A resposta não revela se a conta existe
+	assert.Equal(t, http.StatusOK, known.Code)
+	assert.Equal(t, known.Body.String(), unknown.Body.String())
+	assert.Equal(t, []string{"ana@example.com"}, notifier.sent)
+	repo.AssertExpectations(t)
+}
+
+func TestConfirmPasswordResetErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		hash     string
+		attempts int
+		expires  time.Duration
+		code     string
+	}{
+		{"wrong token", "other-hash", 1, time.Hour, CodeResetTokenInvalid},
+		{"expired token", auth.HashResetToken("token"), 1, -time.Minute, CodeResetTokenExpired},
+		{"exhausted token", auth.HashResetToken("token"), auth.DefaultResetTokenMaxAttempts + 1, time.Hour, CodeResetTokenExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.UserRepository{}
+			router := passwordResetRouter(t, repo, &recordingResetNotifier{})
+
+			u := &user.User{ID: "42", Email: "ana@example.com", IsActive: true}
+			require.NoError(t, u.SetPassword("old-password"))
+			repo.On("GetByEmail", mock.Anything, "ana@example.com").Return(u, nil)
+			repo.On("GetPasswordResetToken", mock.Anything, "42").
+				Return(&user.PasswordResetToken{Hash: tt.hash, UserID: "42", ExpiresAt: time.Now().Add(tt.expires)}, nil)
+			repo.On("IncrementResetAttempts", mock.Anything, tt.hash).Return(tt.attempts, nil)
+			repo.On("ConsumePasswordResetToken", mock.Anything, tt.hash).Return(nil)
+
+			w := postPasswordReset(router, "/auth/password/reset/confirm", confirmResetBody, "10.0.0.1")
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.code)
+			repo.AssertNotCalled(t, "ReplacePassword", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestConfirmPasswordResetRateLimit(t *testing.T) {
+	repo := &mocks.UserRepository{}
+	repo.On("GetByEmail", mock.Anything, "ana@example.com").Return(nil, user.ErrUserNotFound)
+	router := passwordResetRouter(t, repo, &recordingResetNotifier{}, WithPasswordResetLimiter(middleware.NewMemoryRateLimiter(2, time.Hour)))
+
+	assert.Equal(t, http.StatusBadRequest, postPasswordReset(router, "/auth/password/reset/confirm", confirmResetBody, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusBadRequest, postPasswordReset(router, "/auth/password/reset/confirm", confirmResetBody, "10.0.0.1").Code)
+	w := postPasswordReset(router, "/auth/password/reset/confirm", confirmResetBody, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// O limite é por IP, e o bloqueio acontece antes de consultar a conta
+	assert.Equal(t, http.StatusBadRequest, postPasswordReset(router, "/auth/password/reset/confirm", confirmResetBody, "10.0.0.2").Code)
+	repo.AssertNumberOfCalls(t, "GetByEmail", 3)
+}
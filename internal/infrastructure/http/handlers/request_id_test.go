@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/infrastructure/webhooks"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/config"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDReachesDispatchedWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	// O dispatcher usa o mesmo header configurado no middleware
+	const header = "X-Correlation-ID"
+	dispatcher := webhooks.NewDispatcher(config.WebhooksConfig{URLs: []string{receiver.URL}},
+		slog.New(slog.NewTextHandler(io.Discard, nil)), webhooks.WithRequestIDHeader(header))
+
+	repo := &mocks.UserRepository{}
+	repo.On("ExistsByEmail", mock.Anything, "ana@example.com").Return(false, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	h := NewUserHandler(usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEventPublisher(dispatcher)))
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware(middleware.RequestIDConfig{Header: header}))
+	router.POST("/users", h.CreateUser)
+
+	body := `{"email":"ana@example.com","password":"password123","name":"Ana","role":"user"}`
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set(header, "req-abc")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var delivered *http.Request
+	var payload webhooks.Payload
+	select {
+	case delivered = <-received:
+		require.NoError(t, json.Unmarshal(<-bodies, &payload))
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+	require.NoError(t, dispatcher.Close(context.Background()))
+
+	assert.Equal(t, "req-abc", delivered.Header.Get(header))
+	assert.Empty(t, delivered.Header.Get(webhooks.HeaderRequestID))
+	assert.Equal(t, "req-abc", payload.RequestID)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+)
+
+// UserResponse é a representação HTTP de um usuário.
+// É a fronteira entre o domínio e a API: novos campos da entidade só são
+// expostos quando adicionados aqui explicitamente
+type UserResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      user.Role `json:"role"`
+	IsActive  bool      `json:"is_active"`
+	IsAdmin   bool      `json:"is_admin"`
+	CreatedAt Timestamp `json:"created_at" swaggertype:"string"`
+	UpdatedAt Timestamp `json:"updated_at" swaggertype:"string"`
+	// EmailVerified indica se o email atual foi confirmado pelo link de verificação
+	EmailVerified bool `json:"email_verified"`
+	// Username é omitido quando o usuário não tem nome de usuário
+	Username string `json:"username,omitempty"`
+	// LastLoginAt é omitido enquanto o usuário nunca fez login
+	LastLoginAt *Timestamp `json:"last_login_at,omitempty" swaggertype:"string"`
+}
+
+// PagedUsersResponse é a resposta comum das consultas paginadas (listagem e busca)
+type PagedUsersResponse struct {
+	Users  []UserResponse `json:"users"`
+	Total  int64          `json:"total"`
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+}
+
+// BulkItemResponse é o resultado de um item de uma operação em lote. Status é o
+// código HTTP que o item receberia como requisição individual; Error segue o
+// formato padrão de erro e só aparece em itens que falharam
+type BulkItemResponse struct {
+	Index  int            `json:"index"`
+	ID     string         `json:"id,omitempty"`
+	Status int            `json:"status"`
+	Result string         `json:"result"`
+	User   *UserResponse  `json:"user,omitempty"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+// BulkSummary agrega os resultados dos itens de uma operação em lote
+type BulkSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// ImportEmailItem é a situação de um email na pré-validação de importação
+type ImportEmailItem struct {
+	Index int `json:"index"`
+	// Email é o valor normalizado, como seria gravado
+	Email  string `json:"email"`
+	Status string `json:"status"`
+}
+
+// ValidateImportSummary agrega a pré-validação; Invalid conta todo email não valid
+type ValidateImportSummary struct {
+	Total   int `json:"total"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+}
+
+// ValidateImportResponse traz a situação de cada email, na ordem de entrada
+type ValidateImportResponse struct {
+	Items   []ImportEmailItem     `json:"items"`
+	Summary ValidateImportSummary `json:"summary"`
+}
+
+// BulkResponse é o formato comum das operações em lote. A resposta é 200
+// mesmo com falhas parciais: o resultado de cada item está em Items
+type BulkResponse struct {
+	Items   []BulkItemResponse `json:"items"`
+	Summary BulkSummary        `json:"summary"`
+}
+
+// BulkUpdateRolesResponse é o resultado da troca de roles em lote. Os contadores
+// são mantidos por compatibilidade; Items e Summary seguem BulkResponse
+type BulkUpdateRolesResponse struct {
+	BulkResponse
+	Updated  int      `json:"updated"`
+	Skipped  int      `json:"skipped"`
+	NotFound []string `json:"not_found"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// UserStatsResponse é o total de cadastros em um intervalo
+type UserStatsResponse struct {
+	From  Timestamp `json:"from" swaggertype:"string"`
+	To    Timestamp `json:"to" swaggertype:"string"`
+	Count int64     `json:"count"`
+}
+
+// RoleStatsResponse é o total de usuários por papel
+type RoleStatsResponse struct {
+	Roles map[string]int64 `json:"roles"`
+	Total int64            `json:"total"`
+}
+
+// UserDataExportResponse é o pacote de dados pessoais de um usuário. Reaproveita
+// UserResponse, então hash de senha e campos internos nunca são incluídos
+type UserDataExportResponse struct {
+	ExportedAt Timestamp    `json:"exported_at" swaggertype:"string"`
+	Profile    UserResponse `json:"profile"`
+}
+
+// SessionResponse é uma sessão ativa do usuário autenticado. Browser, OS e
+// Device são extraídos do user-agent do login e omitidos quando não reconhecidos
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	IssuedAt  Timestamp `json:"issued_at" swaggertype:"string"`
+	ExpiresAt Timestamp `json:"expires_at" swaggertype:"string"`
+	// Current indica a sessão do token usado na requisição
+	Current   bool   `json:"current"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Browser   string `json:"browser,omitempty"`
+	OS        string `json:"os,omitempty"`
+	Device    string `json:"device,omitempty"`
+}
+
+// SessionsResponse é uma página das sessões ativas, da mais recente para a mais
+// antiga, no formato das demais respostas paginadas
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+	Total    int64             `json:"total"`
+	Offset   int               `json:"offset"`
+	Limit    int               `json:"limit"`
+}
+
+// TokenTypeBearer é o tipo de token retornado no login, usado no header Authorization
+const TokenTypeBearer = "Bearer"
+
+// LoginResponse representa a resposta do login
+type LoginResponse struct {
+	User      UserResponse `json:"user"`
+	Token     string       `json:"token"`
+	TokenType string       `json:"token_type"`
+	ExpiresIn int64        `json:"expires_in"` // segundos até a expiração do token
+}
+
+// NewUserResponse converte a entidade de domínio para a representação HTTP
+func NewUserResponse(u *user.User, format TimestampFormat) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      u.Name,
+		Role:      u.Role,
+		IsActive:  u.IsActive,
+		IsAdmin:   u.IsAdmin(),
+		CreatedAt: NewTimestamp(u.CreatedAt, format),
+		UpdatedAt: NewTimestamp(u.UpdatedAt, format),
+
+		EmailVerified: u.IsEmailVerified(),
+		Username:      u.Username,
+		LastLoginAt:   optionalTimestamp(u.LastLoginAt, format),
+	}
+}
+
+// optionalTimestamp converte um momento opcional do domínio; nil permanece nil
+func optionalTimestamp(t *time.Time, format TimestampFormat) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := NewTimestamp(*t, format)
+	return &ts
+}
+
+// NewPagedUsersResponse converte um resultado paginado para a representação HTTP
+func NewPagedUsersResponse(output *usecase.PagedUsers, format TimestampFormat) PagedUsersResponse {
+	response := PagedUsersResponse{
+		Users:  make([]UserResponse, len(output.Users)),
+		Total:  output.Total,
+		Offset: output.Offset,
+		Limit:  output.Limit,
+	}
+
+	for i, u := range output.Users {
+		response.Users[i] = NewUserResponse(u, format)
+	}
+
+	return response
+}
+
+// NewBulkUpdateRolesResponse converte o resultado do lote para a representação HTTP
+func NewBulkUpdateRolesResponse(output *usecase.BulkUpdateRolesOutput) BulkUpdateRolesResponse {
+	return BulkUpdateRolesResponse{
+		Updated:  output.Updated,
+		Skipped:  output.Skipped,
+		NotFound: emptyIfNil(output.NotFound),
+		DryRun:   output.DryRun,
+	}
+}
+
+// emptyIfNil garante que uma lista vazia serialize como [] e não como null.
+// Toda lista das respostas deve ser criada com make ou passar por aqui, já que
+// qualquer caminho que devolva um slice nil vira null no JSON
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// NewLoginResponse converte o resultado da autenticação para a representação HTTP
+func NewLoginResponse(output *usecase.AuthenticateUserOutput, format TimestampFormat) LoginResponse {
+	return LoginResponse{
+		User:      NewUserResponse(output.User, format),
+		Token:     output.Token,
+		TokenType: TokenTypeBearer,
+		ExpiresIn: int64(output.ExpiresIn / time.Second),
+	}
+}
+
+// NewUserDataExportResponse converte o pacote de dados para a representação HTTP
+func NewUserDataExportResponse(export *usecase.UserDataExport, format TimestampFormat) UserDataExportResponse {
+	return UserDataExportResponse{
+		ExportedAt: NewTimestamp(export.ExportedAt, format),
+		Profile:    NewUserResponse(export.User, format),
+	}
+}
+
+// NewSessionsResponse converte as sessões para a representação HTTP, marcando a
+// sessão currentID
+func NewSessionsResponse(output *usecase.ListSessionsOutput, currentID string, format TimestampFormat) SessionsResponse {
+	sessions := make([]SessionResponse, 0, len(output.Sessions))
+	for _, s := range output.Sessions {
+		device := parseUserAgent(s.UserAgent)
+		sessions = append(sessions, SessionResponse{
+			ID:        s.ID,
+			IssuedAt:  NewTimestamp(s.IssuedAt, format),
+			ExpiresAt: NewTimestamp(s.ExpiresAt, format),
+			Current:   s.ID == currentID,
+			IP:        s.IP,
+			UserAgent: s.UserAgent,
+			Browser:   device.Browser,
+			OS:        device.OS,
+			Device:    device.Device,
+		})
+	}
+	return SessionsResponse{
+		Sessions: sessions,
+		Total:    output.Total,
+		Offset:   output.Offset,
+		Limit:    output.Limit,
+	}
+}
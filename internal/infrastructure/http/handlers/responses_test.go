@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUserResponseOmitsSensitiveFields(t *testing.T) {
+	u := &user.User{
+		ID:        "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60",
+		Email:     "admin@example.com",
+		Password:  "$2a$10$hashedpassword",
+		Name:      "Admin",
+		Role:      user.RoleAdmin,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(NewUserResponse(u, TimestampRFC3339))
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+
+	assert.NotContains(t, fields, "password")
+	assert.NotContains(t, string(data), u.Password)
+	assert.Equal(t, true, fields["is_admin"])
+	assert.Equal(t, u.Email, fields["email"])
+}
+
+func TestNewLoginResponse(t *testing.T) {
+	u := &user.User{ID: "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60", Email: "user@example.com", Role: user.RoleUser}
+
+	resp := NewLoginResponse(&usecase.AuthenticateUserOutput{User: u, Token: "signed-token", ExpiresIn: 24 * time.Hour}, TimestampRFC3339)
+
+	assert.Equal(t, "signed-token", resp.Token)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int64(86400), resp.ExpiresIn)
+	assert.Equal(t, u.ID, resp.User.ID)
+}
+
+// TestEmptyListsSerializeAsArrays garante que listas vazias nas respostas
+// nunca aparecem como null, mesmo quando o repositório devolve slices nil
+func TestEmptyListsSerializeAsArrays(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("ListActive", mock.Anything, 0, 10).Return(nil, nil)
+	repo.On("CountActive", mock.Anything).Return(int64(0), nil)
+	repo.On("Search", mock.Anything, "nobody", false, 0, 10).Return(nil, nil)
+	repo.On("CountSearch", mock.Anything, "nobody", false).Return(int64(0), nil)
+	repo.On("UpdateRoles", mock.Anything, []string{"1"}, user.RoleAdmin).Return(&repository.RoleUpdateResult{
+		Updated: []string{"1"},
+	}, nil)
+
+	router := gin.New()
+	router.GET("/users", h.ListUsers)
+	router.GET("/users/search", h.SearchUsers)
+	router.POST("/users/bulk-role", h.BulkUpdateRoles)
+
+	tests := []struct {
+		name  string
+		req   *http.Request
+		field string
+	}{
+		{"list", httptest.NewRequest(http.MethodGet, "/users", nil), "users"},
+		{"search", httptest.NewRequest(http.MethodGet, "/users/search?q=nobody", nil), "users"},
+		{"bulk role without missing ids", httptest.NewRequest(http.MethodPost, "/users/bulk-role",
+			strings.NewReader(`{"user_ids":["1"],"role":"admin"}`)), "not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var body map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.JSONEq(t, `[]`, string(body[tt.field]))
+		})
+	}
+}
+
+func TestEmptyIfNil(t *testing.T) {
+	data, err := json.Marshal(BulkResponse{Items: emptyIfNil[BulkItemResponse](nil)})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"items":[]`)
+
+	items := []string{"a"}
+	assert.Equal(t, items, emptyIfNil(items))
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/validator"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoleValidationAgrees garante que domínio, handler e validator aceitam o mesmo conjunto de papéis
+func TestRoleValidationAgrees(t *testing.T) {
+	require.NoError(t, user.RegisterRoles("auditor"))
+	t.Cleanup(user.ResetRoles)
+
+	h, _, _ := newTestHandler()
+	v := validator.NewCustomValidator(validator.WithRoles(user.RoleNames))
+
+	candidates := []string{"admin", "user", "guest", "auditor", "moderator", "", "Admin"}
+	for _, role := range candidates {
+		t.Run(role, func(t *testing.T) {
+			domainOK := user.IsValidRole(user.Role(role))
+
+			_, err := h.validateRole(role)
+			handlerOK := err == nil
+
+			validatorOK := v.ValidateVar(role, "role") == nil
+
+			assert.Equal(t, domainOK, handlerOK, "handler disagrees with domain")
+			assert.Equal(t, domainOK, validatorOK, "validator disagrees with domain")
+		})
+	}
+
+	assert.True(t, user.IsValidRole("auditor"))
+	assert.False(t, user.IsValidRole("moderator"))
+}
+
+func TestRegisterRolesRejectsInvalidNames(t *testing.T) {
+	for _, name := range []string{"", "Admin", "has space", "1role"} {
+		assert.ErrorIs(t, user.RegisterRoles(name), user.ErrInvalidRole, name)
+	}
+}
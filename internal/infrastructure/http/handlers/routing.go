@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Códigos de erro de roteamento
+const (
+	CodeRouteNotFound    = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+)
+
+// NoRoute responde rotas inexistentes no formato padrão de erro
+func NoRoute(c *gin.Context) {
+	respondError(c, http.StatusNotFound, ErrorResponse{
+		Error:   "Route not found",
+		Message: fmt.Sprintf("No route for %s %s", c.Request.Method, c.Request.URL.Path),
+		Code:    CodeRouteNotFound,
+	})
+}
+
+// NoMethod responde métodos não suportados por uma rota existente no formato
+// padrão de erro. O header Allow já vem preenchido pelo gin com os métodos do
+// caminho; quando o único é OPTIONS (preflight curinga do CORS), o caminho não
+// existe de fato e a resposta é 404
+func NoMethod(c *gin.Context) {
+	allow := c.Writer.Header().Get("Allow")
+	if allow == "" || allow == http.MethodOptions {
+		c.Writer.Header().Del("Allow")
+		NoRoute(c)
+		return
+	}
+
+	respondError(c, http.StatusMethodNotAllowed, ErrorResponse{
+		Error:   "Method not allowed",
+		Message: fmt.Sprintf("Method %s is not allowed for %s", c.Request.Method, c.Request.URL.Path),
+		Code:    CodeMethodNotAllowed,
+		Details: []string{"allow=" + allow},
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionsEnabled informa se o controle de sessões foi configurado no caso de
+// uso (usecase.WithSessions)
+func (h *UserHandler) SessionsEnabled() bool {
+	return h.userUseCase != nil && h.userUseCase.SessionsEnabled()
+}
+
+// ListMySessions lista as sessões ativas do usuário autenticado
+// @Summary Listar minhas sessões
+// @Description Retorna uma página das sessões ativas (tokens emitidos e não revogados), da mais recente para a mais antiga, com o total
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param limit query int false "Limite de registros" default(10)
+// @Param page query int false "Página (alternativa a offset)" default(1)
+// @Param per_page query int false "Registros por página (alternativa a limit)" default(10)
+// @Success 200 {object} SessionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /me/sessions [get]
+func (h *UserHandler) ListMySessions(c *gin.Context) {
+	pagination, ok := bindPagination(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := ctxkeys.UserID(c)
+	output, err := h.userUseCase.ListSessions(c.Request.Context(), usecase.ListSessionsInput{
+		UserID: userID,
+		Offset: pagination.Offset,
+		Limit:  pagination.Limit,
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to list sessions",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	sessionID, _ := ctxkeys.SessionID(c)
+	respondJSON(c, http.StatusOK, NewSessionsResponse(output, sessionID, h.timestampFormat))
+}
+
+// RevokeMySession encerra uma sessão do usuário autenticado
+// @Summary Revogar sessão
+// @Description Encerra a sessão; o token dela passa a ser rejeitado. Pode ser a sessão atual
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da sessão"
+// @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /me/sessions/{id} [delete]
+func (h *UserHandler) RevokeMySession(c *gin.Context) {
+	userID, _ := ctxkeys.UserID(c)
+	err := h.userUseCase.RevokeSession(c.Request.Context(), usecase.RevokeSessionInput{
+		UserID:    userID,
+		SessionID: c.Param("id"),
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to revoke session",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/infrastructure/sessions"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := sessions.NewMemoryStore()
+	jwtService := auth.NewJWTService("test-secret", time.Hour, auth.WithSessions(store, 0))
+	h := NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, jwtService, usecase.WithSessions(store)))
+	require.True(t, h.SessionsEnabled())
+
+	router := gin.New()
+	me := router.Group("/me", middleware.AuthMiddleware(jwtService))
+	me.GET("/sessions", h.ListMySessions)
+	me.DELETE("/sessions/:id", h.RevokeMySession)
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	laptop, err := jwtService.GenerateToken("1", "ana@example.com", "user")
+	require.NoError(t, err)
+	phone, err := jwtService.GenerateToken("1", "ana@example.com", "user")
+	require.NoError(t, err)
+	other, err := jwtService.GenerateToken("2", "bob@example.com", "user")
+	require.NoError(t, err)
+
+	w := do(http.MethodGet, "/me/sessions", laptop)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp SessionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Sessions, 2)
+	assert.Equal(t, int64(2), resp.Total)
+	// Da mais recente para a mais antiga
+	assert.False(t, resp.Sessions[0].Current)
+	assert.True(t, resp.Sessions[1].Current)
+	phoneID := resp.Sessions[0].ID
+
+	// Uma sessão de outro usuário não é encontrada
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/me/sessions/"+phoneID, other).Code)
+
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/me/sessions/"+phoneID, laptop).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/me/sessions", phone).Code, "the revoked token is rejected")
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/me/sessions/"+phoneID, laptop).Code)
+}
+
+func TestMySessionsPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := sessions.NewMemoryStore()
+	jwtService := auth.NewJWTService("test-secret", time.Hour, auth.WithSessions(store, 0))
+	h := NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, jwtService, usecase.WithSessions(store)))
+
+	router := gin.New()
+	router.GET("/me/sessions", middleware.AuthMiddleware(jwtService), h.ListMySessions)
+
+	var token string
+	for i := 0; i < 25; i++ {
+		var err error
+		token, err = jwtService.GenerateToken("1", "ana@example.com", "user")
+		require.NoError(t, err)
+	}
+
+	get := func(query string) (*httptest.ResponseRecorder, SessionsResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/me/sessions"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp SessionsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w, resp
+	}
+
+	w, first := get("")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, first.Sessions, 10)
+	assert.Equal(t, int64(25), first.Total)
+	assert.Equal(t, 0, first.Offset)
+	assert.Equal(t, 10, first.Limit)
+	assert.True(t, first.Sessions[0].Current, "the newest session comes first")
+
+	_, last := get("?page=3&per_page=10")
+	assert.Len(t, last.Sessions, 5)
+	assert.Equal(t, int64(25), last.Total)
+	assert.Equal(t, 20, last.Offset)
+
+	_, beyond := get("?offset=30")
+	assert.NotNil(t, beyond.Sessions)
+	assert.Empty(t, beyond.Sessions)
+	assert.Equal(t, int64(25), beyond.Total)
+
+	w, _ = get("?limit=0")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoginRecordsSessionDevice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	u, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	u.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+	repo := &mocks.UserRepository{}
+	repo.On("GetByEmail", mock.Anything, u.Email).Return(u, nil)
+	repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+
+	store := sessions.NewMemoryStore()
+	jwtService := auth.NewJWTService("test-secret", time.Hour, auth.WithSessions(store, 0))
+	h := NewUserHandler(usecase.NewUserUseCase(repo, jwtService, usecase.WithSessions(store)))
+
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+	router.GET("/me/sessions", middleware.AuthMiddleware(jwtService), h.ListMySessions)
+
+	const ua = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"ana@example.com","password":"password123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", ua)
+	req.RemoteAddr = "203.0.113.7:4321"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var login LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+
+	req = httptest.NewRequest(http.MethodGet, "/me/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+login.Token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp SessionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Sessions, 1)
+	session := resp.Sessions[0]
+	assert.Equal(t, "203.0.113.7", session.IP)
+	assert.Equal(t, ua, session.UserAgent)
+	assert.Equal(t, "Chrome", session.Browser)
+	assert.Equal(t, "macOS", session.OS)
+	assert.Equal(t, DeviceDesktop, session.Device)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TimestampFormat define como datas são serializadas nas respostas HTTP
+type TimestampFormat string
+
+const (
+	// TimestampRFC3339Nano mantém o formato padrão do Go, com frações de segundo
+	TimestampRFC3339Nano TimestampFormat = "rfc3339nano"
+	// TimestampRFC3339 serializa sem frações de segundo, sempre em UTC
+	TimestampRFC3339 TimestampFormat = "rfc3339"
+	// TimestampUnix serializa como segundos desde a época Unix
+	TimestampUnix TimestampFormat = "unix"
+)
+
+// ParseTimestampFormat valida um formato vindo da configuração; vazio usa o padrão
+func ParseTimestampFormat(value string) (TimestampFormat, error) {
+	switch format := TimestampFormat(value); format {
+	case "":
+		return TimestampRFC3339Nano, nil
+	case TimestampRFC3339Nano, TimestampRFC3339, TimestampUnix:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid timestamp format %q", value)
+	}
+}
+
+// Timestamp é um time.Time que se serializa no formato configurado
+type Timestamp struct {
+	time.Time
+	format TimestampFormat
+}
+
+// NewTimestamp cria um Timestamp com o formato informado
+func NewTimestamp(t time.Time, format TimestampFormat) Timestamp {
+	return Timestamp{Time: t, format: format}
+}
+
+// MarshalJSON implementa json.Marshaler
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	switch t.format {
+	case TimestampUnix:
+		return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+	case TimestampRFC3339:
+		return []byte(strconv.Quote(t.UTC().Format(time.RFC3339))), nil
+	default:
+		return t.Time.MarshalJSON()
+	}
+}
+
+// UnmarshalJSON implementa json.Unmarshaler aceitando RFC3339 ou epoch Unix
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if seconds, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		t.Time = time.Unix(seconds, 0)
+		t.format = TimestampUnix
+		return nil
+	}
+
+	return t.Time.UnmarshalJSON(data)
+}
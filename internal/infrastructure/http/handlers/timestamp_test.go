@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampMarshalJSON(t *testing.T) {
+	ts := time.Date(2024, 3, 15, 10, 30, 45, 123456789, time.FixedZone("BRT", -3*3600))
+
+	tests := []struct {
+		format   TimestampFormat
+		expected string
+	}{
+		{TimestampRFC3339Nano, `"2024-03-15T10:30:45.123456789-03:00"`},
+		{TimestampRFC3339, `"2024-03-15T13:30:45Z"`},
+		{TimestampUnix, `1710509445`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			data, err := json.Marshal(NewTimestamp(ts, tt.format))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestParseTimestampFormat(t *testing.T) {
+	format, err := ParseTimestampFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, TimestampRFC3339Nano, format)
+
+	_, err = ParseTimestampFormat("iso")
+	assert.Error(t, err)
+}
@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"go-api-boilerplate/internal/domain/auth"
 	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
 	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/database"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,14 +22,30 @@ import (
 
 // UserHandler implementa os handlers HTTP para usuários
 type UserHandler struct {
-	userUseCase *usecase.UserUseCase
+	userUseCase     *usecase.UserUseCase
+	timestampFormat TimestampFormat
+	strictJSON      bool
+	oidc            OIDCProvider
+	maxLoginBytes   int64
+	resendLimiter   middleware.RateLimiter
+	resetLimiter    middleware.RateLimiter
 }
 
 // NewUserHandler cria uma nova instância de UserHandler
-func NewUserHandler(userUseCase *usecase.UserUseCase) *UserHandler {
-	return &UserHandler{
-		userUseCase: userUseCase,
+func NewUserHandler(userUseCase *usecase.UserUseCase, opts ...HandlerOption) *UserHandler {
+	h := &UserHandler{
+		userUseCase:     userUseCase,
+		timestampFormat: TimestampRFC3339Nano,
+		maxLoginBytes:   DefaultMaxLoginBodyBytes,
+		resendLimiter:   middleware.NewMemoryRateLimiter(DefaultVerificationResendLimit, DefaultVerificationResendWindow),
+		resetLimiter:    middleware.NewMemoryRateLimiter(DefaultPasswordResetLimit, DefaultPasswordResetWindow),
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // CreateUserRequest representa a requisição de criação de usuário
@@ -30,6 +54,16 @@ type CreateUserRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 	Name     string `json:"name" binding:"required"`
 	Role     string `json:"role" binding:"required"`
+	// Username é opcional e aceito apenas com usernames habilitados
+	Username string `json:"username,omitempty"`
+}
+
+// RegisterRequest representa a requisição de autorregistro público (sem role)
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	Name     string `json:"name" binding:"required"`
+	Username string `json:"username,omitempty"`
 }
 
 // UpdateUserRequest representa a requisição de atualização de usuário
@@ -37,11 +71,29 @@ type UpdateUserRequest struct {
 	Name  *string `json:"name,omitempty"`
 	Email *string `json:"email,omitempty" binding:"omitempty,email"`
 	Role  *string `json:"role,omitempty"`
+	// Username é aceito apenas com usernames habilitados; "" remove o nome de usuário
+	Username *string `json:"username,omitempty"`
+	// IsActive false desativa a conta e encerra suas sessões
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+// ChangePasswordRequest representa a requisição de troca de senha
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
 
-// LoginRequest representa a requisição de login
+// BulkUpdateRolesRequest representa a troca de roles de vários usuários
+type BulkUpdateRolesRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required"`
+	Role    string   `json:"role" binding:"required"`
+}
+
+// LoginRequest representa a requisição de login. Com usernames habilitados,
+// username pode ser enviado no lugar do email
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
+	Email    string `json:"email" binding:"required_without=Username,omitempty,email"`
+	Username string `json:"username,omitempty" binding:"required_without=Email"`
 	Password string `json:"password" binding:"required"`
 }
 
@@ -52,25 +104,28 @@ type LoginRequest struct {
 // @Accept json
 // @Produce json
 // @Param user body CreateUserRequest true "Dados do usuário"
-// @Success 201 {object} user.User
+// @Success 201 {object} UserResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request data",
-			Message: err.Error(),
-		})
+	failures, ok := h.bindJSONCollect(c, &req)
+	if !ok {
+		return
+	}
+	if !failures.empty() {
+		h.addNewUserRules(c.Request.Context(), failures, req.Email, req.Name, &req.Role)
+		respondError(c, http.StatusBadRequest, failures.response())
 		return
 	}
 
 	// Validar role
 	role, err := h.validateRole(req.Role)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid role",
 			Message: err.Error(),
 		})
@@ -83,19 +138,69 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		Password: req.Password,
 		Name:     req.Name,
 		Role:     role,
+		Username: req.Username,
 	}
 
 	output, err := h.userUseCase.CreateUser(c.Request.Context(), input)
 	if err != nil {
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
+		respondError(c, status, ErrorResponse{
 			Error:   "Failed to create user",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, output.User)
+	respondJSON(c, http.StatusCreated, NewUserResponse(output.User, h.timestampFormat))
+}
+
+// Register registra um novo usuário publicamente, sempre com role user
+// @Summary Registrar usuário
+// @Description Autorregistro público; o papel é sempre "user"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body RegisterRequest true "Dados do usuário"
+// @Success 201 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/register [post]
+func (h *UserHandler) Register(c *gin.Context) {
+	var req RegisterRequest
+	failures, ok := h.bindJSONCollect(c, &req)
+	if !ok {
+		return
+	}
+	if !failures.empty() {
+		h.addNewUserRules(c.Request.Context(), failures, req.Email, req.Name, nil)
+		respondError(c, http.StatusBadRequest, failures.response())
+		return
+	}
+
+	input := usecase.RegisterUserInput{
+		Email:    req.Email,
+		Password: req.Password,
+		Name:     req.Name,
+		Username: req.Username,
+	}
+
+	output, err := h.userUseCase.RegisterUser(c.Request.Context(), input)
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to register user",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, NewUserResponse(output.User, h.timestampFormat))
 }
 
 // GetUserByID busca um usuário pelo ID
@@ -105,7 +210,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do usuário"
-// @Success 200 {object} user.User
+// @Success 200 {object} UserResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users/{id} [get]
@@ -113,7 +218,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	// 1. Obtenha o ID da URL e valide-o
 	idStr := c.Param("id")
 	if idStr == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid user ID",
 			Message: "User ID is required",
 		})
@@ -123,7 +228,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	// 2. Valide se é um UUID válido
 	_, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid user ID",
 			Message: "User ID must be a valid UUID",
 		})
@@ -138,15 +243,17 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	if err != nil {
 		// Usa a função centralizada para mapear o erro de domínio para um status HTTP
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
+		respondError(c, status, ErrorResponse{
 			Error:   "Failed to get user",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return // Encerra a execução aqui!
 	}
 
 	// 5. Se não houve erro, retorne o sucesso
-	c.JSON(http.StatusOK, output.User)
+	respondJSON(c, http.StatusOK, NewUserResponse(output.User, h.timestampFormat))
 }
 
 // GetUserByEmail busca um usuário pelo email
@@ -156,14 +263,14 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param email query string true "Email do usuário"
-// @Success 200 {object} user.User
+// @Success 200 {object} UserResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users/email [get]
 func (h *UserHandler) GetUserByEmail(c *gin.Context) {
 	email := c.Query("email")
 	if email == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid email",
 			Message: "Email is required",
 		})
@@ -174,26 +281,29 @@ func (h *UserHandler) GetUserByEmail(c *gin.Context) {
 	output, err := h.userUseCase.GetUserByEmail(c.Request.Context(), input)
 	if err != nil {
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
+		respondError(c, status, ErrorResponse{
 			Error:   "Failed to get user",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, output.User)
+	respondJSON(c, http.StatusOK, NewUserResponse(output.User, h.timestampFormat))
 }
 
 // UpdateUser atualiza um usuário existente
 // @Summary Atualizar usuário
-// @Description Atualiza os dados de um usuário existente
+// @Description Atualiza os dados de um usuário existente. Os campos que o papel do autor pode alterar seguem a matriz de atualização
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do usuário"
 // @Param user body UpdateUserRequest true "Dados para atualização"
-// @Success 200 {object} user.User
+// @Success 200 {object} UserResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -202,7 +312,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	// 1. Obtenha o ID da URL e valide-o
 	idStr := c.Param("id")
 	if idStr == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid user ID",
 			Message: "User ID is required",
 		})
@@ -212,7 +322,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	// 2. Valide se é um UUID válido
 	_, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid user ID",
 			Message: "User ID must be a valid UUID",
 		})
@@ -221,16 +331,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 	// 3. Decodifique o corpo da requisição JSON
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request data",
-			Message: err.Error(),
-		})
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
-	// 4. Prepare o input para o caso de uso
-	input := usecase.UpdateUserInput{ID: idStr}
+	// 4. Prepare o input para o caso de uso; o autor define os campos permitidos
+	actorID, _ := ctxkeys.UserID(c)
+	actorRole, _ := ctxkeys.UserRole(c)
+	input := usecase.UpdateUserInput{ID: idStr, ActorID: actorID, ActorRole: user.Role(actorRole)}
 
 	// 5. Mapear campos opcionais
 	if req.Name != nil {
@@ -239,10 +347,16 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	if req.Email != nil {
 		input.Email = req.Email
 	}
+	if req.Username != nil {
+		input.Username = req.Username
+	}
+	if req.IsActive != nil {
+		input.IsActive = req.IsActive
+	}
 	if req.Role != nil {
 		role, err := h.validateRole(*req.Role)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
+			respondError(c, http.StatusBadRequest, ErrorResponse{
 				Error:   "Invalid role",
 				Message: err.Error(),
 			})
@@ -258,20 +372,22 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	if err != nil {
 		// Usa a função centralizada para mapear o erro de domínio para um status HTTP
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
+		respondError(c, status, ErrorResponse{
 			Error:   "Failed to update user",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return // Encerra a execução aqui!
 	}
 
 	// 8. Se não houve erro, retorne o sucesso
-	c.JSON(http.StatusOK, output.User)
+	respondJSON(c, http.StatusOK, NewUserResponse(output.User, h.timestampFormat))
 }
 
-// DeleteUser remove um usuário
+// DeleteUser exclui um usuário conforme a política configurada
 // @Summary Deletar usuário
-// @Description Remove um usuário do sistema
+// @Description Remove o usuário ou, com users.deletion_policy=anonymize, anonimiza seus dados
 // @Tags users
 // @Accept json
 // @Produce json
@@ -284,7 +400,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	// 1. Obtenha o ID da URL e valide-o
 	idStr := c.Param("id")
 	if idStr == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid user ID",
 			Message: "User ID is required",
 		})
@@ -294,7 +410,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	// 2. Valide se é um UUID válido
 	_, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
+		respondError(c, http.StatusBadRequest, ErrorResponse{
 			Error:   "Invalid user ID",
 			Message: "User ID must be a valid UUID",
 		})
@@ -302,16 +418,19 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	}
 
 	// 3. Chame o caso de uso
-	input := usecase.DeleteUserInput{ID: idStr}
+	actorID, _ := ctxkeys.UserID(c)
+	input := usecase.DeleteUserInput{ID: idStr, ActorID: actorID}
 	err = h.userUseCase.DeleteUser(c.Request.Context(), input)
 
 	// 4. ESTE É O BLOCO MAIS IMPORTANTE: Trate o erro PRIMEIRO
 	if err != nil {
 		// Usa a função centralizada para mapear o erro de domínio para um status HTTP
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
+		respondError(c, status, ErrorResponse{
 			Error:   "Failed to delete user",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return // Encerra a execução aqui!
 	}
@@ -320,6 +439,81 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// RevokeSessions invalida todos os tokens emitidos para um usuário
+// @Summary Revogar sessões do usuário
+// @Description Invalida de uma vez todos os tokens já emitidos para o usuário
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/revoke-sessions [post]
+func (h *UserHandler) RevokeSessions(c *gin.Context) {
+	idStr := c.Param("id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid user ID",
+			Message: "User ID must be a valid UUID",
+		})
+		return
+	}
+
+	err := h.userUseCase.RevokeSessions(c.Request.Context(), usecase.RevokeSessionsInput{UserID: idStr})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to revoke sessions",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AnonymizeUser remove os dados pessoais de um usuário mantendo o registro
+// @Summary Anonimizar usuário
+// @Description Substitui email e nome, invalida a senha, desativa a conta e encerra as sessões; o ID é mantido
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/anonymize [post]
+func (h *UserHandler) AnonymizeUser(c *gin.Context) {
+	idStr := c.Param("id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid user ID",
+			Message: "User ID must be a valid UUID",
+		})
+		return
+	}
+
+	actorID, _ := ctxkeys.UserID(c)
+	err := h.userUseCase.AnonymizeUser(c.Request.Context(), usecase.AnonymizeUserInput{ID: idStr, ActorID: actorID})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to anonymize user",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // ListUsers lista usuários com paginação
 // @Summary Listar usuários
 // @Description Lista usuários com paginação
@@ -328,48 +522,238 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // @Produce json
 // @Param offset query int false "Offset para paginação" default(0)
 // @Param limit query int false "Limite de registros" default(10)
-// @Success 200 {object} usecase.ListUsersOutput
+// @Param page query int false "Página (alternativa a offset)" default(1)
+// @Param per_page query int false "Registros por página (alternativa a limit)" default(10)
+// @Param include_inactive query bool false "Inclui usuários desativados (apenas admins)" default(false)
+// @Success 200 {object} PagedUsersResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	offsetStr := c.DefaultQuery("offset", "0")
-	limitStr := c.DefaultQuery("limit", "10")
+	h.respondPaged(c, "Failed to list users", func(p Pagination) (*usecase.PagedUsers, error) {
+		return h.userUseCase.ListUsers(c.Request.Context(), usecase.ListUsersInput{
+			Offset:          p.Offset,
+			Limit:           p.Limit,
+			IncludeInactive: includeInactive(c),
+		})
+	})
+}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid offset",
-			Message: "Offset must be a positive integer",
+// SearchUsers busca usuários por nome ou email com paginação
+// @Summary Buscar usuários
+// @Description Busca usuários cujo nome ou email contém o termo informado. limit (ou per_page)
+// @Description acima de users.max_search_limit (padrão 100) responde 400 com code LIMIT_TOO_LARGE
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param q query string true "Termo de busca"
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param limit query int false "Limite de registros" default(10)
+// @Param page query int false "Página (alternativa a offset)" default(1)
+// @Param per_page query int false "Registros por página (alternativa a limit)" default(10)
+// @Param include_inactive query bool false "Inclui usuários desativados (apenas admins)" default(false)
+// @Success 200 {object} PagedUsersResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/search [get]
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	h.respondPaged(c, "Failed to search users", func(p Pagination) (*usecase.PagedUsers, error) {
+		return h.userUseCase.SearchUsers(c.Request.Context(), usecase.SearchUsersInput{
+			Query:           c.Query("q"),
+			Offset:          p.Offset,
+			Limit:           p.Limit,
+			IncludeInactive: includeInactive(c),
+		})
+	})
+}
+
+// UserStats conta os usuários criados em um intervalo de datas
+// @Summary Estatísticas de cadastro
+// @Description Total de usuários criados entre from e to (inclusive), em RFC3339
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "Início do intervalo (RFC3339)"
+// @Param to query string true "Fim do intervalo (RFC3339)"
+// @Success 200 {object} UserStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/stats [get]
+func (h *UserHandler) UserStats(c *gin.Context) {
+	from, errFrom := time.Parse(time.RFC3339, c.Query("from"))
+	to, errTo := time.Parse(time.RFC3339, c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid date range",
+			Message: "from and to are required and must be RFC3339 timestamps",
+		})
+		return
+	}
+
+	output, err := h.userUseCase.UserStats(c.Request.Context(), usecase.UserStatsInput{From: from, To: to})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to get user stats",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid limit",
-			Message: "Limit must be a positive integer",
+	respondJSON(c, http.StatusOK, UserStatsResponse{
+		From:  NewTimestamp(output.From, h.timestampFormat),
+		To:    NewTimestamp(output.To, h.timestampFormat),
+		Count: output.Count,
+	})
+}
+
+// RoleStats conta os usuários por papel
+// @Summary Usuários por papel
+// @Description Total de usuários de cada papel, incluindo papéis sem usuários
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} RoleStatsResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/stats/roles [get]
+func (h *UserHandler) RoleStats(c *gin.Context) {
+	output, err := h.userUseCase.RoleStats(c.Request.Context())
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to get role stats",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return
 	}
 
-	input := usecase.ListUsersInput{
-		Offset: offset,
-		Limit:  limit,
+	roles := make(map[string]int64, len(output.Roles))
+	for role, count := range output.Roles {
+		roles[string(role)] = count
 	}
 
-	output, err := h.userUseCase.ListUsers(c.Request.Context(), input)
+	respondJSON(c, http.StatusOK, RoleStatsResponse{Roles: roles, Total: output.Total})
+}
+
+// ExportMyData exporta os dados pessoais do usuário autenticado
+// @Summary Exportar meus dados
+// @Description Pacote JSON com os dados pessoais do usuário autenticado (GDPR), para download
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} UserDataExportResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /me/export [get]
+func (h *UserHandler) ExportMyData(c *gin.Context) {
+	// O titular vem sempre do token: não há como pedir os dados de outro usuário
+	userID, ok := ctxkeys.UserID(c)
+	if !ok || userID == "" {
+		respondError(c, http.StatusUnauthorized, ErrorResponse{
+			Error:   "Authentication required",
+			Message: "User not found in request context",
+		})
+		return
+	}
+
+	export, err := h.userUseCase.ExportUserData(c.Request.Context(), usecase.ExportUserDataInput{UserID: userID})
 	if err != nil {
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
-			Error:   "Failed to list users",
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to export user data",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, output)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-data-%s.json"`, userID))
+	c.Header("Cache-Control", "no-store")
+	respondJSON(c, http.StatusOK, NewUserDataExportResponse(export, h.timestampFormat))
+}
+
+// exportHeader é a primeira linha do CSV de exportação
+var exportHeader = []string{"id", "email", "name", "role", "is_active", "created_at", "updated_at"}
+
+// ExportUsers exporta todos os usuários em CSV
+// @Summary Exportar usuários
+// @Description CSV transmitido em lotes; o trailer X-Export-Truncated indica se o limite de linhas foi atingido
+// @Tags users
+// @Produce text/csv
+// @Security BearerAuth
+// @Success 200 {string} string "CSV"
+// @Failure 500 {object} ErrorResponse
+// @Router /users/export [get]
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	var w *csv.Writer
+	start := func() {
+		clearWriteDeadline(c)
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="users.csv"`)
+		c.Header("Trailer", "X-Export-Truncated")
+		c.Status(http.StatusOK)
+		w = csv.NewWriter(c.Writer)
+		_ = w.Write(exportHeader)
+	}
+
+	output, err := h.userUseCase.ExportUsers(c.Request.Context(), func(batch []*user.User) error {
+		if w == nil {
+			start()
+		}
+		for _, u := range batch {
+			_ = w.Write([]string{
+				u.ID,
+				u.Email,
+				u.Name,
+				string(u.Role),
+				strconv.FormatBool(u.IsActive),
+				u.CreatedAt.UTC().Format(time.RFC3339),
+				u.UpdatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		// Envia cada lote assim que escrito, para o cliente receber dados progressivamente
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if w == nil {
+			status, message := h.mapErrorToHTTPStatus(err)
+			respondError(c, status, ErrorResponse{
+				Error:   "Failed to export users",
+				Message: message,
+				Code:    errorCode(err),
+				Details: errorDetails(err),
+			})
+			return
+		}
+		// O corpo já começou a ser enviado: a resposta termina incompleta e
+		// sem o trailer X-Export-Truncated
+		_ = c.Error(err)
+		return
+	}
+
+	if w == nil {
+		start()
+		w.Flush()
+	}
+	c.Writer.Header().Set("X-Export-Truncated", strconv.FormatBool(output.Truncated))
+}
+
+// includeInactive indica se a consulta deve incluir contas desativadas.
+// Apenas admins podem vê-las; o parâmetro é ignorado para os demais
+func includeInactive(c *gin.Context) bool {
+	role, _ := ctxkeys.UserRole(c)
+	return role == string(user.RoleAdmin) && c.Query("include_inactive") == "true"
 }
 
 // Login autentica um usuário
@@ -379,61 +763,259 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param credentials body LoginRequest true "Credenciais de login"
-// @Success 200 {object} user.User
+// @Success 200 {object} LoginResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
+	// Endpoint público e sem autenticação: corpos grandes são recusados antes do parse
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxLoginBytes)
+
 	var req LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request data",
-			Message: err.Error(),
-		})
+	if !h.bindJSON(c, &req) {
 		return
 	}
 
+	requestID, _ := ctxkeys.RequestID(c)
 	input := usecase.AuthenticateUserInput{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:     req.Email,
+		Username:  req.Username,
+		Password:  req.Password,
+		ClientIP:  c.ClientIP(),
+		RequestID: requestID,
+		UserAgent: c.Request.UserAgent(),
 	}
 
 	output, err := h.userUseCase.AuthenticateUser(c.Request.Context(), input)
 	if err != nil {
 		status, message := h.mapErrorToHTTPStatus(err)
-		c.JSON(status, ErrorResponse{
+		respondError(c, status, ErrorResponse{
 			Error:   "Authentication failed",
 			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user":  output.User,
-		"token": output.Token,
+	respondJSON(c, http.StatusOK, NewLoginResponse(output, h.timestampFormat))
+}
+
+// Logout revoga o token do usuário autenticado
+// @Summary Logout
+// @Description Revoga o token atual até sua expiração
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	token, _ := ctxkeys.Token(c)
+	input := usecase.LogoutInput{Token: token}
+	if err := h.userUseCase.Logout(c.Request.Context(), input); err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to logout",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword troca a senha do usuário autenticado e encerra todas as suas sessões
+// @Summary Trocar senha
+// @Description Troca a senha do usuário autenticado; todos os tokens emitidos são invalidados
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param password body ChangePasswordRequest true "Senha atual e nova senha"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	userID, _ := ctxkeys.UserID(c)
+	input := usecase.ChangePasswordInput{
+		UserID:          userID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}
+	if err := h.userUseCase.ChangePassword(c.Request.Context(), input); err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to change password",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeactivateUser desativa um usuário e encerra todas as suas sessões
+// @Summary Desativar usuário
+// @Description Desativa a conta; todos os tokens emitidos são invalidados
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/deactivate [post]
+func (h *UserHandler) DeactivateUser(c *gin.Context) {
+	idStr := c.Param("id")
+	if _, err := uuid.Parse(idStr); err != nil {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid user ID",
+			Message: "User ID must be a valid UUID",
+		})
+		return
+	}
+
+	output, err := h.userUseCase.DeactivateUser(c.Request.Context(), usecase.DeactivateUserInput{ID: idStr})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to deactivate user",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, NewUserResponse(output.User, h.timestampFormat))
+}
+
+// BulkUpdateRoles define o mesmo role para vários usuários
+// @Summary Trocar roles em lote
+// @Description Define o role de vários usuários em uma única transação. Com dry_run=true,
+// @Description valida e reporta o efeito sem gravar
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkUpdateRolesRequest true "IDs e role de destino"
+// @Param dry_run query bool false "Apenas simula a operação" default(false)
+// @Success 200 {object} BulkUpdateRolesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/bulk-role [post]
+func (h *UserHandler) BulkUpdateRoles(c *gin.Context) {
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	var req BulkUpdateRolesRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	role, err := h.validateRole(req.Role)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid role",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	actorID, _ := ctxkeys.UserID(c)
+	output, err := h.userUseCase.BulkUpdateRoles(c.Request.Context(), usecase.BulkUpdateRolesInput{
+		UserIDs: req.UserIDs,
+		Role:    role,
+		DryRun:  dryRun,
+		ActorID: actorID,
 	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to update roles",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	response := NewBulkUpdateRolesResponse(output)
+	response.BulkResponse = h.newBulkResponse(output.Items, "Failed to update role")
+	respondJSON(c, http.StatusOK, response)
+}
+
+// parseDryRun lê o parâmetro dry_run das operações em lote, respondendo 400 se inválido
+func parseDryRun(c *gin.Context) (bool, bool) {
+	raw := c.Query("dry_run")
+	if raw == "" {
+		return false, true
+	}
+
+	dryRun, err := strconv.ParseBool(raw)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid query parameter",
+			Message: "dry_run must be a boolean",
+		})
+		return false, false
+	}
+	return dryRun, true
 }
 
 // ErrorResponse representa uma resposta de erro padronizada
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+	Error   string   `json:"error"`
+	Message string   `json:"message"`
+	Code    string   `json:"code,omitempty"`
+	Details []string `json:"details,omitempty"`
+	// Retryable indica se repetir a mesma requisição pode ter sucesso (falhas
+	// passageiras); é derivado do status em respondError
+	Retryable bool `json:"retryable"`
 }
 
 // validateRole valida se o role fornecido é válido
 func (h *UserHandler) validateRole(roleStr string) (user.Role, error) {
-	role := user.Role(roleStr)
-	switch role {
-	case user.RoleAdmin, user.RoleUser, user.RoleGuest:
-		return role, nil
-	default:
-		return "", user.ErrInvalidRole
-	}
+	return user.ParseRole(roleStr)
 }
 
+// StatusClientClosedRequest é o status não padronizado (nginx) para requisições
+// abortadas pelo cliente antes da resposta
+const StatusClientClosedRequest = 499
+
 // mapErrorToHTTPStatus mapeia erros do domínio para códigos HTTP
 func (h *UserHandler) mapErrorToHTTPStatus(err error) (int, string) {
+	// Cancelamentos do contexto da requisição chegam envolvidos pelas camadas inferiores
+	if errors.Is(err, context.Canceled) {
+		return StatusClientClosedRequest, "Request canceled by client"
+	}
+	// Consultas canceladas no meio chegam como erro do Postgres, não do contexto
+	if errors.Is(err, context.DeadlineExceeded) || database.IsQueryCanceled(err) {
+		return http.StatusGatewayTimeout, "Request timed out"
+	}
+	// Falhas passageiras do banco (serialização, deadlock, conexão) podem ser repetidas
+	if database.IsTransient(err) {
+		return http.StatusServiceUnavailable, "Service temporarily unavailable, please retry"
+	}
+
 	// Verifica se é um erro do domínio usando errors.Is
 	if errors.Is(err, user.ErrInvalidRole) {
 		return http.StatusBadRequest, "Invalid role"
@@ -447,9 +1029,99 @@ func (h *UserHandler) mapErrorToHTTPStatus(err error) (int, string) {
 	if errors.Is(err, user.ErrInvalidPassword) {
 		return http.StatusUnauthorized, "Invalid password"
 	}
+	if errors.Is(err, user.ErrEmptyName) {
+		return http.StatusBadRequest, "Name cannot be empty"
+	}
+	var validationErr *user.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest, validationErr.Error()
+	}
+	if errors.Is(err, user.ErrEmptyEmail) {
+		return http.StatusBadRequest, "Email cannot be empty"
+	}
+	if errors.Is(err, user.ErrEmailDomainNotAllowed) {
+		return http.StatusUnprocessableEntity, "Email domain is not allowed for registration"
+	}
+	if errors.Is(err, user.ErrDisposableEmail) {
+		return http.StatusUnprocessableEntity, "Disposable email addresses are not allowed"
+	}
 	if errors.Is(err, user.ErrUserDeactivated) {
 		return http.StatusUnauthorized, "User account is deactivated"
 	}
+	if errors.Is(err, user.ErrPasswordExpired) {
+		return http.StatusForbidden, "Password expired; change it with POST /auth/password/expired"
+	}
+	if errors.Is(err, user.ErrVerificationTokenInvalid) {
+		return http.StatusBadRequest, "Invalid verification token"
+	}
+	if errors.Is(err, user.ErrVerificationTokenExpired) {
+		return http.StatusBadRequest, "Verification token has expired"
+	}
+	var permissionErr *user.FieldPermissionError
+	if errors.As(err, &permissionErr) {
+		return http.StatusForbidden, permissionErr.Error()
+	}
+	if errors.Is(err, user.ErrAdminAlreadyExists) {
+		return http.StatusGone, "First admin bootstrap is no longer available"
+	}
+	if errors.Is(err, auth.ErrSessionNotFound) {
+		return http.StatusNotFound, "Session not found"
+	}
+	if errors.Is(err, usecase.ErrUsernamesDisabled) {
+		return http.StatusBadRequest, "Usernames are not enabled"
+	}
+	if errors.Is(err, usecase.ErrEmailUnchanged) {
+		return http.StatusBadRequest, "New email must be different from the current email"
+	}
+	if errors.Is(err, usecase.ErrPasswordUnchanged) {
+		return http.StatusBadRequest, "New password must be different from the current password"
+	}
+	if errors.Is(err, usecase.ErrExternalEmailNotVerified) {
+		return http.StatusForbidden, "Email not verified by the identity provider"
+	}
+	if errors.Is(err, auth.ErrResetTokenInvalid) {
+		return http.StatusBadRequest, "Invalid password reset token"
+	}
+	if errors.Is(err, auth.ErrResetTokenExpired) {
+		return http.StatusBadRequest, "Password reset token has expired"
+	}
+	if errors.Is(err, auth.ErrResetTokenExhausted) {
+		return http.StatusBadRequest, "Password reset token was invalidated after too many attempts; request a new link"
+	}
+	if errors.Is(err, usecase.ErrEmptySearchQuery) {
+		return http.StatusBadRequest, "Search query is required"
+	}
+	if errors.Is(err, usecase.ErrInvalidDateRange) {
+		return http.StatusBadRequest, "from must not be after to"
+	}
+	if errors.Is(err, usecase.ErrInvalidDormantDays) {
+		return http.StatusBadRequest, "days is required and must be at least 1"
+	}
+	if errors.Is(err, usecase.ErrEmptyBulkIDs) {
+		return http.StatusBadRequest, "At least one user ID is required"
+	}
+	var limitErr *usecase.LimitExceededError
+	if errors.As(err, &limitErr) {
+		if errors.Is(err, usecase.ErrTooManyBatchGetIDs) {
+			return http.StatusRequestEntityTooLarge, fmt.Sprintf("At most %d user IDs are allowed per request", limitErr.Max)
+		}
+		return http.StatusBadRequest, fmt.Sprintf("limit (or per_page) must not exceed %d", limitErr.Max)
+	}
+	if errors.Is(err, usecase.ErrTooManyBulkIDs) {
+		return http.StatusBadRequest, fmt.Sprintf("At most %d user IDs are allowed per request", usecase.MaxBulkUserIDs)
+	}
+	if errors.Is(err, usecase.ErrEmptyBulkUsers) {
+		return http.StatusBadRequest, "At least one user is required"
+	}
+	if errors.Is(err, usecase.ErrTooManyBulkUsers) {
+		return http.StatusBadRequest, fmt.Sprintf("At most %d users are allowed per request", usecase.MaxBulkCreateUsers)
+	}
+	if errors.Is(err, usecase.ErrEmptyImportEmails) {
+		return http.StatusBadRequest, "At least one email is required"
+	}
+	if errors.Is(err, usecase.ErrTooManyImportEmails) {
+		return http.StatusBadRequest, fmt.Sprintf("At most %d emails are allowed per request", usecase.MaxImportEmails)
+	}
 
 	return http.StatusInternalServerError, "Internal server error"
 }
@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/emailpolicy"
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHandler cria um handler com repositório e JWT mockados
+func newTestHandler() (*UserHandler, *mocks.UserRepository, *mocks.JWTService) {
+	repo := &mocks.UserRepository{}
+	jwtService := &mocks.JWTService{}
+	return NewUserHandler(usecase.NewUserUseCase(repo, jwtService)), repo, jwtService
+}
+
+func TestContextErrorsMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const id = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"canceled maps to 499", context.Canceled, StatusClientClosedRequest},
+		{"deadline exceeded maps to 504", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"wrapped deadline exceeded maps to 504", fmt.Errorf("query failed: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"query canceled by postgres maps to 504", fmt.Errorf("query failed: %w", &pq.Error{Code: "57014"}), http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo, _ := newTestHandler()
+			repo.On("GetByID", mock.Anything, id).Return(nil, tt.err)
+
+			router := gin.New()
+			router.GET("/users/:id", h.GetUserByID)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+id, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestDomainErrorDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const id = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	h, repo, _ := newTestHandler()
+	repo.On("GetByID", mock.Anything, id).Return(nil, user.ErrUserNotFound)
+
+	router := gin.New()
+	router.GET("/users/:id", h.GetUserByID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+id, nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "User not found", resp.Message)
+	assert.Equal(t, []string{"id=" + id}, resp.Details)
+}
+
+func TestRegisterRejectsEmailDomain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rules, err := user.NewEmailDomainRules([]string{"example.com"}, nil)
+	require.NoError(t, err)
+	h := NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}, usecase.WithEmailDomainRules(rules)))
+
+	router := gin.New()
+	router.POST("/auth/register", h.Register)
+
+	body := `{"email":"ana@gmail.com","password":"password123","name":"Ana"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Email domain is not allowed for registration", resp.Message)
+	assert.Equal(t, CodeEmailDomainNotAllowed, resp.Code)
+	assert.Equal(t, []string{"domain=gmail.com"}, resp.Details)
+}
+
+func TestRegisterRejectsDisposableEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h := NewUserHandler(usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{},
+		usecase.WithEmailPolicy(emailpolicy.NewList([]string{"mailinator.com"}))))
+
+	router := gin.New()
+	router.POST("/auth/register", h.Register)
+
+	body := `{"email":"ana@mailinator.com","password":"password123","name":"Ana"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CodeDisposableEmail, resp.Code)
+	assert.Equal(t, []string{"domain=mailinator.com"}, resp.Details)
+}
+
+func TestLoginReturnsValidToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	u, err := user.NewUser("login@example.com", "password123", "Login User", user.RoleUser)
+	require.NoError(t, err)
+	repo := &mocks.UserRepository{}
+	repo.On("GetByEmail", mock.Anything, u.Email).Return(u, nil)
+	repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+
+	jwtService := auth.NewJWTService("test-secret", time.Hour)
+	h := NewUserHandler(usecase.NewUserUseCase(repo, jwtService))
+
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+
+	body := `{"email":"login@example.com","password":"password123"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, TokenTypeBearer, resp.TokenType)
+	assert.Equal(t, int64(3600), resp.ExpiresIn)
+
+	claims, err := jwtService.ValidateToken(resp.Token)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, claims.UserID)
+	assert.Equal(t, string(user.RoleUser), claims.Role)
+}
+
+func TestLoginRejectsOversizedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &mocks.UserRepository{}
+	h := NewUserHandler(usecase.NewUserUseCase(repo, &mocks.JWTService{}), WithMaxLoginBodyBytes(256))
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+
+	body := `{"email":"login@example.com","password":"` + strings.Repeat("x", 512) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), CodeRequestTooLarge)
+	repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestListUsersIncludeInactive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		role       string
+		query      string
+		listMethod string
+		count      string
+	}{
+		{"admin can include inactive", "admin", "?include_inactive=true", "List", "Count"},
+		{"admin defaults to active only", "admin", "", "ListActive", "CountActive"},
+		{"non-admin flag is ignored", "user", "?include_inactive=true", "ListActive", "CountActive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo, _ := newTestHandler()
+			repo.On(tt.listMethod, mock.Anything, 0, 10).Return([]*user.User{}, nil)
+			repo.On(tt.count, mock.Anything).Return(int64(0), nil)
+
+			router := gin.New()
+			router.GET("/users", func(c *gin.Context) {
+				ctxkeys.SetUserRole(c, tt.role)
+				h.ListUsers(c)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users"+tt.query, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchUsersSharesPagedShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	found := []*user.User{{ID: "1", Email: "ana@example.com", Name: "Ana", Role: user.RoleUser, IsActive: true}}
+	repo.On("Search", mock.Anything, "ana", false, 20, 10).Return(found, nil)
+	repo.On("CountSearch", mock.Anything, "ana", false).Return(int64(21), nil)
+
+	router := gin.New()
+	router.GET("/users/search", h.SearchUsers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/search?q=ana&page=3&per_page=10", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response PagedUsersResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Users, 1)
+	assert.Equal(t, int64(21), response.Total)
+	assert.Equal(t, 20, response.Offset)
+	assert.Equal(t, 10, response.Limit)
+	repo.AssertExpectations(t)
+
+	t.Run("missing query", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/search", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid pagination", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/search?q=ana&limit=0", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), CodeInvalidPagination)
+	})
+}
+
+func TestBulkUpdateRolesDryRunQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("PreviewUpdateRoles", mock.Anything, []string{"1", "2"}, user.RoleAdmin).Return(&repository.RoleUpdateResult{
+		Updated:  []string{"1"},
+		NotFound: []string{"2"},
+	}, nil)
+
+	router := gin.New()
+	router.POST("/users/bulk-role", h.BulkUpdateRoles)
+
+	body := `{"user_ids":["1","2"],"role":"admin"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/bulk-role?dry_run=true", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response BulkUpdateRolesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Updated)
+	assert.Equal(t, []string{"2"}, response.NotFound)
+	assert.True(t, response.DryRun)
+	assert.Equal(t, BulkSummary{Total: 2, Succeeded: 1, Failed: 1}, response.Summary)
+	require.Len(t, response.Items, 2)
+	assert.Equal(t, http.StatusOK, response.Items[0].Status)
+	assert.Equal(t, http.StatusNotFound, response.Items[1].Status)
+	repo.AssertNotCalled(t, "UpdateRoles", mock.Anything, mock.Anything, mock.Anything)
+
+	t.Run("invalid flag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/bulk-role?dry_run=maybe", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestUserStatsDateRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	h, repo, _ := newTestHandler()
+	repo.On("CountCreatedBetween", mock.Anything, from, to).Return(int64(7), nil)
+
+	router := gin.New()
+	router.GET("/users/stats", h.UserStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/stats?from=2025-01-01T00:00:00Z&to=2025-01-31T23:59:59Z", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(7), response["count"])
+	repo.AssertExpectations(t)
+
+	for name, query := range map[string]string{
+		"missing to":    "?from=2025-01-01T00:00:00Z",
+		"not rfc3339":   "?from=2025-01-01&to=2025-01-31",
+		"from after to": "?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/stats"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestRoleStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("CountByRole", mock.Anything).Return(map[user.Role]int64{
+		user.RoleAdmin: 2,
+		user.RoleUser:  5,
+		user.RoleGuest: 0,
+	}, nil)
+
+	router := gin.New()
+	router.GET("/users/stats/roles", h.RoleStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/stats/roles", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response RoleStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]int64{"admin": 2, "user": 5, "guest": 0}, response.Roles)
+	assert.Equal(t, int64(7), response.Total)
+}
+
+func TestCreateUserRejectsBlankNameAfterTrimming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("ExistsByEmail", mock.Anything, "ana@example.com").Return(false, nil)
+
+	router := gin.New()
+	router.POST("/users", h.CreateUser)
+
+	body := `{"email":"Ana@Example.com","password":"password123","name":"   ","role":"user"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Name cannot be empty")
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestExportUsersWritesCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo.On("ListAfterID", mock.Anything, "", usecase.DefaultExportBatchSize).Return([]*user.User{
+		{ID: "1", Email: "ana@example.com", Name: "Ana, Maria", Role: user.RoleUser, IsActive: true, CreatedAt: created, UpdatedAt: created},
+	}, nil)
+
+	router := gin.New()
+	router.GET("/users/export", h.ExportUsers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/export", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id,email,name,role,is_active,created_at,updated_at\n"+
+		"1,ana@example.com,\"Ana, Maria\",user,true,2025-01-02T03:04:05Z,2025-01-02T03:04:05Z\n", w.Body.String())
+	assert.Equal(t, "false", w.Result().Trailer.Get("X-Export-Truncated"))
+}
+
+func TestExportMyData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	u, err := user.NewUser("me@example.com", "password123", "Me", user.RoleUser)
+	require.NoError(t, err)
+	u.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	h, repo, _ := newTestHandler()
+	repo.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+
+	router := gin.New()
+	router.GET("/me/export", func(c *gin.Context) {
+		if id := c.Query("as"); id != "" {
+			ctxkeys.SetUserID(c, id)
+		}
+		h.ExportMyData(c)
+	})
+
+	t.Run("exports own data as attachment", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me/export?as="+u.ID, nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="user-data-`+u.ID+`.json"`, w.Header().Get("Content-Disposition"))
+		assert.NotContains(t, w.Body.String(), "password")
+		assert.NotContains(t, w.Body.String(), "token_version")
+
+		var resp UserDataExportResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, u.ID, resp.Profile.ID)
+		assert.Equal(t, "me@example.com", resp.Profile.Email)
+	})
+
+	t.Run("requires authenticated user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me/export", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestLoginByUsername(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	u, err := user.NewUser("login@example.com", "password123", "Login User", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, u.SetUsername("login_user"))
+	repo := &mocks.UserRepository{}
+	repo.On("GetByUsername", mock.Anything, "login_user").Return(u, nil)
+	repo.On("GetByEmail", mock.Anything, u.Email).Return(u, nil)
+	repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+
+	jwtService := auth.NewJWTService("test-secret", time.Hour)
+	h := NewUserHandler(usecase.NewUserUseCase(repo, jwtService, usecase.WithUsernames()))
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"username", `{"username":"login_user","password":"password123"}`, http.StatusOK},
+		{"email still accepted", `{"email":"login@example.com","password":"password123"}`, http.StatusOK},
+		{"neither email nor username", `{"password":"password123"}`, http.StatusBadRequest},
+		{"invalid email", `{"email":"login","password":"password123"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestUpdateUserOwnRoleIsForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, repo, _ := newTestHandler()
+
+	u, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	u.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+	repo.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+	repo.On("Update", mock.Anything, u).Return(nil)
+
+	router := gin.New()
+	router.PUT("/users/:id", func(c *gin.Context) {
+		ctxkeys.SetUserID(c, u.ID)
+		ctxkeys.SetUserRole(c, string(user.RoleUser))
+		c.Next()
+	}, h.UpdateUser)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/users/"+u.ID, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := put(`{"role":"admin"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, CodeFieldUpdateForbidden, response.Code)
+	assert.Equal(t, `role "user" cannot change its own "role"`, response.Message)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	assert.Equal(t, http.StatusForbidden, put(`{"is_active":false}`).Code)
+	assert.Equal(t, http.StatusOK, put(`{"name":"Ana Maria"}`).Code)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import "strings"
+
+// Tipos de dispositivo reportados em SessionResponse.Device
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+)
+
+// userAgentInfo são os campos amigáveis extraídos de um user-agent; campos não
+// reconhecidos ficam vazios
+type userAgentInfo struct {
+	Browser string
+	OS      string
+	Device  string
+}
+
+// browserTokens identifica o navegador pelo primeiro token encontrado. A ordem
+// importa: Edge e Opera também anunciam Chrome, e Chrome também anuncia Safari
+var browserTokens = []struct {
+	token string
+	name  string
+}{
+	{"edg/", "Edge"},
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"version/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// osTokens identifica o sistema operacional; iOS e Android vêm antes de macOS e
+// Linux, que aparecem nos user-agents deles
+var osTokens = []struct {
+	token string
+	name  string
+}{
+	{"iphone", "iOS"},
+	{"ipad", "iPadOS"},
+	{"ipod", "iOS"},
+	{"android", "Android"},
+	{"windows", "Windows"},
+	{"cros", "ChromeOS"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"linux", "Linux"},
+}
+
+// parseUserAgent reconhece navegador, sistema e tipo de dispositivo dos
+// user-agents mais comuns, para que o usuário identifique a sessão
+// (ex.: "Chrome no macOS")
+func parseUserAgent(ua string) userAgentInfo {
+	lower := strings.ToLower(ua)
+	if lower == "" {
+		return userAgentInfo{}
+	}
+
+	var info userAgentInfo
+	for _, b := range browserTokens {
+		if strings.Contains(lower, b.token) {
+			info.Browser = b.name
+			break
+		}
+	}
+	for _, o := range osTokens {
+		if strings.Contains(lower, o.token) {
+			info.OS = o.name
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "bot") || strings.Contains(lower, "spider") || strings.Contains(lower, "crawl"):
+		info.Device = DeviceBot
+	case info.OS == "iPadOS" || (info.OS == "Android" && !strings.Contains(lower, "mobile")):
+		info.Device = DeviceTablet
+	case info.OS == "iOS" || strings.Contains(lower, "mobi"):
+		info.Device = DeviceMobile
+	case info.OS == "Windows" || info.OS == "macOS" || info.OS == "Linux" || info.OS == "ChromeOS":
+		info.Device = DeviceDesktop
+	}
+
+	return info
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/validator"
+
+	playground "github.com/go-playground/validator/v10"
+)
+
+// CodeValidationFailed indica uma ou mais falhas de validação, todas listadas em details
+const CodeValidationFailed = "VALIDATION_FAILED"
+
+// requestValidator formata as falhas do binding com as mesmas mensagens do validator do projeto
+var requestValidator = validator.NewCustomValidator(validator.WithRoles(user.RoleNames))
+
+// validationFailures acumula as falhas de uma requisição para respondê-las de
+// uma vez, em vez de parar na primeira
+type validationFailures struct {
+	details []string
+	fields  map[string]struct{}
+}
+
+// add registra uma falha do campo field
+func (v *validationFailures) add(field, detail string) {
+	if v.fields == nil {
+		v.fields = make(map[string]struct{})
+	}
+	v.fields[field] = struct{}{}
+	v.details = append(v.details, detail)
+}
+
+// addBinding registra as falhas de um erro de validação do binding
+func (v *validationFailures) addBinding(errs playground.ValidationErrors) {
+	for _, fe := range errs {
+		for _, detail := range requestValidator.GetValidationErrors(playground.ValidationErrors{fe}) {
+			v.add(strings.ToLower(fe.Field()), detail)
+		}
+	}
+}
+
+// failed informa se o campo já tem alguma falha
+func (v *validationFailures) failed(field string) bool {
+	_, ok := v.fields[field]
+	return ok
+}
+
+// empty informa se nenhuma falha foi registrada
+func (v *validationFailures) empty() bool {
+	return len(v.details) == 0
+}
+
+// response monta o ErrorResponse com todas as falhas em details
+func (v *validationFailures) response() ErrorResponse {
+	message := v.details[0]
+	if len(v.details) > 1 {
+		message = fmt.Sprintf("%d validation errors", len(v.details))
+	}
+	return ErrorResponse{
+		Error:   "Invalid request data",
+		Message: message,
+		Code:    CodeValidationFailed,
+		Details: v.details,
+	}
+}
+
+// addNewUserRules acrescenta as regras de criação de usuário que o binding não
+// cobre: papel, regras de nome do domínio e, quando o email é sintaticamente
+// válido, as regras de negócio do email (domínio, descartável, já cadastrado).
+// Só é chamado quando a requisição já falhou, para reportar tudo junto; falhas
+// de infraestrutura nessas consultas são ignoradas, pois a criação não ocorre
+func (h *UserHandler) addNewUserRules(ctx context.Context, failures *validationFailures, email, name string, role *string) {
+	if role != nil && !failures.failed("role") {
+		if _, err := h.validateRole(*role); err != nil {
+			failures.add("role", fmt.Sprintf("role must be one of: %s", roleNames()))
+		}
+	}
+
+	if !failures.failed("name") {
+		var nameErr *user.ValidationError
+		if errors.As(user.ValidateName(user.NormalizeName(name)), &nameErr) {
+			failures.add("name", fmt.Sprintf("name %s", nameErr.Message))
+		}
+	}
+
+	if !failures.failed("email") {
+		if err := h.userUseCase.CheckEmailAvailable(ctx, email); err != nil {
+			if status, message := h.mapErrorToHTTPStatus(err); status < http.StatusInternalServerError {
+				failures.add("email", "email: "+message)
+			}
+		}
+	}
+}
+
+// roleNames lista os papéis válidos para mensagens de erro
+func roleNames() string {
+	return strings.Join(user.RoleNames(), ", ")
+}
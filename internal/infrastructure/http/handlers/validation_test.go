@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserAggregatesValidationErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	router := gin.New()
+	router.POST("/users", h.CreateUser)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users",
+		strings.NewReader(`{"email":"not-an-email","password":"abc","name":"R2D2","role":"nope"}`)))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CodeValidationFailed, resp.Code)
+	assert.Equal(t, "4 validation errors", resp.Message)
+	require.Len(t, resp.Details, 4)
+	assert.Equal(t, "email must be a valid email address", resp.Details[0])
+	assert.Equal(t, "password must be at least 6 characters long", resp.Details[1])
+	assert.True(t, strings.HasPrefix(resp.Details[2], "role must be one of: admin, user, guest"), resp.Details[2])
+	assert.Equal(t, "name contains disallowed character '2'", resp.Details[3])
+	// Email inválido não é consultado no banco
+	repo.AssertNotCalled(t, "ExistsByEmail", mock.Anything, mock.Anything)
+}
+
+func TestRegisterReportsTakenEmailWithValidationErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("ExistsByEmail", mock.Anything, "ana@example.com").Return(true, nil)
+	router := gin.New()
+	router.POST("/auth/register", h.Register)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/register",
+		strings.NewReader(`{"email":"Ana@Example.com","password":"abc","name":"Ana"}`)))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CodeValidationFailed, resp.Code)
+	assert.Equal(t, []string{
+		"password must be at least 6 characters long",
+		"email: User already exists",
+	}, resp.Details)
+	repo.AssertExpectations(t)
+}
+
+func TestSingleValidationErrorUsesItAsMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	h, repo, _ := newTestHandler()
+	repo.On("ExistsByEmail", mock.Anything, "ana@example.com").Return(false, nil)
+	router := gin.New()
+	router.POST("/auth/register", h.Register)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/register",
+		strings.NewReader(`{"email":"ana@example.com","password":"abc","name":"Ana"}`)))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "password must be at least 6 characters long", resp.Message)
+	assert.Equal(t, []string{"password must be at least 6 characters long"}, resp.Details)
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limite padrão de reenvios do link de verificação, aplicado por email e por IP
+const (
+	DefaultVerificationResendLimit  = 3
+	DefaultVerificationResendWindow = time.Hour
+)
+
+// WithVerificationResendLimiter define o limiter do reenvio do link de
+// verificação, consultado com uma chave por IP e outra por email. Sem esta
+// opção é usado um limiter em memória de DefaultVerificationResendLimit
+// reenvios por DefaultVerificationResendWindow
+func WithVerificationResendLimiter(limiter middleware.RateLimiter) HandlerOption {
+	return func(h *UserHandler) {
+		if limiter != nil {
+			h.resendLimiter = limiter
+		}
+	}
+}
+
+// EmailVerificationEnabled informa se a verificação de email foi configurada
+// no caso de uso (usecase.WithEmailVerification)
+func (h *UserHandler) EmailVerificationEnabled() bool {
+	return h.userUseCase != nil && h.userUseCase.EmailVerificationEnabled()
+}
+
+// ResendVerificationRequest representa o pedido de reenvio do link de verificação
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// VerifyEmailRequest representa a confirmação do email com o token do link
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangeEmailRequest representa o pedido de troca de email do próprio usuário
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// MessageResponse é uma resposta de sucesso apenas informativa
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// resendAcceptedMessage é a resposta de todo reenvio aceito, exista a conta ou não
+const resendAcceptedMessage = "If the email belongs to an unverified account, a new verification link has been sent"
+
+// ResendVerification reenvia o link de verificação de email
+// @Summary Reenviar verificação de email
+// @Description Envia um novo link se o email pertence a uma conta não verificada, invalidando os anteriores. A resposta é sempre 200 para não revelar quais emails estão cadastrados
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResendVerificationRequest true "Email da conta"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /auth/verify/resend [post]
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	email := user.NormalizeEmail(req.Email)
+	if !h.allowVerificationResend(c, email) {
+		respondError(c, http.StatusTooManyRequests, ErrorResponse{
+			Error:   "Rate limit exceeded",
+			Message: "Too many verification emails requested, please try again later",
+		})
+		return
+	}
+
+	h.userUseCase.ResendVerification(c.Request.Context(), email)
+	respondJSON(c, http.StatusOK, MessageResponse{Message: resendAcceptedMessage})
+}
+
+// allowVerificationResend consulta o limiter por IP e por email; ambos precisam
+// permitir. Falhas do backend liberam o reenvio, como o rate limit global
+func (h *UserHandler) allowVerificationResend(c *gin.Context, email string) bool {
+	ctx := c.Request.Context()
+	for _, key := range []string{
+		"verify-resend:ip:" + c.ClientIP(),
+		"verify-resend:email:" + usecase.HashIdentifier(email),
+	} {
+		if allowed, err := h.resendLimiter.Allow(ctx, key); err == nil && !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyEmail confirma o email com o token do link de verificação
+// @Summary Verificar email
+// @Description Confirma o email com o token recebido; o token é de uso único
+// @Tags auth
+// @Accept json
+// @Param request body VerifyEmailRequest true "Token do link"
+// @Success 204 "Email verified"
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/verify [post]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.userUseCase.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to verify email",
+			Message: message,
+			Code:    errorCode(err),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestEmailChange inicia a troca de email do usuário autenticado
+// @Summary Trocar email
+// @Description Envia um link de confirmação para o novo email; o email atual continua valendo até a confirmação em /auth/verify
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangeEmailRequest true "Novo email e senha atual"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /me/email [post]
+func (h *UserHandler) RequestEmailChange(c *gin.Context) {
+	var req ChangeEmailRequest
+	if !h.bindJSON(c, &req) {
+		return
+	}
+
+	userID, _ := ctxkeys.UserID(c)
+	err := h.userUseCase.RequestEmailChange(c.Request.Context(), usecase.RequestEmailChangeInput{
+		UserID:   userID,
+		Email:    req.Email,
+		Password: req.Password,
+	})
+	if err != nil {
+		status, message := h.mapErrorToHTTPStatus(err)
+		respondError(c, status, ErrorResponse{
+			Error:   "Failed to change email",
+			Message: message,
+			Code:    errorCode(err),
+			Details: errorDetails(err),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusAccepted, MessageResponse{Message: "A confirmation link has been sent to the new email"})
+}
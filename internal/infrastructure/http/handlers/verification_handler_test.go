@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier guarda os links de verificação enviados
+type recordingNotifier struct {
+	sent []string
+}
+
+func (n *recordingNotifier) SendVerificationEmail(_ context.Context, to, _, _ string) error {
+	n.sent = append(n.sent, to)
+	return nil
+}
+
+// verificationRouter registra as rotas de verificação com o limiter informado
+func verificationRouter(t *testing.T, repo *mocks.UserRepository, notifier *recordingNotifier, opts ...HandlerOption) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	issuer, err := auth.NewResetTokenIssuer(0, time.Hour, nil)
+	require.NoError(t, err)
+
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailVerification(issuer, notifier))
+	h := NewUserHandler(uc, opts...)
+	require.True(t, h.EmailVerificationEnabled())
+
+	router := gin.New()
+	// O reenvio roda em segundo plano; a resposta só volta ao teste depois dele
+	router.Use(func(c *gin.Context) {
+		c.Next()
+		uc.Wait()
+	})
+	router.POST("/auth/verify", h.VerifyEmail)
+	router.POST("/auth/verify/resend", h.ResendVerification)
+	router.POST("/me/email", func(c *gin.Context) {
+		ctxkeys.SetUserID(c, c.GetHeader("X-Test-User"))
+	}, h.RequestEmailChange)
+	return router
+}
+
+func resendVerification(router *gin.Engine, email, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", strings.NewReader(`{"email":"`+email+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestResendVerification(t *testing.T) {
+	t.Run("unverified account gets a new link", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		notifier := &recordingNotifier{}
+		router := verificationRouter(t, repo, notifier)
+
+		u := &user.User{ID: "42", Email: "ana@example.com", Name: "Ana", IsActive: true}
+		repo.On("GetByEmail", mock.Anything, "ana@example.com").Return(u, nil)
+		repo.On("ReplaceVerificationToken", mock.Anything, mock.MatchedBy(func(token *user.VerificationToken) bool {
+			return token.UserID == "42" && token.Email == "ana@example.com" && token.Hash != ""
+		})).Return(nil).Once()
+
+		w := resendVerification(router, "Ana@Example.com", "10.0.0.1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"ana@example.com"}, notifier.sent)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown and verified emails get the same response", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		notifier := &recordingNotifier{}
+		router := verificationRouter(t, repo, notifier)
+
+		verifiedAt := time.Now()
+		repo.On("GetByEmail", mock.Anything, "ghost@example.com").Return(nil, user.ErrUserNotFound)
+		repo.On("GetByEmail", mock.Anything, "done@example.com").Return(&user.User{ID: "7", Email: "done@example.com", IsActive: true, EmailVerifiedAt: &verifiedAt}, nil)
+
+		unknown := resendVerification(router, "ghost@example.com", "10.0.0.1")
+		verified := resendVerification(router, "done@example.com", "10.0.0.1")
+
+		assert.Equal(t, http.StatusOK, unknown.Code)
+		assert.Equal(t, unknown.Body.String(), verified.Body.String())
+		assert.Empty(t, notifier.sent)
+		repo.AssertNotCalled(t, "ReplaceVerificationToken", mock.Anything, mock.Anything)
+	})
+}
+
+func TestResendVerificationRateLimit(t *testing.T) {
+	newRouter := func() (*gin.Engine, *recordingNotifier) {
+		repo := &mocks.UserRepository{}
+		repo.On("GetByEmail", mock.Anything, mock.Anything).Return(&user.User{ID: "42", Email: "ana@example.com", IsActive: true}, nil)
+		repo.On("ReplaceVerificationToken", mock.Anything, mock.Anything).Return(nil)
+		notifier := &recordingNotifier{}
+		return verificationRouter(t, repo, notifier, WithVerificationResendLimiter(middleware.NewMemoryRateLimiter(2, time.Hour))), notifier
+	}
+
+	t.Run("per email", func(t *testing.T) {
+		router, notifier := newRouter()
+		// IPs diferentes não contornam o limite do email
+		assert.Equal(t, http.StatusOK, resendVerification(router, "ana@example.com", "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, resendVerification(router, "ana@example.com", "10.0.0.2").Code)
+		w := resendVerification(router, "ANA@example.com", "10.0.0.3")
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), `"retryable":true`)
+		assert.Len(t, notifier.sent, 2)
+	})
+
+	t.Run("per IP", func(t *testing.T) {
+		router, notifier := newRouter()
+		// Emails diferentes não contornam o limite do IP
+		assert.Equal(t, http.StatusOK, resendVerification(router, "a@example.com", "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, resendVerification(router, "b@example.com", "10.0.0.1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, resendVerification(router, "c@example.com", "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, resendVerification(router, "c@example.com", "10.0.0.2").Code)
+		assert.Len(t, notifier.sent, 3)
+	})
+}
+
+func TestVerifyEmailErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"unknown or used token", user.ErrVerificationTokenInvalid, CodeVerificationTokenInvalid},
+		{"expired token", nil, CodeVerificationTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.UserRepository{}
+			router := verificationRouter(t, repo, &recordingNotifier{})
+			if tt.err != nil {
+				repo.On("ConsumeVerificationToken", mock.Anything, auth.HashResetToken("token")).Return(nil, tt.err)
+			} else {
+				repo.On("ConsumeVerificationToken", mock.Anything, auth.HashResetToken("token")).
+					Return(&user.VerificationToken{UserID: "42", Email: "ana@example.com", ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/verify", strings.NewReader(`{"token":"token"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.code)
+			repo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRequestEmailChangeHandler(t *testing.T) {
+	u, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		notified []string
+	}{
+		{"link is sent to the new email", `{"email":"ana.souza@example.com","password":"password123"}`, http.StatusAccepted, []string{"ana.souza@example.com"}},
+		{"wrong password", `{"email":"ana.souza@example.com","password":"wrong"}`, http.StatusUnauthorized, nil},
+		{"same email", `{"email":"ana@example.com","password":"password123"}`, http.StatusBadRequest, nil},
+		{"invalid email", `{"email":"not-an-email","password":"password123"}`, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.UserRepository{}
+			notifier := &recordingNotifier{}
+			router := verificationRouter(t, repo, notifier)
+			repo.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+			repo.On("ExistsByEmail", mock.Anything, "ana.souza@example.com").Return(false, nil)
+			repo.On("ReplaceVerificationToken", mock.Anything, mock.Anything).Return(nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/email", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Test-User", u.ID)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.notified, notifier.sent)
+			// O email atual continua valendo até a confirmação
+			assert.Equal(t, "ana@example.com", u.Email)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,29 +16,25 @@ func AuthMiddleware(jwtService auth.JWTService) gin.HandlerFunc {
 		// Extrai o token do header Authorization
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Authorization header required",
-				"message": "Token not provided",
+			AbortWithJSON(c, http.StatusUnauthorized, errorResponse{
+				Error:   "Authorization header required",
+				Message: "Token not provided",
 			})
-			c.Abort()
 			return
 		}
 
 		// Verifica se o header tem o formato "Bearer <token>"
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid authorization header format",
-				"message": "Expected format: Bearer <token>",
+		tokenString, ok := bearerToken(authHeader)
+		if !ok {
+			AbortWithJSON(c, http.StatusUnauthorized, errorResponse{
+				Error:   "Invalid authorization header format",
+				Message: "Expected format: Bearer <token>",
 			})
-			c.Abort()
 			return
 		}
 
-		tokenString := tokenParts[1]
-
-		// Valida o token
-		claims, err := jwtService.ValidateToken(tokenString)
+		// Valida o token, reaproveitando a validação feita pelo rate limiting
+		claims, err := validateToken(c, jwtService, tokenString)
 		if err != nil {
 			status := http.StatusUnauthorized
 			message := "Invalid token"
@@ -44,38 +42,81 @@ func AuthMiddleware(jwtService auth.JWTService) gin.HandlerFunc {
 			if err == auth.ErrExpiredToken {
 				message = "Token expired"
 			}
+			if err == auth.ErrTokenNotYetValid {
+				message = "Token not valid yet"
+			}
+			if err == auth.ErrRevokedToken {
+				message = "Token revoked"
+			}
+			if err == auth.ErrInactiveAccount {
+				message = "Account is not active"
+			}
 
-			c.JSON(status, gin.H{
-				"error":   "Authentication failed",
-				"message": message,
+			AbortWithJSON(c, status, errorResponse{
+				Error:   "Authentication failed",
+				Message: message,
 			})
-			c.Abort()
 			return
 		}
 
 		// Adiciona as informações do usuário ao contexto
-		c.Set("userID", claims.UserID)
-		c.Set("userEmail", claims.Email)
-		c.Set("userRole", claims.Role)
+		ctxkeys.SetUserID(c, claims.UserID)
+		ctxkeys.SetUserEmail(c, claims.Email)
+		ctxkeys.SetUserRole(c, claims.Role)
+		ctxkeys.SetToken(c, tokenString)
+		ctxkeys.SetSessionID(c, claims.ID)
 
 		c.Next()
 	}
 }
 
+// validatedTokenKey guarda no contexto do Gin o resultado da validação do
+// bearer token. Uso interno do pacote: handlers leem as claims via ctxkeys
+const validatedTokenKey = "middleware.validatedToken"
+
+// validatedToken é o resultado da validação de um token
+type validatedToken struct {
+	token  string
+	claims *auth.Claims
+	err    error
+}
+
+// validateToken valida o token uma única vez por requisição: o rate limiting
+// (isenção por role) e o AuthMiddleware compartilham o resultado, incluindo
+// as consultas de sessão, versão e status da conta. Ambos recebem o mesmo
+// JWTService do router
+func validateToken(c *gin.Context, jwtService auth.JWTService, token string) (*auth.Claims, error) {
+	if cached, ok := c.Get(validatedTokenKey); ok {
+		if v, ok := cached.(validatedToken); ok && v.token == token {
+			return v.claims, v.err
+		}
+	}
+	claims, err := jwtService.ValidateToken(token)
+	c.Set(validatedTokenKey, validatedToken{token: token, claims: claims, err: err})
+	return claims, err
+}
+
+// bearerToken extrai o token de um header Authorization "Bearer <token>"
+func bearerToken(authHeader string) (string, bool) {
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		return "", false
+	}
+	return tokenParts[1], true
+}
+
 // RoleMiddleware cria um middleware para verificar roles específicos
 func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("userRole")
+		role, exists := ctxkeys.UserRole(c)
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error":   "User role not found",
-				"message": "Authentication required",
+			AbortWithJSON(c, http.StatusUnauthorized, errorResponse{
+				Error:   "User role not found",
+				Message: "Authentication required",
 			})
-			c.Abort()
 			return
 		}
 
-		role := userRole.(string)
 		hasPermission := false
 
 		for _, requiredRole := range requiredRoles {
@@ -86,11 +127,10 @@ func RoleMiddleware(requiredRoles ...string) gin.HandlerFunc {
 		}
 
 		if !hasPermission {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error":   "Insufficient permissions",
-				"message": "You don't have permission to access this resource",
+			AbortWithJSON(c, http.StatusForbidden, errorResponse{
+				Error:   "Insufficient permissions",
+				Message: "You don't have permission to access this resource",
 			})
-			c.Abort()
 			return
 		}
 
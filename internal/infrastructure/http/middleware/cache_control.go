@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Valores de Cache-Control usados pela API
+const (
+	// CacheControlNoStore impede que clientes e proxies guardem a resposta
+	CacheControlNoStore = "no-store"
+	// DefaultReadCacheControl permite cache apenas no cliente, sempre revalidado
+	DefaultReadCacheControl = "private, no-cache"
+)
+
+// CacheControlMiddleware define Cache-Control: leituras (GET e HEAD) recebem
+// readPolicy (vazio usa DefaultReadCacheControl) e as demais requisições sempre
+// recebem no-store. Respostas de leitura que não são 2xx também recebem
+// no-store, para que erros não fiquem em cache; 304 mantém a política, já que
+// apenas revalida a cópia em cache do cliente. Handlers e rotas podem
+// sobrescrever o header (ex.: NoStoreMiddleware em rotas sensíveis)
+func CacheControlMiddleware(readPolicy string) gin.HandlerFunc {
+	if readPolicy == "" {
+		readPolicy = DefaultReadCacheControl
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Header("Cache-Control", CacheControlNoStore)
+			c.Next()
+			return
+		}
+
+		c.Header("Cache-Control", readPolicy)
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, readPolicy: readPolicy}
+		c.Next()
+	}
+}
+
+// NoStoreMiddleware força no-store em rotas sensíveis (autenticação, dados pessoais)
+func NoStoreMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", CacheControlNoStore)
+		c.Next()
+	}
+}
+
+// cacheControlWriter troca a política de leitura por no-store quando o status
+// enviado não é 2xx nem 304. Headers definidos pelo handler são mantidos
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	readPolicy string
+	checked    bool
+}
+
+// check ajusta o header uma única vez, antes do envio dos headers
+func (w *cacheControlWriter) check() {
+	if w.checked {
+		return
+	}
+	w.checked = true
+
+	if w.ResponseWriter.Written() {
+		return
+	}
+	status := w.Status()
+	header := w.Header()
+	// Um 304 substitui os headers da cópia em cache; no-store a descartaria
+	if status == http.StatusNotModified {
+		return
+	}
+	if (status < 200 || status >= 300) && header.Get("Cache-Control") == w.readPolicy {
+		header.Set("Cache-Control", CacheControlNoStore)
+	}
+}
+
+// Write implementa io.Writer
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.check()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implementa io.StringWriter
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.check()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// WriteHeaderNow ajusta o header antes de enviá-lo
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.check()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush ajusta o header antes de enviar dados parciais
+func (w *cacheControlWriter) Flush() {
+	w.check()
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap expõe o writer original para http.ResponseController
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
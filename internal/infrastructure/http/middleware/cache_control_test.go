@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControlMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CacheControlMiddleware("private, max-age=60"))
+	router.GET("/users", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"users": []string{}}) })
+	router.GET("/users/missing", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"error": "User not found"}) })
+	router.GET("/export", func(c *gin.Context) {
+		c.Header("Cache-Control", CacheControlNoStore)
+		c.String(http.StatusOK, "id,email")
+	})
+	router.GET("/users/cached", func(c *gin.Context) { c.AbortWithStatus(http.StatusNotModified) })
+	router.POST("/users", func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{}) })
+	router.GET("/login", NoStoreMiddleware(), func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{"successful read uses configured policy", http.MethodGet, "/users", "private, max-age=60"},
+		{"failed read is not cached", http.MethodGet, "/users/missing", CacheControlNoStore},
+		{"not modified keeps the policy", http.MethodGet, "/users/cached", "private, max-age=60"},
+		{"handler override is kept", http.MethodGet, "/export", CacheControlNoStore},
+		{"mutation is never cached", http.MethodPost, "/users", CacheControlNoStore},
+		{"sensitive route forces no-store", http.MethodGet, "/login", CacheControlNoStore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.want, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestCacheControlMiddlewareDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CacheControlMiddleware(""))
+	router.GET("/", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, DefaultReadCacheControl, w.Header().Get("Cache-Control"))
+}
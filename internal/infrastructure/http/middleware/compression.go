@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCompressionLevel equilibra uso de CPU e economia de banda
+const DefaultCompressionLevel = 5
+
+// DefaultCompressibleTypes são os tipos comprimidos quando nenhum é configurado
+var DefaultCompressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/javascript",
+	"application/xml",
+	"text/html",
+	"text/plain",
+	"text/css",
+	"text/xml",
+}
+
+// neverCompressTypes nunca são comprimidos, mesmo se configurados: streams
+// precisam de flush imediato e os demais já são comprimidos
+var neverCompressTypes = []string{
+	"text/event-stream",
+	"application/gzip",
+	"application/zip",
+	"application/x-gzip",
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"font/woff2",
+}
+
+// CompressionConfig configura a compressão gzip das respostas
+type CompressionConfig struct {
+	// Level é o nível gzip (1-9); 0 usa DefaultCompressionLevel
+	Level int
+	// ContentTypes são os tipos de mídia comprimidos; vazio usa DefaultCompressibleTypes
+	ContentTypes []string
+}
+
+// GzipMiddleware comprime respostas cujo Content-Type está na lista configurada,
+// quando o cliente aceita gzip. A decisão é tomada na primeira escrita, quando
+// o Content-Type já foi definido pelo handler
+func GzipMiddleware(config CompressionConfig) gin.HandlerFunc {
+	level := config.Level
+	if level == 0 {
+		level = DefaultCompressionLevel
+	}
+
+	types := make(map[string]struct{})
+	contentTypes := config.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = DefaultCompressibleTypes
+	}
+	for _, t := range contentTypes {
+		types[strings.ToLower(t)] = struct{}{}
+	}
+	for _, t := range neverCompressTypes {
+		delete(types, t)
+	}
+
+	pool := &sync.Pool{
+		New: func() interface{} {
+			gz, _ := gzip.NewWriterLevel(io.Discard, level)
+			return gz
+		},
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+			c.Next()
+			return
+		}
+
+		w := &gzipWriter{ResponseWriter: c.Writer, types: types, pool: pool}
+		c.Writer = w
+		defer w.close()
+
+		c.Header("Vary", "Accept-Encoding")
+		c.Next()
+	}
+}
+
+// gzipWriter decide na primeira escrita se o corpo deve ser comprimido
+type gzipWriter struct {
+	gin.ResponseWriter
+	types   map[string]struct{}
+	pool    *sync.Pool
+	gz      *gzip.Writer
+	decided bool
+}
+
+// decide habilita a compressão se o Content-Type for comprimível e os headers
+// ainda não tiverem sido enviados
+func (w *gzipWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	header := w.Header()
+	if w.ResponseWriter.Written() || header.Get("Content-Encoding") != "" {
+		return
+	}
+	if status := w.Status(); status == http.StatusNoContent || status == http.StatusNotModified {
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return
+	}
+	if _, ok := w.types[mediaType]; !ok {
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	w.gz = w.pool.Get().(*gzip.Writer)
+	w.gz.Reset(w.ResponseWriter)
+}
+
+// Write implementa io.Writer
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implementa io.StringWriter
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow decide antes de enviar os headers
+func (w *gzipWriter) WriteHeaderNow() {
+	w.decide()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush envia os dados comprimidos pendentes
+func (w *gzipWriter) Flush() {
+	w.decide()
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap expõe o writer original para http.ResponseController
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close finaliza o stream gzip e devolve o writer ao pool
+func (w *gzipWriter) close() {
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(io.Discard)
+	w.pool.Put(w.gz)
+	w.gz = nil
+}
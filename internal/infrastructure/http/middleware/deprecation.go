@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation marca uma rota a ser aposentada
+type Deprecation struct {
+	// Method vazio vale para todos os métodos da rota
+	Method string
+	// Path é o caminho registrado, como em c.FullPath() (ex.: /api/v1/users/:id)
+	Path string
+	// Since é quando a rota ficou obsoleta; Sunset, quando deixará de responder (opcional)
+	Since  time.Time
+	Sunset time.Time
+	// Link é a documentação de migração (opcional)
+	Link string
+}
+
+// DeprecationMiddleware adiciona às respostas das rotas marcadas os headers
+// Deprecation (RFC 9745, "@<unix>"), Sunset (RFC 8594, HTTP-date) e Link com
+// rel="deprecation". As demais rotas não são alteradas
+func DeprecationMiddleware(deprecations []Deprecation) gin.HandlerFunc {
+	byRoute := make(map[string]Deprecation, len(deprecations))
+	for _, d := range deprecations {
+		byRoute[strings.ToUpper(d.Method)+" "+d.Path] = d
+	}
+
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			c.Next()
+			return
+		}
+
+		d, ok := byRoute[c.Request.Method+" "+path]
+		if !ok {
+			d, ok = byRoute[" "+path]
+		}
+		if ok {
+			header := c.Writer.Header()
+			header.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+			if !d.Sunset.IsZero() {
+				header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if d.Link != "" {
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, d.Link))
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	router.Use(DeprecationMiddleware([]Deprecation{
+		{Method: http.MethodGet, Path: "/v1/users", Since: since, Sunset: sunset, Link: "https://docs.example.com/migrate"},
+		{Path: "/v1/legacy/:id", Since: since},
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/v1/users", ok)
+	router.POST("/v1/users", ok)
+	router.GET("/v2/users", ok)
+	router.DELETE("/v1/legacy/:id", ok)
+
+	serve := func(method, path string) http.Header {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Header()
+	}
+
+	t.Run("marked route", func(t *testing.T) {
+		header := serve(http.MethodGet, "/v1/users")
+		assert.Equal(t, "@1767225600", header.Get("Deprecation"))
+		assert.Equal(t, "Wed, 01 Jul 2026 00:00:00 GMT", header.Get("Sunset"))
+		assert.Equal(t, `<https://docs.example.com/migrate>; rel="deprecation"; type="text/html"`, header.Get("Link"))
+	})
+
+	t.Run("route without method matches any method", func(t *testing.T) {
+		header := serve(http.MethodDelete, "/v1/legacy/42")
+		assert.Equal(t, "@1767225600", header.Get("Deprecation"))
+		assert.Empty(t, header.Get("Sunset"))
+		assert.Empty(t, header.Get("Link"))
+	})
+
+	t.Run("unmarked routes", func(t *testing.T) {
+		for _, req := range [][2]string{
+			{http.MethodPost, "/v1/users"},
+			{http.MethodGet, "/v2/users"},
+			{http.MethodGet, "/missing"},
+		} {
+			header := serve(req[0], req[1])
+			assert.Empty(t, header.Get("Deprecation"), req)
+			assert.Empty(t, header.Get("Sunset"), req)
+			assert.Empty(t, header.Get("Link"), req)
+		}
+	})
+}
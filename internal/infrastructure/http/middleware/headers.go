@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderSizeMiddleware rejeita com 431 e registra em log requisições cujos
+// headers excedem maxBytes. O net/http aplica o mesmo limite com uma folga
+// de 4 KiB e responde 431 sem log nem corpo JSON; este middleware torna o
+// limite exato e diagnosticável, indicando o maior header recebido
+func HeaderSizeMiddleware(maxBytes int, logger *slog.Logger) gin.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(c *gin.Context) {
+		size, largest, largestSize := headerSize(c.Request)
+		if size <= maxBytes {
+			c.Next()
+			return
+		}
+
+		logger.Warn("request rejected: headers too large",
+			"header_bytes", size,
+			"max_header_bytes", maxBytes,
+			"largest_header", largest,
+			"largest_header_bytes", largestSize,
+			"client_ip", c.ClientIP(),
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+		)
+
+		AbortWithJSON(c, http.StatusRequestHeaderFieldsTooLarge, errorResponse{
+			Error:   "Request header fields too large",
+			Message: "Request headers exceed the server limit; large Authorization tokens are a common cause",
+		})
+	}
+}
+
+// headerSize estima os bytes da linha de requisição e dos headers como
+// enviados no fio, retornando também o nome e o tamanho do maior header
+func headerSize(r *http.Request) (total int, largest string, largestSize int) {
+	total = len(r.Method) + len(r.RequestURI) + len(r.Proto) + 4 // espaços e CRLF
+	if r.Host != "" {
+		total += len("Host: ") + len(r.Host) + 2
+	}
+
+	for name, values := range r.Header {
+		for _, value := range values {
+			n := len(name) + len(": ") + len(value) + 2
+			total += n
+			if n > largestSize {
+				largest, largestSize = name, n
+			}
+		}
+	}
+
+	return total, largest, largestSize
+}
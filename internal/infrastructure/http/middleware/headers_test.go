@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderSizeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(HeaderSizeMiddleware(1024, slog.New(slog.NewTextHandler(&logs, nil))))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("within limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+strings.Repeat("a", 100))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("oversized authorization header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+strings.Repeat("a", 2048))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "Request header fields too large")
+		assert.Contains(t, logs.String(), "headers too large")
+		assert.Contains(t, logs.String(), "largest_header=Authorization")
+	})
+}
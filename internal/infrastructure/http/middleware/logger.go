@@ -4,12 +4,11 @@ import (
 	"log/slog"
 	"time"
 
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
-const requestIDKey = "requestID"
-
 // Logger cria um middleware de logging para Gin
 func Logger(log *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -17,9 +16,13 @@ func Logger(log *slog.Logger) gin.HandlerFunc {
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
-		// Gera um ID único para cada requisição
-		requestID := uuid.New().String()
-		c.Set(requestIDKey, requestID) // Adiciona o ID ao contexto do Gin
+		// Usa o ID definido pelo RequestIDMiddleware, que roda antes, para que log
+		// e header de resposta tenham o mesmo valor; gera um se ele não estiver na cadeia
+		requestID := GetRequestID(c)
+		if requestID == "" {
+			requestID = generateRequestID()
+			ctxkeys.SetRequestID(c, requestID)
+		}
 
 		// Cria um logger filho com o contexto da requisição
 		reqLog := log.With(
@@ -46,10 +49,6 @@ func Logger(log *slog.Logger) gin.HandlerFunc {
 
 // GetRequestID retorna o ID da requisição do contexto
 func GetRequestID(c *gin.Context) string {
-	if requestID, exists := c.Get(requestIDKey); exists {
-		if id, ok := requestID.(string); ok {
-			return id
-		}
-	}
-	return ""
+	requestID, _ := ctxkeys.RequestID(c)
+	return requestID
 } 
\ No newline at end of file
@@ -0,0 +1,305 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldNaming define a convenção dos nomes dos campos JSON nas respostas
+type FieldNaming string
+
+const (
+	// FieldNamingSnakeCase mantém os nomes dos DTOs (created_at, is_active)
+	FieldNamingSnakeCase FieldNaming = "snake_case"
+	// FieldNamingCamelCase converte os nomes na resposta (createdAt, isActive)
+	FieldNamingCamelCase FieldNaming = "camel_case"
+)
+
+// Valores do parâmetro profile do Accept que escolhem a convenção por requisição,
+// ex.: "Accept: application/json; profile=camelCase"
+const (
+	profileSnakeCase = "snakecase"
+	profileCamelCase = "camelcase"
+)
+
+// fieldNamingKey guarda no contexto a convenção padrão definida pelo middleware
+const fieldNamingKey = "field_naming"
+
+// ParseFieldNaming valida uma convenção vinda da configuração; vazio usa snake_case
+func ParseFieldNaming(value string) (FieldNaming, error) {
+	switch naming := FieldNaming(value); naming {
+	case "":
+		return FieldNamingSnakeCase, nil
+	case FieldNamingSnakeCase, FieldNamingCamelCase:
+		return naming, nil
+	default:
+		return "", fmt.Errorf("invalid field naming %q", value)
+	}
+}
+
+// FieldNamingMiddleware define a convenção padrão das respostas. Sem ele, ou
+// com snake_case, os DTOs são serializados como declarados. Deve vir antes dos
+// middlewares que respondem erros, para que eles sigam a mesma convenção
+func FieldNamingMiddleware(naming FieldNaming) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(fieldNamingKey, naming)
+		c.Next()
+	}
+}
+
+// fieldNaming retorna a convenção da requisição: o profile do Accept tem
+// precedência sobre o padrão configurado
+func fieldNaming(c *gin.Context) FieldNaming {
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil || (mediaType != gin.MIMEJSON && mediaType != "*/*") {
+			continue
+		}
+		switch strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(params["profile"])) {
+		case profileCamelCase:
+			return FieldNamingCamelCase
+		case profileSnakeCase:
+			return FieldNamingSnakeCase
+		}
+	}
+
+	if naming, ok := c.Get(fieldNamingKey); ok {
+		if naming, ok := naming.(FieldNaming); ok {
+			return naming
+		}
+	}
+	return FieldNamingSnakeCase
+}
+
+// RespondJSON serializa o DTO na convenção de nomes da requisição. É a saída
+// JSON de todos os handlers e dos middlewares, no lugar de c.JSON
+func RespondJSON(c *gin.Context, status int, obj any) {
+	if fieldNaming(c) != FieldNamingCamelCase {
+		c.JSON(status, obj)
+		return
+	}
+
+	data, err := camelCaseJSON(obj)
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "application/json; charset=utf-8", data)
+}
+
+// AbortWithJSON interrompe a cadeia respondendo o DTO com RespondJSON
+func AbortWithJSON(c *gin.Context, status int, obj any) {
+	RespondJSON(c, status, obj)
+	c.Abort()
+}
+
+// errorResponse é o corpo dos erros respondidos pelos middlewares, com os
+// mesmos campos de handlers.ErrorResponse
+type errorResponse struct {
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable,omitempty"`
+}
+
+// camelCaseJSON serializa obj convertendo para camelCase apenas os nomes de
+// campos de structs. Chaves de mapas são dados (ex.: nomes de papéis em
+// estatísticas) e ficam como estão, assim como a saída de tipos com MarshalJSON
+func camelCaseJSON(obj any) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	if err := transformValue(dec, &out, reflect.ValueOf(obj)); err != nil {
+		return nil, fmt.Errorf("failed to convert field names: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// transformValue copia o próximo valor do decoder para out. v é o valor Go que
+// o produziu: as chaves só são convertidas quando ele é uma struct. Um v
+// inválido (valor desconhecido ou serialização própria) copia o JSON intacto
+func transformValue(dec *json.Decoder, out *bytes.Buffer, v reflect.Value) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	v = jsonSource(v)
+
+	switch token {
+	case json.Delim('{'):
+		out.WriteByte('{')
+		for i := 0; dec.More(); i++ {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			name := key.(string)
+			child := objectMember(v, name)
+			if v.Kind() == reflect.Struct {
+				name = snakeToCamel(name)
+			}
+			encoded, _ := json.Marshal(name)
+			out.Write(encoded)
+			out.WriteByte(':')
+			if err := transformValue(dec, out, child); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+		_, err = dec.Token()
+		return err
+	case json.Delim('['):
+		out.WriteByte('[')
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			var child reflect.Value
+			if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && i < v.Len() {
+				child = v.Index(i)
+			}
+			if err := transformValue(dec, out, child); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		_, err = dec.Token()
+		return err
+	default:
+		encoded, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		out.Write(encoded)
+		return nil
+	}
+}
+
+// jsonSource resolve ponteiros e interfaces até o valor serializado. Tipos com
+// serialização própria viram um valor inválido, pois suas chaves não são campos
+func jsonSource(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		if customMarshaler(v) {
+			return reflect.Value{}
+		}
+		if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface {
+			return v
+		}
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// customMarshaler informa se v implementa json.Marshaler ou encoding.TextMarshaler
+func customMarshaler(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if v.CanAddr() {
+		pt := reflect.PointerTo(t)
+		return pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType)
+	}
+	return false
+}
+
+// objectMember retorna o valor Go da chave name de um objeto JSON serializado
+// a partir de v: o campo da struct ou o elemento do mapa
+func objectMember(v reflect.Value, name string) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		return structField(v, name)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}
+		}
+		return v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+	default:
+		return reflect.Value{}
+	}
+}
+
+// structField procura o campo serializado como name, seguindo as tags json e
+// os campos promovidos de structs embutidas
+func structField(v reflect.Value, name string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && tagName == "" {
+			embedded := v.Field(i)
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if found := structField(embedded, name); found.IsValid() {
+					return found
+				}
+				continue
+			}
+		}
+
+		if tagName == "" {
+			tagName = field.Name
+		}
+		if tagName == name {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// snakeToCamel converte "created_at" em "createdAt"; nomes sem "_" não mudam
+func snakeToCamel(name string) string {
+	if !strings.Contains(name, "_") {
+		return name
+	}
+
+	var b strings.Builder
+	upper := false
+	for i, r := range name {
+		switch {
+		case r == '_' && i > 0:
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
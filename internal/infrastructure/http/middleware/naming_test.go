@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namingItem tem uma chave com serialização própria e um campo promovido
+type namingItem struct {
+	namingBase
+	CreatedAt time.Time `json:"created_at"`
+	Note      *string   `json:"note_text"`
+}
+
+type namingBase struct {
+	ItemID string `json:"item_id"`
+}
+
+// namingStamp serializa um objeto próprio, que não é convertido
+type namingStamp struct{}
+
+func (namingStamp) MarshalJSON() ([]byte, error) { return []byte(`{"unix_time":1}`), nil }
+
+func TestCamelCaseJSON(t *testing.T) {
+	note := "a_b"
+	obj := struct {
+		UserID   string                    `json:"user_id"`
+		IsActive bool                      `json:"is_active"`
+		Items    []namingItem              `json:"items"`
+		Roles    map[string]int            `json:"roles"`
+		Details  map[string]any            `json:"details"`
+		Stamp    namingStamp               `json:"stamp_value"`
+		ByName   map[string]*namingBase    `json:"by_name"`
+		Raw      map[string]map[string]int `json:"raw_counts"`
+		Total    float64
+	}{
+		UserID:   "a_b",
+		IsActive: true,
+		Items:    []namingItem{{namingBase: namingBase{ItemID: "x_1"}, Note: &note}},
+		Roles:    map[string]int{"support_agent": 2},
+		Details:  map[string]any{"email_domain": namingBase{ItemID: "y"}},
+		ByName:   map[string]*namingBase{"first_one": {ItemID: "z"}},
+		Raw:      map[string]map[string]int{"by_role": {"support_agent": 1}},
+		Total:    1.5,
+	}
+
+	data, err := camelCaseJSON(obj)
+	require.NoError(t, err)
+	assert.Equal(t, `{"userId":"a_b","isActive":true,`+
+		`"items":[{"itemId":"x_1","createdAt":"0001-01-01T00:00:00Z","noteText":"a_b"}],`+
+		`"roles":{"support_agent":2},"details":{"email_domain":{"itemId":"y"}},`+
+		`"stampValue":{"unix_time":1},"byName":{"first_one":{"itemId":"z"}},`+
+		`"rawCounts":{"by_role":{"support_agent":1}},"Total":1.5}`, string(data),
+		"only struct field names change; map keys, values and custom marshalers are kept")
+
+	assert.Equal(t, "emailVerified", snakeToCamel("email_verified"))
+	assert.Equal(t, "_internal", snakeToCamel("_internal"))
+	assert.Equal(t, "total", snakeToCamel("total"))
+}
+
+func TestParseFieldNaming(t *testing.T) {
+	naming, err := ParseFieldNaming("")
+	require.NoError(t, err)
+	assert.Equal(t, FieldNamingSnakeCase, naming)
+
+	_, err = ParseFieldNaming("kebab-case")
+	assert.Error(t, err)
+}
+
+func TestMiddlewareErrorsFollowFieldNaming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(FieldNamingMiddleware(FieldNamingCamelCase))
+	router.Use(Recovery(nil, false))
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	router.GET("/private", AuthMiddleware(nil), func(c *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"requestId":`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/private", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"Authorization header required","message":"Token not provided"}`, w.Body.String())
+}
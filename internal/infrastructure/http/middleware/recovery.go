@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recoveryResponse é o corpo do 500 de um pânico; panic e stack só aparecem
+// com exposeDetails
+type recoveryResponse struct {
+	Error     string   `json:"error"`
+	Message   string   `json:"message"`
+	RequestID string   `json:"request_id"`
+	Panic     string   `json:"panic,omitempty"`
+	Stack     []string `json:"stack,omitempty"`
+}
+
+// Recovery recupera pânicos dos handlers, registra em log a mensagem e o stack
+// com o ID da requisição e responde 500. Com exposeDetails (ambiente de
+// desenvolvimento) a resposta também traz a mensagem e o stack; caso contrário
+// é um erro genérico, sem detalhes internos
+func Recovery(logger *slog.Logger, exposeDetails bool) gin.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler interrompe a resposta de propósito; o net/http
+			// trata esse pânico sem log
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			requestID := GetRequestID(c)
+			message := fmt.Sprint(recovered)
+			stack := string(debug.Stack())
+
+			logger.ErrorContext(c.Request.Context(), "panic recovered",
+				"request_id", requestID,
+				"panic", message,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", stack,
+			)
+
+			// Com a resposta já iniciada, só resta interromper a cadeia
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			body := recoveryResponse{
+				Error:     "Internal server error",
+				Message:   "An unexpected error occurred",
+				RequestID: requestID,
+			}
+			if exposeDetails {
+				body.Panic = message
+				body.Stack = strings.Split(strings.TrimSpace(stack), "\n")
+			}
+			AbortWithJSON(c, http.StatusInternalServerError, body)
+		}()
+
+		c.Next()
+	}
+}
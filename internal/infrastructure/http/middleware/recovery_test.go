@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(t *testing.T, exposeDetails bool) (*httptest.ResponseRecorder, map[string]interface{}, string) {
+		var logs bytes.Buffer
+		router := gin.New()
+		router.Use(RequestIDMiddleware(RequestIDConfig{}))
+		router.Use(Recovery(slog.New(slog.NewJSONHandler(&logs, nil)), exposeDetails))
+		router.GET("/boom", func(c *gin.Context) { panic("database password is hunter2") })
+
+		req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+		req.Header.Set(DefaultRequestIDHeader, "req-panic")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w, body, logs.String()
+	}
+
+	t.Run("production hides details", func(t *testing.T) {
+		w, body, logs := serve(t, false)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "Internal server error", body["error"])
+		assert.Equal(t, "req-panic", body["request_id"])
+		assert.NotContains(t, body, "panic")
+		assert.NotContains(t, body, "stack")
+		assert.NotContains(t, w.Body.String(), "hunter2")
+
+		// O log do servidor sempre traz a mensagem, o stack e o ID
+		assert.Contains(t, logs, `"request_id":"req-panic"`)
+		assert.Contains(t, logs, "hunter2")
+		assert.Contains(t, logs, "recovery_test.go")
+	})
+
+	t.Run("development exposes panic and stack", func(t *testing.T) {
+		w, body, logs := serve(t, true)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "database password is hunter2", body["panic"])
+		stack, ok := body["stack"].([]interface{})
+		require.True(t, ok)
+		assert.Contains(t, w.Body.String(), "recovery_test.go")
+		assert.NotEmpty(t, stack)
+		assert.Contains(t, logs, `"request_id":"req-panic"`)
+	})
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"strings"
+
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DefaultRequestIDHeader é o header canônico do ID de correlação
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limita IDs recebidos de clientes, que vão para logs e respostas
+const maxRequestIDLength = 128
+
+// RequestIDConfig configura de onde o ID de correlação é lido e em qual header é devolvido
+type RequestIDConfig struct {
+	// Header é o primeiro header lido e o único enviado na resposta; vazio usa DefaultRequestIDHeader
+	Header string
+	// Sources são headers alternativos lidos em ordem quando Header está ausente
+	// ou inválido (ex.: X-Correlation-ID, traceparent)
+	Sources []string
+}
+
+// RequestIDMiddleware reaproveita o ID de correlação recebido ou gera um novo,
+// guarda no contexto (usado pelo Logger) e o devolve no header canônico.
+// Valores vazios, longos demais ou com caracteres não imprimíveis são ignorados
+func RequestIDMiddleware(config RequestIDConfig) gin.HandlerFunc {
+	header := config.Header
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	candidates := append([]string{header}, config.Sources...)
+
+	return func(c *gin.Context) {
+		requestID := ""
+		for _, name := range candidates {
+			if id := requestIDFrom(name, c.GetHeader(name)); id != "" {
+				requestID = id
+				break
+			}
+		}
+		if requestID == "" {
+			requestID = generateRequestID()
+		}
+
+		c.Header(header, requestID)
+		ctxkeys.SetRequestID(c, requestID)
+
+		c.Next()
+	}
+}
+
+// requestIDFrom extrai um ID válido do valor de um header. Do traceparent
+// (W3C Trace Context) usa apenas o trace-id, comum a todos os serviços do trace
+func requestIDFrom(name, value string) string {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(name, "traceparent") {
+		parts := strings.Split(value, "-")
+		if len(parts) != 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == "" {
+			return ""
+		}
+		value = parts[1]
+	}
+
+	if value == "" || len(value) > maxRequestIDLength {
+		return ""
+	}
+	for _, r := range value {
+		if r < 0x21 || r > 0x7e {
+			return ""
+		}
+	}
+	return value
+}
+
+// generateRequestID gera um ID único para o request
+func generateRequestID() string {
+	return uuid.NewString()
+}
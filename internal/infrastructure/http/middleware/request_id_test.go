@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddlewareCustomHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(RequestIDMiddleware(RequestIDConfig{
+		Header:  "X-Correlation-ID",
+		Sources: []string{"X-Request-ID", "traceparent"},
+	}))
+	router.Use(Logger(slog.New(slog.NewJSONHandler(&logs, nil))))
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, GetRequestID(c)) })
+
+	serve := func(headers map[string]string) *httptest.ResponseRecorder {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	loggedID := func(t *testing.T) string {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+		id, _ := entry["request_id"].(string)
+		return id
+	}
+
+	t.Run("canonical header wins", func(t *testing.T) {
+		w := serve(map[string]string{"X-Correlation-ID": "corr-1", "X-Request-ID": "req-1"})
+		assert.Equal(t, "corr-1", w.Header().Get("X-Correlation-ID"))
+		assert.Empty(t, w.Header().Get("X-Request-ID"), "only the canonical header is emitted")
+		assert.Equal(t, "corr-1", w.Body.String())
+		assert.Equal(t, "corr-1", loggedID(t))
+	})
+
+	t.Run("sources in priority order", func(t *testing.T) {
+		w := serve(map[string]string{
+			"X-Request-ID": "req-1",
+			"traceparent":  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		})
+		assert.Equal(t, "req-1", w.Header().Get("X-Correlation-ID"))
+	})
+
+	t.Run("traceparent uses the trace id", func(t *testing.T) {
+		w := serve(map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Header().Get("X-Correlation-ID"))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", loggedID(t))
+	})
+
+	t.Run("invalid values are replaced", func(t *testing.T) {
+		w := serve(map[string]string{
+			"X-Correlation-ID": strings.Repeat("a", maxRequestIDLength+1),
+			"traceparent":      "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		})
+		id := w.Header().Get("X-Correlation-ID")
+		assert.Len(t, id, 36, "falls back to a generated uuid")
+		assert.Equal(t, id, loggedID(t))
+	})
+}
@@ -2,130 +2,499 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/clock"
+
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"golang.org/x/time/rate"
 )
 
+// Políticas aplicadas quando o backend de rate limiting falha
+const (
+	RateLimitFailOpen   = "open"   // permite a requisição (prioriza disponibilidade)
+	RateLimitFailClosed = "closed" // responde 429 (prioriza proteção)
+)
+
+// RateLimiter decide se a requisição identificada por key está dentro do limite.
+// Um erro indica que o backend não conseguiu decidir
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
+
+// QuotaRateLimiter é implementado por backends que sabem quanto resta do
+// limite após cada decisão, habilitando o header X-RateLimit-Warning
+type QuotaRateLimiter interface {
+	RateLimiter
+	// AllowQuota decide como Allow e retorna as requisições restantes e o limite
+	AllowQuota(ctx context.Context, key string) (allowed bool, remaining, limit int, err error)
+}
+
+// Headers de rate limiting nas respostas
+const (
+	// HeaderRateLimitWarning avisa que o cliente está perto do limite
+	HeaderRateLimitWarning = "X-RateLimit-Warning"
+	// HeaderRateLimitLimit é o limite (requisições por segundo) aplicado ao chamador
+	HeaderRateLimitLimit = "X-RateLimit-Limit"
+)
+
+// RateLimitTenant é um cliente identificado pela chave no header X-API-Key,
+// com um limite próprio no lugar do limite padrão por IP
+type RateLimitTenant struct {
+	Name   string
+	APIKey string
+	Limit  int // requests per second
+}
+
 // SecurityConfig configurações de segurança
 type SecurityConfig struct {
 	CORSOrigins []string
-	RateLimit   int // requests per second
+	// CORSAllowCredentials envia Access-Control-Allow-Credentials; exige origens exatas
+	CORSAllowCredentials bool
+	// CORSMaxAge é por quanto tempo o navegador pode cachear o preflight; 0 omite o header
+	CORSMaxAge time.Duration
+	RateLimit  int // requests per second
+
+	// RateLimiter é o backend de rate limiting; nil usa limiters em memória por IP
+	RateLimiter RateLimiter
+	// RateLimitIdleTTL é por quanto tempo o limiter em memória guarda a chave de
+	// um cliente inativo; 0 usa DefaultRateLimiterIdleTTL
+	RateLimitIdleTTL time.Duration
+	// RateLimitTenants são os clientes com limite próprio; os demais usam RateLimit
+	RateLimitTenants []RateLimitTenant
+	// TenantRateLimiter cria o backend com o limite de um tenant; nil usa limiters em memória
+	TenantRateLimiter func(limit int) RateLimiter
+	// RateLimitFailMode define a política quando o backend falha (padrão: open)
+	RateLimitFailMode string
+	// OnRateLimitBackendError é chamado a cada falha do backend, com a política aplicada
+	OnRateLimitBackendError func(failMode string)
+	// Logger registra falhas do backend; nil usa slog.Default()
+	Logger *slog.Logger
+
+	// RateLimitExemptRoles isenta do rate limiting requisições com Bearer token
+	// válido para um destes roles. Como o limiter roda antes da autenticação, o
+	// token é validado com TokenValidator; sem ele nenhum role é isento
+	RateLimitExemptRoles []string
+	// RateLimitExemptAPIKeys isenta requisições com um destes valores no header X-API-Key
+	RateLimitExemptAPIKeys []string
+	// TokenValidator valida o token para conhecer o role do chamador. O
+	// resultado é reaproveitado pelo AuthMiddleware, que deve usar o mesmo serviço
+	TokenValidator auth.JWTService
+
+	// RateLimitWarningThreshold é a fração final do limite (ex.: 0.1 = últimos
+	// 10%) em que as respostas recebem X-RateLimit-Warning; 0 desabilita.
+	// Exige um backend que implemente QuotaRateLimiter
+	RateLimitWarningThreshold float64
 }
 
-// CORSMiddleware configura CORS de forma segura
+// HeaderAPIKey é o header com a chave de API isenta de rate limiting
+const HeaderAPIKey = "X-API-Key"
+
+// CORSMiddleware configura CORS de forma segura.
+// Pode ser aplicado globalmente ou por grupo de rotas; neste caso o grupo
+// deve registrar uma rota OPTIONS para que o preflight alcance o middleware.
+// Com credenciais habilitadas, "*" é ignorado e apenas origens exatas são refletidas
 func CORSMiddleware(config SecurityConfig) gin.HandlerFunc {
+	maxAge := ""
+	if config.CORSMaxAge > 0 {
+		maxAge = strconv.Itoa(int(config.CORSMaxAge / time.Second))
+	}
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
-		
-		// Verificar se a origem está na lista permitida
-		allowed := false
-		for _, allowedOrigin := range config.CORSOrigins {
-			if allowedOrigin == "*" || allowedOrigin == origin {
-				allowed = true
-				break
+
+		// A resposta varia conforme a origem; sem isso um cache poderia servir os
+		// headers CORS de uma origem para outra
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if allowOrigin := corsAllowOrigin(config, origin); allowOrigin != "" {
+			c.Header("Access-Control-Allow-Origin", allowOrigin)
+			if config.CORSAllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
 			}
 		}
-		
-		if allowed {
-			c.Header("Access-Control-Allow-Origin", origin)
-		}
-		
-		c.Header("Access-Control-Allow-Credentials", "true")
+
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-		
-		// Adicionar headers de segurança
-		c.Header("X-Content-Type-Options", "nosniff")
-		c.Header("X-Frame-Options", "DENY")
-		c.Header("X-XSS-Protection", "1; mode=block")
-		c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
-		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
-		c.Header("Content-Security-Policy", "default-src 'self'")
-		
+
+		// Os headers de segurança ficam a cargo do SecurityHeadersMiddleware global,
+		// permitindo aplicar este middleware por grupo de rotas sem duplicá-los
+
 		if c.Request.Method == "OPTIONS" {
+			if maxAge != "" {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
 			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
-		
+
 		c.Next()
 	}
 }
 
-// RateLimitMiddleware implementa rate limiting por IP
+// corsAllowOrigin retorna o valor de Access-Control-Allow-Origin para a origem,
+// ou vazio se ela não for permitida. O curinga só vale sem credenciais e é
+// respondido como "*", nunca refletindo a origem da requisição
+func corsAllowOrigin(config SecurityConfig, origin string) string {
+	if origin == "" {
+		return ""
+	}
+
+	wildcard := false
+	for _, allowedOrigin := range config.CORSOrigins {
+		if allowedOrigin == origin {
+			return origin
+		}
+		if allowedOrigin == "*" {
+			wildcard = true
+		}
+	}
+
+	if wildcard && !config.CORSAllowCredentials {
+		return "*"
+	}
+	return ""
+}
+
+// PreflightHandler responde requisições OPTIONS de um grupo de rotas.
+// O CORSMiddleware do grupo já encerra o preflight; este handler só garante
+// que exista uma rota OPTIONS para o middleware do grupo ser executado
+func PreflightHandler(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+// RateLimitMiddleware implementa rate limiting por IP. Requisições com a
+// chave de um tenant em X-API-Key usam o limite do tenant, contado pela chave
+// e não pelo IP. Toda resposta limitada recebe X-RateLimit-Limit
 func RateLimitMiddleware(config SecurityConfig) gin.HandlerFunc {
-	// Criar um limiter por IP
-	limiters := make(map[string]*rate.Limiter)
-	
+	limiter := config.RateLimiter
+	if limiter == nil {
+		limiter = newMemoryRateLimiter(config.RateLimit, config.RateLimitIdleTTL)
+	}
+
+	tenants := make([]tenantLimiter, 0, len(config.RateLimitTenants))
+	for _, tenant := range config.RateLimitTenants {
+		var backend RateLimiter
+		if config.TenantRateLimiter != nil {
+			backend = config.TenantRateLimiter(tenant.Limit)
+		}
+		if backend == nil {
+			backend = newMemoryRateLimiter(tenant.Limit, config.RateLimitIdleTTL)
+		}
+		tenants = append(tenants, tenantLimiter{RateLimitTenant: tenant, limiter: backend})
+	}
+
+	failMode := config.RateLimitFailMode
+	if failMode == "" {
+		failMode = RateLimitFailOpen
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	exemptRoles := make(map[string]struct{}, len(config.RateLimitExemptRoles))
+	for _, role := range config.RateLimitExemptRoles {
+		exemptRoles[role] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
+		if rateLimitExempt(c, config, exemptRoles) {
+			c.Next()
+			return
+		}
+
 		ip := c.ClientIP()
-		
-		// Criar limiter para o IP se não existir
-		limiter, exists := limiters[ip]
-		if !exists {
-			limiter = rate.NewLimiter(rate.Limit(config.RateLimit), config.RateLimit)
-			limiters[ip] = limiter
-		}
-		
+		key, keyLimiter, keyLimit := ip, limiter, config.RateLimit
+		if tenant := matchTenant(c.GetHeader(HeaderAPIKey), tenants); tenant != nil {
+			key, keyLimiter, keyLimit = "tenant:"+tenant.Name, tenant.limiter, tenant.Limit
+		}
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(keyLimit))
+
+		allowed, remaining, limit, err := allowWithQuota(c.Request.Context(), keyLimiter, key, config.RateLimitWarningThreshold)
+		if err != nil {
+			// Sem decisão do backend: aplica a política configurada
+			logger.Error("rate limit backend unavailable",
+				"fail_mode", failMode,
+				"client_ip", ip,
+				"rate_limit_key", key,
+				"error", err,
+			)
+			if config.OnRateLimitBackendError != nil {
+				config.OnRateLimitBackendError(failMode)
+			}
+			allowed = failMode != RateLimitFailClosed
+		}
+
 		// Verificar se o request está dentro do limite
-		if !limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "Rate limit exceeded",
-				"message": "Too many requests, please try again later",
+		if !allowed {
+			AbortWithJSON(c, http.StatusTooManyRequests, errorResponse{
+				Error:     "Rate limit exceeded",
+				Message:   "Too many requests, please try again later",
+				Retryable: true,
 			})
-			c.Abort()
 			return
 		}
-		
+
+		if err == nil && nearRateLimit(remaining, limit, config.RateLimitWarningThreshold) {
+			c.Header(HeaderRateLimitWarning, fmt.Sprintf("%d of %d requests remaining", remaining, limit))
+		}
+
 		c.Next()
 	}
 }
 
-// RequestIDMiddleware adiciona um ID único para cada request
-func RequestIDMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = generateRequestID()
-		}
-		
-		c.Header("X-Request-ID", requestID)
-		c.Set("request_id", requestID)
-		
-		c.Next()
+// tenantLimiter é um tenant com o backend do seu limite
+type tenantLimiter struct {
+	RateLimitTenant
+	limiter RateLimiter
+}
+
+// matchTenant retorna o tenant da chave de API, ou nil se a chave for vazia ou desconhecida
+func matchTenant(apiKey string, tenants []tenantLimiter) *tenantLimiter {
+	if apiKey == "" {
+		return nil
 	}
+	for i := range tenants {
+		if tenants[i].APIKey != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(tenants[i].APIKey)) == 1 {
+			return &tenants[i]
+		}
+	}
+	return nil
+}
+
+// allowWithQuota consulta a cota restante quando o aviso está habilitado e o
+// backend a conhece; caso contrário usa Allow e retorna limit 0
+func allowWithQuota(ctx context.Context, limiter RateLimiter, key string, threshold float64) (bool, int, int, error) {
+	if quota, ok := limiter.(QuotaRateLimiter); ok && threshold > 0 {
+		return quota.AllowQuota(ctx, key)
+	}
+	allowed, err := limiter.Allow(ctx, key)
+	return allowed, 0, 0, err
+}
+
+// nearRateLimit informa se remaining está dentro da fração final threshold do limite
+func nearRateLimit(remaining, limit int, threshold float64) bool {
+	return threshold > 0 && limit > 0 && float64(remaining) <= float64(limit)*threshold
+}
+
+// rateLimitExempt indica se a requisição está isenta do rate limiting por uma
+// chave de API configurada ou por um token válido de um role isento.
+// Requisições sem credencial válida nunca são isentas
+func rateLimitExempt(c *gin.Context, config SecurityConfig, exemptRoles map[string]struct{}) bool {
+	if key := c.GetHeader(HeaderAPIKey); key != "" {
+		for _, exempt := range config.RateLimitExemptAPIKeys {
+			if exempt != "" && subtle.ConstantTimeCompare([]byte(key), []byte(exempt)) == 1 {
+				return true
+			}
+		}
+	}
+
+	if len(exemptRoles) == 0 || config.TokenValidator == nil {
+		return false
+	}
+	token, ok := bearerToken(c.GetHeader("Authorization"))
+	if !ok {
+		return false
+	}
+	claims, err := validateToken(c, config.TokenValidator, token)
+	if err != nil {
+		return false
+	}
+	_, exempt := exemptRoles[claims.Role]
+	return exempt
+}
+
+// DefaultRateLimiterIdleTTL é por quanto tempo a chave de um cliente inativo
+// fica em memória quando SecurityConfig.RateLimitIdleTTL não é definido
+const DefaultRateLimiterIdleTTL = 10 * time.Minute
+
+// memoryRateLimiter mantém um token bucket por chave na memória da instância.
+// O mapa é compartilhado entre as requisições: chaves existentes são lidas com
+// o lock de leitura e só a criação e a remoção usam o lock de escrita. Chaves
+// sem acesso há mais que o TTL de inatividade são removidas em background
+type memoryRateLimiter struct {
+	limit    int
+	interval time.Duration
+	idleTTL  time.Duration
+	clock    clock.Clock
+
+	mu       sync.RWMutex
+	limiters map[string]*memoryLimiterEntry
+
+	// nextSweep (UnixNano) é quando a próxima varredura pode começar;
+	// sweeping impede varreduras simultâneas
+	nextSweep atomic.Int64
+	sweeping  atomic.Bool
+	// onSweep é chamado ao fim de cada varredura (usado nos testes)
+	onSweep func()
+}
+
+// memoryLimiterEntry é o bucket de uma chave e o último acesso a ele (UnixNano)
+type memoryLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+// newMemoryRateLimiter cria um limiter em memória com limit requisições por
+// segundo; idleTTL <= 0 usa DefaultRateLimiterIdleTTL
+func newMemoryRateLimiter(limit int, idleTTL time.Duration) *memoryRateLimiter {
+	if idleTTL <= 0 {
+		idleTTL = DefaultRateLimiterIdleTTL
+	}
+	return &memoryRateLimiter{
+		limit:    limit,
+		interval: time.Second,
+		idleTTL:  idleTTL,
+		clock:    clock.System,
+		limiters: make(map[string]*memoryLimiterEntry),
+	}
+}
+
+// NewMemoryRateLimiter cria um RateLimiter em memória que aceita limit
+// requisições por chave a cada interval, repostas gradualmente. Útil para
+// limites por rota mais restritos que o global (ex.: reenvio de emails)
+func NewMemoryRateLimiter(limit int, interval time.Duration) RateLimiter {
+	m := newMemoryRateLimiter(limit, 0)
+	m.interval = interval
+	return m
+}
+
+// Allow implementa RateLimiter; o backend em memória nunca falha
+func (m *memoryRateLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	allowed, _, _, err := m.AllowQuota(ctx, key)
+	return allowed, err
+}
+
+// AllowQuota implementa QuotaRateLimiter; os tokens restantes do bucket são a cota
+func (m *memoryRateLimiter) AllowQuota(_ context.Context, key string) (bool, int, int, error) {
+	now := m.clock.Now()
+	m.maybeSweep(now)
+
+	entry := m.entry(key, now)
+	entry.lastSeen.Store(now.UnixNano())
+
+	// rate.Limiter é seguro para uso concorrente
+	allowed := entry.limiter.AllowN(now, 1)
+	remaining := int(math.Floor(entry.limiter.TokensAt(now)))
+	if remaining < 0 {
+		remaining = 0
+	}
+	return allowed, remaining, m.limit, nil
+}
+
+// entry retorna o bucket da chave, criando-o no primeiro acesso
+func (m *memoryRateLimiter) entry(key string, now time.Time) *memoryLimiterEntry {
+	m.mu.RLock()
+	entry, exists := m.limiters[key]
+	m.mu.RUnlock()
+	if exists {
+		return entry
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Outra requisição pode ter criado a chave entre os locks
+	if entry, exists = m.limiters[key]; !exists {
+		entry = &memoryLimiterEntry{
+			limiter: rate.NewLimiter(rate.Limit(float64(m.limit)/m.interval.Seconds()), m.limit),
+		}
+		entry.lastSeen.Store(now.UnixNano())
+		m.limiters[key] = entry
+	}
+	return entry
+}
+
+// ttl é o TTL efetivo: nunca menor que o intervalo do limite, para que a
+// remoção só aconteça quando o bucket já estaria cheio de novo
+func (m *memoryRateLimiter) ttl() time.Duration {
+	return max(m.idleTTL, m.interval)
 }
 
-// TimeoutMiddleware adiciona timeout para requests
-func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+// maybeSweep inicia uma varredura em background, no máximo uma por TTL. O mapa
+// só cresce com tráfego, então varreduras disparadas pelas requisições bastam
+// para limitá-lo, sem uma goroutine permanente
+func (m *memoryRateLimiter) maybeSweep(now time.Time) {
+	next := m.nextSweep.Load()
+	if next == 0 {
+		m.nextSweep.CompareAndSwap(0, now.Add(m.ttl()).UnixNano())
+		return
+	}
+	if now.UnixNano() < next || !m.sweeping.CompareAndSwap(false, true) {
+		return
+	}
+	m.nextSweep.Store(now.Add(m.ttl()).UnixNano())
+
+	go func() {
+		defer m.sweeping.Store(false)
+		m.sweep(now)
+		if m.onSweep != nil {
+			m.onSweep()
+		}
+	}()
+}
+
+// sweep remove as chaves sem acesso desde now - TTL
+func (m *memoryRateLimiter) sweep(now time.Time) {
+	cutoff := now.Add(-m.ttl()).UnixNano()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, entry := range m.limiters {
+		if entry.lastSeen.Load() <= cutoff {
+			delete(m.limiters, key)
+		}
+	}
+}
+
+// size retorna o número de chaves em memória
+func (m *memoryRateLimiter) size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.limiters)
+}
+
+// TimeoutMiddleware impõe um prazo a cada requisição. O prazo vai no contexto
+// da requisição e chega às consultas ao banco, que são canceladas quando ele
+// expira; o handler roda na própria goroutine da requisição, então a conexão
+// volta ao pool antes da resposta. Se o handler não respondeu até o prazo, a
+// resposta é 504. exemptPaths (rotas registradas, ex.: "/api/v1/users/events")
+// ficam sem prazo, para streams e exportações longas
+func TimeoutMiddleware(timeout time.Duration, exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]struct{}, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
-		// Criar contexto com timeout
+		if _, ok := exempt[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
-		
-		// Substituir o contexto da request
 		c.Request = c.Request.WithContext(ctx)
-		
-		// Canal para detectar timeout
-		done := make(chan bool)
-		go func() {
-			c.Next()
-			done <- true
-		}()
-		
-		select {
-		case <-done:
-			// Request completou normalmente
-		case <-ctx.Done():
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error":   "Request timeout",
-				"message": "The request took too long to process",
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			AbortWithJSON(c, http.StatusGatewayTimeout, errorResponse{
+				Error:     "Request timeout",
+				Message:   "The request took too long to process",
+				Retryable: true,
 			})
-			c.Abort()
 		}
 	}
 }
@@ -140,12 +509,8 @@ func SecurityHeadersMiddleware() gin.HandlerFunc {
 		c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
 		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
 		c.Header("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
-		
+		c.Header("Content-Security-Policy", "default-src 'self'")
+
 		c.Next()
 	}
 }
-
-// generateRequestID gera um ID único para o request
-func generateRequestID() string {
-	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), uuid.New().String()[:8])
-} 
\ No newline at end of file
@@ -0,0 +1,563 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingLimiter simula um backend de rate limiting indisponível
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestRateLimitBackendFailurePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		failMode string
+		status   int
+		reported string
+	}{
+		{"default fails open", "", http.StatusOK, RateLimitFailOpen},
+		{"fail open allows", RateLimitFailOpen, http.StatusOK, RateLimitFailOpen},
+		{"fail closed rejects", RateLimitFailClosed, http.StatusTooManyRequests, RateLimitFailClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported []string
+			router := gin.New()
+			router.Use(RateLimitMiddleware(SecurityConfig{
+				RateLimit:         10,
+				RateLimiter:       failingLimiter{},
+				RateLimitFailMode: tt.failMode,
+				OnRateLimitBackendError: func(failMode string) {
+					reported = append(reported, failMode)
+				},
+				Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}))
+			router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, []string{tt.reported}, reported)
+		})
+	}
+}
+
+func TestRateLimitWarningHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(SecurityConfig{
+		RateLimit:                 10,
+		RateLimitWarningThreshold: 0.2,
+	}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var warnings []string
+	for i := 0; i < 10; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		warnings = append(warnings, w.Header().Get(HeaderRateLimitWarning))
+	}
+
+	for i, warning := range warnings[:7] {
+		assert.Empty(t, warning, "request %d is well below the limit", i+1)
+	}
+	assert.Equal(t, "2 of 10 requests remaining", warnings[7])
+	assert.Equal(t, "0 of 10 requests remaining", warnings[9])
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Empty(t, w.Header().Get(HeaderRateLimitWarning), "blocked responses carry no warning")
+}
+
+func TestRateLimitWarningDisabledByDefault(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(SecurityConfig{RateLimit: 2}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Empty(t, w.Header().Get(HeaderRateLimitWarning))
+	}
+}
+
+// Rode com -race (make test já o faz): requisições simultâneas de IPs novos
+// criam chaves no mapa do limiter em memória ao mesmo tempo
+func TestRateLimitConcurrentClients(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(SecurityConfig{
+		RateLimit:                 5,
+		RateLimitWarningThreshold: 0.5,
+	}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	const clients, requests = 50, 10
+	var wg sync.WaitGroup
+	codes := make([][]int, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < requests; j++ {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.RemoteAddr = "10.0." + strconv.Itoa(i/250) + "." + strconv.Itoa(i%250+1) + ":1234"
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				codes[i] = append(codes[i], w.Code)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, got := range codes {
+		allowed := 0
+		for _, code := range got {
+			if code == http.StatusOK {
+				allowed++
+			}
+		}
+		// O bucket pode repor um token durante o teste
+		assert.GreaterOrEqual(t, allowed, 5, "client %d", i)
+		assert.LessOrEqual(t, allowed, 6, "client %d", i)
+	}
+}
+
+// newSweepingLimiter cria um limiter com relógio falso que avisa em swept o
+// fim de cada varredura
+func newSweepingLimiter(limit int, idleTTL time.Duration) (*memoryRateLimiter, *clock.Fake, chan struct{}) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	swept := make(chan struct{}, 1)
+	limiter := newMemoryRateLimiter(limit, idleTTL)
+	limiter.clock = fake
+	limiter.onSweep = func() { swept <- struct{}{} }
+	return limiter, fake, swept
+}
+
+func waitSweep(t *testing.T, swept chan struct{}) {
+	t.Helper()
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Fatal("idle keys were not swept")
+	}
+}
+
+func TestMemoryRateLimiterEvictsIdleKeys(t *testing.T) {
+	ctx := context.Background()
+	limiter, fake, swept := newSweepingLimiter(1, 0)
+
+	for _, key := range []string{"10.0.0.1", "10.0.0.2"} {
+		allowed, err := limiter.Allow(ctx, key)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+
+	fake.Advance(DefaultRateLimiterIdleTTL / 2)
+	allowed, _ := limiter.Allow(ctx, "10.0.0.1")
+	assert.True(t, allowed, "the bucket refills after the interval")
+	assert.Equal(t, 2, limiter.size())
+
+	// 10.0.0.2 está inativo há mais que o TTL; 10.0.0.1 foi usado há pouco
+	fake.Advance(DefaultRateLimiterIdleTTL / 2)
+	_, _ = limiter.Allow(ctx, "10.0.0.3")
+	waitSweep(t, swept)
+	assert.Equal(t, 2, limiter.size())
+
+	allowed, _ = limiter.Allow(ctx, "10.0.0.3")
+	assert.False(t, allowed, "keys still in use keep their state")
+}
+
+func TestMemoryRateLimiterIdleTTL(t *testing.T) {
+	ctx := context.Background()
+	limiter, fake, swept := newSweepingLimiter(1, time.Minute)
+
+	_, _ = limiter.Allow(ctx, "10.0.0.1")
+	fake.Advance(time.Minute)
+	_, _ = limiter.Allow(ctx, "10.0.0.2")
+	waitSweep(t, swept)
+
+	assert.Equal(t, 1, limiter.size(), "keys idle for the configured ttl are removed")
+}
+
+// Rode com -race: muitos IPs distintos criam chaves enquanto varreduras em
+// background removem as inativas
+func TestMemoryRateLimiterConcurrentEviction(t *testing.T) {
+	ctx := context.Background()
+	limiter, fake, _ := newSweepingLimiter(2, time.Second)
+	limiter.onSweep = nil
+
+	const clients = 500
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "10.1." + strconv.Itoa(i/250) + "." + strconv.Itoa(i%250+1)
+			for j := 0; j < 5; j++ {
+				_, err := limiter.Allow(ctx, key)
+				assert.NoError(t, err)
+				if i%50 == 0 {
+					fake.Advance(500 * time.Millisecond)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, limiter.size(), clients)
+}
+
+// Rode com -race: varreduras concorrem com entry() criando chaves novas. As
+// chaves inativas somem, e cada chave criada fica no mapa com o mesmo bucket
+// devolvido a quem a criou
+func TestMemoryRateLimiterSweepDuringEntryCreation(t *testing.T) {
+	limiter, fake, _ := newSweepingLimiter(1, time.Minute)
+	limiter.onSweep = nil
+
+	const keys = 200
+	for i := 0; i < keys; i++ {
+		limiter.entry("stale-"+strconv.Itoa(i), fake.Now())
+	}
+	fake.Advance(2 * time.Minute)
+	now := fake.Now()
+
+	stop := make(chan struct{})
+	sweeps := make(chan int)
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-stop:
+				sweeps <- n
+				return
+			default:
+				limiter.sweep(now)
+				n++
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	created := make([]*memoryLimiterEntry, keys)
+	for i := 0; i < keys; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			created[i] = limiter.entry("fresh-"+strconv.Itoa(i), now)
+		}(i)
+	}
+	wg.Wait()
+	close(stop)
+	require.Positive(t, <-sweeps)
+
+	limiter.sweep(now)
+	assert.Equal(t, keys, limiter.size(), "only the stale keys are removed")
+	for i, entry := range created {
+		assert.Same(t, entry, limiter.entry("fresh-"+strconv.Itoa(i), now), "key %d", i)
+	}
+}
+
+func TestMemoryRateLimiterKeepsKeysUntilRefilled(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewMemoryRateLimiter(1, time.Hour).(*memoryRateLimiter)
+	limiter.clock = fake
+	limiter.idleTTL = time.Minute
+
+	allowed, _ := limiter.Allow(ctx, "ana@example.com")
+	require.True(t, allowed)
+
+	// Remover a chave antes do intervalo zeraria o limite
+	fake.Advance(30 * time.Minute)
+	allowed, _ = limiter.Allow(ctx, "ana@example.com")
+	assert.False(t, allowed)
+}
+
+func TestRateLimitTenants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(SecurityConfig{
+		RateLimit: 2,
+		RateLimitTenants: []RateLimitTenant{
+			{Name: "partner-a", APIKey: "partner-a-key-0123456789", Limit: 5},
+			{Name: "partner-b", APIKey: "partner-b-key-0123456789", Limit: 3},
+		},
+	}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// allowed conta as respostas 200 de n requisições com a chave informada
+	allowed := func(apiKey string, n int) (int, string) {
+		ok, limit := 0, ""
+		for i := 0; i < n; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if apiKey != "" {
+				req.Header.Set(HeaderAPIKey, apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code == http.StatusOK {
+				ok++
+			}
+			limit = w.Header().Get(HeaderRateLimitLimit)
+		}
+		return ok, limit
+	}
+
+	ok, limit := allowed("partner-a-key-0123456789", 8)
+	assert.Equal(t, 5, ok)
+	assert.Equal(t, "5", limit, "the effective limit is reported even on 429")
+
+	ok, limit = allowed("partner-b-key-0123456789", 8)
+	assert.Equal(t, 3, ok, "each tenant has its own quota")
+	assert.Equal(t, "3", limit)
+
+	// Chaves desconhecidas e requisições sem chave usam o limite padrão por IP,
+	// que não foi consumido pelos tenants
+	ok, limit = allowed("unknown-key-0123456789", 4)
+	assert.Equal(t, 2, ok)
+	assert.Equal(t, "2", limit)
+	ok, _ = allowed("", 1)
+	assert.Equal(t, 0, ok)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(config SecurityConfig, method, origin string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(CORSMiddleware(config))
+		router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(method, "/", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("credentials never echo wildcard origin", func(t *testing.T) {
+		config := SecurityConfig{CORSOrigins: []string{"*"}, CORSAllowCredentials: true}
+
+		w := request(config, http.MethodGet, "https://evil.example.com")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("credentials reflect only exact origins", func(t *testing.T) {
+		config := SecurityConfig{
+			CORSOrigins:          []string{"*", "https://app.example.com"},
+			CORSAllowCredentials: true,
+		}
+
+		w := request(config, http.MethodGet, "https://app.example.com")
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+		w = request(config, http.MethodGet, "https://app.example.com.evil.com")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("wildcard without credentials answers star", func(t *testing.T) {
+		w := request(SecurityConfig{CORSOrigins: []string{"*"}}, http.MethodGet, "https://any.example.com")
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("vary origin always set", func(t *testing.T) {
+		w := request(SecurityConfig{CORSOrigins: []string{"https://app.example.com"}}, http.MethodGet, "https://other.example.com")
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("max age only on preflight", func(t *testing.T) {
+		config := SecurityConfig{CORSOrigins: []string{"https://app.example.com"}, CORSMaxAge: 10 * time.Minute}
+
+		w := request(config, http.MethodOptions, "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+		w = request(config, http.MethodGet, "https://app.example.com")
+		assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("max age omitted when unset", func(t *testing.T) {
+		w := request(SecurityConfig{CORSOrigins: []string{"*"}}, http.MethodOptions, "https://app.example.com")
+		assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+	})
+}
+
+func TestRateLimitExemptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtService := auth.NewJWTService("test-secret", time.Hour)
+	adminToken, err := jwtService.GenerateToken("1", "admin@example.com", "admin")
+	require.NoError(t, err)
+	userToken, err := jwtService.GenerateToken("2", "user@example.com", "user")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(SecurityConfig{
+		RateLimit:              2,
+		RateLimitExemptRoles:   []string{"admin"},
+		RateLimitExemptAPIKeys: []string{"export-job-key"},
+		TokenValidator:         jwtService,
+	}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Todas as requisições vêm do mesmo IP e compartilham o limite
+	burst := func(setHeader func(*http.Request)) []int {
+		codes := make([]int, 0, 5)
+		for i := 0; i < 5; i++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			setHeader(req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			codes = append(codes, w.Code)
+		}
+		return codes
+	}
+
+	t.Run("admin exceeds the limit", func(t *testing.T) {
+		codes := burst(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+adminToken) })
+		assert.NotContains(t, codes, http.StatusTooManyRequests)
+	})
+
+	t.Run("api key exceeds the limit", func(t *testing.T) {
+		codes := burst(func(r *http.Request) { r.Header.Set(HeaderAPIKey, "export-job-key") })
+		assert.NotContains(t, codes, http.StatusTooManyRequests)
+	})
+
+	t.Run("user is throttled", func(t *testing.T) {
+		codes := burst(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+userToken) })
+		assert.Contains(t, codes, http.StatusTooManyRequests)
+	})
+
+	t.Run("unauthenticated and forged tokens are throttled", func(t *testing.T) {
+		codes := burst(func(*http.Request) {})
+		assert.Equal(t, http.StatusTooManyRequests, codes[len(codes)-1])
+
+		codes = burst(func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+adminToken+"x")
+			r.Header.Set(HeaderAPIKey, "wrong-key")
+		})
+		assert.Equal(t, http.StatusTooManyRequests, codes[len(codes)-1])
+	})
+}
+
+// countingValidator conta as validações de token
+type countingValidator struct {
+	auth.JWTService
+	calls atomic.Int32
+}
+
+func (v *countingValidator) ValidateToken(token string) (*auth.Claims, error) {
+	v.calls.Add(1)
+	return v.JWTService.ValidateToken(token)
+}
+
+func TestRateLimitExemptionSharesTokenValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validator := &countingValidator{JWTService: auth.NewJWTService("test-secret", time.Hour)}
+	token, err := validator.GenerateToken("1", "admin@example.com", "admin")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(RateLimitMiddleware(SecurityConfig{
+		RateLimit:            10,
+		RateLimitExemptRoles: []string{"admin"},
+		TokenValidator:       validator,
+	}))
+	router.GET("/", AuthMiddleware(validator), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, header := range []string{"Bearer " + token, "Bearer " + token + "x"} {
+		validator.calls.Store(0)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", header)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, int32(1), validator.calls.Load(), "token must be validated once per request")
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TimeoutMiddleware(20*time.Millisecond, "/stream"))
+
+	// waitForDeadline simula uma consulta que respeita o contexto da requisição
+	waitForDeadline := func(c *gin.Context) error {
+		select {
+		case <-c.Request.Context().Done():
+			return c.Request.Context().Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	}
+	router.GET("/silent", func(c *gin.Context) {
+		_ = waitForDeadline(c)
+	})
+	router.GET("/mapped", func(c *gin.Context) {
+		if err := waitForDeadline(c); err != nil {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "handler response"})
+		}
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"deadline": hasDeadline})
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	start := time.Now()
+	w := serve("/silent")
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "the deadline cancels the in-flight work")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), `"retryable":true`)
+
+	w = serve("/mapped")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.JSONEq(t, `{"error":"handler response"}`, w.Body.String(), "the handler's own response is kept")
+
+	assert.Equal(t, http.StatusNoContent, serve("/fast").Code)
+	assert.JSONEq(t, `{"deadline":false}`, serve("/stream").Body.String())
+}
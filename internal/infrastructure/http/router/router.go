@@ -2,34 +2,112 @@ package router
 
 import (
 	"log/slog"
+	"sort"
 
 	"go-api-boilerplate/internal/domain/auth"
 	"go-api-boilerplate/internal/infrastructure/http/handlers"
 	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/infrastructure/metrics"
+	"go-api-boilerplate/internal/infrastructure/ratelimit"
+	"go-api-boilerplate/pkg/config"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Option configura dependências opcionais do router
+type Option func(*options)
+
+// options reúne as dependências opcionais de SetupRouter
+type options struct {
+	rateLimit *ratelimit.Backend
+}
+
+// WithRateLimitBackend usa o backend de security.rate_limit_backend
+// (ratelimit.NewBackend). Quem cria o backend registra seu Close no
+// desligamento; sem esta opção, o rate limiting fica em memória
+func WithRateLimitBackend(backend *ratelimit.Backend) Option {
+	return func(o *options) {
+		o.rateLimit = backend
+	}
+}
+
 // SetupRouter configura as rotas da aplicação
-func SetupRouter(userHandler *handlers.UserHandler, jwtService auth.JWTService, log *slog.Logger) *gin.Engine {
+func SetupRouter(userHandler *handlers.UserHandler, diagnosticsHandler *handlers.DiagnosticsHandler, eventsHandler *handlers.EventsHandler, jwtService auth.JWTService, cfg *config.Config, log *slog.Logger, opts ...Option) *gin.Engine {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	router := gin.New() // Use gin.New() para ter mais controle sobre os middlewares
 
-	// Middleware de logging (deve ser o primeiro)
+	// Rotas e métodos inexistentes respondem no formato padrão de erro; métodos
+	// não suportados por uma rota existente respondem 405 com header Allow
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handlers.NoRoute)
+	router.NoMethod(handlers.NoMethod)
+
+	// Barra final e caixa do caminho: no modo strip a barra já foi removida por
+	// server.StripTrailingSlash, e o redirect do gin (301 para GET, 307 para os
+	// demais métodos, preservando o corpo) fica apenas como reserva
+	router.RedirectTrailingSlash = cfg.Server.EffectiveTrailingSlash() != config.TrailingSlashStrict
+	router.RedirectFixedPath = cfg.Server.RedirectFixedPath
+
+	// Middleware de request ID para rastreabilidade (antes do logging, que usa o mesmo ID)
+	router.Use(middleware.RequestIDMiddleware(middleware.RequestIDConfig{
+		Header:  cfg.Server.RequestIDHeader,
+		Sources: cfg.Server.RequestIDSources,
+	}))
+
+	// Middleware de logging
 	router.Use(middleware.Logger(log))
 
-	// Middleware de recuperação de pânico
-	router.Use(gin.Recovery())
+	// Convenção dos nomes dos campos JSON nas respostas (já validada na
+	// configuração), antes dos middlewares que respondem erros
+	fieldNaming, _ := middleware.ParseFieldNaming(cfg.Server.JSONFieldNaming)
+	router.Use(middleware.FieldNamingMiddleware(fieldNaming))
+
+	// Middleware de recuperação de pânico: stack na resposta apenas em desenvolvimento
+	router.Use(middleware.Recovery(log, cfg.IsDevelopment()))
+
+	// Limite de tamanho dos headers, com erro e log claros (ver server.New)
+	router.Use(middleware.HeaderSizeMiddleware(cfg.Server.EffectiveMaxHeaderBytes(), log))
 
 	// Middleware de segurança
+	metrics.RegisterRateLimitMetrics()
+	var newRateLimiter func(limit int) middleware.RateLimiter
+	if o.rateLimit != nil {
+		newRateLimiter = o.rateLimit.Limiter
+	}
+	rateLimiter := o.rateLimit.Limiter(100)
 	securityConfig := middleware.SecurityConfig{
-		CORSOrigins: []string{"*"}, // Em produção, especificar domínios específicos
-		RateLimit:   100,           // 100 requests por segundo por IP
+		RateLimit:               100, // 100 requests por segundo por IP
+		RateLimiter:             rateLimiter,
+		RateLimitIdleTTL:        cfg.Security.RateLimitIdleTTL,
+		RateLimitTenants:        rateLimitTenants(cfg.Security.RateLimitTenants),
+		TenantRateLimiter:       newRateLimiter,
+		RateLimitFailMode:       cfg.Security.RateLimitFailMode,
+		OnRateLimitBackendError: metrics.RateLimitBackendError,
+		Logger:                  log,
+		RateLimitExemptRoles:    cfg.Security.RateLimitExemptRoles,
+		RateLimitExemptAPIKeys:  cfg.Security.RateLimitExemptAPIKeys,
+		TokenValidator:          jwtService,
+
+		RateLimitWarningThreshold: cfg.Security.RateLimitWarningThreshold,
 	}
 
-	// Middleware de CORS seguro
-	router.Use(middleware.CORSMiddleware(securityConfig))
+	// Middleware de CORS por grupo de rotas, cada grupo com suas origens permitidas
+	groupCORS := func(group *gin.RouterGroup, name string) {
+		group.Use(middleware.CORSMiddleware(middleware.SecurityConfig{
+			CORSOrigins:          cfg.Security.CORSOriginsFor(name),
+			CORSAllowCredentials: cfg.Security.CORSAllowCredentials,
+			CORSMaxAge:           cfg.Security.CORSMaxAge,
+		}))
+		group.OPTIONS("", middleware.PreflightHandler)
+		group.OPTIONS("/*path", middleware.PreflightHandler)
+	}
 
 	// Middleware de rate limiting
 	router.Use(middleware.RateLimitMiddleware(securityConfig))
@@ -37,37 +115,131 @@ func SetupRouter(userHandler *handlers.UserHandler, jwtService auth.JWTService,
 	// Middleware de headers de segurança
 	router.Use(middleware.SecurityHeadersMiddleware())
 
-	// Middleware de request ID para rastreabilidade
-	router.Use(middleware.RequestIDMiddleware())
+	// Middleware de compressão das respostas
+	if cfg.Server.Compression {
+		router.Use(middleware.GzipMiddleware(middleware.CompressionConfig{
+			Level:        cfg.Server.CompressionLevel,
+			ContentTypes: cfg.Server.CompressionTypes,
+		}))
+	}
+
+	// Headers de obsolescência nas rotas marcadas em server.deprecations
+	if len(cfg.Server.Deprecations) > 0 {
+		router.Use(middleware.DeprecationMiddleware(deprecations(cfg.Server.Deprecations)))
+	}
+
+	// Prazo das requisições, propagado ao banco pelo contexto; streams ficam de fora
+	if cfg.Server.RequestTimeout > 0 {
+		router.Use(middleware.TimeoutMiddleware(cfg.Server.RequestTimeout,
+			"/api/v1/users/events",
+			"/api/v1/users/export",
+		))
+	}
 
 	// Grupo de rotas da API
 	api := router.Group("/api/v1")
+	// Leituras recebem a política configurada; escritas e erros, no-store
+	api.Use(middleware.CacheControlMiddleware(cfg.Server.CacheControl))
 	{
 		// Rotas de autenticação (públicas)
 		auth := api.Group("/auth")
+		groupCORS(auth, "auth")
+		auth.Use(middleware.NoStoreMiddleware())
 		{
 			auth.POST("/login", userHandler.Login)
-			auth.POST("/register", userHandler.CreateUser) // Endpoint público para registro
+			auth.POST("/register", userHandler.Register) // Endpoint público para registro (role sempre user)
+			auth.POST("/logout", middleware.AuthMiddleware(jwtService), userHandler.Logout)
+			auth.PUT("/password", middleware.AuthMiddleware(jwtService), userHandler.ChangePassword)
+
+			// Troca de senha vencida, sem token, quando a expiração está habilitada
+			// (usecase.WithPasswordMaxAge)
+			if userHandler.PasswordExpirationEnabled() {
+				auth.POST("/password/expired", userHandler.ChangeExpiredPassword)
+			}
+
+			// Criação do primeiro admin, quando habilitada (usecase.WithAdminBootstrap);
+			// responde 410 assim que existir qualquer admin
+			if userHandler.AdminBootstrapEnabled() {
+				auth.POST("/bootstrap-admin", userHandler.BootstrapAdmin)
+			}
+
+			// Verificação de email, quando configurada (usecase.WithEmailVerification)
+			if userHandler.EmailVerificationEnabled() {
+				auth.POST("/verify", userHandler.VerifyEmail)
+				auth.POST("/verify/resend", userHandler.ResendVerification)
+			}
+
+			// Redefinição de senha por email, quando configurada (usecase.WithPasswordReset)
+			if userHandler.PasswordResetEnabled() {
+				auth.POST("/password/reset", userHandler.RequestPasswordReset)
+				auth.POST("/password/reset/confirm", userHandler.ConfirmPasswordReset)
+			}
+
+			// Login via provedor OIDC, quando configurado (handlers.WithOIDC)
+			if userHandler.OIDCEnabled() {
+				auth.GET("/oidc/login", userHandler.OIDCLogin)
+				auth.GET("/oidc/callback", userHandler.OIDCCallback)
+			}
 		}
 
 		// Rotas de usuários (protegidas por autenticação)
 		users := api.Group("/users")
+		groupCORS(users, "users")
 		users.Use(middleware.AuthMiddleware(jwtService)) // Aplica autenticação em todas as rotas de usuários
 		{
 			// Rotas que requerem autenticação básica
 			users.GET("", userHandler.ListUsers)
 			users.GET("/email", userHandler.GetUserByEmail)
+			users.GET("/search", userHandler.SearchUsers)
 			users.GET("/:id", userHandler.GetUserByID)
+			users.POST("/batch-get", userHandler.BatchGetUsers)
+			// Os campos que cada papel altera seguem a matriz de atualização
+			// (usecase.WithUpdatePolicy); por padrão, usuários só alteram o próprio nome
+			users.PUT("/:id", userHandler.UpdateUser)
 
 			// Rotas que requerem role de admin
 			adminRoutes := users.Group("")
 			adminRoutes.Use(middleware.RoleMiddleware("admin"))
 			{
 				adminRoutes.POST("", userHandler.CreateUser)
-				adminRoutes.PUT("/:id", userHandler.UpdateUser)
+				adminRoutes.POST("/bulk", userHandler.BulkCreateUsers)
+				adminRoutes.POST("/bulk-role", userHandler.BulkUpdateRoles)
+				adminRoutes.POST("/validate-import", userHandler.ValidateImport) // pré-validação, nada é criado
+				adminRoutes.GET("/stats", userHandler.UserStats)
+				adminRoutes.GET("/stats/roles", userHandler.RoleStats)
+				adminRoutes.GET("/dormant", userHandler.ListDormantUsers) // sem login há ?days= dias
+				adminRoutes.GET("/export", userHandler.ExportUsers)       // CSV em lotes
 				adminRoutes.DELETE("/:id", userHandler.DeleteUser)
+				adminRoutes.POST("/:id/revoke-sessions", userHandler.RevokeSessions)
+				adminRoutes.POST("/:id/deactivate", userHandler.DeactivateUser)
+				adminRoutes.POST("/:id/anonymize", userHandler.AnonymizeUser)
+				adminRoutes.GET("/events", eventsHandler.Stream) // Server-Sent Events
+			}
+		}
+
+		// Rotas do próprio usuário autenticado
+		me := api.Group("/me")
+		groupCORS(me, "me")
+		me.Use(middleware.NoStoreMiddleware(), middleware.AuthMiddleware(jwtService))
+		{
+			me.GET("/export", userHandler.ExportMyData) // dados pessoais (GDPR)
+			if userHandler.EmailVerificationEnabled() {
+				me.POST("/email", userHandler.RequestEmailChange) // confirmada em /auth/verify
+			}
+			// Sessões ativas, quando configuradas (usecase.WithSessions)
+			if userHandler.SessionsEnabled() {
+				me.GET("/sessions", userHandler.ListMySessions)
+				me.DELETE("/sessions/:id", userHandler.RevokeMySession)
 			}
 		}
+
+		// Rotas administrativas internas (requerem role de admin)
+		admin := api.Group("/admin")
+		groupCORS(admin, "admin")
+		admin.Use(middleware.NoStoreMiddleware(), middleware.AuthMiddleware(jwtService), middleware.RoleMiddleware("admin"))
+		{
+			admin.GET("/diagnostics", diagnosticsHandler.Diagnostics)
+		}
 	}
 
 	// Rota de health check
@@ -78,6 +250,12 @@ func SetupRouter(userHandler *handlers.UserHandler, jwtService auth.JWTService,
 		})
 	})
 
+	// Readiness: a instância só recebe tráfego com o banco acessível
+	router.GET("/health/ready", diagnosticsHandler.Ready)
+
+	// Rota de métricas do Prometheus
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Rota para o arquivo swagger.json (fora do grupo /swagger para evitar conflito)
 	router.GET("/swagger.json", func(c *gin.Context) {
 		c.File("./docs/swagger.json")
@@ -88,3 +266,24 @@ func SetupRouter(userHandler *handlers.UserHandler, jwtService auth.JWTService,
 
 	return router
 }
+
+// deprecations converte server.deprecations, cujas datas já foram validadas na configuração
+func deprecations(configured []config.DeprecationConfig) []middleware.Deprecation {
+	result := make([]middleware.Deprecation, len(configured))
+	for i, d := range configured {
+		since, _ := config.ParseDeprecationTime(d.Since)
+		sunset, _ := config.ParseDeprecationTime(d.Sunset)
+		result[i] = middleware.Deprecation{Method: d.Method, Path: d.Path, Since: since, Sunset: sunset, Link: d.Link}
+	}
+	return result
+}
+
+// rateLimitTenants converte os tenants da configuração, em ordem de nome
+func rateLimitTenants(configured map[string]config.RateLimitTenantConfig) []middleware.RateLimitTenant {
+	tenants := make([]middleware.RateLimitTenant, 0, len(configured))
+	for name, tenant := range configured {
+		tenants = append(tenants, middleware.RateLimitTenant{Name: name, APIKey: tenant.APIKey, Limit: tenant.Limit})
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants
+}
@@ -0,0 +1,135 @@
+package router
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/infrastructure/events"
+	"go-api-boilerplate/internal/infrastructure/http/handlers"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRouter cria um router com dependências mínimas para testes de middleware
+func newTestRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService("test-secret", 0)
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(nil, cfg, nil, nil)
+	eventsHandler := handlers.NewEventsHandler(events.NewStream(0))
+	return SetupRouter(handlers.NewUserHandler(nil), diagnosticsHandler, eventsHandler, jwtService, cfg, log)
+}
+
+func TestGroupCORSOrigins(t *testing.T) {
+	cfg := &config.Config{
+		Security: config.SecurityConfig{
+			CORSOrigins: []string{"https://app.example.com"},
+			CORSGroups: map[string][]string{
+				"users": {"https://admin.example.com"},
+			},
+		},
+	}
+	router := newTestRouter(cfg)
+
+	preflight := func(path, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("origin allowed in auth group", func(t *testing.T) {
+		w := preflight("/api/v1/auth/login", "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("same origin rejected in users group", func(t *testing.T) {
+		w := preflight("/api/v1/users", "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("group origin allowed in users group only", func(t *testing.T) {
+		w := preflight("/api/v1/users/123", "https://admin.example.com")
+		assert.Equal(t, "https://admin.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = preflight("/api/v1/auth/login", "https://admin.example.com")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("global security headers still applied", func(t *testing.T) {
+		w := preflight("/api/v1/auth/login", "https://app.example.com")
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	})
+}
+
+func TestUnknownRouteReturnsJSON(t *testing.T) {
+	router := newTestRouter(&config.Config{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/does-not-exist", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"ROUTE_NOT_FOUND"`)
+	assert.Contains(t, w.Body.String(), "GET /does-not-exist")
+	// Middlewares globais continuam aplicados
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+}
+
+func TestMethodNotAllowedSetsAllow(t *testing.T) {
+	router := newTestRouter(&config.Config{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/auth/login", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Contains(t, w.Header().Get("Allow"), http.MethodPost)
+	assert.Contains(t, w.Body.String(), `"code":"METHOD_NOT_ALLOWED"`)
+}
+
+func TestTrailingSlashRouting(t *testing.T) {
+	serve := func(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("redirect keeps the method and body", func(t *testing.T) {
+		router := newTestRouter(&config.Config{Server: config.ServerConfig{TrailingSlash: config.TrailingSlashRedirect}})
+
+		w := serve(router, http.MethodGet, "/health/")
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "/health", w.Header().Get("Location"))
+
+		w = serve(router, http.MethodPost, "/api/v1/auth/login/")
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, "/api/v1/auth/login", w.Header().Get("Location"))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		router := newTestRouter(&config.Config{Server: config.ServerConfig{TrailingSlash: config.TrailingSlashStrict}})
+
+		assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/health/").Code)
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/health").Code)
+	})
+
+	t.Run("fixed path is opt-in", func(t *testing.T) {
+		router := newTestRouter(&config.Config{})
+		assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/HEALTH").Code)
+
+		router = newTestRouter(&config.Config{Server: config.ServerConfig{RedirectFixedPath: true}})
+		w := serve(router, http.MethodGet, "/HEALTH")
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "/health", w.Header().Get("Location"))
+	})
+}
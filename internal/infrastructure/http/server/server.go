@@ -0,0 +1,65 @@
+package server
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go-api-boilerplate/pkg/config"
+)
+
+// New cria o http.Server com endereço, timeouts e limite de headers da
+// configuração; valores não configurados usam os padrões de ServerConfig.
+// Erros internos do servidor (ex.: falhas de TLS) vão para logger. No modo de
+// barra final strip, o handler é envolvido por StripTrailingSlash
+func New(cfg config.ServerConfig, handler http.Handler, logger *slog.Logger) *http.Server {
+	if cfg.EffectiveTrailingSlash() == config.TrailingSlashStrip {
+		handler = StripTrailingSlash(handler)
+	}
+
+	return &http.Server{
+		Addr:              net.JoinHostPort(cfg.Host, cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.EffectiveReadTimeout(),
+		ReadHeaderTimeout: cfg.EffectiveReadHeaderTimeout(),
+		WriteTimeout:      cfg.EffectiveWriteTimeout(),
+		IdleTimeout:       cfg.EffectiveIdleTimeout(),
+		MaxHeaderBytes:    cfg.EffectiveMaxHeaderBytes(),
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+}
+
+// StripTrailingSlash remove a barra final do caminho antes do roteamento, de
+// modo que "/users/" é atendido como "/users" sem redirecionamento: o corpo e
+// o método da requisição chegam intactos ao handler. Precisa envolver o router,
+// pois o gin decide a rota antes de executar qualquer middleware
+func StripTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Cópia rasa como em http.StripPrefix, sem alterar a requisição original
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = trimTrailingSlash(path)
+		if r.URL.RawPath != "" {
+			r2.URL.RawPath = trimTrailingSlash(r.URL.RawPath)
+		}
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// trimTrailingSlash remove as barras finais, mantendo a raiz "/"
+func trimTrailingSlash(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
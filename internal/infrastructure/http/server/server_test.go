@@ -0,0 +1,145 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := http.NewServeMux()
+
+	t.Run("applies config", func(t *testing.T) {
+		srv := New(config.ServerConfig{
+			Host:              "127.0.0.1",
+			Port:              "8080",
+			ReadTimeout:       time.Second,
+			ReadHeaderTimeout: 500 * time.Millisecond,
+			WriteTimeout:      2 * time.Second,
+			IdleTimeout:       3 * time.Second,
+			MaxHeaderBytes:    16 << 10,
+		}, handler, log)
+
+		assert.Equal(t, "127.0.0.1:8080", srv.Addr)
+		assert.Equal(t, time.Second, srv.ReadTimeout)
+		assert.Equal(t, 500*time.Millisecond, srv.ReadHeaderTimeout)
+		assert.Equal(t, 2*time.Second, srv.WriteTimeout)
+		assert.Equal(t, 3*time.Second, srv.IdleTimeout)
+		assert.Equal(t, 16<<10, srv.MaxHeaderBytes)
+		assert.NotNil(t, srv.ErrorLog)
+	})
+
+	t.Run("read header timeout defaults to read timeout", func(t *testing.T) {
+		srv := New(config.ServerConfig{Port: "8080", ReadTimeout: time.Second}, handler, log)
+		assert.Equal(t, time.Second, srv.ReadHeaderTimeout)
+	})
+
+	t.Run("default max header bytes", func(t *testing.T) {
+		srv := New(config.ServerConfig{Port: "8080"}, handler, log)
+		assert.Equal(t, http.DefaultMaxHeaderBytes, srv.MaxHeaderBytes)
+	})
+
+	t.Run("unset timeouts use defaults", func(t *testing.T) {
+		srv := New(config.ServerConfig{Port: "8080"}, handler, log)
+		assert.Equal(t, config.DefaultReadTimeout, srv.ReadTimeout)
+		assert.Equal(t, config.DefaultReadTimeout, srv.ReadHeaderTimeout)
+		assert.Equal(t, config.DefaultWriteTimeout, srv.WriteTimeout)
+		assert.Equal(t, config.DefaultIdleTimeout, srv.IdleTimeout)
+	})
+}
+
+// TestSlowBodyIsCutOff simula um cliente slowloris: os headers chegam, mas o
+// corpo é enviado um byte por vez. A leitura do corpo falha quando ReadTimeout expira
+func TestSlowBodyIsCutOff(t *testing.T) {
+	readErr := make(chan error, 1)
+	srv := New(config.ServerConfig{ReadTimeout: 200 * time.Millisecond}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.ReadAll(r.Body)
+		readErr <- err
+	}), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = fmt.Fprintf(conn, "POST /users HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\nContent-Length: 1024\r\n\r\n")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := conn.Write([]byte("a")); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	select {
+	case err := <-readErr:
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		assert.True(t, netErr.Timeout())
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow body was not cut off by the read timeout")
+	}
+}
+
+func TestStripTrailingSlash(t *testing.T) {
+	var got string
+	handler := StripTrailingSlash(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path
+	}))
+
+	for path, want := range map[string]string{
+		"/users":   "/users",
+		"/users/":  "/users",
+		"/users//": "/users",
+		"/":        "/",
+		"//":       "/",
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, want, got, path)
+	}
+}
+
+func TestNewTrailingSlashMode(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	serve := func(mode string) int {
+		srv := New(config.ServerConfig{Port: "8080", TrailingSlash: mode}, mux, log)
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/", nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve(""), "strip is the default")
+	assert.Equal(t, http.StatusNoContent, serve(config.TrailingSlashStrip))
+	assert.Equal(t, http.StatusNotFound, serve(config.TrailingSlashStrict))
+}
@@ -0,0 +1,171 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+)
+
+// ErrInvalidCredentials indica que o diretório recusou o bind do usuário
+var ErrInvalidCredentials = errors.New("invalid ldap credentials")
+
+// ErrAccountNotLinked indica que o email pertence a uma conta local não
+// vinculada ao diretório. É também um ErrInvalidPassword, para que o login
+// responda como credenciais inválidas
+var ErrAccountNotLinked = fmt.Errorf("%w: local account is not linked to the ldap directory", user.ErrInvalidPassword)
+
+// IdentityIssuer é o issuer das identidades LDAP em user_identities; o subject
+// é o email normalizado com que o usuário entra
+const IdentityIssuer = "ldap"
+
+// Entry é a entrada do usuário no diretório após um bind bem-sucedido
+type Entry struct {
+	DN    string
+	Email string
+	Name  string
+	// Groups são os grupos do usuário, como DN completo ou nome (CN)
+	Groups []string
+}
+
+// Directory é o acesso ao servidor LDAP/AD (Client, fora dos testes). A
+// implementação localiza o usuário pelo email (ex.: filtro "(mail=%s)" com a
+// conta de serviço), faz bind com a senha informada e lê os atributos.
+// Credenciais recusadas retornam ErrInvalidCredentials; demais erros são
+// falhas do servidor
+type Directory interface {
+	Authenticate(ctx context.Context, email, password string) (*Entry, error)
+}
+
+// Authenticator é um usecase.Authenticator que verifica as credenciais no
+// diretório e provisiona o registro local no primeiro login
+type Authenticator struct {
+	directory   Directory
+	users       repository.UserRepository
+	roles       map[string]user.Role
+	defaultRole user.Role
+}
+
+var _ usecase.Authenticator = (*Authenticator)(nil)
+
+// NewAuthenticator cria o autenticador. roleMapping associa grupos do diretório
+// (DN ou CN, sem diferenciar maiúsculas) a roles, aplicados apenas no
+// provisionamento; usuários sem grupo mapeado recebem defaultRole (vazio usa user)
+func NewAuthenticator(directory Directory, users repository.UserRepository, roleMapping map[string]string, defaultRole string) (*Authenticator, error) {
+	a := &Authenticator{
+		directory:   directory,
+		users:       users,
+		roles:       make(map[string]user.Role, len(roleMapping)),
+		defaultRole: user.RoleUser,
+	}
+
+	if defaultRole != "" {
+		role, err := user.ParseRole(defaultRole)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ldap default role %q: %w", defaultRole, err)
+		}
+		a.defaultRole = role
+	}
+	for group, name := range roleMapping {
+		role, err := user.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role %q for ldap group %q: %w", name, group, err)
+		}
+		a.roles[strings.ToLower(group)] = role
+	}
+
+	return a, nil
+}
+
+// Authenticate implementa usecase.Authenticator. Apenas contas vinculadas ao
+// diretório (IdentityIssuer) são aceitas, e o registro local prevalece
+// (inclusive o role e a desativação). Sem registro, um usuário com senha local
+// inutilizável é criado e vinculado, com o role mapeado dos grupos. Uma conta
+// local com o mesmo email e sem vínculo é recusada com ErrAccountNotLinked
+func (a *Authenticator) Authenticate(ctx context.Context, email, password string) (*user.User, error) {
+	if password == "" {
+		// Binds com senha vazia são anônimos em muitos servidores e sempre "funcionam"
+		return nil, user.ErrInvalidPassword
+	}
+
+	entry, err := a.directory.Authenticate(ctx, email, password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			return nil, user.ErrInvalidPassword
+		}
+		return nil, fmt.Errorf("ldap authentication failed: %w", err)
+	}
+
+	// O email informado identifica a conta; o do diretório nunca o substitui
+	email = user.NormalizeEmail(email)
+
+	u, err := a.users.GetByIdentity(ctx, IdentityIssuer, email)
+	if errors.Is(err, user.ErrUserNotFound) {
+		u, err = a.provision(ctx, email, entry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.IsActiveUser() {
+		return nil, user.ErrUserDeactivated
+	}
+	return u, nil
+}
+
+// provision cria e vincula o registro local do usuário no primeiro login.
+// Contas locais existentes com o mesmo email não são assumidas
+func (a *Authenticator) provision(ctx context.Context, email string, entry *Entry) (*user.User, error) {
+	_, err := a.users.GetByEmail(ctx, email)
+	if err == nil {
+		return nil, ErrAccountNotLinked
+	}
+	if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	name := entry.Name
+	if strings.TrimSpace(name) == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	u, err := user.NewExternalUser(email, name, a.mapRole(entry.Groups))
+	if err != nil {
+		return nil, fmt.Errorf("failed to provision ldap user: %w", err)
+	}
+	if err := a.users.Create(ctx, u); err != nil {
+		return nil, fmt.Errorf("failed to provision ldap user: %w", err)
+	}
+	if err := a.users.LinkIdentity(ctx, u.ID, IdentityIssuer, email); err != nil {
+		return nil, fmt.Errorf("failed to link ldap identity: %w", err)
+	}
+	return u, nil
+}
+
+// mapRole retorna o role do primeiro grupo mapeado, na ordem do diretório.
+// Cada grupo é comparado pelo DN completo e pelo CN
+func (a *Authenticator) mapRole(groups []string) user.Role {
+	for _, group := range groups {
+		group = strings.ToLower(strings.TrimSpace(group))
+		if role, ok := a.roles[group]; ok {
+			return role
+		}
+		if role, ok := a.roles[commonName(group)]; ok {
+			return role
+		}
+	}
+	return a.defaultRole
+}
+
+// commonName extrai o CN de um DN ("cn=admins,ou=groups,dc=example" -> "admins")
+func commonName(dn string) string {
+	first, _, _ := strings.Cut(dn, ",")
+	if cn, ok := strings.CutPrefix(first, "cn="); ok {
+		return cn
+	}
+	return dn
+}
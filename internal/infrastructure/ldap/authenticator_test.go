@@ -0,0 +1,150 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDirectory simula um servidor LDAP com senhas e entradas fixas
+type fakeDirectory struct {
+	passwords map[string]string
+	entries   map[string]*Entry
+	err       error
+}
+
+func (d fakeDirectory) Authenticate(_ context.Context, email, password string) (*Entry, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if d.passwords[email] != password {
+		return nil, ErrInvalidCredentials
+	}
+	return d.entries[email], nil
+}
+
+func newFakeDirectory() fakeDirectory {
+	return fakeDirectory{
+		passwords: map[string]string{"ana@example.com": "directory-secret"},
+		entries: map[string]*Entry{"ana@example.com": {
+			DN:     "uid=ana,ou=people,dc=example,dc=com",
+			Email:  "Ana@Example.com",
+			Name:   "Ana Souza",
+			Groups: []string{"cn=staff,ou=groups,dc=example,dc=com", "cn=API-Admins,ou=groups,dc=example,dc=com"},
+		}},
+	}
+}
+
+func TestAuthenticatorProvisionsOnFirstLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.UserRepository{}
+	authenticator, err := NewAuthenticator(newFakeDirectory(), repo, map[string]string{"api-admins": "admin"}, "")
+	require.NoError(t, err)
+
+	repo.On("GetByIdentity", ctx, IdentityIssuer, "ana@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("GetByEmail", ctx, "ana@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+		return u.Email == "ana@example.com" && u.Name == "Ana Souza" && u.Role == user.RoleAdmin
+	})).Return(nil)
+	repo.On("LinkIdentity", ctx, mock.Anything, IdentityIssuer, "ana@example.com").Return(nil)
+
+	u, err := authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+	require.NoError(t, err)
+	assert.Equal(t, user.RoleAdmin, u.Role)
+	assert.False(t, u.CheckPassword("directory-secret"), "provisioned users have no usable local password")
+	repo.AssertExpectations(t)
+}
+
+func TestAuthenticatorUsesExistingUser(t *testing.T) {
+	ctx := context.Background()
+	existing, err := user.NewExternalUser("ana@example.com", "Ana", user.RoleUser)
+	require.NoError(t, err)
+
+	t.Run("keeps local role", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		authenticator, err := NewAuthenticator(newFakeDirectory(), repo, map[string]string{"api-admins": "admin"}, "")
+		require.NoError(t, err)
+		repo.On("GetByIdentity", ctx, IdentityIssuer, "ana@example.com").Return(existing, nil)
+
+		u, err := authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleUser, u.Role)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unlinked local account is not taken over", func(t *testing.T) {
+		local, err := user.NewUser("ana@example.com", "local-password", "Ana", user.RoleAdmin)
+		require.NoError(t, err)
+		repo := &mocks.UserRepository{}
+		authenticator, err := NewAuthenticator(newFakeDirectory(), repo, nil, "")
+		require.NoError(t, err)
+		repo.On("GetByIdentity", ctx, IdentityIssuer, "ana@example.com").Return(nil, user.ErrUserNotFound)
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(local, nil)
+
+		_, err = authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+		assert.ErrorIs(t, err, ErrAccountNotLinked)
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "LinkIdentity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("directory email does not replace the login email", func(t *testing.T) {
+		directory := newFakeDirectory()
+		directory.entries["ana@example.com"] = &Entry{DN: "uid=ana,dc=example", Email: "root@example.com"}
+		repo := &mocks.UserRepository{}
+		authenticator, err := NewAuthenticator(directory, repo, nil, "")
+		require.NoError(t, err)
+		repo.On("GetByIdentity", ctx, IdentityIssuer, "ana@example.com").Return(existing, nil)
+
+		_, err = authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "GetByIdentity", ctx, IdentityIssuer, "root@example.com")
+	})
+
+	t.Run("deactivated locally", func(t *testing.T) {
+		deactivated := *existing
+		deactivated.Deactivate()
+		repo := &mocks.UserRepository{}
+		authenticator, err := NewAuthenticator(newFakeDirectory(), repo, nil, "")
+		require.NoError(t, err)
+		repo.On("GetByIdentity", ctx, IdentityIssuer, "ana@example.com").Return(&deactivated, nil)
+
+		_, err = authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+		assert.ErrorIs(t, err, user.ErrUserDeactivated)
+	})
+}
+
+func TestAuthenticatorRejections(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.UserRepository{}
+	authenticator, err := NewAuthenticator(newFakeDirectory(), repo, nil, "")
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(ctx, "ana@example.com", "wrong")
+	assert.ErrorIs(t, err, user.ErrInvalidPassword)
+
+	_, err = authenticator.Authenticate(ctx, "ana@example.com", "")
+	assert.ErrorIs(t, err, user.ErrInvalidPassword, "empty passwords never reach the directory")
+
+	down := fakeDirectory{err: errors.New("connection refused")}
+	authenticator, err = NewAuthenticator(down, repo, nil, "")
+	require.NoError(t, err)
+	_, err = authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, user.ErrInvalidPassword, "server failures are not credential failures")
+	repo.AssertNotCalled(t, "GetByIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewAuthenticatorValidatesRoles(t *testing.T) {
+	_, err := NewAuthenticator(fakeDirectory{}, nil, map[string]string{"staff": "root"}, "")
+	assert.ErrorIs(t, err, user.ErrInvalidRole)
+
+	_, err = NewAuthenticator(fakeDirectory{}, nil, nil, "root")
+	assert.ErrorIs(t, err, user.ErrInvalidRole)
+}
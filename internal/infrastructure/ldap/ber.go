@@ -0,0 +1,186 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Tags BER usadas pelo LDAPv3 (RFC 4511). Todas cabem em um byte
+const (
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x30
+	tagSet         = 0x31
+
+	tagBindRequest      = 0x60
+	tagBindResponse     = 0x61
+	tagUnbindRequest    = 0x42
+	tagSearchRequest    = 0x63
+	tagSearchEntry      = 0x64
+	tagSearchDone       = 0x65
+	tagSearchReference  = 0x73
+	tagExtendedResponse = 0x78
+
+	// tagSimpleAuth é a senha do bind simples ([0] primitivo)
+	tagSimpleAuth = 0x80
+)
+
+// maxMessageBytes limita o tamanho de uma mensagem lida do servidor
+const maxMessageBytes = 1 << 20
+
+// errMalformed indica uma mensagem BER inválida ou truncada
+var errMalformed = errors.New("malformed ldap message")
+
+// element é um TLV decodificado; o conteúdo de um elemento construído é lido com children
+type element struct {
+	tag     byte
+	content []byte
+}
+
+// encode monta o TLV com a tag e o conteúdo (concatenação dos filhos, se construído)
+func encode(tag byte, parts ...[]byte) []byte {
+	size := 0
+	for _, p := range parts {
+		size += len(p)
+	}
+
+	out := append([]byte{tag}, encodeLength(size)...)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// encodeLength usa a forma curta até 127 e a longa acima disso
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var digits []byte
+	for v := n; v > 0; v >>= 8 {
+		digits = append([]byte{byte(v)}, digits...)
+	}
+	return append([]byte{0x80 | byte(len(digits))}, digits...)
+}
+
+// encodeInt codifica um inteiro em complemento de dois com o mínimo de bytes
+func encodeInt(tag byte, v int64) []byte {
+	var digits []byte
+	for {
+		digits = append([]byte{byte(v)}, digits...)
+		v >>= 8
+		if (v == 0 && digits[0]&0x80 == 0) || (v == -1 && digits[0]&0x80 != 0) {
+			break
+		}
+	}
+	return encode(tag, digits)
+}
+
+func encodeString(tag byte, s string) []byte {
+	return encode(tag, []byte(s))
+}
+
+func encodeBool(v bool) []byte {
+	if v {
+		return encode(tagBoolean, []byte{0xff})
+	}
+	return encode(tagBoolean, []byte{0x00})
+}
+
+// readElement lê um TLV completo de r, recusando tags de mais de um byte,
+// comprimentos indefinidos e mensagens acima de maxMessageBytes
+func readElement(r *bufio.Reader) (element, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return element{}, err
+	}
+	if tag&0x1f == 0x1f {
+		return element{}, errMalformed
+	}
+
+	length, err := readLength(r)
+	if err != nil {
+		return element{}, err
+	}
+	if length > maxMessageBytes {
+		return element{}, fmt.Errorf("ldap message of %d bytes exceeds the limit", length)
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return element{}, err
+	}
+	return element{tag: tag, content: content}, nil
+}
+
+func readLength(r io.ByteReader) (int, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if first < 0x80 {
+		return int(first), nil
+	}
+
+	count := int(first & 0x7f)
+	if count == 0 || count > 4 {
+		return 0, errMalformed
+	}
+	length := 0
+	for range count {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length = length<<8 | int(b)
+	}
+	return length, nil
+}
+
+// children decodifica o conteúdo de um elemento construído
+func (e element) children() ([]element, error) {
+	var out []element
+	data := e.content
+	for len(data) > 0 {
+		if len(data) < 2 || data[0]&0x1f == 0x1f {
+			return nil, errMalformed
+		}
+		tag := data[0]
+
+		length, header := int(data[1]), 2
+		if data[1] >= 0x80 {
+			count := int(data[1] & 0x7f)
+			if count == 0 || count > 4 || len(data) < 2+count {
+				return nil, errMalformed
+			}
+			length = 0
+			for _, b := range data[2 : 2+count] {
+				length = length<<8 | int(b)
+			}
+			header += count
+		}
+		if length > len(data)-header {
+			return nil, errMalformed
+		}
+
+		out = append(out, element{tag: tag, content: data[header : header+length]})
+		data = data[header+length:]
+	}
+	return out, nil
+}
+
+// int decodifica um INTEGER ou ENUMERATED
+func (e element) int() (int64, error) {
+	if len(e.content) == 0 || len(e.content) > 8 {
+		return 0, errMalformed
+	}
+	v := int64(int8(e.content[0]))
+	for _, b := range e.content[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v, nil
+}
@@ -0,0 +1,419 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Valores padrão do cliente LDAP
+const (
+	// DefaultTimeout limita cada login no diretório (conexão, binds e busca)
+	DefaultTimeout = 10 * time.Second
+	// DefaultUserFilter localiza o usuário pelo email
+	DefaultUserFilter = "(mail=%s)"
+)
+
+// Atributos lidos da entrada do usuário. memberOf traz o DN de cada grupo
+// (Active Directory e OpenLDAP com o overlay memberof)
+const (
+	attrMail        = "mail"
+	attrDisplayName = "displayName"
+	attrCommonName  = "cn"
+	attrMemberOf    = "memberOf"
+)
+
+// Códigos de resultado do LDAPv3 tratados pelo cliente
+const (
+	resultSuccess            = 0
+	resultSizeLimitExceeded  = 4
+	resultInvalidCredentials = 49
+)
+
+// Config configura o acesso ao diretório
+type Config struct {
+	// URL do servidor: ldap://host[:389] ou ldaps://host[:636]
+	URL string
+	// BindDN e BindPassword são a conta de serviço que busca o usuário; vazios
+	// fazem a busca anônima
+	BindDN       string
+	BindPassword string
+	// BaseDN é a raiz da busca, que percorre toda a subárvore
+	BaseDN string
+	// UserFilter localiza o usuário; %s é substituído pelo email escapado.
+	// Vazio usa DefaultUserFilter
+	UserFilter string
+}
+
+// ResultError é uma operação recusada pelo servidor, com o código de resultado do LDAPv3
+type ResultError struct {
+	Op      string
+	Code    int64
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("ldap %s failed with result code %d: %s", e.Op, e.Code, e.Message)
+}
+
+// Client é o Directory que fala LDAPv3 com o servidor. Cada login abre uma
+// conexão: bind com a conta de serviço, busca do usuário por UserFilter sob
+// BaseDN, bind com o DN encontrado e a senha informada. Os atributos (mail,
+// displayName ou cn e memberOf) vêm da busca
+type Client struct {
+	config    Config
+	address   string
+	useTLS    bool
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+var _ Directory = (*Client)(nil)
+
+// Option configura comportamentos opcionais do Client
+type Option func(*Client)
+
+// WithTimeout define o prazo de cada login no diretório
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// WithTLSConfig define a configuração TLS das conexões ldaps:// (ex.: uma CA
+// interna em RootCAs)
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// NewClient cria o cliente; url e base DN são obrigatórios e o filtro precisa
+// conter %s
+func NewClient(config Config, opts ...Option) (*Client, error) {
+	if config.URL == "" || config.BaseDN == "" {
+		return nil, errors.New("ldap url and base dn are required")
+	}
+	if config.UserFilter == "" {
+		config.UserFilter = DefaultUserFilter
+	}
+	if !strings.Contains(config.UserFilter, "%s") {
+		return nil, fmt.Errorf("ldap user filter %q must contain %%s", config.UserFilter)
+	}
+	if _, err := compileFilter(strings.ReplaceAll(config.UserFilter, "%s", "x")); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap url: %w", err)
+	}
+	c := &Client{config: config, timeout: DefaultTimeout}
+	port := u.Port()
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		c.useTLS = true
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, fmt.Errorf("invalid ldap url %q: scheme must be ldap or ldaps", config.URL)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ldap url %q: missing host", config.URL)
+	}
+	c.address = net.JoinHostPort(u.Hostname(), port)
+	c.tlsConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Authenticate implementa Directory. Email sem entrada e senha recusada
+// retornam ErrInvalidCredentials; um filtro que encontra mais de uma entrada
+// e a recusa da conta de serviço são falhas do servidor
+func (c *Client) Authenticate(ctx context.Context, email, password string) (*Entry, error) {
+	if password == "" {
+		// Bind simples com senha vazia é anônimo e seria aceito
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	if c.config.BindDN != "" {
+		if err := conn.bind(c.config.BindDN, c.config.BindPassword); err != nil {
+			return nil, conn.fail(ctx, fmt.Errorf("ldap service account bind: %w", err))
+		}
+	}
+
+	filter := strings.ReplaceAll(c.config.UserFilter, "%s", EscapeFilter(email))
+	entries, err := conn.search(c.config.BaseDN, filter, c.timeout)
+	if err != nil {
+		return nil, conn.fail(ctx, err)
+	}
+	switch {
+	case len(entries) == 0:
+		// Email desconhecido responde como senha errada
+		return nil, ErrInvalidCredentials
+	case len(entries) > 1:
+		return nil, fmt.Errorf("ldap user filter matched more than one entry")
+	case entries[0].DN == "":
+		return nil, fmt.Errorf("ldap search returned an entry without dn")
+	}
+
+	if err := conn.bind(entries[0].DN, password); err != nil {
+		var result *ResultError
+		if errors.As(err, &result) && result.Code == resultInvalidCredentials {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, conn.fail(ctx, err)
+	}
+	return entries[0], nil
+}
+
+// dial abre a conexão com o prazo de c.timeout (ou o de ctx, se menor).
+// Cancelar ctx interrompe a leitura em andamento
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+
+	var netConn net.Conn
+	var err error
+	if c.useTLS {
+		netConn, err = (&tls.Dialer{NetDialer: dialer, Config: c.tlsConfig}).DialContext(ctx, "tcp", c.address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ldap server: %w", err)
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := netConn.SetDeadline(deadline); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("failed to set ldap deadline: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		netConn.SetDeadline(time.Unix(1, 0))
+	})
+	return &conn{Conn: netConn, reader: bufio.NewReader(netConn), stop: stop}, nil
+}
+
+// conn é uma conexão LDAP com IDs de mensagem sequenciais
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+	nextID int64
+	stop   func() bool
+}
+
+// close envia o unbind e fecha a conexão
+func (c *conn) close() {
+	c.stop()
+	c.nextID++
+	c.Write(encode(tagSequence, encodeInt(tagInteger, c.nextID), encode(tagUnbindRequest)))
+	c.Conn.Close()
+}
+
+// fail prefere o erro do contexto quando ele interrompeu a operação
+func (c *conn) fail(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// send envia a operação em uma nova mensagem e retorna o ID dela
+func (c *conn) send(op []byte) (int64, error) {
+	c.nextID++
+	if _, err := c.Write(encode(tagSequence, encodeInt(tagInteger, c.nextID), op)); err != nil {
+		return 0, fmt.Errorf("failed to write ldap request: %w", err)
+	}
+	return c.nextID, nil
+}
+
+// receive lê a próxima resposta da mensagem id e retorna a operação dela
+func (c *conn) receive(id int64) (element, error) {
+	message, err := readElement(c.reader)
+	if err != nil {
+		return element{}, fmt.Errorf("failed to read ldap response: %w", err)
+	}
+	if message.tag != tagSequence {
+		return element{}, errMalformed
+	}
+	parts, err := message.children()
+	if err != nil || len(parts) < 2 {
+		return element{}, errMalformed
+	}
+	messageID, err := parts[0].int()
+	if err != nil {
+		return element{}, err
+	}
+
+	// ID 0 é uma notificação do servidor, como o aviso de desconexão
+	if messageID == 0 && parts[1].tag == tagExtendedResponse {
+		return element{}, errors.New("ldap server closed the connection")
+	}
+	if messageID != id {
+		return element{}, fmt.Errorf("unexpected ldap message id %d", messageID)
+	}
+	return parts[1], nil
+}
+
+// bind faz o bind simples (LDAPv3) com dn e password
+func (c *conn) bind(dn, password string) error {
+	id, err := c.send(encode(tagBindRequest,
+		encodeInt(tagInteger, 3),
+		encodeString(tagOctetString, dn),
+		encodeString(tagSimpleAuth, password),
+	))
+	if err != nil {
+		return err
+	}
+
+	response, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if response.tag != tagBindResponse {
+		return errMalformed
+	}
+	return checkResult("bind", response)
+}
+
+// search busca na subárvore de base as entradas que atendem filter. Basta
+// saber se há mais de uma, então o servidor devolve no máximo duas
+func (c *conn) search(base, filter string, timeout time.Duration) ([]*Entry, error) {
+	compiled, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([][]byte, 0, 4)
+	for _, attr := range []string{attrMail, attrDisplayName, attrCommonName, attrMemberOf} {
+		attributes = append(attributes, encodeString(tagOctetString, attr))
+	}
+	id, err := c.send(encode(tagSearchRequest,
+		encodeString(tagOctetString, base),
+		encodeInt(tagEnumerated, 2), // wholeSubtree
+		encodeInt(tagEnumerated, 0), // neverDerefAliases
+		encodeInt(tagInteger, 2),
+		encodeInt(tagInteger, int64(max(timeout/time.Second, 1))),
+		encodeBool(false),
+		compiled,
+		encode(tagSequence, attributes...),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for {
+		response, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch response.tag {
+		case tagSearchEntry:
+			entry, err := parseEntry(response)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case tagSearchReference:
+			// Referências a outros servidores não são seguidas
+		case tagSearchDone:
+			err := checkResult("search", response)
+			var result *ResultError
+			if errors.As(err, &result) && result.Code == resultSizeLimitExceeded && len(entries) > 1 {
+				return entries, nil
+			}
+			return entries, err
+		default:
+			return nil, errMalformed
+		}
+	}
+}
+
+// checkResult converte um LDAPResult diferente de success em ResultError
+func checkResult(op string, response element) error {
+	parts, err := response.children()
+	if err != nil || len(parts) < 3 {
+		return errMalformed
+	}
+	code, err := parts[0].int()
+	if err != nil {
+		return err
+	}
+	if code != resultSuccess {
+		return &ResultError{Op: op, Code: code, Message: string(parts[2].content)}
+	}
+	return nil
+}
+
+// parseEntry lê o DN e os atributos de um SearchResultEntry
+func parseEntry(response element) (*Entry, error) {
+	parts, err := response.children()
+	if err != nil || len(parts) < 2 {
+		return nil, errMalformed
+	}
+	attributes, err := parts[1].children()
+	if err != nil {
+		return nil, errMalformed
+	}
+
+	entry := &Entry{DN: string(parts[0].content)}
+	var commonName string
+	for _, attribute := range attributes {
+		fields, err := attribute.children()
+		if err != nil || len(fields) < 2 {
+			return nil, errMalformed
+		}
+		values, err := fields[1].children()
+		if err != nil {
+			return nil, errMalformed
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		switch name := string(fields[0].content); {
+		case strings.EqualFold(name, attrMail):
+			entry.Email = string(values[0].content)
+		case strings.EqualFold(name, attrDisplayName):
+			entry.Name = string(values[0].content)
+		case strings.EqualFold(name, attrCommonName):
+			commonName = string(values[0].content)
+		case strings.EqualFold(name, attrMemberOf):
+			for _, v := range values {
+				entry.Groups = append(entry.Groups, string(v.content))
+			}
+		}
+	}
+	if entry.Name == "" {
+		entry.Name = commonName
+	}
+	return entry, nil
+}
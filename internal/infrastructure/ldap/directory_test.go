@@ -0,0 +1,366 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBaseDN          = "ou=people,dc=example,dc=com"
+	testServiceDN       = "cn=service,dc=example,dc=com"
+	testServicePassword = "service-secret"
+	testUserDN          = "uid=ana,ou=people,dc=example,dc=com"
+)
+
+// fakeEntry é uma entrada do diretório fake, com a senha do bind
+type fakeEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// fakeServer é um servidor LDAPv3 mínimo: bind simples e busca com filtros
+// &, |, !, igualdade e presença. A busca exige o bind da conta de serviço
+type fakeServer struct {
+	listener net.Listener
+	entries  []fakeEntry
+
+	mu    sync.Mutex
+	binds []string
+}
+
+func newFakeServer(t *testing.T, entries ...fakeEntry) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeServer{listener: listener, entries: entries}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *fakeServer) bindDNs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *fakeServer) serve(c net.Conn) {
+	defer c.Close()
+	reader := bufio.NewReader(c)
+	boundAsService := false
+
+	for {
+		message, err := readElement(reader)
+		if err != nil {
+			return
+		}
+		parts, err := message.children()
+		if err != nil || len(parts) < 2 {
+			return
+		}
+		id, _ := parts[0].int()
+		op := parts[1]
+		respond := func(tag byte, code int64, children ...[]byte) {
+			result := append([][]byte{encodeInt(tagEnumerated, code), encodeString(tagOctetString, ""), encodeString(tagOctetString, "")}, children...)
+			c.Write(encode(tagSequence, encodeInt(tagInteger, id), encode(tag, result...)))
+		}
+
+		switch op.tag {
+		case tagBindRequest:
+			fields, _ := op.children()
+			dn, password := string(fields[1].content), string(fields[2].content)
+			s.mu.Lock()
+			s.binds = append(s.binds, dn)
+			s.mu.Unlock()
+
+			boundAsService = dn == testServiceDN && password == testServicePassword
+			code := int64(resultInvalidCredentials)
+			if boundAsService || s.checkPassword(dn, password) {
+				code = resultSuccess
+			}
+			respond(tagBindResponse, code)
+		case tagSearchRequest:
+			if !boundAsService {
+				respond(tagSearchDone, 50)
+				continue
+			}
+			fields, _ := op.children()
+			base := string(fields[0].content)
+			sizeLimit, _ := fields[3].int()
+
+			sent := int64(0)
+			code := int64(resultSuccess)
+			for _, e := range s.entries {
+				if !strings.HasSuffix(e.dn, base) || !matches(fields[6], e) {
+					continue
+				}
+				if sent == sizeLimit {
+					code = resultSizeLimitExceeded
+					break
+				}
+				c.Write(encode(tagSequence, encodeInt(tagInteger, id), encodeEntry(e)))
+				sent++
+			}
+			respond(tagSearchDone, code)
+		case tagUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *fakeServer) checkPassword(dn, password string) bool {
+	for _, e := range s.entries {
+		if e.dn == dn {
+			return e.password == password
+		}
+	}
+	return false
+}
+
+func encodeEntry(e fakeEntry) []byte {
+	var attributes [][]byte
+	for name, values := range e.attributes {
+		var encoded [][]byte
+		for _, v := range values {
+			encoded = append(encoded, encodeString(tagOctetString, v))
+		}
+		attributes = append(attributes, encode(tagSequence, encodeString(tagOctetString, name), encode(tagSet, encoded...)))
+	}
+	return encode(tagSearchEntry, encodeString(tagOctetString, e.dn), encode(tagSequence, attributes...))
+}
+
+func matches(filter element, e fakeEntry) bool {
+	switch filter.tag {
+	case filterAnd, filterOr:
+		children, _ := filter.children()
+		for _, child := range children {
+			if matches(child, e) == (filter.tag == filterOr) {
+				return filter.tag == filterOr
+			}
+		}
+		return filter.tag == filterAnd
+	case filterNot:
+		children, _ := filter.children()
+		return !matches(children[0], e)
+	case filterPresent:
+		return len(attributeValues(e, string(filter.content))) > 0
+	case filterEquality:
+		fields, _ := filter.children()
+		for _, v := range attributeValues(e, string(fields[0].content)) {
+			if strings.EqualFold(v, string(fields[1].content)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func attributeValues(e fakeEntry, name string) []string {
+	for attr, values := range e.attributes {
+		if strings.EqualFold(attr, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func anaEntry() fakeEntry {
+	return fakeEntry{
+		dn:       testUserDN,
+		password: "directory-secret",
+		attributes: map[string][]string{
+			"objectClass": {"person"},
+			"mail":        {"ana@example.com"},
+			"cn":          {"ana"},
+			"displayName": {"Ana Souza"},
+			"memberOf":    {"cn=staff,ou=groups,dc=example,dc=com", "cn=API-Admins,ou=groups,dc=example,dc=com"},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, server *fakeServer, filter string) *Client {
+	t.Helper()
+	client, err := NewClient(Config{
+		URL:          server.url(),
+		BindDN:       testServiceDN,
+		BindPassword: testServicePassword,
+		BaseDN:       testBaseDN,
+		UserFilter:   filter,
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestClientAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeServer(t, anaEntry())
+	client := newTestClient(t, server, "(&(objectClass=person)(mail=%s))")
+
+	entry, err := client.Authenticate(ctx, "ana@example.com", "directory-secret")
+	require.NoError(t, err)
+	assert.Equal(t, testUserDN, entry.DN)
+	assert.Equal(t, "ana@example.com", entry.Email)
+	assert.Equal(t, "Ana Souza", entry.Name)
+	assert.Equal(t, []string{"cn=staff,ou=groups,dc=example,dc=com", "cn=API-Admins,ou=groups,dc=example,dc=com"}, entry.Groups)
+
+	// Conta de serviço para a busca, depois o DN encontrado com a senha do usuário
+	assert.Equal(t, []string{testServiceDN, testUserDN}, server.bindDNs())
+}
+
+func TestClientAuthenticateRejections(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("wrong password", func(t *testing.T) {
+		client := newTestClient(t, newFakeServer(t, anaEntry()), "")
+		_, err := client.Authenticate(ctx, "ana@example.com", "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		client := newTestClient(t, newFakeServer(t, anaEntry()), "")
+		_, err := client.Authenticate(ctx, "bruno@example.com", "directory-secret")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("filter characters in the email are escaped", func(t *testing.T) {
+		server := newFakeServer(t, anaEntry())
+		client := newTestClient(t, server, "")
+		for _, email := range []string{"*", "x)(mail=*", "x)(|(mail=*)"} {
+			_, err := client.Authenticate(ctx, email, "directory-secret")
+			assert.ErrorIs(t, err, ErrInvalidCredentials, email)
+		}
+		assert.NotContains(t, server.bindDNs(), testUserDN)
+	})
+
+	t.Run("empty password does not reach the server", func(t *testing.T) {
+		server := newFakeServer(t, anaEntry())
+		client := newTestClient(t, server, "")
+		_, err := client.Authenticate(ctx, "ana@example.com", "")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Empty(t, server.bindDNs())
+	})
+
+	t.Run("refused service account is a server failure", func(t *testing.T) {
+		server := newFakeServer(t, anaEntry())
+		client, err := NewClient(Config{URL: server.url(), BindDN: testServiceDN, BindPassword: "wrong", BaseDN: testBaseDN})
+		require.NoError(t, err)
+
+		_, err = client.Authenticate(ctx, "ana@example.com", "directory-secret")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidCredentials)
+		var result *ResultError
+		require.ErrorAs(t, err, &result)
+		assert.Equal(t, int64(resultInvalidCredentials), result.Code)
+	})
+
+	t.Run("ambiguous filter is a server failure", func(t *testing.T) {
+		twin := anaEntry()
+		twin.dn = "uid=ana2,ou=people,dc=example,dc=com"
+		client := newTestClient(t, newFakeServer(t, anaEntry(), twin), "")
+
+		_, err := client.Authenticate(ctx, "ana@example.com", "directory-secret")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		server := newFakeServer(t)
+		client := newTestClient(t, server, "")
+		server.listener.Close()
+
+		_, err := client.Authenticate(ctx, "ana@example.com", "directory-secret")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
+
+func TestClientProvisionsWithMappedGroups(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, newFakeServer(t, anaEntry()), "")
+	repo := &mocks.UserRepository{}
+	authenticator, err := NewAuthenticator(client, repo, map[string]string{"api-admins": "admin"}, "")
+	require.NoError(t, err)
+
+	repo.On("GetByIdentity", ctx, IdentityIssuer, "ana@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("GetByEmail", ctx, "ana@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+		return u.Name == "Ana Souza" && u.Role == user.RoleAdmin
+	})).Return(nil)
+	repo.On("LinkIdentity", ctx, mock.Anything, IdentityIssuer, "ana@example.com").Return(nil)
+
+	u, err := authenticator.Authenticate(ctx, "ana@example.com", "directory-secret")
+	require.NoError(t, err)
+	assert.Equal(t, user.RoleAdmin, u.Role)
+	repo.AssertExpectations(t)
+}
+
+func TestNewClientValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"missing url", Config{BaseDN: testBaseDN}},
+		{"missing base dn", Config{URL: "ldap://ldap.example.com"}},
+		{"unsupported scheme", Config{URL: "http://ldap.example.com", BaseDN: testBaseDN}},
+		{"missing host", Config{URL: "ldaps://", BaseDN: testBaseDN}},
+		{"filter without placeholder", Config{URL: "ldap://ldap.example.com", BaseDN: testBaseDN, UserFilter: "(mail=ana)"}},
+		{"malformed filter", Config{URL: "ldap://ldap.example.com", BaseDN: testBaseDN, UserFilter: "(&(mail=%s)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.config)
+			assert.Error(t, err)
+		})
+	}
+
+	client, err := NewClient(Config{URL: "ldaps://ldap.example.com", BaseDN: testBaseDN})
+	require.NoError(t, err)
+	assert.Equal(t, "ldap.example.com:636", client.address)
+	assert.Equal(t, DefaultUserFilter, client.config.UserFilter)
+}
+
+func TestCompileFilter(t *testing.T) {
+	for _, filter := range []string{
+		"(mail=ana@example.com)",
+		"(!(mail=*))",
+		"(|(mail=ana*)(cn=*souza)(cn=a*n*a))",
+		"(&(uid>=a)(uid<=z)(cn~=ana))",
+		"(mail=\\2a)",
+	} {
+		_, err := compileFilter(filter)
+		assert.NoError(t, err, filter)
+	}
+
+	for _, filter := range []string{"", "mail=ana", "(mail=ana", "(mail=ana))", "(&)", "(=ana)", "(mail=a(b)", "(mail=\\zz)", "(mail=\\2)", "(uid>=a*)", "(cn:dn:=ana)"} {
+		_, err := compileFilter(filter)
+		assert.Error(t, err, filter)
+	}
+
+	assert.Equal(t, "a\\2a\\28b\\29\\5c", EscapeFilter("a*(b)\\"))
+}
@@ -0,0 +1,218 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Tags dos filtros de busca (RFC 4511, seção 4.5.1)
+const (
+	filterAnd         = 0xa0
+	filterOr          = 0xa1
+	filterNot         = 0xa2
+	filterEquality    = 0xa3
+	filterSubstrings  = 0xa4
+	filterGreaterOrEq = 0xa5
+	filterLessOrEq    = 0xa6
+	filterPresent     = 0x87
+	filterApprox      = 0xa8
+
+	substringInitial = 0x80
+	substringAny     = 0x81
+	substringFinal   = 0x82
+)
+
+// EscapeFilter escapa um valor para uso em um filtro de busca (RFC 4515), de
+// modo que "*", parênteses e barras do email informado no login não alterem o filtro
+func EscapeFilter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '*', '(', ')', 0:
+			fmt.Fprintf(&b, "\\%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// compileFilter converte um filtro em texto (RFC 4515) para BER. Filtros
+// extensíveis (attr:regra:=valor) não são suportados
+func compileFilter(filter string) ([]byte, error) {
+	p := filterParser{input: filter}
+	out, err := p.filter()
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap filter %q: %w", filter, err)
+	}
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("invalid ldap filter %q: unexpected text after position %d", filter, p.pos)
+	}
+	return out, nil
+}
+
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) filter() ([]byte, error) {
+	if !p.consume('(') {
+		return nil, fmt.Errorf("expected ( at position %d", p.pos)
+	}
+	if p.pos >= len(p.input) {
+		return nil, fmt.Errorf("unterminated filter")
+	}
+
+	var out []byte
+	var err error
+	switch p.input[p.pos] {
+	case '&':
+		p.pos++
+		out, err = p.list(filterAnd)
+	case '|':
+		p.pos++
+		out, err = p.list(filterOr)
+	case '!':
+		p.pos++
+		var inner []byte
+		inner, err = p.filter()
+		out = encode(filterNot, inner)
+	default:
+		out, err = p.item()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.consume(')') {
+		return nil, fmt.Errorf("expected ) at position %d", p.pos)
+	}
+	return out, nil
+}
+
+// list lê um ou mais filtros de um & ou |
+func (p *filterParser) list(tag byte) ([]byte, error) {
+	var filters [][]byte
+	for p.pos < len(p.input) && p.input[p.pos] == '(' {
+		f, err := p.filter()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("empty filter list at position %d", p.pos)
+	}
+	return encode(tag, filters...), nil
+}
+
+// item lê uma comparação simples até o ) que a encerra
+func (p *filterParser) item() ([]byte, error) {
+	end := strings.IndexByte(p.input[p.pos:], ')')
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated filter")
+	}
+	text := p.input[p.pos : p.pos+end]
+	p.pos += end
+
+	eq := strings.IndexByte(text, '=')
+	if eq <= 0 {
+		return nil, fmt.Errorf("missing attribute or operator in %q", text)
+	}
+	attr, raw := text[:eq], text[eq+1:]
+
+	tag := byte(filterEquality)
+	switch attr[len(attr)-1] {
+	case '~':
+		tag = filterApprox
+	case '>':
+		tag = filterGreaterOrEq
+	case '<':
+		tag = filterLessOrEq
+	}
+	if tag != filterEquality {
+		attr = attr[:len(attr)-1]
+	}
+	if attr == "" || strings.ContainsAny(attr, "():*\\") {
+		return nil, fmt.Errorf("invalid attribute in %q", text)
+	}
+
+	if tag == filterEquality && raw == "*" {
+		return encodeString(filterPresent, attr), nil
+	}
+	if tag == filterEquality && strings.Contains(raw, "*") {
+		return substrings(attr, raw)
+	}
+	if strings.Contains(raw, "*") {
+		return nil, fmt.Errorf("wildcard not allowed in %q", text)
+	}
+
+	value, err := unescapeValue(raw)
+	if err != nil {
+		return nil, err
+	}
+	return encode(tag, encodeString(tagOctetString, attr), encodeString(tagOctetString, value)), nil
+}
+
+// substrings codifica attr=ini*meio*fim; cada parte é opcional
+func substrings(attr, raw string) ([]byte, error) {
+	parts := strings.Split(raw, "*")
+	var encoded [][]byte
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		value, err := unescapeValue(part)
+		if err != nil {
+			return nil, err
+		}
+
+		tag := byte(substringAny)
+		switch i {
+		case 0:
+			tag = substringInitial
+		case len(parts) - 1:
+			tag = substringFinal
+		}
+		encoded = append(encoded, encodeString(tag, value))
+	}
+	if len(encoded) == 0 {
+		return nil, fmt.Errorf("empty substring filter for %q", attr)
+	}
+	return encode(filterSubstrings, encodeString(tagOctetString, attr), encode(tagSequence, encoded...)), nil
+}
+
+// unescapeValue decodifica os escapes \XX de um valor de filtro
+func unescapeValue(raw string) (string, error) {
+	if strings.ContainsAny(raw, "()") {
+		return "", fmt.Errorf("unescaped parenthesis in %q", raw)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			b.WriteByte(raw[i])
+			continue
+		}
+		if i+2 >= len(raw) {
+			return "", fmt.Errorf("truncated escape in %q", raw)
+		}
+		decoded, err := hex.DecodeString(raw[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("invalid escape in %q", raw)
+		}
+		b.Write(decoded)
+		i += 2
+	}
+	return b.String(), nil
+}
+
+func (p *filterParser) consume(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// databaseUp é 1 quando a última verificação do banco teve sucesso
+	databaseUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "database_up",
+		Help: "Resultado da última verificação de saúde do banco (1 = ok)",
+	})
+	// databaseHealthFailures conta as verificações de saúde do banco que falharam
+	databaseHealthFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "database_health_check_failures_total",
+		Help: "Total de verificações de saúde do banco que falharam",
+	})
+
+	// Uso do pool de conexões, amostrado por database.RunPoolStatsSampler
+	dbConnectionsInUse = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_connections_in_use",
+		Help: "Conexões do pool em uso na última amostra",
+	})
+	dbConnectionsIdle = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_connections_idle",
+		Help: "Conexões ociosas do pool na última amostra",
+	})
+	dbConnectionsMaxOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_connections_max_open",
+		Help: "Limite de conexões abertas do pool (0 = ilimitado)",
+	})
+	dbWaitCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "db_wait_count_total",
+		Help: "Total de vezes em que uma requisição esperou por uma conexão livre",
+	})
+	dbWaitDuration = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "db_wait_duration_seconds_total",
+		Help: "Tempo total esperando por conexões livres, em segundos",
+	})
+)
+
+// lastPoolStats guarda os acumulados da amostra anterior, já que sql.DBStats
+// traz totais desde a abertura do pool e os counters só aceitam incrementos
+var lastPoolStats struct {
+	mu           sync.Mutex
+	waitCount    int64
+	waitDuration float64
+}
+
+var registerDatabaseOnce sync.Once
+
+// RegisterDatabaseMetrics registra as métricas do banco no registry padrão,
+// servido em /metrics. Pode ser chamado mais de uma vez
+func RegisterDatabaseMetrics() {
+	registerDatabaseOnce.Do(func() {
+		prometheus.MustRegister(databaseUp, databaseHealthFailures,
+			dbConnectionsInUse, dbConnectionsIdle, dbConnectionsMaxOpen, dbWaitCount, dbWaitDuration)
+	})
+}
+
+// DatabaseHealthCheck registra o resultado de uma verificação de saúde do banco.
+// Compatível com o callback de database.StartHealthProbe
+func DatabaseHealthCheck(err error) {
+	if err != nil {
+		databaseUp.Set(0)
+		databaseHealthFailures.Inc()
+		return
+	}
+	databaseUp.Set(1)
+}
+
+// DatabasePoolStats atualiza as métricas do pool com uma amostra de
+// sql.DB.Stats(). Compatível com o callback de database.RunPoolStatsSampler;
+// pressupõe um único pool amostrado
+func DatabasePoolStats(stats sql.DBStats) {
+	dbConnectionsInUse.Set(float64(stats.InUse))
+	dbConnectionsIdle.Set(float64(stats.Idle))
+	dbConnectionsMaxOpen.Set(float64(stats.MaxOpenConnections))
+
+	lastPoolStats.mu.Lock()
+	defer lastPoolStats.mu.Unlock()
+
+	// Totais menores que os anteriores indicam um pool novo: recomeça do zero
+	if stats.WaitCount < lastPoolStats.waitCount {
+		lastPoolStats.waitCount, lastPoolStats.waitDuration = 0, 0
+	}
+	waitDuration := stats.WaitDuration.Seconds()
+	if delta := stats.WaitCount - lastPoolStats.waitCount; delta > 0 {
+		dbWaitCount.Add(float64(delta))
+	}
+	if delta := waitDuration - lastPoolStats.waitDuration; delta > 0 {
+		dbWaitDuration.Add(delta)
+	}
+	lastPoolStats.waitCount = stats.WaitCount
+	lastPoolStats.waitDuration = waitDuration
+}
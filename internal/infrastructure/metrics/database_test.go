@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabasePoolStats(t *testing.T) {
+	baseCount := testutil.ToFloat64(dbWaitCount)
+	baseDuration := testutil.ToFloat64(dbWaitDuration)
+
+	DatabasePoolStats(sql.DBStats{MaxOpenConnections: 10, InUse: 4, Idle: 2, WaitCount: 3, WaitDuration: 2 * time.Second})
+	DatabasePoolStats(sql.DBStats{MaxOpenConnections: 10, InUse: 10, Idle: 0, WaitCount: 5, WaitDuration: 3 * time.Second})
+
+	assert.Equal(t, float64(10), testutil.ToFloat64(dbConnectionsInUse))
+	assert.Equal(t, float64(0), testutil.ToFloat64(dbConnectionsIdle))
+	assert.Equal(t, float64(10), testutil.ToFloat64(dbConnectionsMaxOpen))
+	// Os counters acumulam apenas os incrementos entre amostras
+	assert.Equal(t, baseCount+5, testutil.ToFloat64(dbWaitCount))
+	assert.InDelta(t, baseDuration+3, testutil.ToFloat64(dbWaitDuration), 1e-9)
+
+	// Um pool novo (totais menores) não decrementa os counters
+	DatabasePoolStats(sql.DBStats{WaitCount: 1, WaitDuration: time.Second})
+	assert.Equal(t, baseCount+6, testutil.ToFloat64(dbWaitCount))
+	assert.InDelta(t, baseDuration+4, testutil.ToFloat64(dbWaitDuration), 1e-9)
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// activeUsersTimeout limita a consulta feita a cada scrape
+const activeUsersTimeout = 2 * time.Second
+
+// ActiveUsersCounter conta os usuários ativos; implementado pelo repositório
+type ActiveUsersCounter interface {
+	CountActive(ctx context.Context) (int64, error)
+}
+
+// DomainMetrics agrupa os contadores de negócio expostos ao Prometheus.
+// Os coletores do Prometheus são seguros para uso concorrente
+type DomainMetrics struct {
+	usersCreated  prometheus.Counter
+	logins        *prometheus.CounterVec
+	loginFailures prometheus.Counter
+}
+
+var _ usecase.Metrics = (*DomainMetrics)(nil)
+
+// NewDomainMetrics cria e registra as métricas de domínio no registerer informado.
+// Deve ser chamado uma única vez por registerer; em testes use prometheus.NewRegistry()
+func NewDomainMetrics(reg prometheus.Registerer) *DomainMetrics {
+	m := &DomainMetrics{
+		usersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "users_created_total",
+			Help: "Total de usuários criados",
+		}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logins_total",
+			Help: "Total de tentativas de login por resultado",
+		}, []string{"result"}),
+		loginFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "login_failures_total",
+			Help: "Total de tentativas de login que falharam",
+		}),
+	}
+
+	reg.MustRegister(m.usersCreated, m.logins, m.loginFailures)
+	return m
+}
+
+// UserCreated registra a criação de um usuário
+func (m *DomainMetrics) UserCreated() {
+	m.usersCreated.Inc()
+}
+
+// LoginAttempt registra uma tentativa de login com o resultado informado
+func (m *DomainMetrics) LoginAttempt(result string) {
+	m.logins.WithLabelValues(result).Inc()
+	if result != usecase.LoginResultSuccess {
+		m.loginFailures.Inc()
+	}
+}
+
+// activeUsersCollector expõe o gauge users_active consultando o repositório no scrape
+type activeUsersCollector struct {
+	counter ActiveUsersCounter
+	desc    *prometheus.Desc
+	logger  *slog.Logger
+}
+
+// RegisterActiveUsers registra o gauge users_active, calculado sob demanda a cada scrape.
+// Consultar no scrape evita que o gauge divirja do banco entre réplicas
+func RegisterActiveUsers(reg prometheus.Registerer, counter ActiveUsersCounter, logger *slog.Logger) {
+	reg.MustRegister(&activeUsersCollector{
+		counter: counter,
+		desc:    prometheus.NewDesc("users_active", "Número de usuários ativos", nil, nil),
+		logger:  logger,
+	})
+}
+
+// Describe implementa prometheus.Collector
+func (c *activeUsersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implementa prometheus.Collector
+func (c *activeUsersCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), activeUsersTimeout)
+	defer cancel()
+
+	count, err := c.counter.CountActive(ctx)
+	if err != nil {
+		c.logger.Error("Failed to count active users", "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count))
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// staticCounter retorna sempre o mesmo total de usuários ativos
+type staticCounter int64
+
+func (s staticCounter) CountActive(ctx context.Context) (int64, error) { return int64(s), nil }
+
+func TestDomainMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewDomainMetrics(reg)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.UserCreated()
+			m.LoginAttempt(usecase.LoginResultSuccess)
+			m.LoginAttempt(usecase.LoginResultInvalidPassword)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, float64(50), testutil.ToFloat64(m.usersCreated))
+	assert.Equal(t, float64(50), testutil.ToFloat64(m.logins.WithLabelValues(usecase.LoginResultSuccess)))
+	assert.Equal(t, float64(50), testutil.ToFloat64(m.logins.WithLabelValues(usecase.LoginResultInvalidPassword)))
+	assert.Equal(t, float64(50), testutil.ToFloat64(m.loginFailures))
+}
+
+func TestActiveUsersGauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	RegisterActiveUsers(reg, staticCounter(7), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	expected := `
+# HELP users_active Número de usuários ativos
+# TYPE users_active gauge
+users_active 7
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "users_active"))
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// rateLimitBackendErrors conta falhas do backend de rate limiting por política aplicada.
+// Qualquer incremento deve gerar alerta: com fail-open o limite deixa de ser aplicado
+var rateLimitBackendErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "rate_limit_backend_errors_total",
+	Help: "Total de falhas do backend de rate limiting por política aplicada",
+}, []string{"fail_mode"})
+
+var registerRateLimitOnce sync.Once
+
+// RegisterRateLimitMetrics registra as métricas de rate limiting no registry padrão,
+// servido em /metrics. Pode ser chamado mais de uma vez
+func RegisterRateLimitMetrics() {
+	registerRateLimitOnce.Do(func() {
+		prometheus.MustRegister(rateLimitBackendErrors)
+	})
+}
+
+// RateLimitBackendError registra uma falha do backend com a política aplicada
+func RateLimitBackendError(failMode string) {
+	rateLimitBackendErrors.WithLabelValues(failMode).Inc()
+}
@@ -0,0 +1,378 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Valores padrão do provedor
+const (
+	// DefaultHTTPTimeout limita cada chamada ao provedor (discovery, token, JWKS)
+	DefaultHTTPTimeout = 10 * time.Second
+	// clockSkew tolera diferenças de relógio na validação do ID token
+	clockSkew = time.Minute
+	// minKeyRefresh limita a releitura do JWKS ao receber um kid desconhecido
+	minKeyRefresh = time.Minute
+	// maxResponseBytes limita o corpo lido das respostas do provedor
+	maxResponseBytes = 1 << 20
+)
+
+// DefaultScopes são os escopos pedidos quando nenhum é configurado
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+var (
+	// ErrExchangeFailed indica que o provedor recusou o código de autorização
+	ErrExchangeFailed = errors.New("oidc code exchange failed")
+	// ErrInvalidIDToken indica um ID token ausente, mal assinado ou com claims inválidas
+	ErrInvalidIDToken = errors.New("invalid oidc id token")
+)
+
+// Config configura o cliente OIDC (authorization code flow)
+type Config struct {
+	// Issuer é a URL do provedor; os endpoints vêm de
+	// {Issuer}/.well-known/openid-configuration
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL é a URL do callback registrada no provedor
+	RedirectURL string
+	// Scopes pedidos na autorização; vazio usa DefaultScopes
+	Scopes []string
+}
+
+// Identity é a identidade extraída de um ID token válido
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider implementa o login via um provedor OIDC: monta a URL de
+// autorização, troca o código pelo ID token e valida sua assinatura (JWKS) e
+// suas claims. Discovery e chaves são lidos sob demanda e mantidos em cache
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu        sync.Mutex
+	endpoints *discovery
+	keys      map[string]*rsa.PublicKey
+	keysAt    time.Time
+}
+
+// Option configura comportamentos opcionais do Provider
+type Option func(*Provider)
+
+// WithHTTPClient define o cliente HTTP usado nas chamadas ao provedor
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// NewProvider cria o provedor; issuer, client id, client secret e redirect URL
+// são obrigatórios
+func NewProvider(config Config, opts ...Option) (*Provider, error) {
+	if config.Issuer == "" || config.ClientID == "" || config.ClientSecret == "" || config.RedirectURL == "" {
+		return nil, errors.New("oidc issuer, client id, client secret and redirect url are required")
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+
+	p := &Provider{
+		config: config,
+		client: &http.Client{Timeout: DefaultHTTPTimeout},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// AuthCodeURL retorna a URL de autorização do provedor. state protege o
+// callback contra CSRF e nonce vincula o ID token a esta tentativa de login
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.config.ClientID},
+		"redirect_uri":  {p.config.RedirectURL},
+		"scope":         {strings.Join(p.config.Scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	separator := "?"
+	if strings.Contains(endpoints.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return endpoints.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange troca o código de autorização pelo ID token e retorna a identidade
+// validada. nonce deve ser o mesmo enviado em AuthCodeURL
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (*Identity, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := p.exchangeCode(ctx, endpoints.TokenEndpoint, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.verifyIDToken(ctx, rawIDToken, nonce)
+}
+
+// discovery é o subconjunto usado do documento de discovery
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// discover lê o documento de discovery uma única vez
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	var doc discovery
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &doc); err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc discovery: %w", err)
+	}
+	if doc.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("oidc discovery issuer %q does not match %q", doc.Issuer, p.config.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("oidc discovery is missing required endpoints")
+	}
+
+	p.endpoints = &doc
+	return p.endpoints, nil
+}
+
+// exchangeCode chama o token endpoint (client_secret_basic) e retorna o ID token
+func (p *Provider) exchangeCode(ctx context.Context, tokenEndpoint, code string) (string, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.config.RedirectURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	decodeErr := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&body)
+
+	// Erros do protocolo (RFC 6749 5.2) vêm como 400/401 com o campo error
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("%w: %s", ErrExchangeFailed, body.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", decodeErr)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: token response has no id_token", ErrInvalidIDToken)
+	}
+	return body.IDToken, nil
+}
+
+// idTokenClaims são as claims lidas do ID token
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// verifyIDToken valida assinatura, issuer, audiência, expiração e nonce
+func (p *Provider) verifyIDToken(ctx context.Context, raw, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(p.config.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return &Identity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// key retorna a chave pública do kid, relendo o JWKS quando o kid é
+// desconhecido (rotação de chaves no provedor). Sem kid, a única chave publicada
+// é usada
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysAt) < minKeyRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, endpoints.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey busca a chave em cache; deve ser chamado com p.mu travado
+func (p *Provider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// fetchKeys lê as chaves RSA de assinatura publicadas no JWKS
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch oidc jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// getJSON faz um GET e decodifica a resposta JSON
+func (p *Provider) getJSON(ctx context.Context, url string, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(dst)
+}
+
+// flexBool aceita booleanos JSON e também "true"/"false" como string, formato
+// usado por alguns provedores em email_verified
+type flexBool bool
+
+// UnmarshalJSON implementa json.Unmarshaler
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
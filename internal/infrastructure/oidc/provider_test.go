@@ -0,0 +1,214 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIssuer simula um provedor OIDC: discovery, token endpoint e JWKS
+type mockIssuer struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	code   string
+	claims jwt.MapClaims
+	// jwksCalls conta as leituras do JWKS
+	jwksCalls int
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockIssuer{key: key, code: "valid-code"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		m.jwksCalls++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "client-id" || secret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("code") != m.code {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "provider-access-token",
+			"token_type":   "Bearer",
+			"id_token":     m.sign(t, m.claims),
+		})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	m.claims = m.defaultClaims("nonce-1")
+	return m
+}
+
+// defaultClaims são as claims de um ID token válido para o client de teste
+func (m *mockIssuer) defaultClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            m.URL,
+		"sub":            "subject-123",
+		"aud":            "client-id",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "ana@example.com",
+		"email_verified": true,
+		"name":           "Ana Souza",
+	}
+}
+
+// sign assina as claims com a chave publicada no JWKS
+func (m *mockIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(m.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (m *mockIssuer) provider(t *testing.T) *Provider {
+	p, err := NewProvider(Config{
+		Issuer:       m.URL,
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://api.example.com/api/v1/auth/oidc/callback",
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	issuer := newMockIssuer(t)
+	p := issuer.provider(t)
+
+	raw, err := p.AuthCodeURL(context.Background(), "state-1", "nonce-1")
+	require.NoError(t, err)
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, issuer.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	q := u.Query()
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "client-id", q.Get("client_id"))
+	assert.Equal(t, "https://api.example.com/api/v1/auth/oidc/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, "state-1", q.Get("state"))
+	assert.Equal(t, "nonce-1", q.Get("nonce"))
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid code returns the identity", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		identity, err := issuer.provider(t).Exchange(ctx, "valid-code", "nonce-1")
+		require.NoError(t, err)
+		assert.Equal(t, &Identity{
+			Issuer:        issuer.URL,
+			Subject:       "subject-123",
+			Email:         "ana@example.com",
+			EmailVerified: true,
+			Name:          "Ana Souza",
+		}, identity)
+	})
+
+	t.Run("email_verified as string", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		issuer.claims["email_verified"] = "true"
+		identity, err := issuer.provider(t).Exchange(ctx, "valid-code", "nonce-1")
+		require.NoError(t, err)
+		assert.True(t, identity.EmailVerified)
+	})
+
+	t.Run("rejected code", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		_, err := issuer.provider(t).Exchange(ctx, "stolen-code", "nonce-1")
+		assert.ErrorIs(t, err, ErrExchangeFailed)
+	})
+
+	t.Run("keys are cached", func(t *testing.T) {
+		issuer := newMockIssuer(t)
+		p := issuer.provider(t)
+		for i := 0; i < 3; i++ {
+			_, err := p.Exchange(ctx, "valid-code", "nonce-1")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, issuer.jwksCalls)
+	})
+}
+
+func TestExchangeRejectsInvalidIDTokens(t *testing.T) {
+	ctx := context.Background()
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mutate func(m *mockIssuer, claims jwt.MapClaims)
+		nonce  string
+	}{
+		{"nonce mismatch", func(*mockIssuer, jwt.MapClaims) {}, "other-nonce"},
+		{"wrong audience", func(_ *mockIssuer, c jwt.MapClaims) { c["aud"] = "another-client" }, "nonce-1"},
+		{"wrong issuer", func(_ *mockIssuer, c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, "nonce-1"},
+		{"expired", func(_ *mockIssuer, c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, "nonce-1"},
+		{"missing subject", func(_ *mockIssuer, c jwt.MapClaims) { delete(c, "sub") }, "nonce-1"},
+		{"signed by another key", func(m *mockIssuer, _ jwt.MapClaims) { m.key = otherKey }, "nonce-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			p := issuer.provider(t)
+			// Lê o JWKS com a chave original antes de qualquer alteração
+			_, err := p.Exchange(ctx, "valid-code", "nonce-1")
+			require.NoError(t, err)
+
+			tt.mutate(issuer, issuer.claims)
+			_, err = p.Exchange(ctx, "valid-code", tt.nonce)
+			assert.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestNewProviderRequiresCredentials(t *testing.T) {
+	_, err := NewProvider(Config{Issuer: "https://accounts.example.com", ClientID: "client-id"})
+	assert.Error(t, err)
+}
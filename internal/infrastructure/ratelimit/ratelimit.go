@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"time"
+
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Backend é o backend de rate limit configurado em security.rate_limit_backend.
+// Com redis, mantém a conexão compartilhada pelos limitadores criados por
+// Limiter, que Close libera no desligamento
+type Backend struct {
+	client *redis.Client
+}
+
+// NewBackend cria o backend configurado. Retorna nil para o backend em memória,
+// que é o padrão do RateLimitMiddleware; os métodos aceitam receptor nil
+func NewBackend(cfg *config.Config) *Backend {
+	if cfg.Security.RateLimitBackend != "redis" {
+		return nil
+	}
+
+	return &Backend{client: redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})}
+}
+
+// Limiter cria um limitador de limit requisições por segundo sobre a conexão
+// compartilhada (ex.: SecurityConfig.TenantRateLimiter). Retorna nil no
+// backend em memória
+func (b *Backend) Limiter(limit int) middleware.RateLimiter {
+	if b == nil {
+		return nil
+	}
+	return NewRedisLimiter(b.client, limit, time.Second)
+}
+
+// Close fecha a conexão com o Redis. Deve ser registrado no desligamento
+// (worker.Manager.OnShutdown), depois que o servidor parou de atender
+func (b *Backend) Close() error {
+	if b == nil {
+		return nil
+	}
+	return b.client.Close()
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix isola as chaves de rate limiting no Redis
+const keyPrefix = "ratelimit:"
+
+// RedisLimiter implementa middleware.RateLimiter com janela fixa no Redis,
+// compartilhando o limite entre réplicas
+type RedisLimiter struct {
+	client redis.UniversalClient
+	limit  int64
+	window time.Duration
+	clock  clock.Clock
+}
+
+var _ middleware.QuotaRateLimiter = (*RedisLimiter)(nil)
+
+// RedisOption configura comportamentos opcionais do RedisLimiter
+type RedisOption func(*RedisLimiter)
+
+// WithClock define a fonte de tempo que determina a janela atual; nil mantém
+// clock.System
+func WithClock(c clock.Clock) RedisOption {
+	return func(l *RedisLimiter) {
+		if c != nil {
+			l.clock = c
+		}
+	}
+}
+
+// NewRedisLimiter cria um limiter de limit requisições por janela
+func NewRedisLimiter(client redis.UniversalClient, limit int, window time.Duration, opts ...RedisOption) *RedisLimiter {
+	l := &RedisLimiter{client: client, limit: int64(limit), window: window, clock: clock.System}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Allow incrementa o contador da janela atual e verifica o limite
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	allowed, _, _, err := l.AllowQuota(ctx, key)
+	return allowed, err
+}
+
+// AllowQuota implementa middleware.QuotaRateLimiter; o restante é o que falta
+// para o limite na janela atual
+func (l *RedisLimiter) AllowQuota(ctx context.Context, key string) (bool, int, int, error) {
+	bucket := l.clock.Now().UnixNano() / int64(l.window)
+	redisKey := keyPrefix + key + ":" + strconv.FormatInt(bucket, 10)
+
+	var incr *redis.IntCmd
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, redisKey)
+		pipe.Expire(ctx, redisKey, l.window)
+		return nil
+	})
+	if err != nil {
+		return false, 0, int(l.limit), fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	remaining := l.limit - incr.Val()
+	if remaining < 0 {
+		remaining = 0
+	}
+	return incr.Val() <= l.limit, int(remaining), int(l.limit), nil
+}
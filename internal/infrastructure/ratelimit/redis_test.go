@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisLimiter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	ctx := context.Background()
+	limiter := NewRedisLimiter(client, 2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		allowed, err := limiter.Allow(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, err := limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, allowed, "third request in the window must be limited")
+
+	allowed, err = limiter.Allow(ctx, "10.0.0.2")
+	require.NoError(t, err)
+	assert.True(t, allowed, "limits are per key")
+
+	_, remaining, limit, err := limiter.AllowQuota(ctx, "10.0.0.3")
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, 2, limit)
+
+	server.Close()
+	_, err = limiter.Allow(ctx, "10.0.0.1")
+	assert.Error(t, err, "backend failure must be reported to the middleware")
+}
+
+func TestRedisLimiterWindowFollowsClock(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	limiter := NewRedisLimiter(client, 1, time.Minute, WithClock(fake))
+
+	allowed, err := limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	fake.Advance(59 * time.Second)
+	allowed, err = limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, allowed, "still in the same window")
+
+	fake.Advance(time.Second)
+	allowed, err = limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, allowed, "the next window starts a new counter")
+}
+
+func TestNewBackend(t *testing.T) {
+	memory := NewBackend(&config.Config{Security: config.SecurityConfig{RateLimitBackend: "memory"}})
+	assert.Nil(t, memory, "the memory backend is the middleware default")
+	assert.Nil(t, memory.Limiter(10))
+	assert.NoError(t, memory.Close())
+
+	server := miniredis.RunT(t)
+	cfg := &config.Config{
+		Security: config.SecurityConfig{RateLimitBackend: "redis"},
+		Redis:    config.RedisConfig{Addr: server.Addr()},
+	}
+	backend := NewBackend(cfg)
+	require.NotNil(t, backend)
+
+	ctx := context.Background()
+	for _, limit := range []int{1, 3} {
+		_, remaining, got, err := backend.Limiter(limit).(*RedisLimiter).AllowQuota(ctx, "tenant:"+strconv.Itoa(limit))
+		require.NoError(t, err)
+		assert.Equal(t, limit, got)
+		assert.Equal(t, limit-1, remaining)
+	}
+
+	// Após Close, os limitadores deixam de alcançar o Redis
+	require.NoError(t, backend.Close())
+	_, err := backend.Limiter(1).Allow(ctx, "10.0.0.1")
+	assert.Error(t, err)
+}
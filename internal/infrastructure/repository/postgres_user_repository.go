@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	domainRepo "go-api-boilerplate/internal/domain/repository"
 	"go-api-boilerplate/internal/domain/user"
 	db "go-api-boilerplate/internal/infrastructure/database"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/pkg/database"
 	"github.com/google/uuid"
 )
 
@@ -16,18 +19,73 @@ import (
 type PostgresUserRepository struct {
 	db      *sql.DB
 	querier *db.Queries
+	clock   clock.Clock
+}
+
+// PostgresOption configura comportamentos opcionais do PostgresUserRepository
+type PostgresOption func(*PostgresUserRepository)
+
+// WithClock define a fonte de tempo dos timestamps preenchidos pelo repositório
+func WithClock(c clock.Clock) PostgresOption {
+	return func(r *PostgresUserRepository) {
+		r.clock = c
+	}
 }
 
 // NewPostgresUserRepository cria uma nova instância de PostgresUserRepository
-func NewPostgresUserRepository(sqlDB *sql.DB) domainRepo.UserRepository {
-	return &PostgresUserRepository{
+func NewPostgresUserRepository(sqlDB *sql.DB, opts ...PostgresOption) domainRepo.UserRepository {
+	r := &PostgresUserRepository{
 		db:      sqlDB,
 		querier: db.New(sqlDB),
+		clock:   clock.System,
 	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
 // Create cria um novo usuário no repositório
 func (r *PostgresUserRepository) Create(ctx context.Context, u *user.User) error {
+	return r.insertUser(ctx, r.querier, u)
+}
+
+// CreateFirstAdmin cria o primeiro admin. Um advisory lock serializa chamadas
+// concorrentes, então apenas uma vê a tabela sem admins e insere
+func (r *PostgresUserRepository) CreateFirstAdmin(ctx context.Context, u *user.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.querier.WithTx(tx)
+	if err := q.LockFirstAdminBootstrap(ctx); err != nil {
+		return fmt.Errorf("failed to lock admin bootstrap: %w", err)
+	}
+
+	exists, err := q.ExistsByRole(ctx, string(user.RoleAdmin))
+	if err != nil {
+		return fmt.Errorf("failed to check admin existence in database: %w", err)
+	}
+	if exists {
+		return user.ErrAdminAlreadyExists
+	}
+
+	if err := r.insertUser(ctx, q, u); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertUser preenche ID e timestamps ausentes e insere o usuário com q
+func (r *PostgresUserRepository) insertUser(ctx context.Context, q *db.Queries, u *user.User) error {
 	// Gera um novo UUID se não existir
 	if u.ID == "" {
 		u.ID = uuid.New().String()
@@ -35,23 +93,31 @@ func (r *PostgresUserRepository) Create(ctx context.Context, u *user.User) error
 
 	// Define timestamps se não existirem
 	if u.CreatedAt.IsZero() {
-		u.CreatedAt = time.Now()
+		u.CreatedAt = r.clock.Now()
 	}
 	if u.UpdatedAt.IsZero() {
-		u.UpdatedAt = time.Now()
+		u.UpdatedAt = r.clock.Now()
 	}
 
 	// Insere no banco de dados
-	dbUser, err := r.querier.CreateUser(ctx, db.CreateUserParams{
-		Email:     u.Email,
-		Password:  u.Password,
-		Name:      u.Name,
-		Role:      string(u.Role),
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+	dbUser, err := q.CreateUser(ctx, db.CreateUserParams{
+		Email:           u.Email,
+		Password:        u.Password,
+		Name:            u.Name,
+		Role:            string(u.Role),
+		IsActive:        u.IsActive,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+		EmailVerifiedAt: nullTime(u.EmailVerifiedAt),
+		Username:        nullString(u.Username),
+
+		PasswordChangedAt: nullTime(u.PasswordChangedAt),
 	})
 	if err != nil {
+		// Email ou username ocupados entre a verificação e a inserção
+		if database.IsUniqueViolation(err) {
+			return user.ErrUserAlreadyExists
+		}
 		return fmt.Errorf("failed to create user in database: %w", err)
 	}
 
@@ -79,6 +145,34 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*user.
 	return r.mapDBUserToDomainUser(&dbUser, nil), nil
 }
 
+// MaxIDsPerQuery limita o array de IDs enviado em cada consulta WHERE id = ANY($1)
+const MaxIDsPerQuery = 1000
+
+// GetByIDs busca vários usuários pelo ID; IDs inválidos são ignorados. Listas
+// maiores que MaxIDsPerQuery são consultadas em partes
+func (r *PostgresUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	parsed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if userID, err := uuid.Parse(id); err == nil {
+			parsed = append(parsed, userID)
+		}
+	}
+
+	users := make([]*user.User, 0, len(parsed))
+	for start := 0; start < len(parsed); start += MaxIDsPerQuery {
+		chunk := parsed[start:min(start+MaxIDsPerQuery, len(parsed))]
+		dbUsers, err := r.querier.GetUsersByIDs(ctx, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+		}
+		for _, dbUser := range dbUsers {
+			users = append(users, r.mapDBUserToDomainUser(&dbUser, nil))
+		}
+	}
+
+	return users, nil
+}
+
 // GetByEmail busca um usuário pelo email
 func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	dbUser, err := r.querier.GetUserByEmail(ctx, email)
@@ -92,10 +186,24 @@ func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (
 	return r.mapDBUserToDomainUser(&dbUser, nil), nil
 }
 
-// Update atualiza um usuário existente
+// GetByUsername busca um usuário pelo nome de usuário, sem diferenciar maiúsculas
+func (r *PostgresUserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	dbUser, err := r.querier.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by username: %w", err)
+	}
+
+	return r.mapDBUserToDomainUser(&dbUser, nil), nil
+}
+
+// Update atualiza um usuário existente. Uma troca de role incrementa o
+// token_version no mesmo UPDATE
 func (r *PostgresUserRepository) Update(ctx context.Context, u *user.User) error {
 	// Atualiza o timestamp
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = r.clock.Now()
 
 	// Converte string ID para UUID
 	userID, err := uuid.Parse(u.ID)
@@ -112,11 +220,17 @@ func (r *PostgresUserRepository) Update(ctx context.Context, u *user.User) error
 		Role:      string(u.Role),
 		IsActive:  u.IsActive,
 		UpdatedAt: u.UpdatedAt,
+		Username:  nullString(u.Username),
+
+		PasswordChangedAt: nullTime(u.PasswordChangedAt),
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return user.ErrUserNotFound
 		}
+		if database.IsUniqueViolation(err) {
+			return user.ErrUserAlreadyExists
+		}
 		return fmt.Errorf("failed to update user in database: %w", err)
 	}
 
@@ -133,17 +247,199 @@ func (r *PostgresUserRepository) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("invalid user ID format: %w", err)
 	}
 
-	err = r.querier.DeleteUser(ctx, userID)
+	rows, err := r.querier.DeleteUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user from database: %w", err)
+	}
+	if rows == 0 {
+		return user.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Anonymize grava a entidade já anonimizada (ver user.Anonymize) em uma
+// transação. O UPDATE também desativa a conta e incrementa a versão dos
+// tokens, invalidando todas as sessões. Os tokens de verificação pendentes,
+// que guardam os endereços de email antigos e novos, os vínculos com
+// identidades externas e o token de redefinição de senha são removidos
+func (r *PostgresUserRepository) Anonymize(ctx context.Context, u *user.User) error {
+	userID, err := uuid.Parse(u.ID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.querier.WithTx(tx)
+	dbUser, err := q.AnonymizeUser(ctx, db.AnonymizeUserParams{
+		ID:        userID,
+		Email:     u.Email,
+		Password:  u.Password,
+		Name:      u.Name,
+		UpdatedAt: u.UpdatedAt,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return user.ErrUserNotFound
 		}
-		return fmt.Errorf("failed to delete user from database: %w", err)
+		return fmt.Errorf("failed to anonymize user in database: %w", err)
+	}
+	if _, err := q.DeleteEmailVerificationTokensByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete verification tokens: %w", err)
+	}
+	if _, err := q.DeleteUserIdentitiesByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to unlink external identities: %w", err)
+	}
+	if _, err := q.DeletePasswordResetTokensByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete password reset tokens: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.mapDBUserToDomainUser(&dbUser, u)
+
 	return nil
 }
 
+// IncrementTokenVersion incrementa a versão dos tokens do usuário e retorna a nova versão
+func (r *PostgresUserRepository) IncrementTokenVersion(ctx context.Context, id string) (int, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	version, err := r.querier.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, user.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("failed to increment token version: %w", err)
+	}
+
+	return int(version), nil
+}
+
+// IsActive informa se o usuário está ativo
+func (r *PostgresUserRepository) IsActive(ctx context.Context, id string) (bool, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	active, err := r.querier.GetUserActiveStatus(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, user.ErrUserNotFound
+		}
+		return false, fmt.Errorf("failed to get user active status: %w", err)
+	}
+
+	return active, nil
+}
+
+// GetTokenVersion retorna a versão atual dos tokens do usuário
+func (r *PostgresUserRepository) GetTokenVersion(ctx context.Context, id string) (int, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	version, err := r.querier.GetTokenVersion(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, user.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("failed to get token version: %w", err)
+	}
+
+	return int(version), nil
+}
+
+// UpdateRoles define o role de vários usuários em uma única transação. As linhas
+// são bloqueadas antes da classificação, evitando corrida com atualizações
+// concorrentes, e o token_version dos alterados é incrementado no mesmo UPDATE
+func (r *PostgresUserRepository) UpdateRoles(ctx context.Context, ids []string, role user.Role) (*domainRepo.RoleUpdateResult, error) {
+	return r.updateRoles(ctx, ids, role, true)
+}
+
+// PreviewUpdateRoles executa UpdateRoles e desfaz a transação, reportando o
+// efeito sem alterar dados
+func (r *PostgresUserRepository) PreviewUpdateRoles(ctx context.Context, ids []string, role user.Role) (*domainRepo.RoleUpdateResult, error) {
+	return r.updateRoles(ctx, ids, role, false)
+}
+
+// updateRoles classifica e atualiza os usuários; sem commit a transação é desfeita
+func (r *PostgresUserRepository) updateRoles(ctx context.Context, ids []string, role user.Role, commit bool) (*domainRepo.RoleUpdateResult, error) {
+	result := &domainRepo.RoleUpdateResult{}
+
+	parsed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, userID)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.querier.WithTx(tx)
+
+	rows, err := q.GetUserRolesForUpdate(ctx, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock users: %w", err)
+	}
+	current := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		current[row.ID] = row.Role
+	}
+
+	var toUpdate []uuid.UUID
+	for _, id := range ids {
+		userID, err := uuid.Parse(id)
+		existing, found := current[userID]
+		switch {
+		case err != nil || !found:
+			result.NotFound = append(result.NotFound, id)
+		case existing == string(role):
+			result.Unchanged = append(result.Unchanged, id)
+		default:
+			result.Updated = append(result.Updated, id)
+			toUpdate = append(toUpdate, userID)
+		}
+	}
+
+	if len(toUpdate) > 0 {
+		if _, err := q.UpdateUserRoles(ctx, db.UpdateUserRolesParams{
+			Role: string(role),
+			Ids:  toUpdate,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to update user roles: %w", err)
+		}
+	}
+
+	if !commit {
+		return result, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit role update: %w", err)
+	}
+
+	return result, nil
+}
+
 // List retorna uma lista de usuários com paginação
 func (r *PostgresUserRepository) List(ctx context.Context, offset, limit int) ([]*user.User, error) {
 	dbUsers, err := r.querier.ListUsers(ctx, db.ListUsersParams{
@@ -162,6 +458,63 @@ func (r *PostgresUserRepository) List(ctx context.Context, offset, limit int) ([
 	return users, nil
 }
 
+// ListActive retorna uma lista paginada apenas com usuários ativos
+func (r *PostgresUserRepository) ListActive(ctx context.Context, offset, limit int) ([]*user.User, error) {
+	dbUsers, err := r.querier.ListActiveUsers(ctx, db.ListActiveUsersParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active users from database: %w", err)
+	}
+
+	users := make([]*user.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.mapDBUserToDomainUser(&dbUser, nil)
+	}
+
+	return users, nil
+}
+
+// Search retorna uma página de usuários cujo nome ou email contém query
+func (r *PostgresUserRepository) Search(ctx context.Context, query string, includeInactive bool, offset, limit int) ([]*user.User, error) {
+	dbUsers, err := r.querier.SearchUsers(ctx, db.SearchUsersParams{
+		Pattern:         containsPattern(query),
+		IncludeInactive: includeInactive,
+		Limit:           int32(limit),
+		Offset:          int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users in database: %w", err)
+	}
+
+	users := make([]*user.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.mapDBUserToDomainUser(&dbUser, nil)
+	}
+
+	return users, nil
+}
+
+// CountSearch retorna o total de usuários que correspondem a query
+func (r *PostgresUserRepository) CountSearch(ctx context.Context, query string, includeInactive bool) (int64, error) {
+	count, err := r.querier.CountSearchUsers(ctx, db.CountSearchUsersParams{
+		Pattern:         containsPattern(query),
+		IncludeInactive: includeInactive,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count searched users in database: %w", err)
+	}
+
+	return count, nil
+}
+
+// containsPattern monta um padrão ILIKE de substring, escapando os curingas do usuário
+func containsPattern(query string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query)
+	return "%" + escaped + "%"
+}
+
 // Count retorna o total de usuários
 func (r *PostgresUserRepository) Count(ctx context.Context) (int64, error) {
 	count, err := r.querier.CountUsers(ctx)
@@ -172,6 +525,255 @@ func (r *PostgresUserRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// CountActive retorna o total de usuários ativos
+func (r *PostgresUserRepository) CountActive(ctx context.Context) (int64, error) {
+	count, err := r.querier.CountActiveUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active users in database: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListAfterID retorna até limit usuários com ID maior que afterID, em ordem de ID.
+// Diferente de OFFSET, o custo não cresce com a posição na tabela
+func (r *PostgresUserRepository) ListAfterID(ctx context.Context, afterID string, limit int) ([]*user.User, error) {
+	cursor := uuid.Nil
+	if afterID != "" {
+		parsed, err := uuid.Parse(afterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID format: %w", err)
+		}
+		cursor = parsed
+	}
+
+	dbUsers, err := r.querier.ListUsersAfterID(ctx, db.ListUsersAfterIDParams{
+		ID:    cursor,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users after ID: %w", err)
+	}
+
+	users := make([]*user.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.mapDBUserToDomainUser(&dbUser, nil)
+	}
+
+	return users, nil
+}
+
+// Iterate percorre todos os usuários por keyset (id > último), um lote por vez,
+// sem carregar a tabela inteira nem pagar o custo crescente de OFFSET. Usuários
+// criados durante a iteração podem ou não ser visitados, conforme o ID
+func (r *PostgresUserRepository) Iterate(ctx context.Context, batchSize int, fn func([]*user.User) error) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d: must be positive", batchSize)
+	}
+
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, err := r.ListAfterID(ctx, afterID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := fn(batch); err != nil {
+			return err
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+// CountByRole retorna o total de usuários por papel em uma única consulta.
+// Papéis válidos sem usuários aparecem com zero; papéis fora do registro
+// atual (ex.: customizados removidos da configuração) também são mantidos
+func (r *PostgresUserRepository) CountByRole(ctx context.Context) (map[user.Role]int64, error) {
+	rows, err := r.querier.CountUsersByRole(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %w", err)
+	}
+
+	counts := make(map[user.Role]int64, len(rows))
+	for _, role := range user.ValidRoles() {
+		counts[role] = 0
+	}
+	for _, row := range rows {
+		counts[user.Role(row.Role)] = row.Count
+	}
+
+	return counts, nil
+}
+
+// CountCreatedBetween retorna o total de usuários criados no intervalo [from, to]
+func (r *PostgresUserRepository) CountCreatedBetween(ctx context.Context, from, to time.Time) (int64, error) {
+	count, err := r.querier.CountUsersCreatedBetween(ctx, db.CountUsersCreatedBetweenParams{
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users created between dates: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListCreatedBetween retorna uma página de usuários criados no intervalo [from, to]
+func (r *PostgresUserRepository) ListCreatedBetween(ctx context.Context, from, to time.Time, offset, limit int) ([]*user.User, error) {
+	dbUsers, err := r.querier.ListUsersCreatedBetween(ctx, db.ListUsersCreatedBetweenParams{
+		FromTime: from,
+		ToTime:   to,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users created between dates: %w", err)
+	}
+
+	users := make([]*user.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.mapDBUserToDomainUser(&dbUser, nil)
+	}
+
+	return users, nil
+}
+
+// ReplacePassword grava apenas a senha, condicionada ao hash lido, para não
+// desfazer alterações concorrentes como um Update da linha inteira faria
+func (r *PostgresUserRepository) ReplacePassword(ctx context.Context, u *user.User, previousHash string) (bool, error) {
+	userID, err := uuid.Parse(u.ID)
+	if err != nil {
+		return false, fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	rows, err := r.querier.ReplaceUserPassword(ctx, db.ReplaceUserPasswordParams{
+		Password:          u.Password,
+		PasswordChangedAt: nullTime(u.PasswordChangedAt),
+		ID:                userID,
+		PreviousPassword:  previousHash,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to replace password in database: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+// RecordLogin grava at como o momento do último login do usuário
+func (r *PostgresUserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	if err := r.querier.UpdateLastLogin(ctx, db.UpdateLastLoginParams{
+		LastLoginAt: nullTime(&at),
+		ID:          userID,
+	}); err != nil {
+		return fmt.Errorf("failed to record login in database: %w", err)
+	}
+
+	return nil
+}
+
+// CountDormant retorna o total de usuários sem login desde since
+func (r *PostgresUserRepository) CountDormant(ctx context.Context, since time.Time) (int64, error) {
+	count, err := r.querier.CountDormantUsers(ctx, nullTime(&since))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count dormant users: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListDormant retorna uma página de usuários sem login desde since
+func (r *PostgresUserRepository) ListDormant(ctx context.Context, since time.Time, offset, limit int) ([]*user.User, error) {
+	dbUsers, err := r.querier.ListDormantUsers(ctx, db.ListDormantUsersParams{
+		Since:  nullTime(&since),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dormant users: %w", err)
+	}
+
+	users := make([]*user.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.mapDBUserToDomainUser(&dbUser, nil)
+	}
+
+	return users, nil
+}
+
+// ListInactiveByRole retorna os usuários ativos do papel sem atividade desde since
+func (r *PostgresUserRepository) ListInactiveByRole(ctx context.Context, role user.Role, since time.Time) ([]*user.User, error) {
+	dbUsers, err := r.querier.ListInactiveUsersByRole(ctx, db.ListInactiveUsersByRoleParams{
+		Role:  string(role),
+		Since: since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive users by role: %w", err)
+	}
+
+	users := make([]*user.User, len(dbUsers))
+	for i, dbUser := range dbUsers {
+		users[i] = r.mapDBUserToDomainUser(&dbUser, nil)
+	}
+
+	return users, nil
+}
+
+// CountActiveByRole retorna o total de usuários ativos do papel
+func (r *PostgresUserRepository) CountActiveByRole(ctx context.Context, role user.Role) (int64, error) {
+	count, err := r.querier.CountActiveUsersByRole(ctx, string(role))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active users by role: %w", err)
+	}
+
+	return count, nil
+}
+
+// DowngradeInactive muda o papel e incrementa token_version em uma única
+// instrução, condicionada ao papel, à conta ativa e à inatividade no momento da escrita
+func (r *PostgresUserRepository) DowngradeInactive(ctx context.Context, ids []string, from, to user.Role, since time.Time) ([]string, error) {
+	parsed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, userID)
+	}
+
+	updated, err := r.querier.DowngradeInactiveUsers(ctx, db.DowngradeInactiveUsersParams{
+		NewRole: string(to),
+		Ids:     parsed,
+		Role:    string(from),
+		Since:   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to downgrade inactive users: %w", err)
+	}
+
+	result := make([]string, len(updated))
+	for i, id := range updated {
+		result[i] = id.String()
+	}
+
+	return result, nil
+}
+
 // ExistsByEmail verifica se existe um usuário com o email fornecido
 func (r *PostgresUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	exists, err := r.querier.ExistsByEmail(ctx, email)
@@ -182,6 +784,30 @@ func (r *PostgresUserRepository) ExistsByEmail(ctx context.Context, email string
 	return exists, nil
 }
 
+// ExistsByUsername verifica se o nome de usuário já está em uso, sem diferenciar maiúsculas
+func (r *PostgresUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	exists, err := r.querier.ExistsByUsername(ctx, username)
+	if err != nil {
+		return false, fmt.Errorf("failed to check username existence in database: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ExistingEmails retorna quais dos emails fornecidos já estão cadastrados
+func (r *PostgresUserRepository) ExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return []string{}, nil
+	}
+
+	existing, err := r.querier.ListExistingEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing emails in database: %w", err)
+	}
+
+	return existing, nil
+}
+
 // ExistsByID verifica se existe um usuário com o ID fornecido
 func (r *PostgresUserRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
 	userID, err := uuid.Parse(id)
@@ -197,6 +823,252 @@ func (r *PostgresUserRepository) ExistsByID(ctx context.Context, id string) (boo
 	return exists, nil
 }
 
+// GetByIdentity busca o usuário vinculado à identidade externa
+func (r *PostgresUserRepository) GetByIdentity(ctx context.Context, issuer, subject string) (*user.User, error) {
+	dbUser, err := r.querier.GetUserByIdentity(ctx, db.GetUserByIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by identity: %w", err)
+	}
+
+	return r.mapDBUserToDomainUser(&dbUser, nil), nil
+}
+
+// LinkIdentity vincula uma identidade externa ao usuário
+func (r *PostgresUserRepository) LinkIdentity(ctx context.Context, userID, issuer, subject string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+
+	err = r.querier.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		Issuer:    issuer,
+		Subject:   subject,
+		UserID:    id,
+		CreatedAt: r.clock.Now(),
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return user.ErrIdentityAlreadyLinked
+		}
+		return fmt.Errorf("failed to link identity in database: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceVerificationToken grava o token de verificação, descartando os anteriores do usuário
+func (r *PostgresUserRepository) ReplaceVerificationToken(ctx context.Context, token *user.VerificationToken) error {
+	userID, err := uuid.Parse(token.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = r.clock.Now()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.querier.WithTx(tx)
+	if _, err := q.DeleteEmailVerificationTokensByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to invalidate verification tokens: %w", err)
+	}
+	err = q.CreateEmailVerificationToken(ctx, db.CreateEmailVerificationTokenParams{
+		TokenHash: token.Hash,
+		UserID:    userID,
+		Email:     token.Email,
+		ReplacesEmail: sql.NullString{
+			String: token.ReplacesEmail,
+			Valid:  token.ReplacesEmail != "",
+		},
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ConsumeVerificationToken remove e retorna o token com o hash informado
+func (r *PostgresUserRepository) ConsumeVerificationToken(ctx context.Context, hash string) (*user.VerificationToken, error) {
+	row, err := r.querier.ConsumeEmailVerificationToken(ctx, hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrVerificationTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to consume verification token: %w", err)
+	}
+
+	return &user.VerificationToken{
+		Hash:          row.TokenHash,
+		UserID:        row.UserID.String(),
+		Email:         row.Email,
+		ReplacesEmail: row.ReplacesEmail.String,
+		ExpiresAt:     row.ExpiresAt,
+		CreatedAt:     row.CreatedAt,
+	}, nil
+}
+
+// MarkEmailVerified marca o email atual do usuário como verificado
+func (r *PostgresUserRepository) MarkEmailVerified(ctx context.Context, userID, email string, verifiedAt time.Time) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrUserNotFound
+	}
+
+	affected, err := r.querier.MarkUserEmailVerified(ctx, db.MarkUserEmailVerifiedParams{
+		VerifiedAt: verifiedAt,
+		ID:         id,
+		Email:      email,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark email as verified: %w", err)
+	}
+	if affected == 0 {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
+
+// ChangeEmail troca o email do usuário, desde que oldEmail ainda seja o atual
+func (r *PostgresUserRepository) ChangeEmail(ctx context.Context, userID, oldEmail, newEmail string, verifiedAt time.Time) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return user.ErrUserNotFound
+	}
+
+	affected, err := r.querier.ChangeUserEmail(ctx, db.ChangeUserEmailParams{
+		NewEmail:   newEmail,
+		VerifiedAt: verifiedAt,
+		ID:         id,
+		OldEmail:   oldEmail,
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return user.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("failed to change email in database: %w", err)
+	}
+	if affected == 0 {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
+
+// ReplacePasswordResetToken grava o token de redefinição, descartando o
+// anterior do usuário
+func (r *PostgresUserRepository) ReplacePasswordResetToken(ctx context.Context, token *user.PasswordResetToken) error {
+	userID, err := uuid.Parse(token.UserID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID format: %w", err)
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = r.clock.Now()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := r.querier.WithTx(tx)
+	if _, err := q.DeletePasswordResetTokensByUser(ctx, userID); err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+	err = q.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+		TokenHash: token.Hash,
+		UserID:    userID,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetPasswordResetToken retorna o token de redefinição pendente do usuário
+func (r *PostgresUserRepository) GetPasswordResetToken(ctx context.Context, userID string) (*user.PasswordResetToken, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, user.ErrPasswordResetTokenNotFound
+	}
+
+	row, err := r.querier.GetPasswordResetTokenByUser(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, user.ErrPasswordResetTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	return &user.PasswordResetToken{
+		Hash:      row.TokenHash,
+		UserID:    row.UserID.String(),
+		ExpiresAt: row.ExpiresAt,
+		Attempts:  int(row.Attempts),
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+// IncrementResetAttempts incrementa as tentativas do token em um único UPDATE,
+// de modo que confirmações concorrentes recebem totais distintos
+func (r *PostgresUserRepository) IncrementResetAttempts(ctx context.Context, hash string) (int, error) {
+	attempts, err := r.querier.IncrementPasswordResetAttempts(ctx, hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, user.ErrPasswordResetTokenNotFound
+		}
+		return 0, fmt.Errorf("failed to increment password reset attempts: %w", err)
+	}
+
+	return int(attempts), nil
+}
+
+// ConsumePasswordResetToken remove o token com o hash informado
+func (r *PostgresUserRepository) ConsumePasswordResetToken(ctx context.Context, hash string) error {
+	affected, err := r.querier.ConsumePasswordResetToken(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+	if affected == 0 {
+		return user.ErrPasswordResetTokenNotFound
+	}
+	return nil
+}
+
+// nullTime converte um horário opcional do domínio para o banco
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// nullString converte um texto opcional do domínio (vazio = ausente) para o banco
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
 // mapDBUserToDomainUser mapeia um User do banco de dados para a entidade de domínio
 func (r *PostgresUserRepository) mapDBUserToDomainUser(dbUser *db.User, domainUser *user.User) *user.User {
 	if domainUser == nil {
@@ -211,6 +1083,23 @@ func (r *PostgresUserRepository) mapDBUserToDomainUser(dbUser *db.User, domainUs
 	domainUser.IsActive = dbUser.IsActive
 	domainUser.CreatedAt = dbUser.CreatedAt
 	domainUser.UpdatedAt = dbUser.UpdatedAt
+	domainUser.TokenVersion = int(dbUser.TokenVersion)
+	domainUser.Username = dbUser.Username.String
+	domainUser.EmailVerifiedAt = nil
+	if dbUser.EmailVerifiedAt.Valid {
+		verifiedAt := dbUser.EmailVerifiedAt.Time
+		domainUser.EmailVerifiedAt = &verifiedAt
+	}
+	domainUser.PasswordChangedAt = nil
+	if dbUser.PasswordChangedAt.Valid {
+		changedAt := dbUser.PasswordChangedAt.Time
+		domainUser.PasswordChangedAt = &changedAt
+	}
+	domainUser.LastLoginAt = nil
+	if dbUser.LastLoginAt.Valid {
+		lastLoginAt := dbUser.LastLoginAt.Time
+		domainUser.LastLoginAt = &lastLoginAt
+	}
 
 	return domainUser
 }
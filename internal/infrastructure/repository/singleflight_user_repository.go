@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	domainRepo "go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// SingleflightUserRepository decora um UserRepository deduplicando leituras
+// concorrentes idênticas: chamadas simultâneas para o mesmo ID ou email
+// compartilham uma única consulta ao banco, inclusive o erro (ex.: ErrUserNotFound)
+type SingleflightUserRepository struct {
+	domainRepo.UserRepository
+	group singleflight.Group
+}
+
+// NewSingleflightUserRepository cria o decorator sobre o repositório informado
+func NewSingleflightUserRepository(inner domainRepo.UserRepository) domainRepo.UserRepository {
+	return &SingleflightUserRepository{UserRepository: inner}
+}
+
+// GetByID busca um usuário pelo ID, compartilhando consultas concorrentes
+func (r *SingleflightUserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	return r.do(ctx, "id:"+id, func(ctx context.Context) (*user.User, error) {
+		return r.UserRepository.GetByID(ctx, id)
+	})
+}
+
+// GetByEmail busca um usuário pelo email, compartilhando consultas concorrentes
+func (r *SingleflightUserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	return r.do(ctx, "email:"+email, func(ctx context.Context) (*user.User, error) {
+		return r.UserRepository.GetByEmail(ctx, email)
+	})
+}
+
+// do executa fn uma única vez por chave entre chamadas concorrentes.
+// A consulta compartilhada não herda o cancelamento de quem a iniciou,
+// mas cada chamador continua respeitando o próprio contexto
+func (r *SingleflightUserRepository) do(ctx context.Context, key string, fn func(context.Context) (*user.User, error)) (*user.User, error) {
+	shared := context.WithoutCancel(ctx)
+	ch := r.group.DoChan(key, func() (interface{}, error) {
+		return fn(shared)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// Cada chamador recebe sua própria cópia, pois a entidade é mutável
+		u := *res.Val.(*user.User)
+		return &u, nil
+	}
+}
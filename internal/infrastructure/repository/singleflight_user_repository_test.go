@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowRepository conta as chamadas e segura cada leitura até ser liberada
+type slowRepository struct {
+	mocks.UserRepository
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (r *slowRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	r.calls.Add(1)
+	<-r.release
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &user.User{ID: "1", Email: email}, nil
+}
+
+func TestSingleflightUserRepository(t *testing.T) {
+	run := func(inner *slowRepository) ([]*user.User, []error) {
+		repo := NewSingleflightUserRepository(inner)
+
+		const callers = 20
+		users := make([]*user.User, callers)
+		errs := make([]error, callers)
+
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				users[i], errs[i] = repo.GetByEmail(context.Background(), "a@b.com")
+			}(i)
+		}
+
+		// Aguarda os chamadores se acumularem na mesma consulta antes de liberá-la
+		time.Sleep(50 * time.Millisecond)
+		close(inner.release)
+		wg.Wait()
+
+		return users, errs
+	}
+
+	t.Run("concurrent reads share one call", func(t *testing.T) {
+		inner := &slowRepository{release: make(chan struct{})}
+		users, errs := run(inner)
+
+		assert.Equal(t, int32(1), inner.calls.Load())
+		for i := range users {
+			assert.NoError(t, errs[i])
+			assert.Equal(t, "a@b.com", users[i].Email)
+		}
+		assert.NotSame(t, users[0], users[1])
+	})
+
+	t.Run("not found is shared", func(t *testing.T) {
+		inner := &slowRepository{release: make(chan struct{}), err: user.ErrUserNotFound}
+		_, errs := run(inner)
+
+		assert.Equal(t, int32(1), inner.calls.Load())
+		for _, err := range errs {
+			assert.ErrorIs(t, err, user.ErrUserNotFound)
+		}
+	})
+
+	t.Run("key is cleared after the flight", func(t *testing.T) {
+		inner := &slowRepository{release: make(chan struct{})}
+		close(inner.release)
+		repo := NewSingleflightUserRepository(inner)
+
+		_, _ = repo.GetByEmail(context.Background(), "a@b.com")
+		_, _ = repo.GetByEmail(context.Background(), "a@b.com")
+
+		assert.Equal(t, int32(2), inner.calls.Load())
+	})
+}
@@ -0,0 +1,163 @@
+package sessions
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/clock"
+)
+
+// pruneInterval define a frequência mínima da limpeza de sessões expiradas
+const pruneInterval = time.Minute
+
+// MemoryStore implementa auth.SessionStore em memória.
+// Adequado para uma única instância; outras réplicas não veem as sessões
+type MemoryStore struct {
+	mu        sync.Mutex
+	users     map[string]map[string]auth.Session
+	lastPrune time.Time
+	clock     clock.Clock
+}
+
+var _ auth.SessionStore = (*MemoryStore)(nil)
+
+// MemoryOption configura comportamentos opcionais do MemoryStore
+type MemoryOption func(*MemoryStore)
+
+// WithClock define a fonte de tempo da expiração e da limpeza das sessões;
+// nil mantém clock.System
+func WithClock(c clock.Clock) MemoryOption {
+	return func(s *MemoryStore) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// NewMemoryStore cria uma nova instância de MemoryStore
+func NewMemoryStore(opts ...MemoryOption) *MemoryStore {
+	s := &MemoryStore{
+		users: make(map[string]map[string]auth.Session),
+		clock: clock.System,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.lastPrune = s.clock.Now()
+
+	return s
+}
+
+// Add registra a sessão até sua expiração
+func (s *MemoryStore) Add(ctx context.Context, session auth.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maybePruneAllLocked(s.clock.Now())
+	sessions, ok := s.users[session.UserID]
+	if !ok {
+		sessions = make(map[string]auth.Session)
+		s.users[session.UserID] = sessions
+	}
+	sessions[session.ID] = session
+
+	return nil
+}
+
+// List retorna as sessões não expiradas do usuário, da mais antiga para a mais recente
+func (s *MemoryStore) List(ctx context.Context, userID string) ([]auth.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.maybePruneAllLocked(now)
+	s.pruneLocked(userID, now)
+	active := make([]auth.Session, 0, len(s.users[userID]))
+	for _, session := range s.users[userID] {
+		active = append(active, session)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].IssuedAt.Before(active[j].IssuedAt)
+	})
+
+	return active, nil
+}
+
+// Exists verifica se a sessão existe e ainda não expirou
+func (s *MemoryStore) Exists(ctx context.Context, userID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.maybePruneAllLocked(now)
+	session, ok := s.users[userID][id]
+	if !ok {
+		return false, nil
+	}
+	if now.After(session.ExpiresAt) {
+		s.deleteLocked(userID, id)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Remove encerra uma sessão do usuário
+func (s *MemoryStore) Remove(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.users[userID][id]
+	if !ok {
+		return auth.ErrSessionNotFound
+	}
+	s.deleteLocked(userID, id)
+	if s.clock.Now().After(session.ExpiresAt) {
+		return auth.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RemoveAll encerra todas as sessões do usuário
+func (s *MemoryStore) RemoveAll(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, userID)
+	return nil
+}
+
+// maybePruneAllLocked descarta as sessões expiradas de todos os usuários, no
+// máximo uma vez por pruneInterval, para que user-agent e IP não fiquem em
+// memória depois da expiração; exige s.mu
+func (s *MemoryStore) maybePruneAllLocked(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	for userID := range s.users {
+		s.pruneLocked(userID, now)
+	}
+	s.lastPrune = now
+}
+
+// pruneLocked descarta as sessões expiradas do usuário; exige s.mu
+func (s *MemoryStore) pruneLocked(userID string, now time.Time) {
+	for id, session := range s.users[userID] {
+		if now.After(session.ExpiresAt) {
+			s.deleteLocked(userID, id)
+		}
+	}
+}
+
+// deleteLocked remove uma sessão e o usuário sem sessões; exige s.mu
+func (s *MemoryStore) deleteLocked(userID, id string) {
+	delete(s.users[userID], id)
+	if len(s.users[userID]) == 0 {
+		delete(s.users, userID)
+	}
+}
@@ -0,0 +1,200 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Prefixos das chaves de sessão no Redis: cada sessão é uma chave com o TTL do
+// token, e o índice do usuário é um sorted set de jti ordenado pela emissão
+const (
+	sessionKeyPrefix = "jwt:session:"
+	indexKeyPrefix   = "jwt:sessions:"
+)
+
+// RedisStore implementa auth.SessionStore no Redis, compartilhado entre réplicas.
+// Sessões expiram automaticamente junto com o token; o índice é limpo na listagem
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+var _ auth.SessionStore = (*RedisStore)(nil)
+
+// redisSession é o valor gravado na chave de cada sessão
+type redisSession struct {
+	UserID    string    `json:"user_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+}
+
+// NewRedisStore cria uma nova instância de RedisStore
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Add registra a sessão até sua expiração
+func (s *RedisStore) Add(ctx context.Context, session auth.Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(redisSession{
+		UserID:    session.UserID,
+		IssuedAt:  session.IssuedAt,
+		ExpiresAt: session.ExpiresAt,
+		UserAgent: session.UserAgent,
+		IP:        session.IP,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	index := indexKeyPrefix + session.UserID
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKeyPrefix+session.ID, data, ttl)
+		pipe.ZAdd(ctx, index, redis.Z{Score: float64(session.IssuedAt.UnixMicro()), Member: session.ID})
+		// O índice vive até a sessão mais longa do usuário
+		pipe.ExpireNX(ctx, index, ttl)
+		pipe.ExpireGT(ctx, index, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+
+	return nil
+}
+
+// List retorna as sessões não expiradas do usuário, da mais antiga para a mais recente
+func (s *RedisStore) List(ctx context.Context, userID string) ([]auth.Session, error) {
+	index := indexKeyPrefix + userID
+	ids, err := s.client.ZRange(ctx, index, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = sessionKeyPrefix + id
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	active := make([]auth.Session, 0, len(ids))
+	var expired []interface{}
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			expired = append(expired, ids[i])
+			continue
+		}
+		var stored redisSession
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			return nil, fmt.Errorf("failed to decode session: %w", err)
+		}
+		active = append(active, auth.Session{
+			ID:        ids[i],
+			UserID:    stored.UserID,
+			IssuedAt:  stored.IssuedAt,
+			ExpiresAt: stored.ExpiresAt,
+			UserAgent: stored.UserAgent,
+			IP:        stored.IP,
+		})
+	}
+
+	if len(expired) > 0 {
+		if err := s.client.ZRem(ctx, index, expired...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to prune sessions: %w", err)
+		}
+	}
+
+	return active, nil
+}
+
+// Exists verifica se a sessão existe e pertence ao usuário
+func (s *RedisStore) Exists(ctx context.Context, userID, id string) (bool, error) {
+	stored, err := s.get(ctx, id)
+	if err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return stored.UserID == userID, nil
+}
+
+// Remove encerra uma sessão do usuário
+func (s *RedisStore) Remove(ctx context.Context, userID, id string) error {
+	removed, err := s.client.ZRem(ctx, indexKeyPrefix+userID, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	// Fora do índice do usuário, a sessão é de outro usuário ou não existe
+	if removed == 0 {
+		return auth.ErrSessionNotFound
+	}
+
+	deleted, err := s.client.Del(ctx, sessionKeyPrefix+id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	if deleted == 0 {
+		return auth.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RemoveAll encerra todas as sessões do usuário
+func (s *RedisStore) RemoveAll(ctx context.Context, userID string) error {
+	index := indexKeyPrefix + userID
+	ids, err := s.client.ZRange(ctx, index, 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKeyPrefix+id)
+	}
+	keys = append(keys, index)
+	if err := s.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to remove sessions: %w", err)
+	}
+
+	return nil
+}
+
+// get lê a sessão gravada; ausente ou expirada retorna auth.ErrSessionNotFound
+func (s *RedisStore) get(ctx context.Context, id string) (*redisSession, error) {
+	raw, err := s.client.Get(ctx, sessionKeyPrefix+id).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, auth.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	var stored redisSession
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+
+	return &stored, nil
+}
@@ -0,0 +1,26 @@
+package sessions
+
+import (
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// New cria o armazenamento configurado em security.session_store; vazio
+// desabilita o controle de sessões e retorna nil
+func New(cfg *config.Config) auth.SessionStore {
+	switch cfg.Security.SessionStore {
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		return NewRedisStore(client)
+	case "memory":
+		return NewMemoryStore()
+	default:
+		return nil
+	}
+}
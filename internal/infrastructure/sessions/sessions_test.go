@@ -0,0 +1,176 @@
+package sessions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedisStore cria um RedisStore sobre um miniredis descartável
+func newRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisStore(client), server
+}
+
+func TestMaxSessionsRevokesOldest(t *testing.T) {
+	redisStore, _ := newRedisStore(t)
+	stores := map[string]auth.SessionStore{
+		"memory": NewMemoryStore(),
+		"redis":  redisStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			service := auth.NewJWTService("secret", time.Hour, auth.WithSessions(store, 2))
+
+			first, err := service.GenerateToken("1", "a@b.com", "user")
+			require.NoError(t, err)
+			second, err := service.GenerateToken("1", "a@b.com", "user")
+			require.NoError(t, err)
+			other, err := service.GenerateToken("2", "c@d.com", "user")
+			require.NoError(t, err)
+			third, err := service.GenerateToken("1", "a@b.com", "user")
+			require.NoError(t, err)
+
+			_, err = service.ValidateToken(first)
+			assert.ErrorIs(t, err, auth.ErrRevokedToken, "the oldest session is revoked beyond the limit")
+			for _, token := range []string{second, third, other} {
+				_, err = service.ValidateToken(token)
+				assert.NoError(t, err)
+			}
+
+			active, err := store.List(context.Background(), "1")
+			require.NoError(t, err)
+			require.Len(t, active, 2)
+			assert.Equal(t, mustJTI(t, second), active[0].ID)
+			assert.Equal(t, mustJTI(t, third), active[1].ID)
+		})
+	}
+}
+
+func TestRevokeTokenEndsSession(t *testing.T) {
+	store := NewMemoryStore()
+	service := auth.NewJWTService("secret", time.Hour, auth.WithSessions(store, 0))
+
+	token, err := service.GenerateToken("1", "a@b.com", "user")
+	require.NoError(t, err)
+
+	// Sem blacklist, o logout remove a sessão e o token deixa de valer
+	require.NoError(t, service.RevokeToken(context.Background(), token))
+	_, err = service.ValidateToken(token)
+	assert.ErrorIs(t, err, auth.ErrRevokedToken)
+}
+
+func TestSessionStores(t *testing.T) {
+	ctx := context.Background()
+	redisStore, server := newRedisStore(t)
+	now := time.Now()
+
+	stores := map[string]auth.SessionStore{
+		"memory": NewMemoryStore(),
+		"redis":  redisStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.Add(ctx, auth.Session{ID: "b", UserID: "1", IssuedAt: now.Add(time.Second), ExpiresAt: now.Add(time.Hour), UserAgent: "curl/8.4.0", IP: "203.0.113.7"}))
+			require.NoError(t, store.Add(ctx, auth.Session{ID: "a", UserID: "1", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+			require.NoError(t, store.Add(ctx, auth.Session{ID: "c", UserID: "2", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+			active, err := store.List(ctx, "1")
+			require.NoError(t, err)
+			require.Len(t, active, 2)
+			assert.Equal(t, "a", active[0].ID, "oldest first")
+			assert.Equal(t, "b", active[1].ID)
+			assert.Equal(t, "curl/8.4.0", active[1].UserAgent)
+			assert.Equal(t, "203.0.113.7", active[1].IP)
+
+			exists, err := store.Exists(ctx, "2", "a")
+			require.NoError(t, err)
+			assert.False(t, exists, "a session belongs to a single user")
+
+			assert.ErrorIs(t, store.Remove(ctx, "2", "a"), auth.ErrSessionNotFound)
+			require.NoError(t, store.Remove(ctx, "1", "a"))
+			assert.ErrorIs(t, store.Remove(ctx, "1", "a"), auth.ErrSessionNotFound)
+
+			require.NoError(t, store.RemoveAll(ctx, "1"))
+			active, err = store.List(ctx, "1")
+			require.NoError(t, err)
+			assert.Empty(t, active)
+
+			exists, err = store.Exists(ctx, "2", "c")
+			require.NoError(t, err)
+			assert.True(t, exists)
+		})
+	}
+
+	t.Run("redis sessions expire with the token", func(t *testing.T) {
+		require.NoError(t, redisStore.Add(ctx, auth.Session{ID: "d", UserID: "3", IssuedAt: now, ExpiresAt: now.Add(time.Minute)}))
+		server.FastForward(time.Minute + time.Second)
+
+		active, err := redisStore.List(ctx, "3")
+		require.NoError(t, err)
+		assert.Empty(t, active)
+		assert.False(t, server.Exists("jwt:session:d"))
+	})
+
+	t.Run("memory ignores expired sessions", func(t *testing.T) {
+		store := NewMemoryStore()
+		require.NoError(t, store.Add(ctx, auth.Session{ID: "e", UserID: "4", IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Second)}))
+
+		active, err := store.List(ctx, "4")
+		require.NoError(t, err)
+		assert.Empty(t, active)
+		assert.ErrorIs(t, store.Remove(ctx, "4", "e"), auth.ErrSessionNotFound)
+	})
+}
+
+func TestMemoryStoreExpiresWithClock(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	store := NewMemoryStore(WithClock(fake))
+	now := fake.Now()
+
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "a", UserID: "1", IssuedAt: now, ExpiresAt: now.Add(30 * time.Second), UserAgent: "curl/8.4.0"}))
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "b", UserID: "2", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	exists, err := store.Exists(ctx, "1", "a")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	fake.Advance(31 * time.Second)
+	exists, err = store.Exists(ctx, "1", "a")
+	require.NoError(t, err)
+	assert.False(t, exists, "the session expires with the clock")
+
+	// A limpeza periódica descarta sessões expiradas de usuários não consultados
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "c", UserID: "3", IssuedAt: fake.Now(), ExpiresAt: fake.Now().Add(time.Second)}))
+	fake.Advance(pruneInterval)
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "d", UserID: "2", IssuedAt: fake.Now(), ExpiresAt: fake.Now().Add(time.Hour)}))
+	store.mu.Lock()
+	_, pending := store.users["3"]
+	store.mu.Unlock()
+	assert.False(t, pending, "user-agent and IP do not outlive the session")
+}
+
+// mustJTI extrai o jti de um token sem validá-lo
+func mustJTI(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &auth.Claims{})
+	require.NoError(t, err)
+	claims, ok := parsed.Claims.(*auth.Claims)
+	require.True(t, ok)
+	return claims.ID
+}
@@ -0,0 +1,313 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+// Headers enviados em cada entrega
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	// HeaderRequestID correlaciona a entrega com a requisição que gerou o
+	// evento; é o nome padrão, trocado por WithRequestIDHeader
+	HeaderRequestID = "X-Request-ID"
+)
+
+// Valores padrão quando não configurados
+const (
+	defaultTimeout    = 5 * time.Second
+	defaultMaxRetries = 3
+	defaultQueueSize  = 100
+	defaultBackoff    = 500 * time.Millisecond
+)
+
+// Payload é o corpo JSON enviado aos receptores
+type Payload struct {
+	DeliveryID string          `json:"delivery_id"`
+	Event      user.EventType  `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Data       PayloadUserData `json:"data"`
+}
+
+// PayloadUserData contém os dados públicos do usuário afetado
+type PayloadUserData struct {
+	ID       string    `json:"id"`
+	Email    string    `json:"email,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Role     user.Role `json:"role,omitempty"`
+	IsActive *bool     `json:"is_active,omitempty"`
+}
+
+// delivery é uma entrega pendente para uma URL
+type delivery struct {
+	url       string
+	event     user.EventType
+	id        string
+	requestID string
+	body      []byte
+}
+
+// endpoint é a fila de uma URL; cada uma tem seu próprio worker, então um
+// receptor lento ou fora do ar não atrasa as entregas dos demais
+type endpoint struct {
+	url   string
+	queue chan delivery
+}
+
+// Dispatcher entrega eventos de usuário via HTTP de forma assíncrona, com
+// assinatura HMAC, timeout e novas tentativas com backoff exponencial
+type Dispatcher struct {
+	endpoints  []*endpoint
+	secret     []byte
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+	logger     *slog.Logger
+	// requestIDHeader é o header do ID de correlação nas entregas
+	requestIDHeader string
+
+	// ctx é cancelado quando Close desiste de drenar as filas, interrompendo
+	// requisições e esperas de backoff em andamento
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu protege closed: após Close, Publish descarta os eventos em vez de
+	// enviar para filas fechadas
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Option configura comportamentos opcionais do Dispatcher
+type Option func(*Dispatcher)
+
+// WithRequestIDHeader define o header do ID de correlação nas entregas. Use o
+// mesmo de server.request_id_header, para que receptores vejam o nome que a
+// API usa; vazio mantém HeaderRequestID
+func WithRequestIDHeader(name string) Option {
+	return func(d *Dispatcher) {
+		if name != "" {
+			d.requestIDHeader = name
+		}
+	}
+}
+
+// NewDispatcher cria o dispatcher e inicia um worker de entrega por URL. Close
+// deve ser chamado no desligamento para drenar as filas
+func NewDispatcher(cfg config.WebhooksConfig, logger *slog.Logger, opts ...Option) *Dispatcher {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxRetries := cfg.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	} else if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		secret:     []byte(cfg.Secret),
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: maxRetries,
+		backoff:    defaultBackoff,
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+
+		requestIDHeader: HeaderRequestID,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	for _, url := range cfg.URLs {
+		ep := &endpoint{url: url, queue: make(chan delivery, queueSize)}
+		d.endpoints = append(d.endpoints, ep)
+		d.wg.Add(1)
+		go d.run(ep)
+	}
+
+	return d
+}
+
+// Publish enfileira uma entrega por URL configurada. Nunca bloqueia: se a fila
+// da URL estiver cheia, a entrega é descartada e registrada em log. Após Close,
+// os eventos são descartados
+func (d *Dispatcher) Publish(ctx context.Context, event user.Event) {
+	if len(d.endpoints) == 0 {
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.logger.Debug("webhook dispatcher closed, dropping event",
+			"event", event.Type, "request_id", event.RequestID)
+		return
+	}
+
+	for _, ep := range d.endpoints {
+		payload := newPayload(event)
+		body, err := json.Marshal(payload)
+		if err != nil {
+			d.logger.Error("failed to marshal webhook payload",
+				"event", event.Type, "request_id", event.RequestID, "error", err)
+			return
+		}
+
+		select {
+		case ep.queue <- delivery{url: ep.url, event: event.Type, id: payload.DeliveryID, requestID: event.RequestID, body: body}:
+		default:
+			d.logger.Warn("webhook queue full, dropping delivery",
+				"event", event.Type, "delivery_id", payload.DeliveryID, "request_id", event.RequestID, "url", ep.url)
+		}
+	}
+}
+
+// Close para de aceitar entregas e aguarda as filas esvaziarem. Se ctx expirar
+// antes, as entregas em andamento (inclusive esperas de backoff) são
+// interrompidas e as pendentes, descartadas
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, ep := range d.endpoints {
+			close(ep.queue)
+		}
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		return ctx.Err()
+	}
+}
+
+// run processa a fila de uma URL até ela ser fechada
+func (d *Dispatcher) run(ep *endpoint) {
+	defer d.wg.Done()
+	for del := range ep.queue {
+		d.deliver(del)
+	}
+}
+
+// deliver tenta entregar uma vez mais maxRetries novas tentativas
+func (d *Dispatcher) deliver(del delivery) {
+	backoff := d.backoff
+	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-d.ctx.Done():
+				timer.Stop()
+			}
+			backoff *= 2
+		}
+		if d.ctx.Err() != nil {
+			d.logger.Warn("webhook delivery canceled by shutdown",
+				"event", del.event, "delivery_id", del.id, "request_id", del.requestID, "url", del.url)
+			return
+		}
+
+		err := d.send(del)
+		if err == nil {
+			d.logger.Debug("webhook delivered",
+				"event", del.event, "delivery_id", del.id, "request_id", del.requestID, "url", del.url,
+				"attempt", attempt+1)
+			return
+		}
+
+		d.logger.Warn("webhook delivery failed",
+			"event", del.event, "delivery_id", del.id, "request_id", del.requestID, "url", del.url,
+			"attempt", attempt+1, "error", err)
+	}
+
+	d.logger.Error("webhook delivery abandoned",
+		"event", del.event, "delivery_id", del.id, "request_id", del.requestID, "url", del.url)
+}
+
+// send executa uma única requisição de entrega
+func (d *Dispatcher) send(del delivery) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, del.url, bytes.NewReader(del.body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(del.event))
+	req.Header.Set(HeaderDelivery, del.id)
+	req.Header.Set(HeaderSignature, Sign(d.secret, del.body))
+	if del.requestID != "" {
+		req.Header.Set(d.requestIDHeader, del.requestID)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign calcula a assinatura "sha256=<hex>" do corpo com o segredo compartilhado
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newPayload monta o corpo da entrega com um delivery ID novo
+func newPayload(event user.Event) Payload {
+	payload := Payload{
+		DeliveryID: uuid.NewString(),
+		Event:      event.Type,
+		OccurredAt: event.OccurredAt,
+		RequestID:  event.RequestID,
+		Data:       PayloadUserData{ID: event.UserID},
+	}
+	if u := event.User; u != nil {
+		isActive := u.IsActive
+		payload.Data.Email = u.Email
+		payload.Data.Name = u.Name
+		payload.Data.Role = u.Role
+		payload.Data.IsActive = &isActive
+	}
+	return payload
+}
@@ -0,0 +1,199 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	const secret = "webhook-secret"
+
+	var attempts atomic.Int32
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Primeira tentativa falha para exercitar o retry
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(config.WebhooksConfig{
+		URLs:   []string{server.URL},
+		Secret: secret,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.backoff = time.Millisecond
+
+	u := &user.User{ID: "42", Email: "a@b.com", Name: "A", Role: user.RoleUser, IsActive: true}
+	d.Publish(context.Background(), user.NewEvent(user.EventUserCreated, u))
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	require.NoError(t, d.Close(context.Background()))
+	assert.Equal(t, int32(2), attempts.Load())
+
+	assert.Equal(t, Sign([]byte(secret), body), req.Header.Get(HeaderSignature))
+	assert.Equal(t, string(user.EventUserCreated), req.Header.Get(HeaderEvent))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, req.Header.Get(HeaderDelivery), payload.DeliveryID)
+	assert.NotEmpty(t, payload.DeliveryID)
+	assert.Equal(t, "42", payload.Data.ID)
+	assert.Equal(t, "a@b.com", payload.Data.Email)
+}
+
+func TestDispatcherPublishDoesNotBlockWhenQueueFull(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+	defer close(block)
+
+	d := NewDispatcher(config.WebhooksConfig{
+		URLs:      []string{server.URL},
+		Secret:    "s",
+		QueueSize: 1,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			d.Publish(context.Background(), user.NewEvent(user.EventUserDeleted, nil))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full queue")
+	}
+}
+
+func TestDispatcherForwardsRequestID(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(config.WebhooksConfig{URLs: []string{server.URL}},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	event := user.NewEvent(user.EventUserUpdated, &user.User{ID: "42"})
+	event.RequestID = "req-123"
+	d.Publish(context.Background(), event)
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+	require.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, "req-123", req.Header.Get(HeaderRequestID))
+	var payload Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "req-123", payload.RequestID)
+}
+
+func TestDispatcherPublishAfterCloseIsDropped(t *testing.T) {
+	d := NewDispatcher(config.WebhooksConfig{URLs: []string{"http://127.0.0.1:1"}, Secret: "s"},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, d.Close(context.Background()))
+
+	assert.NotPanics(t, func() {
+		d.Publish(context.Background(), user.NewEvent(user.EventUserDeleted, nil))
+	})
+	require.NoError(t, d.Close(context.Background()))
+}
+
+func TestDispatcherSlowEndpointDoesNotBlockOthers(t *testing.T) {
+	block := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer slow.Close()
+	defer close(block)
+
+	delivered := make(chan struct{}, 1)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer fast.Close()
+
+	d := NewDispatcher(config.WebhooksConfig{URLs: []string{slow.URL, fast.URL}, Secret: "s", Timeout: time.Minute},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.Publish(context.Background(), user.NewEvent(user.EventUserCreated, &user.User{ID: "42"}))
+
+	select {
+	case <-delivered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("fast endpoint waited for the slow one")
+	}
+}
+
+func TestDispatcherCloseInterruptsBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(config.WebhooksConfig{URLs: []string{server.URL}, Secret: "s", MaxRetries: 5},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	d.backoff = time.Hour
+	d.Publish(context.Background(), user.NewEvent(user.EventUserCreated, &user.User{ID: "42"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, d.Close(ctx), context.DeadlineExceeded)
+
+	// O worker sai da espera de uma hora logo após o cancelamento
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("retry backoff was not interrupted by Close")
+	}
+}
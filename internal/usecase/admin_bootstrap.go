@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// WithAdminBootstrap habilita a criação do primeiro admin pela API, para
+// instalações sem seeder. Ela só funciona enquanto não existir nenhum admin
+func WithAdminBootstrap() Option {
+	return func(uc *UserUseCase) {
+		uc.adminBootstrap = true
+	}
+}
+
+// AdminBootstrapEnabled informa se a criação do primeiro admin foi habilitada
+func (uc *UserUseCase) AdminBootstrapEnabled() bool {
+	return uc.adminBootstrap
+}
+
+// BootstrapAdminInput representa os dados do primeiro admin
+type BootstrapAdminInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
+}
+
+// BootstrapAdmin cria o primeiro admin. Depois que existe qualquer admin
+// (criado aqui, por seeder ou promovido), retorna user.ErrAdminAlreadyExists
+func (uc *UserUseCase) BootstrapAdmin(ctx context.Context, input BootstrapAdminInput) (*CreateUserOutput, error) {
+	if !uc.adminBootstrap {
+		return nil, user.ErrAdminAlreadyExists
+	}
+
+	// Verificação antecipada: evita o hash da senha quando já há admin. A
+	// garantia contra chamadas concorrentes fica no CreateFirstAdmin
+	counts, err := uc.userRepo.CountByRole(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admins: %w", err)
+	}
+	if counts[user.RoleAdmin] > 0 {
+		return nil, user.ErrAdminAlreadyExists
+	}
+
+	if err := uc.CheckEmailAvailable(ctx, input.Email); err != nil {
+		return nil, err
+	}
+	if input.Username != "" {
+		if err := uc.CheckUsernameAvailable(ctx, input.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	admin, err := user.NewUser(input.Email, input.Password, input.Name, user.RoleAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user entity: %w", err)
+	}
+	if input.Username != "" {
+		if err := admin.SetUsername(input.Username); err != nil {
+			return nil, fmt.Errorf("failed to create user entity: %w", err)
+		}
+	}
+
+	if err := uc.userRepo.CreateFirstAdmin(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to create admin in repository: %w", err)
+	}
+
+	uc.logger.WarnContext(ctx, "first admin created via bootstrap endpoint", "user_id", admin.ID)
+	uc.metrics.UserCreated()
+	uc.publish(ctx, user.NewEvent(user.EventUserCreated, admin))
+
+	return &CreateUserOutput{User: admin}, nil
+}
@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapAdmin(t *testing.T) {
+	ctx := context.Background()
+	input := usecase.BootstrapAdminInput{
+		Email:    "root@example.com",
+		Password: "password123",
+		Name:     "Root",
+	}
+
+	t.Run("first admin", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithAdminBootstrap())
+		repo.On("CountByRole", ctx).Return(map[user.Role]int64{user.RoleAdmin: 0, user.RoleUser: 3}, nil)
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+		repo.On("CreateFirstAdmin", ctx, mock.MatchedBy(func(u *user.User) bool {
+			return u.Role == user.RoleAdmin && u.Email == input.Email
+		})).Return(nil)
+
+		output, err := uc.BootstrapAdmin(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleAdmin, output.User.Role)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("admin already exists", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithAdminBootstrap())
+		repo.On("CountByRole", ctx).Return(map[user.Role]int64{user.RoleAdmin: 1}, nil)
+
+		_, err := uc.BootstrapAdmin(ctx, input)
+		assert.ErrorIs(t, err, user.ErrAdminAlreadyExists)
+		repo.AssertNotCalled(t, "CreateFirstAdmin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin created concurrently", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithAdminBootstrap())
+		repo.On("CountByRole", ctx).Return(map[user.Role]int64{}, nil)
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+		repo.On("CreateFirstAdmin", ctx, mock.Anything).Return(user.ErrAdminAlreadyExists)
+
+		_, err := uc.BootstrapAdmin(ctx, input)
+		assert.ErrorIs(t, err, user.ErrAdminAlreadyExists)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{})
+
+		assert.False(t, uc.AdminBootstrapEnabled())
+		_, err := uc.BootstrapAdmin(ctx, input)
+		assert.ErrorIs(t, err, user.ErrAdminAlreadyExists)
+		repo.AssertNotCalled(t, "CountByRole", mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithAdminBootstrap())
+		repo.On("CountByRole", ctx).Return(nil, errors.New("connection refused"))
+
+		_, err := uc.BootstrapAdmin(ctx, input)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, user.ErrAdminAlreadyExists)
+	})
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// AdminDowngradeActor identifica o job de rebaixamento nos logs de auditoria
+const AdminDowngradeActor = "system:admin-downgrade"
+
+// ErrAdminDowngradeDisabled indica que o rebaixamento de admins inativos não
+// foi habilitado (WithAdminDowngrade)
+var ErrAdminDowngradeDisabled = errors.New("admin downgrade is not enabled")
+
+// AdminDowngradePolicy configura o rebaixamento automático de admins inativos
+type AdminDowngradePolicy struct {
+	// InactiveAfter é o tempo sem login a partir do qual um admin vira user
+	InactiveAfter time.Duration
+	// DryRun apenas registra quem seria rebaixado, sem alterar nada
+	DryRun bool
+}
+
+// WithAdminDowngrade habilita o rebaixamento para user dos admins sem login há
+// mais de policy.InactiveAfter. InactiveAfter <= 0 mantém o recurso desabilitado
+func WithAdminDowngrade(policy AdminDowngradePolicy) Option {
+	return func(uc *UserUseCase) {
+		uc.adminDowngrade = policy
+	}
+}
+
+// AdminDowngradeEnabled informa se o rebaixamento de admins inativos foi habilitado
+func (uc *UserUseCase) AdminDowngradeEnabled() bool {
+	return uc.adminDowngrade.InactiveAfter > 0
+}
+
+// AdminDowngradeOutput representa o resultado de uma execução do rebaixamento
+type AdminDowngradeOutput struct {
+	// Downgraded são os admins rebaixados ou, com DryRun, os que seriam
+	Downgraded []string `json:"downgraded"`
+	// Kept é o admin inativo mantido para que não reste nenhum admin; vazio
+	// quando havia admins ativos
+	Kept   string `json:"kept,omitempty"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// DowngradeInactiveAdmins rebaixa para user os admins ativos sem login na
+// janela da política. Quem nunca entrou conta a partir da criação da conta. Se
+// todos os admins ativos estiverem inativos, o de atividade mais recente é
+// mantido. A escrita só alcança quem continua admin e inativo, de modo que um
+// login concorrente não é desfeito. Cada rebaixamento é auditado, encerra as
+// sessões do usuário (o token atual ainda carrega o papel admin) e publica
+// user.updated
+func (uc *UserUseCase) DowngradeInactiveAdmins(ctx context.Context) (*AdminDowngradeOutput, error) {
+	if !uc.AdminDowngradeEnabled() {
+		return nil, ErrAdminDowngradeDisabled
+	}
+
+	now := uc.clock.Now()
+	output := &AdminDowngradeOutput{Downgraded: []string{}, DryRun: uc.adminDowngrade.DryRun}
+
+	since := now.Add(-uc.adminDowngrade.InactiveAfter)
+	inactive, err := uc.userRepo.ListInactiveByRole(ctx, user.RoleAdmin, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive admins: %w", err)
+	}
+	if len(inactive) == 0 {
+		return output, nil
+	}
+
+	// Contas desativadas não administram nada e não contam como admin restante
+	admins, err := uc.userRepo.CountActiveByRole(ctx, user.RoleAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admins: %w", err)
+	}
+	if int64(len(inactive)) >= admins {
+		kept := inactive[len(inactive)-1]
+		inactive = inactive[:len(inactive)-1]
+		output.Kept = kept.ID
+		uc.logger.WarnContext(ctx, "all admins are inactive; keeping the most recently active one", "user_id", kept.ID)
+	}
+	if len(inactive) == 0 {
+		return output, nil
+	}
+
+	byID := make(map[string]*user.User, len(inactive))
+	ids := make([]string, len(inactive))
+	for i, u := range inactive {
+		byID[u.ID] = u
+		ids[i] = u.ID
+	}
+
+	if uc.adminDowngrade.DryRun {
+		for _, u := range inactive {
+			uc.logger.InfoContext(ctx, "inactive admin would be downgraded (dry run)",
+				"user_id", u.ID, "last_login_at", u.LastLoginAt)
+		}
+		output.Downgraded = ids
+		return output, nil
+	}
+
+	// token_version é incrementado na mesma instrução que muda o papel
+	updated, err := uc.userRepo.DowngradeInactive(ctx, ids, user.RoleAdmin, user.RoleUser, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to downgrade inactive admins: %w", err)
+	}
+
+	for _, id := range updated {
+		u := byID[id]
+		uc.logger.InfoContext(ctx, "user.role_changed",
+			"user_id", id, "role", user.RoleUser, "previous_role", user.RoleAdmin,
+			"actor_id", AdminDowngradeActor, "last_login_at", u.LastLoginAt)
+
+		u.Role = user.RoleUser
+		u.UpdatedAt = now
+		uc.publish(ctx, user.NewEvent(user.EventUserUpdated, u))
+		// token_version já invalidou os tokens; a listagem de sessões também é limpa
+		uc.discardSessions(ctx, id)
+		output.Downgraded = append(output.Downgraded, id)
+	}
+
+	return output, nil
+}
+
+// RunAdminDowngrade executa DowngradeInactiveAdmins imediatamente e a cada
+// interval até ctx ser cancelado, bloqueando enquanto isso. É a forma usada com
+// worker.Manager; sem WithAdminDowngrade ou com interval <= 0 retorna
+// imediatamente. Falhas são registradas e a próxima execução tenta de novo
+func (uc *UserUseCase) RunAdminDowngrade(ctx context.Context, interval time.Duration) {
+	if !uc.AdminDowngradeEnabled() || interval <= 0 {
+		return
+	}
+
+	uc.runAdminDowngrade(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.runAdminDowngrade(ctx)
+		}
+	}
+}
+
+// runAdminDowngrade executa uma rodada do job, registrando o resultado
+func (uc *UserUseCase) runAdminDowngrade(ctx context.Context) {
+	output, err := uc.DowngradeInactiveAdmins(ctx)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "admin downgrade failed", "error", err)
+		return
+	}
+	if len(output.Downgraded) > 0 {
+		uc.logger.InfoContext(ctx, "admin downgrade finished",
+			"downgraded", len(output.Downgraded), "dry_run", output.DryRun)
+	}
+}
@@ -0,0 +1,180 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/sessions"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newDowngradeAdmin cria um admin com o último login informado
+func newDowngradeAdmin(id string, lastLogin time.Time) *user.User {
+	return &user.User{ID: id, Email: id + "@example.com", Role: user.RoleAdmin, IsActive: true, LastLoginAt: &lastLogin}
+}
+
+func TestDowngradeInactiveAdmins(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+
+	t.Run("advancing the clock past the window downgrades the admin", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		repo := &mocks.UserRepository{}
+		publisher := &recordingPublisher{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{},
+			usecase.WithClock(fake),
+			usecase.WithEventPublisher(publisher),
+			usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{InactiveAfter: window}))
+
+		stale := newDowngradeAdmin("stale", start.AddDate(0, 0, -10))
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, start.Add(-window)).Return([]*user.User{}, nil).Once()
+
+		output, err := uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, output.Downgraded)
+
+		fake.Advance(25 * 24 * time.Hour)
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, fake.Now().Add(-window)).Return([]*user.User{stale}, nil).Once()
+		repo.On("CountActiveByRole", ctx, user.RoleAdmin).Return(int64(2), nil)
+		repo.On("DowngradeInactive", ctx, []string{"stale"}, user.RoleAdmin, user.RoleUser, fake.Now().Add(-window)).Return([]string{"stale"}, nil).Once()
+
+		output, err = uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stale"}, output.Downgraded)
+		assert.False(t, output.DryRun)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "IncrementTokenVersion", mock.Anything, mock.Anything)
+
+		require.Len(t, publisher.events, 1)
+		assert.Equal(t, user.EventUserUpdated, publisher.events[0].Type)
+		assert.Equal(t, user.RoleUser, stale.Role)
+	})
+
+	t.Run("sessions of downgraded admins are removed", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		store := sessions.NewMemoryStore()
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{},
+			usecase.WithClock(clock.NewFake(start)),
+			usecase.WithSessions(store),
+			usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{InactiveAfter: window}))
+
+		stale := newDowngradeAdmin("stale", start.AddDate(0, -3, 0))
+		returned := newDowngradeAdmin("returned", start.AddDate(0, -2, 0))
+		now := time.Now()
+		for _, id := range []string{"stale", "returned"} {
+			require.NoError(t, store.Add(ctx, auth.Session{ID: id + "-s", UserID: id, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+		}
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, start.Add(-window)).Return([]*user.User{stale, returned}, nil)
+		repo.On("CountActiveByRole", ctx, user.RoleAdmin).Return(int64(3), nil)
+		repo.On("DowngradeInactive", ctx, []string{"stale", "returned"}, user.RoleAdmin, user.RoleUser, start.Add(-window)).Return([]string{"stale"}, nil)
+
+		_, err := uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+
+		exists, err := store.Exists(ctx, "stale", "stale-s")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		// Quem não foi rebaixado mantém as sessões
+		exists, err = store.Exists(ctx, "returned", "returned-s")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		publisher := &recordingPublisher{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{},
+			usecase.WithClock(clock.NewFake(start)),
+			usecase.WithEventPublisher(publisher),
+			usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{InactiveAfter: window, DryRun: true}))
+
+		stale := newDowngradeAdmin("stale", start.AddDate(0, -3, 0))
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, start.Add(-window)).Return([]*user.User{stale}, nil)
+		repo.On("CountActiveByRole", ctx, user.RoleAdmin).Return(int64(3), nil)
+
+		output, err := uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stale"}, output.Downgraded)
+		assert.True(t, output.DryRun)
+		assert.Equal(t, user.RoleAdmin, stale.Role)
+		assert.Empty(t, publisher.events)
+		repo.AssertNotCalled(t, "DowngradeInactive", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("the most recently active admin is kept when all are inactive", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{},
+			usecase.WithClock(clock.NewFake(start)),
+			usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{InactiveAfter: window}))
+
+		oldest := newDowngradeAdmin("oldest", start.AddDate(-1, 0, 0))
+		latest := newDowngradeAdmin("latest", start.AddDate(0, -2, 0))
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, start.Add(-window)).Return([]*user.User{oldest, latest}, nil)
+		repo.On("CountActiveByRole", ctx, user.RoleAdmin).Return(int64(2), nil)
+		repo.On("DowngradeInactive", ctx, []string{"oldest"}, user.RoleAdmin, user.RoleUser, start.Add(-window)).Return([]string{"oldest"}, nil)
+
+		output, err := uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"oldest"}, output.Downgraded)
+		assert.Equal(t, "latest", output.Kept)
+		assert.Equal(t, user.RoleAdmin, latest.Role)
+	})
+
+	t.Run("admins that became active before the write are not reported", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		publisher := &recordingPublisher{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{},
+			usecase.WithClock(clock.NewFake(start)),
+			usecase.WithEventPublisher(publisher),
+			usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{InactiveAfter: window}))
+
+		stale := newDowngradeAdmin("stale", start.AddDate(0, -3, 0))
+		returned := newDowngradeAdmin("returned", start.AddDate(0, -2, 0))
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, start.Add(-window)).Return([]*user.User{stale, returned}, nil)
+		repo.On("CountActiveByRole", ctx, user.RoleAdmin).Return(int64(3), nil)
+		// returned entrou entre a listagem e a escrita condicional
+		repo.On("DowngradeInactive", ctx, []string{"stale", "returned"}, user.RoleAdmin, user.RoleUser, start.Add(-window)).Return([]string{"stale"}, nil)
+
+		output, err := uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stale"}, output.Downgraded)
+		assert.Equal(t, user.RoleAdmin, returned.Role)
+		require.Len(t, publisher.events, 1)
+	})
+
+	t.Run("deactivated admins do not count as remaining admins", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{},
+			usecase.WithClock(clock.NewFake(start)),
+			usecase.WithAdminDowngrade(usecase.AdminDowngradePolicy{InactiveAfter: window}))
+
+		only := newDowngradeAdmin("only", start.AddDate(0, -3, 0))
+		repo.On("ListInactiveByRole", ctx, user.RoleAdmin, start.Add(-window)).Return([]*user.User{only}, nil)
+		// Os demais admins estão desativados e ficam fora da contagem
+		repo.On("CountActiveByRole", ctx, user.RoleAdmin).Return(int64(1), nil)
+
+		output, err := uc.DowngradeInactiveAdmins(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, output.Downgraded)
+		assert.Equal(t, "only", output.Kept)
+		repo.AssertNotCalled(t, "DowngradeInactive", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		uc, _, _ := newTestUseCase()
+		assert.False(t, uc.AdminDowngradeEnabled())
+
+		_, err := uc.DowngradeInactiveAdmins(ctx)
+		assert.ErrorIs(t, err, usecase.ErrAdminDowngradeDisabled)
+	})
+}
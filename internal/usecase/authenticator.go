@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// Authenticator verifica as credenciais de login e retorna o usuário local
+// correspondente. Falhas são reportadas com os erros de domínio
+// user.ErrUserNotFound, user.ErrUserDeactivated, user.ErrInvalidPassword e
+// user.ErrPasswordExpired; qualquer outro erro é tratado como falha do backend
+type Authenticator interface {
+	Authenticate(ctx context.Context, email, password string) (*user.User, error)
+}
+
+// WithAuthenticator delega a verificação de credenciais do login a outro
+// backend (ex.: ldap.Authenticator). Sem esta opção, a senha local (bcrypt) é usada
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(uc *UserUseCase) {
+		uc.authenticator = authenticator
+	}
+}
+
+// localAuthenticator é o backend padrão: confere a senha com o hash do usuário
+type localAuthenticator struct {
+	uc *UserUseCase
+}
+
+// Authenticate implementa Authenticator. Com WithPasswordMaxAge, uma senha
+// correta porém vencida retorna user.ErrPasswordExpired
+func (a localAuthenticator) Authenticate(ctx context.Context, email, password string) (*user.User, error) {
+	u, err := a.uc.checkLocalPassword(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.uc.passwordExpired(u) {
+		return nil, user.ErrPasswordExpired
+	}
+
+	a.uc.rehashPassword(ctx, u, password)
+	return u, nil
+}
+
+// checkLocalPassword confere a senha local do usuário ativo com o email informado.
+// A senha é comparada antes do status da conta: uma conta desativada só é
+// revelada a quem acerta a senha, com o mesmo custo de qualquer outra tentativa
+func (uc *UserUseCase) checkLocalPassword(ctx context.Context, email, password string) (*user.User, error) {
+	u, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			// Mesmo custo de uma senha errada, para não revelar emails cadastrados
+			user.SimulatePasswordCheck(password)
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if !u.CheckPassword(password) {
+		return nil, user.ErrInvalidPassword
+	}
+
+	if !u.IsActiveUser() {
+		return nil, user.ErrUserDeactivated
+	}
+
+	return u, nil
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// MaxBulkUserIDs limita a quantidade de usuários alterados por operação em lote
+const MaxBulkUserIDs = 100
+
+// MaxBulkCreateUsers limita a quantidade de usuários criados por operação em lote
+const MaxBulkCreateUsers = 100
+
+var (
+	// ErrEmptyBulkIDs indica uma operação em lote sem IDs
+	ErrEmptyBulkIDs = errors.New("at least one user ID is required")
+	// ErrTooManyBulkIDs indica uma operação em lote acima de MaxBulkUserIDs
+	ErrTooManyBulkIDs = fmt.Errorf("at most %d user IDs are allowed per request", MaxBulkUserIDs)
+	// ErrEmptyBulkUsers indica uma criação em lote sem usuários
+	ErrEmptyBulkUsers = errors.New("at least one user is required")
+	// ErrTooManyBulkUsers indica uma criação em lote acima de MaxBulkCreateUsers
+	ErrTooManyBulkUsers = fmt.Errorf("at most %d users are allowed per request", MaxBulkCreateUsers)
+)
+
+// Situação de cada item de uma operação em lote
+const (
+	BulkItemCreated   = "created"
+	BulkItemUpdated   = "updated"
+	BulkItemUnchanged = "unchanged"
+	BulkItemFound     = "found"
+	BulkItemFailed    = "failed"
+)
+
+// BulkItemResult é o resultado de um item de uma operação em lote. Index é a
+// posição do item na entrada (a primeira ocorrência, para IDs repetidos). Err é
+// preenchido apenas quando Status é BulkItemFailed
+type BulkItemResult struct {
+	Index  int        `json:"index"`
+	ID     string     `json:"id,omitempty"`
+	Status string     `json:"status"`
+	User   *user.User `json:"user,omitempty"`
+	Err    error      `json:"-"`
+}
+
+// Failed informa se o item falhou
+func (r BulkItemResult) Failed() bool {
+	return r.Status == BulkItemFailed
+}
+
+// bulkItemFailed monta o resultado de um item que falhou
+func bulkItemFailed(index int, id string, err error) BulkItemResult {
+	return BulkItemResult{Index: index, ID: id, Status: BulkItemFailed, Err: err}
+}
+
+// BulkUpdateRolesInput representa os dados de entrada da troca de roles em lote
+type BulkUpdateRolesInput struct {
+	UserIDs []string  `json:"user_ids"`
+	Role    user.Role `json:"role"`
+	// DryRun valida e reporta o efeito da operação sem persistir alterações
+	DryRun bool `json:"dry_run"`
+	// ActorID é o administrador que executou a operação, usado apenas na auditoria
+	ActorID string `json:"-"`
+}
+
+// BulkUpdateRolesOutput representa o resultado da troca de roles em lote
+type BulkUpdateRolesOutput struct {
+	Updated  int      `json:"updated"`
+	Skipped  int      `json:"skipped"`
+	NotFound []string `json:"not_found"`
+	DryRun   bool     `json:"dry_run"`
+	// Items traz o resultado de cada ID, sem repetições, na ordem de entrada
+	Items []BulkItemResult `json:"items"`
+}
+
+// BulkUpdateRoles define o mesmo role para vários usuários em uma única
+// transação. IDs repetidos são considerados uma vez; usuários que já têm o role
+// são contados como ignorados. Com DryRun, o resultado é o mesmo de uma execução
+// real, mas nada é gravado nem auditado
+func (uc *UserUseCase) BulkUpdateRoles(ctx context.Context, input BulkUpdateRolesInput) (*BulkUpdateRolesOutput, error) {
+	if !user.IsValidRole(input.Role) {
+		return nil, user.NewDomainError(user.ErrInvalidRole, "role", string(input.Role))
+	}
+
+	ids, positions, err := uniqueBulkIDs(input.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	update := uc.userRepo.UpdateRoles
+	if input.DryRun {
+		update = uc.userRepo.PreviewUpdateRoles
+	}
+
+	result, err := update(ctx, ids, input.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update roles: %w", err)
+	}
+
+	if !input.DryRun {
+		for _, id := range result.Updated {
+			uc.logger.InfoContext(ctx, "user.role_changed",
+				"user_id", id, "role", input.Role, "actor_id", input.ActorID)
+		}
+	}
+
+	notFound := result.NotFound
+	if notFound == nil {
+		notFound = []string{}
+	}
+
+	return &BulkUpdateRolesOutput{
+		Updated:  len(result.Updated),
+		Skipped:  len(result.Unchanged),
+		NotFound: notFound,
+		DryRun:   input.DryRun,
+		Items:    roleUpdateItems(ids, positions, result),
+	}, nil
+}
+
+// roleUpdateItems classifica cada ID do lote conforme o resultado do
+// repositório; positions é a posição de cada ID na entrada
+func roleUpdateItems(ids []string, positions []int, result *repository.RoleUpdateResult) []BulkItemResult {
+	status := make(map[string]string, len(ids))
+	for _, id := range result.Updated {
+		status[id] = BulkItemUpdated
+	}
+	for _, id := range result.Unchanged {
+		status[id] = BulkItemUnchanged
+	}
+
+	items := make([]BulkItemResult, len(ids))
+	for i, id := range ids {
+		if s, ok := status[id]; ok {
+			items[i] = BulkItemResult{Index: positions[i], ID: id, Status: s}
+			continue
+		}
+		items[i] = bulkItemFailed(positions[i], id, user.NewDomainError(user.ErrUserNotFound, "id", id))
+	}
+	return items
+}
+
+// BulkCreateUsersInput representa a criação de vários usuários
+type BulkCreateUsersInput struct {
+	Users []CreateUserInput `json:"users"`
+}
+
+// BulkCreateUsersOutput traz o resultado de cada usuário, na ordem de entrada
+type BulkCreateUsersOutput struct {
+	Items []BulkItemResult `json:"items"`
+}
+
+// BulkCreateUsers cria vários usuários com as mesmas regras de CreateUser. Cada
+// item é independente (sem transação): falhas não impedem a criação dos demais.
+// Apenas o cancelamento do contexto interrompe o lote
+func (uc *UserUseCase) BulkCreateUsers(ctx context.Context, input BulkCreateUsersInput) (*BulkCreateUsersOutput, error) {
+	if len(input.Users) == 0 {
+		return nil, ErrEmptyBulkUsers
+	}
+	if len(input.Users) > MaxBulkCreateUsers {
+		return nil, ErrTooManyBulkUsers
+	}
+
+	items := make([]BulkItemResult, len(input.Users))
+	for i, in := range input.Users {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		output, err := uc.CreateUser(ctx, in)
+		if err != nil {
+			items[i] = bulkItemFailed(i, "", err)
+			continue
+		}
+		items[i] = BulkItemResult{Index: i, ID: output.User.ID, Status: BulkItemCreated, User: output.User}
+	}
+
+	return &BulkCreateUsersOutput{Items: items}, nil
+}
+
+// BatchGetUsersInput representa a busca de vários usuários pelo ID
+type BatchGetUsersInput struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchGetUsersOutput traz o resultado de cada ID, sem repetições, na ordem de entrada
+type BatchGetUsersOutput struct {
+	Items []BulkItemResult `json:"items"`
+}
+
+// BatchGetUsers busca vários usuários em uma única consulta. IDs inexistentes
+// ou inválidos são reportados como itens com ErrUserNotFound. Mais IDs distintos
+// que o máximo configurado (WithResultLimits) são rejeitados com LimitExceededError
+func (uc *UserUseCase) BatchGetUsers(ctx context.Context, input BatchGetUsersInput) (*BatchGetUsersOutput, error) {
+	ids, positions := uniqueIDs(input.IDs)
+	if len(ids) == 0 {
+		return nil, ErrEmptyBulkIDs
+	}
+	if len(ids) > uc.maxBatchGetIDs {
+		return nil, &LimitExceededError{Err: ErrTooManyBatchGetIDs, Max: uc.maxBatchGetIDs, Got: len(ids)}
+	}
+
+	users, err := uc.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+	}
+
+	byID := make(map[string]*user.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	items := make([]BulkItemResult, len(ids))
+	for i, id := range ids {
+		// O repositório devolve IDs na forma canônica (minúsculas)
+		if u, ok := byID[strings.ToLower(id)]; ok {
+			items[i] = BulkItemResult{Index: positions[i], ID: id, Status: BulkItemFound, User: u}
+			continue
+		}
+		items[i] = bulkItemFailed(positions[i], id, user.NewDomainError(user.ErrUserNotFound, "id", id))
+	}
+
+	return &BatchGetUsersOutput{Items: items}, nil
+}
+
+// uniqueBulkIDs remove IDs repetidos preservando a ordem e aplica os limites do lote
+func uniqueBulkIDs(ids []string) ([]string, []int, error) {
+	unique, positions := uniqueIDs(ids)
+	if len(unique) == 0 {
+		return nil, nil, ErrEmptyBulkIDs
+	}
+	if len(unique) > MaxBulkUserIDs {
+		return nil, nil, ErrTooManyBulkIDs
+	}
+	return unique, positions, nil
+}
+
+// uniqueIDs remove IDs repetidos preservando a ordem. positions traz a posição
+// na entrada da primeira ocorrência de cada ID
+func uniqueIDs(ids []string) (unique []string, positions []int) {
+	seen := make(map[string]struct{}, len(ids))
+	unique = make([]string, 0, len(ids))
+	positions = make([]int, 0, len(ids))
+	for i, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+		positions = append(positions, i)
+	}
+	return unique, positions
+}
@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkUpdateRoles(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	repo.On("UpdateRoles", ctx, []string{"1", "2", "3"}, user.RoleAdmin).Return(&repository.RoleUpdateResult{
+		Updated:   []string{"1"},
+		Unchanged: []string{"2"},
+		NotFound:  []string{"3"},
+	}, nil)
+
+	output, err := uc.BulkUpdateRoles(ctx, usecase.BulkUpdateRolesInput{
+		UserIDs: []string{"1", "2", "1", "3"},
+		Role:    user.RoleAdmin,
+		ActorID: "root",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, output.Updated)
+	assert.Equal(t, 1, output.Skipped)
+	assert.Equal(t, []string{"3"}, output.NotFound)
+	repo.AssertExpectations(t)
+
+	// Os itens trazem a posição na entrada, não no lote sem repetições
+	require.Len(t, output.Items, 3)
+	for i, want := range []int{0, 1, 3} {
+		assert.Equal(t, want, output.Items[i].Index)
+	}
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "user.role_changed", entry["msg"])
+	assert.Equal(t, "1", entry["user_id"])
+	assert.Equal(t, "root", entry["actor_id"])
+}
+
+func TestBulkUpdateRolesValidation(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+
+	tooMany := make([]string, usecase.MaxBulkUserIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(i)
+	}
+
+	tests := []struct {
+		name  string
+		input usecase.BulkUpdateRolesInput
+		err   error
+	}{
+		{"invalid role", usecase.BulkUpdateRolesInput{UserIDs: []string{"1"}, Role: "superuser"}, user.ErrInvalidRole},
+		{"no ids", usecase.BulkUpdateRolesInput{Role: user.RoleUser}, usecase.ErrEmptyBulkIDs},
+		{"too many ids", usecase.BulkUpdateRolesInput{UserIDs: tooMany, Role: user.RoleUser}, usecase.ErrTooManyBulkIDs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.BulkUpdateRoles(ctx, tt.input)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+	repo.AssertNotCalled(t, "UpdateRoles", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkUpdateRolesDryRun(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	repo.On("PreviewUpdateRoles", ctx, []string{"1"}, user.RoleAdmin).Return(&repository.RoleUpdateResult{
+		Updated: []string{"1"},
+	}, nil)
+
+	output, err := uc.BulkUpdateRoles(ctx, usecase.BulkUpdateRolesInput{UserIDs: []string{"1"}, Role: user.RoleAdmin, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, output.DryRun)
+	assert.Equal(t, 1, output.Updated)
+	assert.Empty(t, output.NotFound)
+	assert.NotNil(t, output.NotFound)
+
+	repo.AssertNotCalled(t, "UpdateRoles", mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, buf.String(), "dry runs are not audited")
+}
+
+func TestBatchGetUsersLimit(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	repo.On("GetByIDs", ctx, mock.Anything).Return([]*user.User{}, nil)
+
+	ids := make([]string, usecase.DefaultMaxBatchGetIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i)
+	}
+
+	output, err := uc.BatchGetUsers(ctx, usecase.BatchGetUsersInput{IDs: ids[:usecase.DefaultMaxBatchGetIDs]})
+	require.NoError(t, err)
+	assert.Len(t, output.Items, usecase.DefaultMaxBatchGetIDs)
+
+	_, err = uc.BatchGetUsers(ctx, usecase.BatchGetUsersInput{IDs: ids})
+	var limitErr *usecase.LimitExceededError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, usecase.ErrTooManyBatchGetIDs)
+	assert.Equal(t, usecase.DefaultMaxBatchGetIDs, limitErr.Max)
+	assert.Equal(t, usecase.DefaultMaxBatchGetIDs+1, limitErr.Got)
+	repo.AssertNumberOfCalls(t, "GetByIDs", 1)
+}
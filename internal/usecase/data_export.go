@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// ExportUserDataInput identifica o titular dos dados exportados
+type ExportUserDataInput struct {
+	UserID string `json:"user_id"`
+}
+
+// UserDataExport reúne os dados pessoais mantidos sobre um usuário (direito de
+// acesso/portabilidade do GDPR). Novos repositórios com dados do usuário devem
+// acrescentar aqui suas seções
+type UserDataExport struct {
+	User       *user.User `json:"user"`
+	ExportedAt time.Time  `json:"exported_at"`
+}
+
+// ExportUserData monta o pacote de dados de um único usuário
+func (uc *UserUseCase) ExportUserData(ctx context.Context, input ExportUserDataInput) (*UserDataExport, error) {
+	u, err := uc.userRepo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.NewDomainError(err, "id", input.UserID)
+		}
+		return nil, fmt.Errorf("failed to get user for data export: %w", err)
+	}
+
+	uc.logger.InfoContext(ctx, "user.data_exported", "user_id", input.UserID)
+
+	return &UserDataExport{User: u, ExportedAt: uc.clock.Now()}, nil
+}
@@ -0,0 +1,29 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportUserDataUsesClock(t *testing.T) {
+	exportedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithClock(clock.NewFake(exportedAt)))
+
+	u := newTestUser(t, "password123")
+	repo.On("GetByID", mock.Anything, u.ID).Return(u, nil)
+
+	export, err := uc.ExportUserData(context.Background(), usecase.ExportUserDataInput{UserID: u.ID})
+	require.NoError(t, err)
+	assert.Equal(t, exportedAt, export.ExportedAt)
+	assert.Equal(t, u, export.User)
+}
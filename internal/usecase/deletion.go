@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// Políticas de exclusão de contas
+const (
+	// DeletionPolicyDelete remove a linha do usuário
+	DeletionPolicyDelete = "delete"
+	// DeletionPolicyAnonymize mantém a linha e remove os dados pessoais (GDPR)
+	DeletionPolicyAnonymize = "anonymize"
+)
+
+// WithDeletionPolicy define o que DeleteUser faz com a conta: delete (padrão)
+// ou anonymize. Valores desconhecidos mantêm o padrão
+func WithDeletionPolicy(policy string) Option {
+	return func(uc *UserUseCase) {
+		if policy == DeletionPolicyAnonymize {
+			uc.deletionPolicy = policy
+		}
+	}
+}
+
+// AnonymizeUserInput representa os dados de entrada da anonimização
+type AnonymizeUserInput struct {
+	ID      string `json:"id"`
+	ActorID string `json:"-"`
+}
+
+// AnonymizeUser remove os dados pessoais do usuário mantendo a linha para
+// integridade referencial. A conta é desativada e todas as sessões são
+// invalidadas na mesma operação do repositório; os registros de sessão, com
+// user-agent e IP, são descartados em seguida
+func (uc *UserUseCase) AnonymizeUser(ctx context.Context, input AnonymizeUserInput) error {
+	dbUser, err := uc.userRepo.GetByID(ctx, input.ID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.NewDomainError(err, "id", input.ID)
+		}
+		return fmt.Errorf("failed to get user for anonymization: %w", err)
+	}
+
+	dbUser.Anonymize()
+	if err := uc.userRepo.Anonymize(ctx, dbUser); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.NewDomainError(err, "id", input.ID)
+		}
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+	uc.discardSessions(ctx, input.ID)
+
+	uc.logger.InfoContext(ctx, "user.anonymized", "user_id", input.ID, "actor_id", input.ActorID)
+	uc.publishDeleted(ctx, input.ID)
+
+	return nil
+}
+
+// publishDeleted publica a exclusão sem dados do usuário, para que os
+// consumidores também descartem o que tiverem dele
+func (uc *UserUseCase) publishDeleted(ctx context.Context, id string) {
+	event := user.NewEvent(user.EventUserDeleted, nil)
+	event.UserID = id
+	uc.publish(ctx, event)
+}
@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/sessions"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteUserPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delete removes the row", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		u := newTestUser(t, "password123")
+		repo.On("Delete", ctx, u.ID).Return(nil)
+
+		require.NoError(t, uc.DeleteUser(ctx, usecase.DeleteUserInput{ID: u.ID}))
+		repo.AssertNotCalled(t, "Anonymize", mock.Anything, mock.Anything)
+	})
+
+	t.Run("anonymize scrubs personal data", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithDeletionPolicy(usecase.DeletionPolicyAnonymize))
+		u := newTestUser(t, "password123")
+		repo.On("GetByID", ctx, u.ID).Return(u, nil)
+		repo.On("Anonymize", ctx, mock.MatchedBy(func(saved *user.User) bool {
+			return saved.ID == u.ID && saved.IsAnonymized() && !saved.IsActive && saved.Name == user.AnonymizedName
+		})).Return(nil)
+
+		require.NoError(t, uc.DeleteUser(ctx, usecase.DeleteUserInput{ID: u.ID}))
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		assert.False(t, u.CheckPassword("password123"))
+	})
+
+	t.Run("anonymize not found", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("GetByID", ctx, "missing").Return(nil, user.ErrUserNotFound)
+
+		err := uc.AnonymizeUser(ctx, usecase.AnonymizeUserInput{ID: "missing"})
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+		repo.AssertNotCalled(t, "Anonymize", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteUserDiscardsSessions(t *testing.T) {
+	ctx := context.Background()
+
+	for _, policy := range []string{usecase.DeletionPolicyDelete, usecase.DeletionPolicyAnonymize} {
+		t.Run(policy, func(t *testing.T) {
+			store := sessions.NewMemoryStore()
+			repo := &mocks.UserRepository{}
+			uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithSessions(store), usecase.WithDeletionPolicy(policy))
+			u := newTestUser(t, "password123")
+			repo.On("Delete", ctx, u.ID).Return(nil)
+			repo.On("GetByID", ctx, u.ID).Return(u, nil)
+			repo.On("Anonymize", ctx, mock.Anything).Return(nil)
+
+			now := time.Now()
+			require.NoError(t, store.Add(ctx, auth.Session{
+				ID:        "a",
+				UserID:    u.ID,
+				UserAgent: "Mozilla/5.0",
+				IP:        "203.0.113.7",
+				IssuedAt:  now,
+				ExpiresAt: now.Add(time.Hour),
+			}))
+			require.NoError(t, store.Add(ctx, auth.Session{ID: "other", UserID: "7", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+			require.NoError(t, uc.DeleteUser(ctx, usecase.DeleteUserInput{ID: u.ID}))
+
+			remaining, err := store.List(ctx, u.ID)
+			require.NoError(t, err)
+			assert.Empty(t, remaining)
+			others, err := store.List(ctx, "7")
+			require.NoError(t, err)
+			assert.Len(t, others, 1)
+		})
+	}
+}
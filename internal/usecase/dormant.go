@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// ErrInvalidDormantDays indica uma janela de inatividade menor que um dia
+var ErrInvalidDormantDays = errors.New("days must be at least 1")
+
+// ListDormantUsersInput representa os dados de entrada da listagem de contas
+// inativas: usuários sem login nos últimos Days dias, ou que nunca entraram
+type ListDormantUsersInput struct {
+	Days   int `json:"days"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// ListDormantUsers lista as contas sem login na janela informada, dos usuários
+// que nunca entraram aos de login mais antigo
+func (uc *UserUseCase) ListDormantUsers(ctx context.Context, input ListDormantUsersInput) (*PagedUsers, error) {
+	if input.Days < 1 {
+		return nil, ErrInvalidDormantDays
+	}
+
+	offset, limit := normalizePage(input.Offset, input.Limit)
+	since := uc.clock.Now().AddDate(0, 0, -input.Days)
+
+	users, err := uc.userRepo.ListDormant(ctx, since, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dormant users: %w", err)
+	}
+
+	total, err := uc.userRepo.CountDormant(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count dormant users: %w", err)
+	}
+
+	return newPagedUsers(users, total, offset, limit), nil
+}
+
+// touchLastLogin registra o momento de um login bem-sucedido. Falhas não
+// impedem o login; a conta apenas pode aparecer como inativa por mais tempo
+func (uc *UserUseCase) touchLastLogin(ctx context.Context, u *user.User) {
+	now := uc.clock.Now().UTC()
+	if err := uc.userRepo.RecordLogin(ctx, u.ID, now); err != nil {
+		uc.logger.WarnContext(ctx, "failed to record last login", "user_id", u.ID, "error", err)
+		return
+	}
+	u.LastLoginAt = &now
+}
@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDormantUsers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("the cutoff is days before the clock", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithClock(clock.NewFake(now)))
+		since := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+		dormant := []*user.User{newTestUser(t, "password123")}
+		repo.On("ListDormant", ctx, since, 10, 5).Return(dormant, nil)
+		repo.On("CountDormant", ctx, since).Return(int64(11), nil)
+
+		page, err := uc.ListDormantUsers(ctx, usecase.ListDormantUsersInput{Days: 90, Offset: 10, Limit: 5})
+		require.NoError(t, err)
+		assert.Equal(t, dormant, page.Users)
+		assert.Equal(t, int64(11), page.Total)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("at least one day", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+
+		_, err := uc.ListDormantUsers(ctx, usecase.ListDormantUsersInput{Days: 0})
+		assert.ErrorIs(t, err, usecase.ErrInvalidDormantDays)
+		repo.AssertNotCalled(t, "ListDormant", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthenticateUserRecordsLastLogin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("the clock time is stored and returned", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithClock(clock.NewFake(now)))
+		u := newTestUser(t, "password123")
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		repo.On("RecordLogin", ctx, u.ID, now).Return(nil).Once()
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		require.NoError(t, err)
+		require.NotNil(t, output.User.LastLoginAt)
+		assert.Equal(t, now, *output.User.LastLoginAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("a storage failure does not block the login", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		u := newTestUser(t, "password123")
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		repo.On("RecordLogin", ctx, u.ID, mock.Anything).Return(errors.New("connection reset"))
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+		assert.Nil(t, output.User.LastLoginAt)
+	})
+
+	t.Run("failed logins are not recorded", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		u := newTestUser(t, "password123")
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "wrong-password"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "RecordLogin", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// WithEmailDomainRules restringe os domínios de email aceitos em CreateUser e
+// RegisterUser. Sem esta opção todos os domínios são aceitos
+func WithEmailDomainRules(rules user.EmailDomainRules) Option {
+	return func(uc *UserUseCase) {
+		uc.emailDomains = rules
+	}
+}
+
+// WithEmailPolicy define uma verificação adicional dos emails de cadastro,
+// aplicada após as regras de domínio (ex.: emailpolicy.List para descartáveis).
+// nil mantém a política padrão, que aceita todos os emails
+func WithEmailPolicy(policy user.EmailPolicy) Option {
+	return func(uc *UserUseCase) {
+		if policy == nil {
+			uc.emailPolicy = noopEmailPolicy{}
+			return
+		}
+		uc.emailPolicy = policy
+	}
+}
+
+// noopEmailPolicy é a política padrão, que aceita todos os emails
+type noopEmailPolicy struct{}
+
+func (noopEmailPolicy) Check(context.Context, string) error { return nil }
+
+// checkEmail aplica as regras de domínio e a EmailPolicy a um email já normalizado
+func (uc *UserUseCase) checkEmail(ctx context.Context, email string) error {
+	if err := uc.emailDomains.Check(email); err != nil {
+		return user.NewDomainError(err, "domain", user.EmailDomain(email))
+	}
+
+	if err := uc.emailPolicy.Check(ctx, email); err != nil {
+		if errors.Is(err, user.ErrDisposableEmail) {
+			return err
+		}
+		return fmt.Errorf("failed to check email policy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterUserEmailDomainRules(t *testing.T) {
+	rules, err := user.NewEmailDomainRules([]string{"*.example.com", "example.com"}, nil)
+	require.NoError(t, err)
+
+	t.Run("rejects domain outside the allowlist", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailDomainRules(rules))
+
+		_, err := uc.RegisterUser(context.Background(), usecase.RegisterUserInput{
+			Email: "  Ana@Gmail.COM ", Password: "password123", Name: "Ana",
+		})
+
+		assert.ErrorIs(t, err, user.ErrEmailDomainNotAllowed)
+		var domainErr *user.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "domain=gmail.com", domainErr.Detail())
+		repo.AssertNotCalled(t, "ExistsByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("accepts normalized company email", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailDomainRules(rules))
+		repo.On("ExistsByEmail", mock.Anything, "ana@eu.example.com").Return(false, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := uc.RegisterUser(context.Background(), usecase.RegisterUserInput{
+			Email: "Ana@EU.Example.com", Password: "password123", Name: "Ana",
+		})
+		assert.NoError(t, err)
+	})
+}
+
+// stubEmailPolicy retorna sempre o mesmo resultado
+type stubEmailPolicy struct{ err error }
+
+func (p stubEmailPolicy) Check(context.Context, string) error { return p.err }
+
+func TestCreateUserEmailPolicy(t *testing.T) {
+	input := usecase.CreateUserInput{Email: "ana@example.com", Password: "password123", Name: "Ana", Role: user.RoleUser}
+
+	t.Run("rejection keeps the domain error", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		rejection := user.NewDomainError(user.ErrDisposableEmail, "domain", "example.com")
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailPolicy(stubEmailPolicy{rejection}))
+
+		_, err := uc.CreateUser(context.Background(), input)
+		assert.ErrorIs(t, err, user.ErrDisposableEmail)
+		repo.AssertNotCalled(t, "ExistsByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("policy failure is not a rejection", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailPolicy(stubEmailPolicy{errors.New("lookup failed")}))
+
+		_, err := uc.CreateUser(context.Background(), input)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, user.ErrDisposableEmail)
+		assert.Contains(t, err.Error(), "failed to check email policy")
+	})
+
+	t.Run("nil policy accepts every email", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		repo.On("ExistsByEmail", mock.Anything, "ana@example.com").Return(true, nil)
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailPolicy(nil))
+
+		_, err := uc.CreateUser(context.Background(), input)
+		assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+	})
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// Erros da verificação e da troca de email
+var (
+	// ErrEmailVerificationDisabled indica que WithEmailVerification não foi configurado
+	ErrEmailVerificationDisabled = errors.New("email verification is not configured")
+	// ErrEmailUnchanged indica um pedido de troca para o email atual
+	ErrEmailUnchanged = errors.New("new email is the same as the current email")
+)
+
+// VerificationNotifier envia o link de verificação de email. token é o valor em
+// claro a embutir no link; apenas o hash é persistido
+type VerificationNotifier interface {
+	SendVerificationEmail(ctx context.Context, to, name, token string) error
+}
+
+// WithEmailVerification habilita a verificação de email: usuários locais
+// recebem o link ao serem criados e podem pedir o reenvio. issuer define o
+// tamanho e a validade dos tokens; app.NewVerificationTokenIssuer o cria a partir da
+// configuração de security
+func WithEmailVerification(issuer *auth.ResetTokenIssuer, notifier VerificationNotifier) Option {
+	return func(uc *UserUseCase) {
+		uc.verificationTokens = issuer
+		uc.verificationNotifier = notifier
+	}
+}
+
+// EmailVerificationEnabled informa se a verificação de email foi configurada
+func (uc *UserUseCase) EmailVerificationEnabled() bool {
+	return uc.verificationTokens != nil && uc.verificationNotifier != nil
+}
+
+// sendVerification emite um token para email, invalidando os links anteriores
+// do usuário, e o envia pelo notificador. Um email diferente do atual torna o
+// token uma troca de email
+func (uc *UserUseCase) sendVerification(ctx context.Context, u *user.User, email string) error {
+	plain, issued, err := uc.verificationTokens.Issue()
+	if err != nil {
+		return err
+	}
+
+	token := &user.VerificationToken{
+		Hash:      issued.Hash,
+		UserID:    u.ID,
+		Email:     email,
+		ExpiresAt: issued.ExpiresAt,
+		CreatedAt: uc.clock.Now(),
+	}
+	if email != u.Email {
+		token.ReplacesEmail = u.Email
+	}
+	err = uc.userRepo.ReplaceVerificationToken(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to store verification token: %w", err)
+	}
+
+	if err := uc.verificationNotifier.SendVerificationEmail(ctx, email, u.Name, plain); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+	return nil
+}
+
+// ResendVerification envia um novo link se email pertence a uma conta ativa
+// ainda não verificada, invalidando os links anteriores. Não retorna erro nem
+// indica se a conta existe, para não permitir a enumeração de emails: a busca e
+// o envio rodam em segundo plano, de modo que o tempo da resposta também não
+// depende da conta. Falhas são registradas em log; Wait aguarda os envios
+// pendentes
+func (uc *UserUseCase) ResendVerification(ctx context.Context, email string) {
+	if !uc.EmailVerificationEnabled() {
+		return
+	}
+
+	// O envio sobrevive ao fim da requisição, mas mantém os valores do contexto
+	ctx = context.WithoutCancel(ctx)
+	uc.background.Add(1)
+	go func() {
+		defer uc.background.Done()
+		uc.resendVerification(ctx, email)
+	}()
+}
+
+// resendVerification é a parte em segundo plano de ResendVerification
+func (uc *UserUseCase) resendVerification(ctx context.Context, email string) {
+	u, err := uc.userRepo.GetByEmail(ctx, user.NormalizeEmail(email))
+	if err != nil {
+		if !errors.Is(err, user.ErrUserNotFound) {
+			uc.logger.ErrorContext(ctx, "failed to resend verification email", "email_hash", HashIdentifier(email), "error", err)
+		}
+		return
+	}
+	if !u.IsActiveUser() || u.IsEmailVerified() {
+		return
+	}
+
+	if err := uc.sendVerification(ctx, u, u.Email); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to resend verification email", "user_id", u.ID, "error", err)
+		return
+	}
+	uc.logger.InfoContext(ctx, "user.verification_resent", "user_id", u.ID)
+}
+
+// Wait bloqueia até que os envios disparados em segundo plano terminem. No
+// desligamento, registre com workers.OnShutdown para não perder links em andamento
+func (uc *UserUseCase) Wait() {
+	uc.background.Wait()
+}
+
+// VerifyEmail confirma o email com o token recebido no link. O token é de uso
+// único; tokens expirados ou emitidos para um email que não é mais o do usuário
+// são rejeitados. Em uma troca de email, o novo endereço substitui o atual já
+// verificado
+func (uc *UserUseCase) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return user.ErrVerificationTokenInvalid
+	}
+
+	stored, err := uc.userRepo.ConsumeVerificationToken(ctx, auth.HashResetToken(token))
+	if err != nil {
+		return err
+	}
+
+	now := uc.clock.Now()
+	if !now.Before(stored.ExpiresAt) {
+		return user.ErrVerificationTokenExpired
+	}
+
+	if stored.IsEmailChange() {
+		return uc.confirmEmailChange(ctx, stored, now)
+	}
+
+	if err := uc.userRepo.MarkEmailVerified(ctx, stored.UserID, stored.Email, now); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.ErrVerificationTokenInvalid
+		}
+		return err
+	}
+
+	uc.logger.InfoContext(ctx, "user.email_verified", "user_id", stored.UserID)
+	return nil
+}
+
+// RequestEmailChangeInput representa o pedido de troca de email do próprio usuário
+type RequestEmailChangeInput struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	Password string `json:"-"`
+}
+
+// RequestEmailChange envia um link de confirmação para o novo email, após
+// conferir a senha atual e as regras de cadastro do endereço. O email atual
+// continua valendo até a confirmação (VerifyEmail); um novo pedido ou reenvio
+// invalida o link anterior
+func (uc *UserUseCase) RequestEmailChange(ctx context.Context, input RequestEmailChangeInput) error {
+	if !uc.EmailVerificationEnabled() {
+		return ErrEmailVerificationDisabled
+	}
+
+	u, err := uc.userRepo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.NewDomainError(err, "id", input.UserID)
+		}
+		return fmt.Errorf("failed to get user for email change: %w", err)
+	}
+
+	if !u.CheckPassword(input.Password) {
+		return user.ErrInvalidPassword
+	}
+
+	email := user.NormalizeEmail(input.Email)
+	if email == u.Email {
+		return ErrEmailUnchanged
+	}
+	if err := uc.CheckEmailAvailable(ctx, email); err != nil {
+		return err
+	}
+
+	if err := uc.sendVerification(ctx, u, email); err != nil {
+		return err
+	}
+
+	uc.logger.InfoContext(ctx, "user.email_change_requested", "user_id", u.ID)
+	return nil
+}
+
+// confirmEmailChange aplica a troca de email de um token já validado
+func (uc *UserUseCase) confirmEmailChange(ctx context.Context, stored *user.VerificationToken, now time.Time) error {
+	err := uc.userRepo.ChangeEmail(ctx, stored.UserID, stored.ReplacesEmail, stored.Email, now)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			// O email mudou depois do pedido
+			return user.ErrVerificationTokenInvalid
+		case errors.Is(err, user.ErrUserAlreadyExists):
+			return user.NewDomainError(err, "email", stored.Email)
+		}
+		return err
+	}
+
+	uc.logger.InfoContext(ctx, "user.email_changed", "user_id", stored.UserID)
+	if u, err := uc.userRepo.GetByID(ctx, stored.UserID); err == nil {
+		uc.publish(ctx, user.NewEvent(user.EventUserUpdated, u))
+	}
+	return nil
+}
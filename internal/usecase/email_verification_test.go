@@ -0,0 +1,231 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureNotifier guarda o último token enviado
+type captureNotifier struct {
+	to, token string
+	err       error
+}
+
+func (n *captureNotifier) SendVerificationEmail(_ context.Context, to, _, token string) error {
+	n.to, n.token = to, token
+	return n.err
+}
+
+func newVerificationUseCase(t *testing.T, c clock.Clock, notifier usecase.VerificationNotifier) (*usecase.UserUseCase, *mocks.UserRepository) {
+	t.Helper()
+	issuer, err := auth.NewResetTokenIssuer(0, time.Hour, c)
+	require.NoError(t, err)
+	repo := &mocks.UserRepository{}
+	return usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithClock(c), usecase.WithEmailVerification(issuer, notifier)), repo
+}
+
+func TestCreateUserSendsVerification(t *testing.T) {
+	ctx := context.Background()
+	input := usecase.CreateUserInput{Email: "ana@example.com", Password: "password123", Name: "Ana", Role: user.RoleUser}
+
+	t.Run("link is sent to the new user", func(t *testing.T) {
+		notifier := &captureNotifier{}
+		uc, repo := newVerificationUseCase(t, clock.System, notifier)
+		repo.On("ExistsByEmail", ctx, "ana@example.com").Return(false, nil)
+		repo.On("Create", ctx, mock.AnythingOfType("*user.User")).Return(nil)
+		var stored *user.VerificationToken
+		repo.On("ReplaceVerificationToken", ctx, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*user.VerificationToken)
+		})
+
+		output, err := uc.CreateUser(ctx, input)
+
+		require.NoError(t, err)
+		assert.False(t, output.User.IsEmailVerified())
+		assert.Equal(t, "ana@example.com", notifier.to)
+		// Apenas o hash do token enviado é persistido
+		require.NotNil(t, stored)
+		assert.Equal(t, "ana@example.com", stored.Email)
+		assert.Equal(t, auth.HashResetToken(notifier.token), stored.Hash)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("delivery failure does not undo the registration", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.System, &captureNotifier{err: errors.New("smtp down")})
+		repo.On("ExistsByEmail", ctx, "ana@example.com").Return(false, nil)
+		repo.On("Create", ctx, mock.AnythingOfType("*user.User")).Return(nil)
+		repo.On("ReplaceVerificationToken", ctx, mock.Anything).Return(nil)
+
+		_, err := uc.CreateUser(ctx, input)
+		assert.NoError(t, err)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	stored := &user.VerificationToken{UserID: "42", Email: "ana@example.com", ExpiresAt: now.Add(time.Hour)}
+	hash := auth.HashResetToken("plain-token")
+
+	t.Run("valid token marks the email as verified", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(stored, nil)
+		repo.On("MarkEmailVerified", ctx, "42", "ana@example.com", now).Return(nil)
+
+		require.NoError(t, uc.VerifyEmail(ctx, "plain-token"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now.Add(2*time.Hour)), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(stored, nil)
+
+		assert.ErrorIs(t, uc.VerifyEmail(ctx, "plain-token"), user.ErrVerificationTokenExpired)
+		repo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("token for a previous email", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(stored, nil)
+		repo.On("MarkEmailVerified", ctx, "42", "ana@example.com", now).Return(user.ErrUserNotFound)
+
+		assert.ErrorIs(t, uc.VerifyEmail(ctx, "plain-token"), user.ErrVerificationTokenInvalid)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		uc, _ := newVerificationUseCase(t, clock.NewFake(now), &captureNotifier{})
+		assert.ErrorIs(t, uc.VerifyEmail(ctx, ""), user.ErrVerificationTokenInvalid)
+	})
+}
+
+func TestResendVerificationDisabled(t *testing.T) {
+	uc, repo, _ := newTestUseCase()
+	assert.False(t, uc.EmailVerificationEnabled())
+
+	uc.ResendVerification(context.Background(), "ana@example.com")
+	repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+}
+
+func TestResendVerificationRunsInBackground(t *testing.T) {
+	notifier := &captureNotifier{}
+	uc, repo := newVerificationUseCase(t, clock.System, notifier)
+
+	release := make(chan time.Time)
+	u := &user.User{ID: "42", Email: "ana@example.com", Name: "Ana", IsActive: true}
+	repo.On("GetByEmail", mock.Anything, "ana@example.com").WaitUntil(release).Return(u, nil)
+	repo.On("ReplaceVerificationToken", mock.Anything, mock.Anything).Return(nil)
+
+	// A requisição termina antes da busca da conta, e o cancelamento do seu
+	// contexto não interrompe o envio
+	ctx, cancel := context.WithCancel(context.Background())
+	uc.ResendVerification(ctx, "Ana@Example.com")
+	cancel()
+	assert.Empty(t, notifier.to)
+
+	close(release)
+	uc.Wait()
+	assert.Equal(t, "ana@example.com", notifier.to)
+	repo.AssertExpectations(t)
+}
+
+func TestRequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+
+	newUseCase := func(t *testing.T) (*usecase.UserUseCase, *mocks.UserRepository, *captureNotifier, *user.User) {
+		notifier := &captureNotifier{}
+		uc, repo := newVerificationUseCase(t, clock.System, notifier)
+		u := newTestUser(t, "password123")
+		repo.On("GetByID", ctx, u.ID).Return(u, nil)
+		return uc, repo, notifier, u
+	}
+
+	t.Run("link goes to the new email and the current one is kept", func(t *testing.T) {
+		uc, repo, notifier, u := newUseCase(t)
+		repo.On("ExistsByEmail", ctx, "new@example.com").Return(false, nil)
+		repo.On("ReplaceVerificationToken", ctx, mock.MatchedBy(func(token *user.VerificationToken) bool {
+			return token.Email == "new@example.com" && token.ReplacesEmail == "test@example.com" && token.IsEmailChange()
+		})).Return(nil)
+
+		err := uc.RequestEmailChange(ctx, usecase.RequestEmailChangeInput{UserID: u.ID, Email: " New@Example.com ", Password: "password123"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", notifier.to)
+		assert.Equal(t, "test@example.com", u.Email)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		uc, repo, _, u := newUseCase(t)
+		err := uc.RequestEmailChange(ctx, usecase.RequestEmailChangeInput{UserID: u.ID, Email: "new@example.com", Password: "wrong"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "ReplaceVerificationToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("same email", func(t *testing.T) {
+		uc, _, _, u := newUseCase(t)
+		err := uc.RequestEmailChange(ctx, usecase.RequestEmailChangeInput{UserID: u.ID, Email: "TEST@example.com", Password: "password123"})
+		assert.ErrorIs(t, err, usecase.ErrEmailUnchanged)
+	})
+
+	t.Run("email already in use", func(t *testing.T) {
+		uc, repo, _, u := newUseCase(t)
+		repo.On("ExistsByEmail", ctx, "taken@example.com").Return(true, nil)
+		err := uc.RequestEmailChange(ctx, usecase.RequestEmailChangeInput{UserID: u.ID, Email: "taken@example.com", Password: "password123"})
+		assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+	})
+}
+
+func TestVerifyEmailConfirmsEmailChange(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	hash := auth.HashResetToken("plain-token")
+	pending := &user.VerificationToken{UserID: "42", Email: "new@example.com", ReplacesEmail: "old@example.com", ExpiresAt: now.Add(time.Hour)}
+
+	t.Run("confirmation swaps the email", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(pending, nil)
+		repo.On("ChangeEmail", ctx, "42", "old@example.com", "new@example.com", now).Return(nil)
+		repo.On("GetByID", ctx, "42").Return(&user.User{ID: "42", Email: "new@example.com"}, nil)
+
+		require.NoError(t, uc.VerifyEmail(ctx, "plain-token"))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired change keeps the current email", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now.Add(2*time.Hour)), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(pending, nil)
+
+		assert.ErrorIs(t, uc.VerifyEmail(ctx, "plain-token"), user.ErrVerificationTokenExpired)
+		repo.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("email changed after the request", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(pending, nil)
+		repo.On("ChangeEmail", ctx, "42", "old@example.com", "new@example.com", now).Return(user.ErrUserNotFound)
+
+		assert.ErrorIs(t, uc.VerifyEmail(ctx, "plain-token"), user.ErrVerificationTokenInvalid)
+	})
+
+	t.Run("new email taken meanwhile", func(t *testing.T) {
+		uc, repo := newVerificationUseCase(t, clock.NewFake(now), &captureNotifier{})
+		repo.On("ConsumeVerificationToken", ctx, hash).Return(pending, nil)
+		repo.On("ChangeEmail", ctx, "42", "old@example.com", "new@example.com", now).Return(user.ErrUserAlreadyExists)
+
+		assert.ErrorIs(t, uc.VerifyEmail(ctx, "plain-token"), user.ErrUserAlreadyExists)
+	})
+}
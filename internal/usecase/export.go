@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// Padrões da exportação de usuários
+const (
+	DefaultExportBatchSize = 1000
+	DefaultExportMaxRows   = 100000
+)
+
+// WithExportLimits define o tamanho dos lotes lidos do banco e o total máximo de
+// linhas de uma exportação; valores <= 0 mantêm os padrões
+func WithExportLimits(batchSize, maxRows int) Option {
+	return func(uc *UserUseCase) {
+		if batchSize > 0 {
+			uc.exportBatchSize = batchSize
+		}
+		if maxRows > 0 {
+			uc.exportMaxRows = maxRows
+		}
+	}
+}
+
+// ExportUsersOutput resume uma exportação concluída
+type ExportUsersOutput struct {
+	Rows int `json:"rows"`
+	// Truncated indica que o limite de linhas foi atingido antes do fim da tabela
+	Truncated bool `json:"truncated"`
+}
+
+// ExportUsers percorre os usuários em lotes por keyset (ordem de ID), entregando
+// cada lote a write assim que lido. A memória fica limitada a um lote e o banco
+// nunca é consultado pela tabela inteira. Para ao atingir o limite de linhas ou
+// quando write retorna erro
+func (uc *UserUseCase) ExportUsers(ctx context.Context, write func([]*user.User) error) (*ExportUsersOutput, error) {
+	output := &ExportUsersOutput{}
+	afterID := ""
+
+	for {
+		limit := uc.exportBatchSize
+		if remaining := uc.exportMaxRows - output.Rows; remaining < limit {
+			limit = remaining
+		}
+		if limit == 0 {
+			// Só está truncado se ainda houver usuários além do limite
+			more, err := uc.userRepo.ListAfterID(ctx, afterID, 1)
+			if err != nil {
+				return output, fmt.Errorf("failed to list users for export: %w", err)
+			}
+			output.Truncated = len(more) > 0
+			return output, nil
+		}
+
+		batch, err := uc.userRepo.ListAfterID(ctx, afterID, limit)
+		if err != nil {
+			return output, fmt.Errorf("failed to list users for export: %w", err)
+		}
+		if len(batch) == 0 {
+			return output, nil
+		}
+
+		if err := write(batch); err != nil {
+			return output, err
+		}
+
+		output.Rows += len(batch)
+		afterID = batch[len(batch)-1].ID
+
+		if len(batch) < limit {
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedUsers cria n usuários com IDs ordenáveis u01, u02...
+func seedUsers(n int) []*user.User {
+	users := make([]*user.User, n)
+	for i := range users {
+		users[i] = &user.User{ID: fmt.Sprintf("u%02d", i+1)}
+	}
+	return users
+}
+
+// collect acumula os lotes recebidos pela exportação
+func collect(batches *[][]*user.User) func([]*user.User) error {
+	return func(batch []*user.User) error {
+		*batches = append(*batches, batch)
+		return nil
+	}
+}
+
+func TestExportUsersStreamsInBatches(t *testing.T) {
+	ctx := context.Background()
+	users := seedUsers(5)
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithExportLimits(2, 0))
+
+	repo.On("ListAfterID", ctx, "", 2).Return(users[0:2], nil).Once()
+	repo.On("ListAfterID", ctx, "u02", 2).Return(users[2:4], nil).Once()
+	repo.On("ListAfterID", ctx, "u04", 2).Return(users[4:5], nil).Once()
+
+	var batches [][]*user.User
+	output, err := uc.ExportUsers(ctx, collect(&batches))
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, output.Rows)
+	assert.False(t, output.Truncated)
+	assert.Len(t, batches, 3)
+	repo.AssertExpectations(t)
+}
+
+func TestExportUsersStopsAtRowCap(t *testing.T) {
+	ctx := context.Background()
+	users := seedUsers(5)
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithExportLimits(2, 3))
+
+	repo.On("ListAfterID", ctx, "", 2).Return(users[0:2], nil).Once()
+	repo.On("ListAfterID", ctx, "u02", 1).Return(users[2:3], nil).Once()
+	repo.On("ListAfterID", ctx, "u03", 1).Return(users[3:4], nil).Once()
+
+	var batches [][]*user.User
+	output, err := uc.ExportUsers(ctx, collect(&batches))
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, output.Rows)
+	assert.True(t, output.Truncated)
+	repo.AssertExpectations(t)
+}
+
+func TestExportUsersStopsOnWriteError(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithExportLimits(2, 0))
+	repo.On("ListAfterID", ctx, "", 2).Return(seedUsers(2), nil).Once()
+
+	broken := errors.New("client went away")
+	_, err := uc.ExportUsers(ctx, func([]*user.User) error { return broken })
+	assert.ErrorIs(t, err, broken)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// ErrExternalEmailNotVerified indica um login externo sem email verificado pelo
+// provedor; sem essa garantia a conta não pode ser vinculada nem criada
+var ErrExternalEmailNotVerified = errors.New("external identity email is not verified")
+
+// ExternalLoginInput representa uma identidade já verificada por um provedor
+// externo (ex.: ID token OIDC)
+type ExternalLoginInput struct {
+	Issuer        string `json:"issuer"`
+	Subject       string `json:"subject"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+
+	// Metadados da requisição, usados nos logs de auditoria e, com WithSessions,
+	// guardados na sessão do token emitido
+	ClientIP  string `json:"-"`
+	RequestID string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// AuthenticateExternal emite o token da API para uma identidade externa. O
+// usuário é localizado pelo vínculo (issuer, subject); sem vínculo, a identidade
+// é vinculada ao usuário com o mesmo email ou um novo usuário (RoleUser, sem
+// senha local) é criado com as regras de email do cadastro. Vincular ou criar
+// exige email verificado pelo provedor
+func (uc *UserUseCase) AuthenticateExternal(ctx context.Context, input ExternalLoginInput) (*AuthenticateUserOutput, error) {
+	input.Email = user.NormalizeEmail(input.Email)
+	login := AuthenticateUserInput{Email: input.Email, ClientIP: input.ClientIP, RequestID: input.RequestID, UserAgent: input.UserAgent}
+
+	userEntity, err := uc.userRepo.GetByIdentity(ctx, input.Issuer, input.Subject)
+	if errors.Is(err, user.ErrUserNotFound) {
+		userEntity, err = uc.linkExternalIdentity(ctx, input)
+	}
+	if err != nil {
+		if errors.Is(err, ErrExternalEmailNotVerified) {
+			uc.recordLogin(ctx, login, LoginResultUnverifiedEmail)
+			return nil, err
+		}
+		uc.recordLogin(ctx, login, LoginResultError)
+		return nil, err
+	}
+
+	if !userEntity.IsActiveUser() {
+		uc.recordLogin(ctx, login, LoginResultDeactivated)
+		return nil, user.ErrUserDeactivated
+	}
+
+	return uc.issueLoginToken(ctx, login, userEntity)
+}
+
+// linkExternalIdentity vincula a identidade ao usuário com o mesmo email,
+// criando-o se necessário
+func (uc *UserUseCase) linkExternalIdentity(ctx context.Context, input ExternalLoginInput) (*user.User, error) {
+	if !input.EmailVerified || input.Email == "" {
+		return nil, ErrExternalEmailNotVerified
+	}
+
+	u, err := uc.userRepo.GetByEmail(ctx, input.Email)
+	created := false
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		if u, err = uc.provisionExternalUser(ctx, input); err != nil {
+			return nil, err
+		}
+		created = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if err := uc.userRepo.LinkIdentity(ctx, u.ID, input.Issuer, input.Subject); err != nil {
+		if !errors.Is(err, user.ErrIdentityAlreadyLinked) {
+			return nil, fmt.Errorf("failed to link external identity: %w", err)
+		}
+		// Outro login concorrente vinculou a identidade primeiro
+		return uc.userRepo.GetByIdentity(ctx, input.Issuer, input.Subject)
+	}
+
+	if created {
+		uc.metrics.UserCreated()
+		uc.publish(ctx, user.NewEvent(user.EventUserCreated, u))
+	}
+	return u, nil
+}
+
+// provisionExternalUser cria o usuário local de uma identidade externa
+func (uc *UserUseCase) provisionExternalUser(ctx context.Context, input ExternalLoginInput) (*user.User, error) {
+	if err := uc.checkEmail(ctx, input.Email); err != nil {
+		return nil, err
+	}
+
+	name := input.Name
+	if strings.TrimSpace(name) == "" {
+		name, _, _ = strings.Cut(input.Email, "@")
+	}
+
+	u, err := user.NewExternalUser(input.Email, name, user.RoleUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user entity: %w", err)
+	}
+	if err := uc.userRepo.Create(ctx, u); err != nil {
+		return nil, fmt.Errorf("failed to create user in repository: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,118 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://accounts.example.com"
+
+func externalInput() usecase.ExternalLoginInput {
+	return usecase.ExternalLoginInput{
+		Issuer:        testIssuer,
+		Subject:       "subject-123",
+		Email:         "Ana@Example.com",
+		EmailVerified: true,
+		Name:          "Ana Souza",
+	}
+}
+
+func TestAuthenticateExternal(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("linked identity", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		linked := newTestUser(t, "password123")
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(linked, nil)
+		jwtService.On("GenerateToken", linked.ID, linked.Email, string(linked.Role)).Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, linked.ID, mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateExternal(ctx, externalInput())
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "LinkIdentity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("links to the user with the same email", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		existing := newTestUser(t, "password123")
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(nil, user.ErrUserNotFound)
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(existing, nil)
+		repo.On("LinkIdentity", ctx, existing.ID, testIssuer, "subject-123").Return(nil)
+		jwtService.On("GenerateToken", existing.ID, existing.Email, string(existing.Role)).Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, existing.ID, mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateExternal(ctx, externalInput())
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, output.User.ID)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("provisions a new user", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(nil, user.ErrUserNotFound)
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(nil, user.ErrUserNotFound)
+		repo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+			return u.Email == "ana@example.com" && u.Name == "Ana Souza" && u.Role == user.RoleUser
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*user.User).ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+		}).Return(nil)
+		repo.On("LinkIdentity", ctx, "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60", testIssuer, "subject-123").Return(nil)
+		jwtService.On("GenerateToken", "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60", "ana@example.com", "user").Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60", mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateExternal(ctx, externalInput())
+		require.NoError(t, err)
+		assert.False(t, output.User.CheckPassword(""), "provisioned users have no usable local password")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unverified email is never linked", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(nil, user.ErrUserNotFound)
+
+		input := externalInput()
+		input.EmailVerified = false
+		_, err := uc.AuthenticateExternal(ctx, input)
+		assert.ErrorIs(t, err, usecase.ErrExternalEmailNotVerified)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deactivated user", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		linked := newTestUser(t, "password123")
+		linked.Deactivate()
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(linked, nil)
+
+		_, err := uc.AuthenticateExternal(ctx, externalInput())
+		assert.ErrorIs(t, err, user.ErrUserDeactivated)
+	})
+
+	t.Run("concurrent link reads the winning user", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		existing := newTestUser(t, "password123")
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(nil, user.ErrUserNotFound).Once()
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(existing, nil)
+		repo.On("LinkIdentity", ctx, existing.ID, testIssuer, "subject-123").Return(user.ErrIdentityAlreadyLinked)
+		repo.On("GetByIdentity", ctx, testIssuer, "subject-123").Return(existing, nil).Once()
+		jwtService.On("GenerateToken", existing.ID, existing.Email, string(existing.Role)).Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, existing.ID, mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateExternal(ctx, externalInput())
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, output.User.ID)
+	})
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// MaxImportEmails limita a quantidade de emails pré-validados por requisição
+const MaxImportEmails = 1000
+
+var (
+	// ErrEmptyImportEmails indica uma pré-validação sem emails
+	ErrEmptyImportEmails = errors.New("at least one email is required")
+	// ErrTooManyImportEmails indica uma pré-validação acima de MaxImportEmails
+	ErrTooManyImportEmails = fmt.Errorf("at most %d emails are allowed per request", MaxImportEmails)
+)
+
+// Situação de cada email na pré-validação de uma importação
+const (
+	ImportEmailValid         = "valid"
+	ImportEmailInvalidFormat = "invalid_format"
+	ImportEmailDomainBlocked = "domain_blocked"
+	ImportEmailAlreadyExists = "already_exists"
+	// ImportEmailDuplicate marca repetições de um email anterior da mesma lista
+	ImportEmailDuplicate = "duplicate"
+)
+
+// ValidateImportEmailsInput representa os emails a pré-validar. O formato é
+// verificado pela camada de entrada; aqui chegam apenas emails bem formados
+type ValidateImportEmailsInput struct {
+	Emails []string `json:"emails"`
+}
+
+// ImportEmailResult é a situação de um email, na posição da entrada
+type ImportEmailResult struct {
+	// Email é o valor normalizado, como seria gravado
+	Email  string `json:"email"`
+	Status string `json:"status"`
+}
+
+// ValidateImportEmailsOutput traz a situação de cada email, na ordem de entrada
+type ValidateImportEmailsOutput struct {
+	Items []ImportEmailResult `json:"items"`
+}
+
+// ValidateImportEmails aplica a cada email as regras de cadastro de CreateUser
+// (normalização, regras de domínio, EmailPolicy e unicidade) sem criar nada. A
+// existência é verificada em uma única consulta
+func (uc *UserUseCase) ValidateImportEmails(ctx context.Context, input ValidateImportEmailsInput) (*ValidateImportEmailsOutput, error) {
+	if len(input.Emails) == 0 {
+		return nil, ErrEmptyImportEmails
+	}
+	if len(input.Emails) > MaxImportEmails {
+		return nil, ErrTooManyImportEmails
+	}
+
+	items := make([]ImportEmailResult, len(input.Emails))
+	seen := make(map[string]struct{}, len(input.Emails))
+	var candidates []string
+	for i, raw := range input.Emails {
+		email := user.NormalizeEmail(raw)
+		items[i].Email = email
+
+		if _, ok := seen[email]; ok {
+			items[i].Status = ImportEmailDuplicate
+			continue
+		}
+		seen[email] = struct{}{}
+
+		if err := uc.checkEmail(ctx, email); err != nil {
+			if !errors.Is(err, user.ErrEmailDomainNotAllowed) && !errors.Is(err, user.ErrDisposableEmail) {
+				return nil, err
+			}
+			items[i].Status = ImportEmailDomainBlocked
+			continue
+		}
+		candidates = append(candidates, email)
+	}
+
+	taken := make(map[string]struct{})
+	if len(candidates) > 0 {
+		existing, err := uc.userRepo.ExistingEmails(ctx, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing emails: %w", err)
+		}
+		for _, email := range existing {
+			taken[email] = struct{}{}
+		}
+	}
+
+	for i := range items {
+		if items[i].Status != "" {
+			continue
+		}
+		if _, ok := taken[items[i].Email]; ok {
+			items[i].Status = ImportEmailAlreadyExists
+		} else {
+			items[i].Status = ImportEmailValid
+		}
+	}
+
+	return &ValidateImportEmailsOutput{Items: items}, nil
+}
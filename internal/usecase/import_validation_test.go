@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateImportEmails(t *testing.T) {
+	ctx := context.Background()
+	rules, err := user.NewEmailDomainRules(nil, []string{"blocked.com"})
+	require.NoError(t, err)
+
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEmailDomainRules(rules))
+	repo.On("ExistingEmails", ctx, []string{"ana@example.com", "taken@example.com"}).
+		Return([]string{"taken@example.com"}, nil).Once()
+
+	output, err := uc.ValidateImportEmails(ctx, usecase.ValidateImportEmailsInput{Emails: []string{
+		" Ana@Example.com",
+		"taken@example.com",
+		"spam@blocked.com",
+		"ana@example.com",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []usecase.ImportEmailResult{
+		{Email: "ana@example.com", Status: usecase.ImportEmailValid},
+		{Email: "taken@example.com", Status: usecase.ImportEmailAlreadyExists},
+		{Email: "spam@blocked.com", Status: usecase.ImportEmailDomainBlocked},
+		{Email: "ana@example.com", Status: usecase.ImportEmailDuplicate},
+	}, output.Items)
+	repo.AssertExpectations(t)
+}
+
+func TestValidateImportEmailsLimits(t *testing.T) {
+	uc, repo, _ := newTestUseCase()
+
+	_, err := uc.ValidateImportEmails(context.Background(), usecase.ValidateImportEmailsInput{})
+	assert.ErrorIs(t, err, usecase.ErrEmptyImportEmails)
+
+	emails := strings.Split(strings.Repeat("a@example.com,", usecase.MaxImportEmails+1), ",")[:usecase.MaxImportEmails+1]
+	_, err = uc.ValidateImportEmails(context.Background(), usecase.ValidateImportEmailsInput{Emails: emails})
+	assert.ErrorIs(t, err, usecase.ErrTooManyImportEmails)
+	repo.AssertNotCalled(t, "ExistingEmails")
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Padrões dos limites de leitura em lote e de busca
+const (
+	DefaultMaxBatchGetIDs = 100
+	DefaultMaxSearchLimit = 100
+)
+
+var (
+	// ErrTooManyBatchGetIDs indica uma busca em lote acima do limite de IDs
+	ErrTooManyBatchGetIDs = errors.New("too many user IDs")
+	// ErrSearchLimitTooLarge indica uma página de busca acima do limite
+	ErrSearchLimitTooLarge = errors.New("search limit too large")
+)
+
+// LimitExceededError informa o limite configurado que a requisição excedeu.
+// Err é ErrTooManyBatchGetIDs ou ErrSearchLimitTooLarge
+type LimitExceededError struct {
+	Err error
+	Max int
+	Got int
+}
+
+// Error implementa error
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%v: got %d, at most %d allowed", e.Err, e.Got, e.Max)
+}
+
+// Unwrap expõe o erro sentinela
+func (e *LimitExceededError) Unwrap() error {
+	return e.Err
+}
+
+// WithResultLimits define quantos IDs a busca em lote aceita e o maior limit de
+// uma página de busca; valores <= 0 mantêm os padrões
+func WithResultLimits(maxBatchGetIDs, maxSearchLimit int) Option {
+	return func(uc *UserUseCase) {
+		if maxBatchGetIDs > 0 {
+			uc.maxBatchGetIDs = maxBatchGetIDs
+		}
+		if maxSearchLimit > 0 {
+			uc.maxSearchLimit = maxSearchLimit
+		}
+	}
+}
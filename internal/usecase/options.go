@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/pkg/requestid"
+)
+
+// Option configura dependências opcionais do UserUseCase
+type Option func(*UserUseCase)
+
+// Resultados de uma tentativa de login, usados em métricas e logs
+const (
+	LoginResultSuccess         = "success"
+	LoginResultInvalidPassword = "invalid_password"
+	LoginResultNotFound        = "not_found"
+	LoginResultDeactivated     = "deactivated"
+	LoginResultError           = "error"
+	// LoginResultUnverifiedEmail indica um login externo cujo email não foi
+	// verificado pelo provedor
+	LoginResultUnverifiedEmail = "unverified_email"
+	// LoginResultPasswordExpired indica uma senha correta, mas vencida
+	// (WithPasswordMaxAge); nenhum token é emitido
+	LoginResultPasswordExpired = "password_expired"
+)
+
+// Metrics recebe os eventos de negócio do caso de uso
+type Metrics interface {
+	UserCreated()
+	LoginAttempt(result string)
+}
+
+// WithMetrics define o coletor de métricas de negócio
+func WithMetrics(metrics Metrics) Option {
+	return func(uc *UserUseCase) {
+		uc.metrics = metrics
+	}
+}
+
+// noopMetrics é o coletor padrão quando nenhuma métrica é configurada
+type noopMetrics struct{}
+
+func (noopMetrics) UserCreated()        {}
+func (noopMetrics) LoginAttempt(string) {}
+
+// WithEventPublisher define o destino dos eventos do ciclo de vida de usuários
+func WithEventPublisher(publisher user.EventPublisher) Option {
+	return func(uc *UserUseCase) {
+		uc.events = publisher
+	}
+}
+
+// publish entrega o evento com o ID de correlação da requisição em ctx, para
+// que webhooks e streams possam ser ligados à requisição que os originou
+func (uc *UserUseCase) publish(ctx context.Context, event user.Event) {
+	event.RequestID = requestid.FromContext(ctx)
+	uc.logger.DebugContext(ctx, "user event published", "event", event.Type, "user_id", event.UserID)
+	uc.events.Publish(ctx, event)
+}
+
+// noopPublisher é o publicador padrão quando nenhum é configurado
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, user.Event) {}
+
+// WithLogger define o logger dos eventos de auditoria (ex.: resultados de login)
+func WithLogger(logger *slog.Logger) Option {
+	return func(uc *UserUseCase) {
+		uc.logger = logger
+	}
+}
+
+// WithClock define a fonte de tempo do caso de uso
+func WithClock(c clock.Clock) Option {
+	return func(uc *UserUseCase) {
+		uc.clock = c
+	}
+}
+
+// WithUpdatePolicy define a matriz que limita os campos que cada papel altera
+// em UpdateUser; o padrão é user.DefaultUpdatePolicy
+func WithUpdatePolicy(policy user.UpdatePolicy) Option {
+	return func(uc *UserUseCase) {
+		uc.updatePolicy = policy
+	}
+}
+
+// discardHandler descarta os logs quando nenhum logger é configurado
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// recordLogin registra métricas e o log estruturado de uma tentativa de login.
+// Nunca registra senha nem token; email e username são registrados apenas como hash
+func (uc *UserUseCase) recordLogin(ctx context.Context, input AuthenticateUserInput, result string) {
+	uc.metrics.LoginAttempt(result)
+
+	level := slog.LevelWarn
+	switch result {
+	case LoginResultSuccess:
+		level = slog.LevelInfo
+	case LoginResultError:
+		level = slog.LevelError
+	}
+
+	attrs := []slog.Attr{
+		slog.String("result", result),
+		slog.String("ip", input.ClientIP),
+		slog.String("request_id", input.RequestID),
+	}
+	// No login por username, o email só é conhecido se o username existir
+	if input.Email != "" || input.Username == "" {
+		attrs = append(attrs, slog.String("email_hash", HashIdentifier(input.Email)))
+	}
+	if input.Username != "" {
+		attrs = append(attrs, slog.String("username_hash", HashIdentifier(input.Username)))
+	}
+	uc.logger.LogAttrs(ctx, level, "auth.login", attrs...)
+}
+
+// HashIdentifier gera um identificador estável e não reversível para um email,
+// permitindo correlacionar tentativas (ex.: força bruta) sem expor o endereço
+func HashIdentifier(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:8])
+}
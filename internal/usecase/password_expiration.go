@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+var (
+	// ErrPasswordExpirationDisabled indica que a expiração de senhas não foi
+	// habilitada (WithPasswordMaxAge)
+	ErrPasswordExpirationDisabled = errors.New("password expiration is not enabled")
+	// ErrPasswordUnchanged indica uma nova senha igual à atual
+	ErrPasswordUnchanged = errors.New("new password must be different from the current password")
+)
+
+// WithPasswordMaxAge habilita a expiração de senhas locais: senhas trocadas há
+// maxAge ou mais deixam de concluir o login (user.ErrPasswordExpired) até
+// serem trocadas por ChangeExpiredPassword. maxAge <= 0 mantém desabilitado
+func WithPasswordMaxAge(maxAge time.Duration) Option {
+	return func(uc *UserUseCase) {
+		uc.passwordMaxAge = maxAge
+	}
+}
+
+// PasswordExpirationEnabled informa se a expiração de senhas foi habilitada
+func (uc *UserUseCase) PasswordExpirationEnabled() bool {
+	return uc.passwordMaxAge > 0
+}
+
+// passwordExpired informa se a senha do usuário venceu pela política
+func (uc *UserUseCase) passwordExpired(u *user.User) bool {
+	return u.PasswordExpired(uc.clock.Now(), uc.passwordMaxAge)
+}
+
+// ChangeExpiredPasswordInput representa a troca de senha sem token, usada
+// quando o login responde senha expirada. Como no login, Username pode ser
+// informado no lugar de Email com WithUsernames
+type ChangeExpiredPasswordInput struct {
+	Email           string `json:"email"`
+	Username        string `json:"username,omitempty"`
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+
+	// Metadados da requisição, como em AuthenticateUserInput
+	ClientIP  string `json:"-"`
+	RequestID string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// ChangeExpiredPassword confere as credenciais atuais, troca a senha
+// (reiniciando o prazo de expiração), encerra as sessões existentes e conclui
+// o login com a nova senha. Falhas de credenciais são auditadas como tentativas
+// de login. Aceita também senhas ainda não vencidas. Se a senha for trocada
+// por outra operação entre a conferência e a escrita, o retorno é
+// user.ErrInvalidPassword
+func (uc *UserUseCase) ChangeExpiredPassword(ctx context.Context, input ChangeExpiredPasswordInput) (*AuthenticateUserOutput, error) {
+	if !uc.PasswordExpirationEnabled() {
+		return nil, ErrPasswordExpirationDisabled
+	}
+
+	login := AuthenticateUserInput{
+		Email:     input.Email,
+		Username:  input.Username,
+		Password:  input.CurrentPassword,
+		ClientIP:  input.ClientIP,
+		RequestID: input.RequestID,
+		UserAgent: input.UserAgent,
+	}
+
+	var err error
+	if login.Username != "" {
+		err = uc.resolveUsername(ctx, &login)
+		if errors.Is(err, user.ErrUserNotFound) {
+			user.SimulatePasswordCheck(login.Password)
+		}
+	}
+
+	var u *user.User
+	if err == nil {
+		login.Email = user.NormalizeEmail(login.Email)
+		u, err = uc.checkLocalPassword(ctx, login.Email, login.Password)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			uc.recordLogin(ctx, login, LoginResultNotFound)
+			return nil, user.ErrInvalidPassword
+		case errors.Is(err, user.ErrUserDeactivated):
+			uc.recordLogin(ctx, login, LoginResultDeactivated)
+			return nil, user.ErrUserDeactivated
+		case errors.Is(err, user.ErrInvalidPassword):
+			uc.recordLogin(ctx, login, LoginResultInvalidPassword)
+			return nil, user.ErrInvalidPassword
+		}
+		uc.recordLogin(ctx, login, LoginResultError)
+		return nil, err
+	}
+
+	if input.NewPassword == input.CurrentPassword {
+		return nil, ErrPasswordUnchanged
+	}
+
+	// Grava só a senha, condicionada ao hash conferido: um Update do registro
+	// inteiro sobrescreveria alterações concorrentes (papel, status, email)
+	previous := u.Password
+	if err := u.ChangePassword(input.NewPassword); err != nil {
+		return nil, fmt.Errorf("failed to set password: %w", err)
+	}
+	replaced, err := uc.userRepo.ReplacePassword(ctx, u, previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user in repository: %w", err)
+	}
+	if !replaced {
+		// A senha conferida foi trocada por outra operação e não vale mais
+		uc.recordLogin(ctx, login, LoginResultInvalidPassword)
+		return nil, user.ErrInvalidPassword
+	}
+
+	// A nova senha já está gravada: falhar aqui deixaria o cliente sem token e
+	// sem a senha antiga, então o erro é registrado e o login concluído
+	if err := uc.RevokeSessions(ctx, RevokeSessionsInput{UserID: u.ID}); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to revoke sessions after password change", "user_id", u.ID, "error", err)
+	}
+
+	uc.logger.InfoContext(ctx, "auth.password_changed", "user_id", u.ID, "reason", "expired")
+
+	return uc.issueLoginToken(ctx, login, u)
+}
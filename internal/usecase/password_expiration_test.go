@@ -0,0 +1,157 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordExpiration(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	maxAge := 90 * 24 * time.Hour
+
+	// newExpiringUser cria um usuário cuja senha foi trocada em start
+	newExpiringUser := func(t *testing.T) *user.User {
+		u := newTestUser(t, "password123")
+		changed := start
+		u.PasswordChangedAt = &changed
+		return u
+	}
+
+	t.Run("advancing the clock past the max age blocks the login", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithClock(fake), usecase.WithPasswordMaxAge(maxAge))
+		u := newExpiringUser(t)
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		repo.On("RecordLogin", ctx, u.ID, mock.Anything).Return(nil).Once()
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil).Once()
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		fake.Advance(maxAge - time.Minute)
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		require.NoError(t, err)
+
+		fake.Advance(time.Minute)
+		_, err = uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		assert.ErrorIs(t, err, user.ErrPasswordExpired)
+		jwtService.AssertNumberOfCalls(t, "GenerateToken", 1)
+	})
+
+	t.Run("a wrong password is reported before the expiration", func(t *testing.T) {
+		fake := clock.NewFake(start.Add(2 * maxAge))
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithClock(fake), usecase.WithPasswordMaxAge(maxAge))
+		u := newExpiringUser(t)
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "wrong-password"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+	})
+
+	t.Run("changing the expired password restarts the count and logs in", func(t *testing.T) {
+		now := start.Add(2 * maxAge)
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithClock(clock.NewFake(now)), usecase.WithPasswordMaxAge(maxAge))
+		u := newExpiringUser(t)
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		previous := u.Password
+		repo.On("ReplacePassword", ctx, u, previous).Return(true, nil).Once()
+		repo.On("IncrementTokenVersion", ctx, u.ID).Return(2, nil).Once()
+		repo.On("RecordLogin", ctx, u.ID, now).Return(nil).Once()
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.ChangeExpiredPassword(ctx, usecase.ChangeExpiredPasswordInput{
+			Email:           u.Email,
+			CurrentPassword: "password123",
+			NewPassword:     "new-password456",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+		assert.True(t, u.CheckPassword("new-password456"))
+		assert.False(t, u.PasswordExpired(now, maxAge))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a concurrent password change is not overwritten", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithClock(clock.NewFake(start.Add(2*maxAge))), usecase.WithPasswordMaxAge(maxAge))
+		u := newExpiringUser(t)
+		previous := u.Password
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		repo.On("ReplacePassword", ctx, u, previous).Return(false, nil).Once()
+
+		_, err := uc.ChangeExpiredPassword(ctx, usecase.ChangeExpiredPasswordInput{
+			Email:           u.Email,
+			CurrentPassword: "password123",
+			NewPassword:     "new-password456",
+		})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "IncrementTokenVersion", mock.Anything, mock.Anything)
+		jwtService.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failed session revocation still completes the login", func(t *testing.T) {
+		now := start.Add(2 * maxAge)
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithClock(clock.NewFake(now)), usecase.WithPasswordMaxAge(maxAge))
+		u := newExpiringUser(t)
+		previous := u.Password
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		repo.On("ReplacePassword", ctx, u, previous).Return(true, nil).Once()
+		repo.On("IncrementTokenVersion", ctx, u.ID).Return(0, errors.New("connection reset")).Once()
+		repo.On("RecordLogin", ctx, u.ID, now).Return(nil).Once()
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.ChangeExpiredPassword(ctx, usecase.ChangeExpiredPasswordInput{
+			Email:           u.Email,
+			CurrentPassword: "password123",
+			NewPassword:     "new-password456",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("the new password must differ from the current one", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithClock(clock.NewFake(start)), usecase.WithPasswordMaxAge(maxAge))
+		u := newExpiringUser(t)
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+
+		_, err := uc.ChangeExpiredPassword(ctx, usecase.ChangeExpiredPasswordInput{
+			Email:           u.Email,
+			CurrentPassword: "password123",
+			NewPassword:     "password123",
+		})
+		assert.ErrorIs(t, err, usecase.ErrPasswordUnchanged)
+		repo.AssertNotCalled(t, "ReplacePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		assert.False(t, uc.PasswordExpirationEnabled())
+
+		_, err := uc.ChangeExpiredPassword(ctx, usecase.ChangeExpiredPasswordInput{Email: "test@example.com"})
+		assert.ErrorIs(t, err, usecase.ErrPasswordExpirationDisabled)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+}
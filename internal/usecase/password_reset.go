@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// ErrPasswordResetDisabled indica que WithPasswordReset não foi configurado
+var ErrPasswordResetDisabled = errors.New("password reset is not configured")
+
+// PasswordResetNotifier envia o link de redefinição de senha. token é o valor
+// em claro a embutir no link; apenas o hash é persistido
+type PasswordResetNotifier interface {
+	SendPasswordResetEmail(ctx context.Context, to, name, token string) error
+}
+
+// WithPasswordReset habilita a redefinição de senha por email. issuer define o
+// tamanho, a validade e o máximo de confirmações dos tokens;
+// app.NewResetTokenIssuer o cria a partir da configuração de security
+func WithPasswordReset(issuer *auth.ResetTokenIssuer, notifier PasswordResetNotifier) Option {
+	return func(uc *UserUseCase) {
+		uc.resetTokens = issuer
+		uc.resetNotifier = notifier
+	}
+}
+
+// PasswordResetEnabled informa se a redefinição de senha foi configurada
+func (uc *UserUseCase) PasswordResetEnabled() bool {
+	return uc.resetTokens != nil && uc.resetNotifier != nil
+}
+
+// RequestPasswordReset envia um link de redefinição se email pertence a uma
+// conta ativa com senha local, invalidando o link anterior. Não retorna erro
+// nem indica se a conta existe, para não permitir a enumeração de emails: a
+// busca e o envio rodam em segundo plano, de modo que o tempo da resposta
+// também não depende da conta. Falhas são registradas em log; Wait aguarda os
+// envios pendentes
+func (uc *UserUseCase) RequestPasswordReset(ctx context.Context, email string) {
+	if !uc.PasswordResetEnabled() {
+		return
+	}
+
+	// O envio sobrevive ao fim da requisição, mas mantém os valores do contexto
+	ctx = context.WithoutCancel(ctx)
+	uc.background.Add(1)
+	go func() {
+		defer uc.background.Done()
+		uc.requestPasswordReset(ctx, email)
+	}()
+}
+
+// requestPasswordReset é a parte em segundo plano de RequestPasswordReset
+func (uc *UserUseCase) requestPasswordReset(ctx context.Context, email string) {
+	u, err := uc.userRepo.GetByEmail(ctx, user.NormalizeEmail(email))
+	if err != nil {
+		if !errors.Is(err, user.ErrUserNotFound) {
+			uc.logger.ErrorContext(ctx, "failed to send password reset email", "email_hash", HashIdentifier(email), "error", err)
+		}
+		return
+	}
+	if !u.IsActiveUser() || !u.HasLocalPassword() {
+		return
+	}
+
+	plain, issued, err := uc.resetTokens.Issue()
+	if err == nil {
+		err = uc.userRepo.ReplacePasswordResetToken(ctx, &user.PasswordResetToken{
+			Hash:      issued.Hash,
+			UserID:    u.ID,
+			ExpiresAt: issued.ExpiresAt,
+			CreatedAt: uc.clock.Now(),
+		})
+	}
+	if err == nil {
+		err = uc.resetNotifier.SendPasswordResetEmail(ctx, u.Email, u.Name, plain)
+	}
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "failed to send password reset email", "user_id", u.ID, "error", err)
+		return
+	}
+	uc.logger.InfoContext(ctx, "user.password_reset_requested", "user_id", u.ID)
+}
+
+// ConfirmPasswordResetInput representa a confirmação da redefinição de senha
+// com o token recebido por email
+type ConfirmPasswordResetInput struct {
+	Email       string `json:"email"`
+	Token       string `json:"-"`
+	NewPassword string `json:"-"`
+}
+
+// ConfirmPasswordReset troca a senha se o token confere com o pendente da
+// conta. Cada confirmação é contada no armazenamento antes da comparação;
+// esgotado o máximo do issuer, o token é descartado e o retorno é
+// auth.ErrResetTokenExhausted, mesmo para o token correto. Tokens expirados
+// retornam auth.ErrResetTokenExpired; os demais casos, inclusive conta
+// inexistente, retornam auth.ErrResetTokenInvalid. Com sucesso, o token é
+// consumido e todas as sessões são invalidadas. Senhas fora da política
+// retornam o erro de validação sem contar tentativa
+func (uc *UserUseCase) ConfirmPasswordReset(ctx context.Context, input ConfirmPasswordResetInput) error {
+	if !uc.PasswordResetEnabled() {
+		return ErrPasswordResetDisabled
+	}
+	if input.Token == "" {
+		return auth.ErrResetTokenInvalid
+	}
+
+	u, err := uc.userRepo.GetByEmail(ctx, user.NormalizeEmail(input.Email))
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return auth.ErrResetTokenInvalid
+		}
+		return fmt.Errorf("failed to get user for password reset: %w", err)
+	}
+	if !u.IsActiveUser() || !u.HasLocalPassword() {
+		return auth.ErrResetTokenInvalid
+	}
+
+	// A nova senha é validada antes do token, para que uma senha recusada não
+	// consuma tentativas nem o próprio token
+	previous := u.Password
+	if err := u.ChangePassword(input.NewPassword); err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	stored, err := uc.userRepo.GetPasswordResetToken(ctx, u.ID)
+	if err != nil {
+		if errors.Is(err, user.ErrPasswordResetTokenNotFound) {
+			return auth.ErrResetTokenInvalid
+		}
+		return fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	err = uc.resetTokens.Confirm(ctx, input.Token, auth.ResetToken{
+		Hash:      stored.Hash,
+		ExpiresAt: stored.ExpiresAt,
+		Attempts:  stored.Attempts,
+	}, uc.userRepo)
+	switch {
+	case err == nil:
+	case errors.Is(err, user.ErrPasswordResetTokenNotFound):
+		// Usado ou substituído por outra requisição depois da leitura
+		return auth.ErrResetTokenInvalid
+	case errors.Is(err, auth.ErrResetTokenExhausted), errors.Is(err, auth.ErrResetTokenExpired):
+		uc.discardResetToken(ctx, stored)
+		if errors.Is(err, auth.ErrResetTokenExhausted) {
+			uc.logger.WarnContext(ctx, "user.password_reset_exhausted", "user_id", u.ID)
+		}
+		return err
+	default:
+		return err
+	}
+
+	// Uso único: entre confirmações corretas concorrentes, só uma remove o token
+	if err := uc.userRepo.ConsumePasswordResetToken(ctx, stored.Hash); err != nil {
+		if errors.Is(err, user.ErrPasswordResetTokenNotFound) {
+			return auth.ErrResetTokenInvalid
+		}
+		return err
+	}
+
+	// Grava só a senha, como ChangeExpiredPassword: um Update do registro
+	// inteiro sobrescreveria alterações concorrentes (papel, status, email)
+	replaced, err := uc.userRepo.ReplacePassword(ctx, u, previous)
+	if err != nil {
+		return fmt.Errorf("failed to update user in repository: %w", err)
+	}
+	if !replaced {
+		// A senha mudou depois da leitura; o token já foi consumido
+		return auth.ErrResetTokenInvalid
+	}
+
+	// A nova senha já está gravada e o token consumido, então uma falha aqui é
+	// apenas registrada
+	if err := uc.RevokeSessions(ctx, RevokeSessionsInput{UserID: u.ID}); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to revoke sessions after password change", "user_id", u.ID, "error", err)
+	}
+
+	uc.logger.InfoContext(ctx, "auth.password_changed", "user_id", u.ID, "reason", "reset")
+	return nil
+}
+
+// discardResetToken remove um token que não será mais aceito. A contagem
+// persistida já o rejeita, então uma falha é apenas registrada
+func (uc *UserUseCase) discardResetToken(ctx context.Context, stored *user.PasswordResetToken) {
+	err := uc.userRepo.ConsumePasswordResetToken(ctx, stored.Hash)
+	if err != nil && !errors.Is(err, user.ErrPasswordResetTokenNotFound) {
+		uc.logger.ErrorContext(ctx, "failed to discard password reset token", "user_id", stored.UserID, "error", err)
+	}
+}
@@ -0,0 +1,168 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/clock"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureResetNotifier guarda o último link de redefinição enviado
+type captureResetNotifier struct {
+	to, token string
+}
+
+func (n *captureResetNotifier) SendPasswordResetEmail(_ context.Context, to, _, token string) error {
+	n.to, n.token = to, token
+	return nil
+}
+
+func newPasswordResetUseCase(t *testing.T, c clock.Clock, notifier usecase.PasswordResetNotifier) (*usecase.UserUseCase, *mocks.UserRepository) {
+	t.Helper()
+	issuer, err := auth.NewResetTokenIssuer(0, time.Hour, c, auth.WithResetMaxAttempts(3))
+	require.NoError(t, err)
+	repo := &mocks.UserRepository{}
+	return usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithClock(c), usecase.WithPasswordReset(issuer, notifier)), repo
+}
+
+func resetUser(t *testing.T) *user.User {
+	t.Helper()
+	u := &user.User{ID: "42", Email: "ana@example.com", Name: "Ana", IsActive: true}
+	require.NoError(t, u.SetPassword("old-password"))
+	return u
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	t.Run("active local account gets a link", func(t *testing.T) {
+		notifier := &captureResetNotifier{}
+		uc, repo := newPasswordResetUseCase(t, clock.System, notifier)
+		repo.On("GetByEmail", mock.Anything, "ana@example.com").Return(resetUser(t), nil)
+		var stored *user.PasswordResetToken
+		repo.On("ReplacePasswordResetToken", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*user.PasswordResetToken)
+		})
+
+		uc.RequestPasswordReset(context.Background(), "Ana@Example.com")
+		uc.Wait()
+
+		assert.Equal(t, "ana@example.com", notifier.to)
+		// Apenas o hash do token enviado é persistido
+		require.NotNil(t, stored)
+		assert.Equal(t, "42", stored.UserID)
+		assert.Equal(t, auth.HashResetToken(notifier.token), stored.Hash)
+	})
+
+	t.Run("accounts without a local password are skipped", func(t *testing.T) {
+		notifier := &captureResetNotifier{}
+		uc, repo := newPasswordResetUseCase(t, clock.System, notifier)
+		// A anonimização deixa a senha inutilizável; a conta é reativada para
+		// isolar a checagem da senha local
+		anonymized := resetUser(t)
+		anonymized.Anonymize()
+		anonymized.IsActive = true
+		repo.On("GetByEmail", mock.Anything, "ana@example.com").Return(anonymized, nil)
+
+		uc.RequestPasswordReset(context.Background(), "ana@example.com")
+		uc.Wait()
+
+		assert.Empty(t, notifier.to)
+		repo.AssertNotCalled(t, "ReplacePasswordResetToken", mock.Anything, mock.Anything)
+	})
+}
+
+func TestConfirmPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	hash := auth.HashResetToken("plain-token")
+	stored := &user.PasswordResetToken{Hash: hash, UserID: "42", ExpiresAt: now.Add(time.Hour)}
+	input := usecase.ConfirmPasswordResetInput{Email: "ana@example.com", Token: "plain-token", NewPassword: "new-password"}
+
+	t.Run("valid token sets the password and revokes sessions", func(t *testing.T) {
+		uc, repo := newPasswordResetUseCase(t, clock.NewFake(now), &captureResetNotifier{})
+		u := resetUser(t)
+		previous := u.Password
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(u, nil)
+		repo.On("GetPasswordResetToken", ctx, "42").Return(stored, nil)
+		repo.On("IncrementResetAttempts", ctx, hash).Return(1, nil)
+		repo.On("ConsumePasswordResetToken", ctx, hash).Return(nil).Once()
+		repo.On("ReplacePassword", ctx, u, previous).Return(true, nil)
+		repo.On("IncrementTokenVersion", ctx, "42").Return(2, nil)
+
+		require.NoError(t, uc.ConfirmPasswordReset(ctx, input))
+		assert.True(t, u.CheckPassword("new-password"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("token is discarded once attempts are exhausted", func(t *testing.T) {
+		uc, repo := newPasswordResetUseCase(t, clock.NewFake(now), &captureResetNotifier{})
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(resetUser(t), nil)
+		repo.On("GetPasswordResetToken", ctx, "42").Return(stored, nil)
+		// O contador persistido avança a cada confirmação
+		for n := 1; n <= 4; n++ {
+			repo.On("IncrementResetAttempts", ctx, hash).Return(n, nil).Once()
+		}
+		repo.On("ConsumePasswordResetToken", ctx, hash).Return(nil)
+
+		wrong := input
+		wrong.Token = "guess"
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, wrong), auth.ErrResetTokenInvalid)
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, wrong), auth.ErrResetTokenInvalid)
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, wrong), auth.ErrResetTokenExhausted)
+
+		// Nem o token correto é aceito depois do máximo
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, input), auth.ErrResetTokenExhausted)
+		repo.AssertNotCalled(t, "ReplacePassword", mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertCalled(t, "ConsumePasswordResetToken", ctx, hash)
+	})
+
+	t.Run("expired token is discarded", func(t *testing.T) {
+		uc, repo := newPasswordResetUseCase(t, clock.NewFake(now.Add(2*time.Hour)), &captureResetNotifier{})
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(resetUser(t), nil)
+		repo.On("GetPasswordResetToken", ctx, "42").Return(stored, nil)
+		repo.On("IncrementResetAttempts", ctx, hash).Return(1, nil)
+		repo.On("ConsumePasswordResetToken", ctx, hash).Return(nil).Once()
+
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, input), auth.ErrResetTokenExpired)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("token used by a concurrent confirmation", func(t *testing.T) {
+		uc, repo := newPasswordResetUseCase(t, clock.NewFake(now), &captureResetNotifier{})
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(resetUser(t), nil)
+		repo.On("GetPasswordResetToken", ctx, "42").Return(stored, nil)
+		repo.On("IncrementResetAttempts", ctx, hash).Return(1, nil)
+		repo.On("ConsumePasswordResetToken", ctx, hash).Return(user.ErrPasswordResetTokenNotFound)
+
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, input), auth.ErrResetTokenInvalid)
+		repo.AssertNotCalled(t, "ReplacePassword", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown email or no pending token", func(t *testing.T) {
+		uc, repo := newPasswordResetUseCase(t, clock.NewFake(now), &captureResetNotifier{})
+		repo.On("GetByEmail", ctx, "ghost@example.com").Return(nil, user.ErrUserNotFound)
+		repo.On("GetByEmail", ctx, "ana@example.com").Return(resetUser(t), nil)
+		repo.On("GetPasswordResetToken", ctx, "42").Return(nil, user.ErrPasswordResetTokenNotFound)
+
+		ghost := input
+		ghost.Email = "ghost@example.com"
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, ghost), auth.ErrResetTokenInvalid)
+		assert.ErrorIs(t, uc.ConfirmPasswordReset(ctx, input), auth.ErrResetTokenInvalid)
+		repo.AssertNotCalled(t, "IncrementResetAttempts", mock.Anything, mock.Anything)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		uc, _, _ := newTestUseCase()
+		assert.False(t, uc.PasswordResetEnabled())
+		assert.True(t, errors.Is(uc.ConfirmPasswordReset(ctx, input), usecase.ErrPasswordResetDisabled))
+	})
+}
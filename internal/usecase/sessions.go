@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go-api-boilerplate/internal/domain/auth"
+)
+
+// WithSessions habilita a listagem e a revogação das sessões do próprio
+// usuário. Use o mesmo store passado a auth.WithSessions no JWTService
+func WithSessions(store auth.SessionStore) Option {
+	return func(uc *UserUseCase) {
+		uc.sessions = store
+	}
+}
+
+// SessionsEnabled informa se o controle de sessões foi configurado
+func (uc *UserUseCase) SessionsEnabled() bool {
+	return uc.sessions != nil
+}
+
+// sessionTokenOptions guarda o dispositivo do login na sessão do token emitido;
+// sem WithSessions, nenhuma opção é passada ao JWTService
+func (uc *UserUseCase) sessionTokenOptions(userAgent, clientIP string) []auth.TokenOption {
+	if uc.sessions == nil {
+		return nil
+	}
+	return []auth.TokenOption{auth.WithSessionMetadata(userAgent, clientIP)}
+}
+
+// discardSessions remove as sessões de uma conta excluída, anonimizada ou
+// rebaixada, para que a listagem não mostre sessões mortas. Os tokens já são
+// rejeitados pela exclusão ou pelo token_version, então uma falha do store é
+// apenas registrada
+func (uc *UserUseCase) discardSessions(ctx context.Context, userID string) {
+	if uc.sessions == nil {
+		return
+	}
+	if err := uc.sessions.RemoveAll(ctx, userID); err != nil {
+		uc.logger.ErrorContext(ctx, "failed to discard sessions", "user_id", userID, "error", err)
+	}
+}
+
+// ListSessionsInput representa os dados de entrada para listar sessões
+type ListSessionsInput struct {
+	UserID string `json:"user_id"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// ListSessionsOutput representa uma página das sessões ativas, da mais recente
+// para a mais antiga, e o total de sessões ativas
+type ListSessionsOutput struct {
+	Sessions []auth.Session `json:"sessions"`
+	Total    int64          `json:"total"`
+	Offset   int            `json:"offset"`
+	Limit    int            `json:"limit"`
+}
+
+// ListSessions lista as sessões ativas do usuário com paginação. Sessões
+// emitidas no mesmo instante são desempatadas pelo ID, para que as páginas
+// sejam estáveis
+func (uc *UserUseCase) ListSessions(ctx context.Context, input ListSessionsInput) (*ListSessionsOutput, error) {
+	offset, limit := normalizePage(input.Offset, input.Limit)
+
+	sessions, err := uc.sessions.List(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].IssuedAt.Equal(sessions[j].IssuedAt) {
+			return sessions[i].IssuedAt.After(sessions[j].IssuedAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+
+	page := []auth.Session{}
+	if offset < len(sessions) {
+		page = sessions[offset:min(offset+limit, len(sessions))]
+	}
+
+	return &ListSessionsOutput{
+		Sessions: page,
+		Total:    int64(len(sessions)),
+		Offset:   offset,
+		Limit:    limit,
+	}, nil
+}
+
+// RevokeSessionInput representa os dados de entrada para revogar uma sessão
+type RevokeSessionInput struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+}
+
+// RevokeSession encerra uma sessão do usuário; o token dela passa a ser
+// rejeitado na validação
+func (uc *UserUseCase) RevokeSession(ctx context.Context, input RevokeSessionInput) error {
+	if err := uc.sessions.Remove(ctx, input.UserID, input.SessionID); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/infrastructure/sessions"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeSessionsClearsSessionStore(t *testing.T) {
+	ctx := context.Background()
+	store := sessions.NewMemoryStore()
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithSessions(store))
+	repo.On("IncrementTokenVersion", ctx, "42").Return(3, nil)
+
+	now := time.Now()
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "a", UserID: "42", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "b", UserID: "42", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	require.NoError(t, uc.RevokeSessions(ctx, usecase.RevokeSessionsInput{UserID: "42"}))
+
+	output, err := uc.ListSessions(ctx, usecase.ListSessionsInput{UserID: "42"})
+	require.NoError(t, err)
+	assert.NotNil(t, output.Sessions)
+	assert.Empty(t, output.Sessions)
+	assert.ErrorIs(t, uc.RevokeSession(ctx, usecase.RevokeSessionInput{UserID: "42", SessionID: "a"}), auth.ErrSessionNotFound)
+}
+
+func TestListSessionsPagination(t *testing.T) {
+	ctx := context.Background()
+	store := sessions.NewMemoryStore()
+	uc := usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}, usecase.WithSessions(store))
+
+	// 23 sessões, as duas últimas emitidas no mesmo instante
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 23; i++ {
+		issuedAt := base.Add(time.Duration(min(i, 21)) * time.Minute)
+		require.NoError(t, store.Add(ctx, auth.Session{
+			ID:        fmt.Sprintf("s%02d", i),
+			UserID:    "42",
+			IssuedAt:  issuedAt,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}))
+	}
+	require.NoError(t, store.Add(ctx, auth.Session{ID: "other", UserID: "7", IssuedAt: base, ExpiresAt: time.Now().Add(time.Hour)}))
+
+	var seen []string
+	for offset := 0; offset < 30; offset += 10 {
+		output, err := uc.ListSessions(ctx, usecase.ListSessionsInput{UserID: "42", Offset: offset, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(23), output.Total)
+		assert.Equal(t, offset, output.Offset)
+		assert.Equal(t, 10, output.Limit)
+		for _, s := range output.Sessions {
+			seen = append(seen, s.ID)
+		}
+	}
+
+	// Mais recentes primeiro; o empate é desfeito pelo ID, sem repetir nem pular sessões
+	require.Len(t, seen, 23)
+	assert.Equal(t, []string{"s21", "s22", "s20"}, seen[:3])
+	assert.Equal(t, "s00", seen[22])
+
+	output, err := uc.ListSessions(ctx, usecase.ListSessionsInput{UserID: "42", Offset: 40, Limit: 10})
+	require.NoError(t, err)
+	assert.NotNil(t, output.Sessions)
+	assert.Empty(t, output.Sessions)
+	assert.Equal(t, int64(23), output.Total)
+}
@@ -0,0 +1,100 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUserFieldPermissions(t *testing.T) {
+	ctx := context.Background()
+	admin := user.RoleAdmin
+
+	t.Run("user changing own role", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		self := newTestUser(t, "password123")
+		repo.On("GetByID", ctx, self.ID).Return(self, nil)
+
+		_, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{
+			ID: self.ID, Role: &admin, ActorID: self.ID, ActorRole: user.RoleUser,
+		})
+		require.ErrorIs(t, err, user.ErrFieldUpdateForbidden)
+		assert.Contains(t, err.Error(), `"role"`)
+		assert.Equal(t, user.RoleUser, self.Role)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("user changing own name", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		self := newTestUser(t, "password123")
+		name := "New Name"
+		// Reenviar o papel atual não é uma alteração
+		role := user.RoleUser
+		repo.On("GetByID", ctx, self.ID).Return(self, nil)
+		repo.On("Update", ctx, self).Return(nil)
+
+		output, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{
+			ID: self.ID, Name: &name, Role: &role, ActorID: self.ID, ActorRole: user.RoleUser,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, name, output.User.Name)
+	})
+
+	t.Run("user updating another user", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		name := "New Name"
+
+		_, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{
+			ID: "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60", Name: &name, ActorID: "other", ActorRole: user.RoleUser,
+		})
+		require.ErrorIs(t, err, user.ErrFieldUpdateForbidden)
+		// Rejeitado antes da busca, sem revelar se o usuário existe
+		repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin deactivating a user", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		target := newTestUser(t, "password123")
+		inactive := false
+		repo.On("GetByID", ctx, target.ID).Return(target, nil)
+		repo.On("Update", ctx, target).Return(nil)
+		repo.On("IncrementTokenVersion", ctx, target.ID).Return(2, nil)
+
+		output, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{
+			ID: target.ID, Role: &admin, IsActive: &inactive, ActorID: "admin-id", ActorRole: user.RoleAdmin,
+		})
+		require.NoError(t, err)
+		assert.False(t, output.User.IsActive)
+		assert.Equal(t, user.RoleAdmin, output.User.Role)
+		repo.AssertCalled(t, "IncrementTokenVersion", ctx, target.ID)
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		policy := user.UpdatePolicy{user.RoleGuest: {user.FieldName: user.UpdateScopeSelf}}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithUpdatePolicy(policy))
+		self := newTestUser(t, "password123")
+		self.Role = user.RoleGuest
+		name := "Guest Name"
+		repo.On("GetByID", ctx, self.ID).Return(self, nil)
+		repo.On("Update", ctx, self).Return(nil)
+
+		_, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{
+			ID: self.ID, Name: &name, ActorID: self.ID, ActorRole: user.RoleGuest,
+		})
+		require.NoError(t, err)
+
+		// Sem linha na matriz, admins não alteram nada
+		_, err = uc.UpdateUser(ctx, usecase.UpdateUserInput{
+			ID: self.ID, Name: &name, ActorID: "admin-id", ActorRole: user.RoleAdmin,
+		})
+		assert.ErrorIs(t, err, user.ErrFieldUpdateForbidden)
+	})
+}
@@ -2,25 +2,87 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
 
 	"go-api-boilerplate/internal/domain/auth"
 	"go-api-boilerplate/internal/domain/repository"
 	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/pkg/clock"
+)
+
+var (
+	// ErrEmptySearchQuery indica uma busca sem termo
+	ErrEmptySearchQuery = errors.New("search query cannot be empty")
+	// ErrInvalidDateRange indica um intervalo com início posterior ao fim
+	ErrInvalidDateRange = errors.New("from must not be after to")
 )
 
 // UserUseCase implementa os casos de uso relacionados a usuários
 type UserUseCase struct {
 	userRepo   repository.UserRepository
 	jwtService auth.JWTService
+	metrics    Metrics
+	events     user.EventPublisher
+	logger     *slog.Logger
+	clock      clock.Clock
+
+	exportBatchSize int
+	exportMaxRows   int
+	maxBatchGetIDs  int
+	maxSearchLimit  int
+	deletionPolicy  string
+	emailDomains    user.EmailDomainRules
+	emailPolicy     user.EmailPolicy
+	authenticator   Authenticator
+
+	verificationTokens   *auth.ResetTokenIssuer
+	verificationNotifier VerificationNotifier
+
+	resetTokens   *auth.ResetTokenIssuer
+	resetNotifier PasswordResetNotifier
+
+	usernamesEnabled bool
+	adminBootstrap   bool
+	adminDowngrade   AdminDowngradePolicy
+	passwordMaxAge   time.Duration
+	updatePolicy     user.UpdatePolicy
+
+	sessions auth.SessionStore
+
+	// background acompanha os envios em segundo plano (ver Wait)
+	background sync.WaitGroup
 }
 
 // NewUserUseCase cria uma nova instância de UserUseCase
-func NewUserUseCase(userRepo repository.UserRepository, jwtService auth.JWTService) *UserUseCase {
-	return &UserUseCase{
+func NewUserUseCase(userRepo repository.UserRepository, jwtService auth.JWTService, opts ...Option) *UserUseCase {
+	uc := &UserUseCase{
 		userRepo:   userRepo,
 		jwtService: jwtService,
+		metrics:    noopMetrics{},
+		events:     noopPublisher{},
+		logger:     slog.New(discardHandler{}),
+		clock:      clock.System,
+
+		exportBatchSize: DefaultExportBatchSize,
+		exportMaxRows:   DefaultExportMaxRows,
+		maxBatchGetIDs:  DefaultMaxBatchGetIDs,
+		maxSearchLimit:  DefaultMaxSearchLimit,
+		deletionPolicy:  DeletionPolicyDelete,
+		emailPolicy:     noopEmailPolicy{},
+		updatePolicy:    user.DefaultUpdatePolicy(),
+	}
+	uc.authenticator = localAuthenticator{uc: uc}
+
+	for _, opt := range opts {
+		opt(uc)
 	}
+
+	return uc
 }
 
 // CreateUserInput representa os dados de entrada para criação de usuário
@@ -29,6 +91,8 @@ type CreateUserInput struct {
 	Password string    `json:"password"`
 	Name     string    `json:"name"`
 	Role     user.Role `json:"role"`
+	// Username é opcional e exige WithUsernames
+	Username string `json:"username,omitempty"`
 }
 
 // CreateUserOutput representa os dados de saída da criação de usuário
@@ -38,28 +102,83 @@ type CreateUserOutput struct {
 
 // CreateUser cria um novo usuário
 func (uc *UserUseCase) CreateUser(ctx context.Context, input CreateUserInput) (*CreateUserOutput, error) {
-	// Verifica se o email já existe
-	exists, err := uc.userRepo.ExistsByEmail(ctx, input.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check email existence: %w", err)
+	if err := uc.CheckEmailAvailable(ctx, input.Email); err != nil {
+		return nil, err
 	}
-
-	if exists {
-		return nil, user.ErrUserAlreadyExists
+	if input.Username != "" {
+		if err := uc.CheckUsernameAvailable(ctx, input.Username); err != nil {
+			return nil, err
+		}
 	}
 
 	// Cria a entidade User
-	user, err := user.NewUser(input.Email, input.Password, input.Name, input.Role)
+	newUser, err := user.NewUser(input.Email, input.Password, input.Name, input.Role)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user entity: %w", err)
 	}
+	if input.Username != "" {
+		if err := newUser.SetUsername(input.Username); err != nil {
+			return nil, fmt.Errorf("failed to create user entity: %w", err)
+		}
+	}
 
 	// Persiste no repositório
-	if err := uc.userRepo.Create(ctx, user); err != nil {
+	if err := uc.userRepo.Create(ctx, newUser); err != nil {
 		return nil, fmt.Errorf("failed to create user in repository: %w", err)
 	}
 
-	return &CreateUserOutput{User: user}, nil
+	uc.metrics.UserCreated()
+	uc.publish(ctx, user.NewEvent(user.EventUserCreated, newUser))
+
+	// Falhas no envio não desfazem o cadastro: o usuário pode pedir o reenvio
+	if uc.EmailVerificationEnabled() {
+		if err := uc.sendVerification(ctx, newUser, newUser.Email); err != nil {
+			uc.logger.ErrorContext(ctx, "failed to send verification email", "user_id", newUser.ID, "error", err)
+		}
+	}
+
+	return &CreateUserOutput{User: newUser}, nil
+}
+
+// CheckEmailAvailable aplica ao email normalizado as regras de domínio, a
+// EmailPolicy e a verificação de email já cadastrado, as mesmas regras de
+// CreateUser. Permite reportar essas falhas junto com as de validação
+func (uc *UserUseCase) CheckEmailAvailable(ctx context.Context, email string) error {
+	email = user.NormalizeEmail(email)
+	if err := uc.checkEmail(ctx, email); err != nil {
+		return err
+	}
+
+	exists, err := uc.userRepo.ExistsByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to check email existence: %w", err)
+	}
+	if exists {
+		return user.NewDomainError(user.ErrUserAlreadyExists, "email", email)
+	}
+
+	return nil
+}
+
+// RegisterUserInput representa os dados de entrada do autorregistro público.
+// Não possui role: usuários registrados publicamente sempre recebem RoleUser
+type RegisterUserInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
+}
+
+// RegisterUser registra um novo usuário com o papel padrão. A atribuição
+// de outros papéis fica restrita a admins autenticados via CreateUser
+func (uc *UserUseCase) RegisterUser(ctx context.Context, input RegisterUserInput) (*CreateUserOutput, error) {
+	return uc.CreateUser(ctx, CreateUserInput{
+		Email:    input.Email,
+		Password: input.Password,
+		Name:     input.Name,
+		Role:     user.RoleUser,
+		Username: input.Username,
+	})
 }
 
 // GetUserByIDInput representa os dados de entrada para busca de usuário por ID
@@ -76,9 +195,9 @@ type GetUserByIDOutput struct {
 func (uc *UserUseCase) GetUserByID(ctx context.Context, input GetUserByIDInput) (*GetUserByIDOutput, error) {
 	userEntity, err := uc.userRepo.GetByID(ctx, input.ID)
 	if err != nil {
-		// Propaga erros de domínio sem envolver
-		if err == user.ErrUserNotFound {
-			return nil, err
+		// Erros de domínio recebem o contexto da busca
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.NewDomainError(err, "id", input.ID)
 		}
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
@@ -98,11 +217,12 @@ type GetUserByEmailOutput struct {
 
 // GetUserByEmail busca um usuário pelo email
 func (uc *UserUseCase) GetUserByEmail(ctx context.Context, input GetUserByEmailInput) (*GetUserByEmailOutput, error) {
-	userEntity, err := uc.userRepo.GetByEmail(ctx, input.Email)
+	email := user.NormalizeEmail(input.Email)
+	userEntity, err := uc.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		// Propaga erros de domínio sem envolver
-		if err == user.ErrUserNotFound {
-			return nil, err
+		// Erros de domínio recebem o contexto da busca
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.NewDomainError(err, "email", email)
 		}
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
@@ -116,6 +236,38 @@ type UpdateUserInput struct {
 	Name  *string    `json:"name,omitempty"`
 	Email *string    `json:"email,omitempty"`
 	Role  *user.Role `json:"role,omitempty"`
+	// Username exige WithUsernames; vazio remove o nome de usuário
+	Username *string `json:"username,omitempty"`
+	// IsActive false desativa a conta e encerra suas sessões, como DeactivateUser
+	IsActive *bool `json:"is_active,omitempty"`
+
+	// ActorID e ActorRole identificam o autor, cujas alterações são verificadas
+	// na matriz de atualização (WithUpdatePolicy). ActorRole vazio indica uma
+	// chamada interna, sem verificação
+	ActorID   string    `json:"-"`
+	ActorRole user.Role `json:"-"`
+}
+
+// changedFields lista os campos informados cujo valor difere do atual, na
+// forma normalizada que será gravada
+func (input UpdateUserInput) changedFields(current *user.User) []string {
+	var fields []string
+	if input.Name != nil && user.NormalizeName(*input.Name) != current.Name {
+		fields = append(fields, user.FieldName)
+	}
+	if input.Email != nil && user.NormalizeEmail(*input.Email) != current.Email {
+		fields = append(fields, user.FieldEmail)
+	}
+	if input.Username != nil && user.NormalizeUsername(*input.Username) != current.Username {
+		fields = append(fields, user.FieldUsername)
+	}
+	if input.Role != nil && *input.Role != current.Role {
+		fields = append(fields, user.FieldRole)
+	}
+	if input.IsActive != nil && *input.IsActive != current.IsActive {
+		fields = append(fields, user.FieldIsActive)
+	}
+	return fields
 }
 
 // UpdateUserOutput representa os dados de saída da atualização de usuário
@@ -123,18 +275,38 @@ type UpdateUserOutput struct {
 	User *user.User `json:"user"`
 }
 
-// UpdateUser atualiza um usuário existente
+// UpdateUser atualiza um usuário existente. Com ActorRole, apenas os campos
+// permitidos pela matriz de atualização podem mudar; reenviar o valor atual de
+// um campo não conta como alteração
 func (uc *UserUseCase) UpdateUser(ctx context.Context, input UpdateUserInput) (*UpdateUserOutput, error) {
+	self := input.ActorID == input.ID
+	// Antes da busca, para não revelar a quem não pode atualizar outros
+	// usuários se o ID existe
+	if input.ActorRole != "" {
+		if err := uc.updatePolicy.Authorize(input.ActorRole, self, nil); err != nil {
+			return nil, err
+		}
+	}
+
 	// Busca o usuário existente
 	dbUser, err := uc.userRepo.GetByID(ctx, input.ID)
 	if err != nil {
-		// Propaga erros de domínio sem envolver
-		if err == user.ErrUserNotFound {
-			return nil, err
+		// Erros de domínio recebem o contexto da busca
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.NewDomainError(err, "id", input.ID)
 		}
 		return nil, fmt.Errorf("failed to get user for update: %w", err)
 	}
 
+	if input.ActorRole != "" {
+		if err := uc.updatePolicy.Authorize(input.ActorRole, self, input.changedFields(dbUser)); err != nil {
+			return nil, err
+		}
+	}
+
+	previousEmail := dbUser.Email
+	deactivated := false
+
 	// Atualiza os campos fornecidos
 	if input.Name != nil {
 		if err := dbUser.UpdateName(*input.Name); err != nil {
@@ -144,18 +316,19 @@ func (uc *UserUseCase) UpdateUser(ctx context.Context, input UpdateUserInput) (*
 
 	if input.Email != nil {
 		// Verifica se o novo email já existe (se for diferente do atual)
-		if *input.Email != dbUser.Email {
-			exists, err := uc.userRepo.ExistsByEmail(ctx, *input.Email)
+		email := user.NormalizeEmail(*input.Email)
+		if email != dbUser.Email {
+			exists, err := uc.userRepo.ExistsByEmail(ctx, email)
 			if err != nil {
 				return nil, fmt.Errorf("failed to check email existence: %w", err)
 			}
 
 			if exists {
-				return nil, user.ErrUserAlreadyExists
+				return nil, user.NewDomainError(user.ErrUserAlreadyExists, "email", email)
 			}
 		}
 
-		if err := dbUser.UpdateEmail(*input.Email); err != nil {
+		if err := dbUser.UpdateEmail(email); err != nil {
 			return nil, fmt.Errorf("failed to update email: %w", err)
 		}
 	}
@@ -166,29 +339,69 @@ func (uc *UserUseCase) UpdateUser(ctx context.Context, input UpdateUserInput) (*
 		}
 	}
 
+	if input.Username != nil {
+		if err := uc.updateUsername(ctx, dbUser, *input.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.IsActive != nil && *input.IsActive != dbUser.IsActive {
+		if *input.IsActive {
+			dbUser.Activate()
+		} else {
+			dbUser.Deactivate()
+			deactivated = true
+		}
+	}
+
 	// Persiste as alterações
 	if err := uc.userRepo.Update(ctx, dbUser); err != nil {
 		return nil, fmt.Errorf("failed to update user in repository: %w", err)
 	}
 
+	if deactivated {
+		if err := uc.RevokeSessions(ctx, RevokeSessionsInput{UserID: dbUser.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.publish(ctx, user.NewEvent(user.EventUserUpdated, dbUser))
+
+	// A troca direta (admin) deixa o novo email não verificado; o link é enviado a ele
+	if uc.EmailVerificationEnabled() && !dbUser.IsEmailVerified() && dbUser.Email != previousEmail {
+		if err := uc.sendVerification(ctx, dbUser, dbUser.Email); err != nil {
+			uc.logger.ErrorContext(ctx, "failed to send verification email", "user_id", dbUser.ID, "error", err)
+		}
+	}
+
 	return &UpdateUserOutput{User: dbUser}, nil
 }
 
 // DeleteUserInput representa os dados de entrada para exclusão de usuário
 type DeleteUserInput struct {
-	ID string `json:"id"`
+	ID      string `json:"id"`
+	ActorID string `json:"-"`
 }
 
-// DeleteUser remove um usuário
+// DeleteUser exclui a conta conforme a política configurada (WithDeletionPolicy):
+// remove a linha ou, com anonymize, delega para AnonymizeUser
 func (uc *UserUseCase) DeleteUser(ctx context.Context, input DeleteUserInput) error {
+	if uc.deletionPolicy == DeletionPolicyAnonymize {
+		return uc.AnonymizeUser(ctx, AnonymizeUserInput{ID: input.ID, ActorID: input.ActorID})
+	}
+
 	// Remove o usuário diretamente - o repositório retornará ErrUserNotFound se não existir
 	if err := uc.userRepo.Delete(ctx, input.ID); err != nil {
-		// Propaga erros de domínio sem envolver
-		if err == user.ErrUserNotFound {
-			return err
+		// Erros de domínio recebem o contexto da operação
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.NewDomainError(err, "id", input.ID)
 		}
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+	uc.discardSessions(ctx, input.ID)
+
+	uc.logger.InfoContext(ctx, "user.deleted", "user_id", input.ID, "actor_id", input.ActorID)
+	uc.publishDeleted(ctx, input.ID)
 
 	return nil
 }
@@ -197,85 +410,353 @@ func (uc *UserUseCase) DeleteUser(ctx context.Context, input DeleteUserInput) er
 type ListUsersInput struct {
 	Offset int `json:"offset"`
 	Limit  int `json:"limit"`
+	// IncludeInactive inclui usuários desativados; deve ser habilitado apenas para admins
+	IncludeInactive bool `json:"include_inactive"`
 }
 
-// ListUsersOutput representa os dados de saída da listagem de usuários
-type ListUsersOutput struct {
-	Users []*user.User `json:"users"`
-	Total int64        `json:"total"`
+// PagedUsers é o resultado comum das consultas paginadas de usuários
+// (listagem, busca e demais filtros)
+type PagedUsers struct {
+	Users  []*user.User `json:"users"`
+	Total  int64        `json:"total"`
+	Offset int          `json:"offset"`
+	Limit  int          `json:"limit"`
 }
 
-// ListUsers lista usuários com paginação
-func (uc *UserUseCase) ListUsers(ctx context.Context, input ListUsersInput) (*ListUsersOutput, error) {
-	// Valida parâmetros de paginação
-	if input.Limit <= 0 {
-		input.Limit = 10 // Default limit
+// newPagedUsers monta um resultado paginado. Users nunca é nil: uma página
+// vazia serializa como [] e não como null
+func newPagedUsers(users []*user.User, total int64, offset, limit int) *PagedUsers {
+	if users == nil {
+		users = []*user.User{}
 	}
+	return &PagedUsers{Users: users, Total: total, Offset: offset, Limit: limit}
+}
 
-	if input.Offset < 0 {
-		input.Offset = 0
+// ListUsers lista usuários com paginação
+func (uc *UserUseCase) ListUsers(ctx context.Context, input ListUsersInput) (*PagedUsers, error) {
+	offset, limit := normalizePage(input.Offset, input.Limit)
+
+	// Por padrão, usuários desativados ficam fora da listagem
+	list, count := uc.userRepo.ListActive, uc.userRepo.CountActive
+	if input.IncludeInactive {
+		list, count = uc.userRepo.List, uc.userRepo.Count
 	}
 
 	// Busca usuários
-	users, err := uc.userRepo.List(ctx, input.Offset, input.Limit)
+	users, err := list(ctx, offset, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
 
 	// Conta total de usuários
-	total, err := uc.userRepo.Count(ctx)
+	total, err := count(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count users: %w", err)
 	}
 
-	return &ListUsersOutput{
-		Users: users,
-		Total: total,
-	}, nil
+	return newPagedUsers(users, total, offset, limit), nil
+}
+
+// SearchUsersInput representa os dados de entrada para busca de usuários
+type SearchUsersInput struct {
+	Query  string `json:"query"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+	// IncludeInactive inclui usuários desativados; deve ser habilitado apenas para admins
+	IncludeInactive bool `json:"include_inactive"`
+}
+
+// SearchUsers busca usuários por nome ou email com paginação. Limit acima do
+// máximo configurado (WithResultLimits) é rejeitado com LimitExceededError
+func (uc *UserUseCase) SearchUsers(ctx context.Context, input SearchUsersInput) (*PagedUsers, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+	if input.Limit > uc.maxSearchLimit {
+		return nil, &LimitExceededError{Err: ErrSearchLimitTooLarge, Max: uc.maxSearchLimit, Got: input.Limit}
+	}
+
+	offset, limit := normalizePage(input.Offset, input.Limit)
+
+	users, err := uc.userRepo.Search(ctx, query, input.IncludeInactive, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	total, err := uc.userRepo.CountSearch(ctx, query, input.IncludeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count searched users: %w", err)
+	}
+
+	return newPagedUsers(users, total, offset, limit), nil
+}
+
+// UserStatsInput representa o intervalo das estatísticas de cadastro
+type UserStatsInput struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// UserStatsOutput representa o total de cadastros no intervalo
+type UserStatsOutput struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Count int64     `json:"count"`
+}
+
+// UserStats conta os usuários criados no intervalo [From, To]
+func (uc *UserUseCase) UserStats(ctx context.Context, input UserStatsInput) (*UserStatsOutput, error) {
+	if input.From.After(input.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	count, err := uc.userRepo.CountCreatedBetween(ctx, input.From, input.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users created between dates: %w", err)
+	}
+
+	return &UserStatsOutput{From: input.From, To: input.To, Count: count}, nil
+}
+
+// RoleStatsOutput representa o total de usuários por papel
+type RoleStatsOutput struct {
+	Roles map[user.Role]int64 `json:"roles"`
+	Total int64               `json:"total"`
 }
 
-// AuthenticateUserInput representa os dados de entrada para autenticação
+// RoleStats conta os usuários de cada papel, incluindo papéis sem usuários
+func (uc *UserUseCase) RoleStats(ctx context.Context) (*RoleStatsOutput, error) {
+	counts, err := uc.userRepo.CountByRole(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %w", err)
+	}
+
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+
+	return &RoleStatsOutput{Roles: counts, Total: total}, nil
+}
+
+// normalizePage aplica os padrões de paginação compartilhados pelas consultas
+func normalizePage(offset, limit int) (int, int) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset, limit
+}
+
+// AuthenticateUserInput representa os dados de entrada para autenticação.
+// Com WithUsernames, Username pode ser informado no lugar de Email
 type AuthenticateUserInput struct {
 	Email    string `json:"email"`
+	Username string `json:"username,omitempty"`
 	Password string `json:"password"`
+
+	// Metadados da requisição, usados nos logs de auditoria e, com WithSessions,
+	// guardados na sessão do token emitido
+	ClientIP  string `json:"-"`
+	RequestID string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // AuthenticateUserOutput representa os dados de saída da autenticação
 type AuthenticateUserOutput struct {
 	User  *user.User `json:"user"`
 	Token string     `json:"token"`
+	// ExpiresIn é a validade do token a partir da emissão
+	ExpiresIn time.Duration `json:"expires_in"`
 }
 
-// AuthenticateUser autentica um usuário
+// AuthenticateUser autentica um usuário. A verificação das credenciais é
+// delegada ao Authenticator configurado (senha local por padrão)
 func (uc *UserUseCase) AuthenticateUser(ctx context.Context, input AuthenticateUserInput) (*AuthenticateUserOutput, error) {
-	// Busca o usuário pelo email
-	userEntity, err := uc.userRepo.GetByEmail(ctx, input.Email)
-	if err != nil {
-		// Se o usuário não foi encontrado, retorna erro de domínio
-		if err == user.ErrUserNotFound {
-			return nil, user.ErrInvalidPassword
+	var err error
+	if input.Username != "" {
+		err = uc.resolveUsername(ctx, &input)
+		if errors.Is(err, user.ErrUserNotFound) {
+			// Mesmo custo de uma senha errada, como no login por email
+			user.SimulatePasswordCheck(input.Password)
 		}
-		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
-	// Verifica se o usuário está ativo
-	if !userEntity.IsActiveUser() {
-		return nil, user.ErrUserDeactivated
+	var userEntity *user.User
+	if err == nil {
+		// Email normalizado como no cadastro
+		input.Email = user.NormalizeEmail(input.Email)
+		userEntity, err = uc.authenticator.Authenticate(ctx, input.Email, input.Password)
 	}
-
-	// Verifica a senha
-	if !userEntity.CheckPassword(input.Password) {
-		return nil, user.ErrInvalidPassword
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrUserNotFound):
+			// Usuário inexistente é indistinguível de senha errada para o cliente
+			uc.recordLogin(ctx, input, LoginResultNotFound)
+			return nil, user.ErrInvalidPassword
+		case errors.Is(err, user.ErrUserDeactivated):
+			uc.recordLogin(ctx, input, LoginResultDeactivated)
+			return nil, user.ErrUserDeactivated
+		case errors.Is(err, user.ErrInvalidPassword):
+			uc.recordLogin(ctx, input, LoginResultInvalidPassword)
+			return nil, user.ErrInvalidPassword
+		case errors.Is(err, user.ErrPasswordExpired):
+			uc.recordLogin(ctx, input, LoginResultPasswordExpired)
+			return nil, user.ErrPasswordExpired
+		}
+		uc.recordLogin(ctx, input, LoginResultError)
+		return nil, err
 	}
 
-	// Gera o token JWT
-	token, err := uc.jwtService.GenerateToken(userEntity.ID, userEntity.Email, string(userEntity.Role))
+	return uc.issueLoginToken(ctx, input, userEntity)
+}
+
+// issueLoginToken conclui um login (senha, troca de senha expirada ou
+// identidade externa): emite o token JWT e registra o login bem-sucedido
+func (uc *UserUseCase) issueLoginToken(ctx context.Context, input AuthenticateUserInput, userEntity *user.User) (*AuthenticateUserOutput, error) {
+	token, err := uc.jwtService.GenerateToken(userEntity.ID, userEntity.Email, string(userEntity.Role), uc.sessionTokenOptions(input.UserAgent, input.ClientIP)...)
 	if err != nil {
+		uc.recordLogin(ctx, input, LoginResultError)
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	uc.touchLastLogin(ctx, userEntity)
+	uc.recordLogin(ctx, input, LoginResultSuccess)
+
 	return &AuthenticateUserOutput{
-		User:  userEntity,
-		Token: token,
+		User:      userEntity,
+		Token:     token,
+		ExpiresIn: uc.jwtService.ExpiresIn(),
 	}, nil
 }
+
+// rehashPassword migra o hash para o pepper atual após um login bem-sucedido.
+// Grava apenas a senha, e só se ela não tiver sido trocada desde a leitura,
+// para não desfazer alterações concorrentes. Falhas não impedem o login; o hash
+// antigo continua válido enquanto sua versão de pepper estiver configurada
+func (uc *UserUseCase) rehashPassword(ctx context.Context, u *user.User, password string) {
+	if !u.PasswordNeedsRehash() {
+		return
+	}
+
+	previous := u.Password
+	if err := u.SetPassword(password); err != nil {
+		uc.logger.WarnContext(ctx, "failed to rehash password", "user_id", u.ID, "error", err)
+		return
+	}
+	replaced, err := uc.userRepo.ReplacePassword(ctx, u, previous)
+	if err != nil {
+		u.Password = previous
+		uc.logger.WarnContext(ctx, "failed to rehash password", "user_id", u.ID, "error", err)
+		return
+	}
+	if !replaced {
+		u.Password = previous
+		uc.logger.DebugContext(ctx, "password changed concurrently, skipping rehash", "user_id", u.ID)
+	}
+}
+
+// LogoutInput representa os dados de entrada para logout
+type LogoutInput struct {
+	Token string `json:"token"`
+}
+
+// Logout revoga o token atual até sua expiração
+func (uc *UserUseCase) Logout(ctx context.Context, input LogoutInput) error {
+	if err := uc.jwtService.RevokeToken(ctx, input.Token); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeSessionsInput representa os dados de entrada para revogar todas as sessões
+type RevokeSessionsInput struct {
+	UserID string `json:"user_id"`
+}
+
+// RevokeSessions invalida de uma vez todos os tokens já emitidos para o usuário,
+// incrementando sua versão de tokens
+func (uc *UserUseCase) RevokeSessions(ctx context.Context, input RevokeSessionsInput) error {
+	if _, err := uc.userRepo.IncrementTokenVersion(ctx, input.UserID); err != nil {
+		// Erros de domínio recebem o contexto da operação
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.NewDomainError(err, "id", input.UserID)
+		}
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	// Os tokens já são rejeitados pela versão; a listagem de sessões também é limpa
+	if uc.sessions != nil {
+		if err := uc.sessions.RemoveAll(ctx, input.UserID); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ChangePasswordInput representa os dados de entrada para troca de senha
+type ChangePasswordInput struct {
+	UserID          string `json:"user_id"`
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ChangePassword troca a senha após conferir a atual e invalida todas as
+// sessões existentes do usuário
+func (uc *UserUseCase) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	dbUser, err := uc.userRepo.GetByID(ctx, input.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return user.NewDomainError(err, "id", input.UserID)
+		}
+		return fmt.Errorf("failed to get user for password change: %w", err)
+	}
+
+	if !dbUser.CheckPassword(input.CurrentPassword) {
+		return user.ErrInvalidPassword
+	}
+
+	if err := dbUser.ChangePassword(input.NewPassword); err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	if err := uc.userRepo.Update(ctx, dbUser); err != nil {
+		return fmt.Errorf("failed to update user in repository: %w", err)
+	}
+
+	return uc.RevokeSessions(ctx, RevokeSessionsInput{UserID: dbUser.ID})
+}
+
+// DeactivateUserInput representa os dados de entrada para desativação de usuário
+type DeactivateUserInput struct {
+	ID string `json:"id"`
+}
+
+// DeactivateUser desativa a conta e invalida todas as sessões existentes
+func (uc *UserUseCase) DeactivateUser(ctx context.Context, input DeactivateUserInput) (*UpdateUserOutput, error) {
+	dbUser, err := uc.userRepo.GetByID(ctx, input.ID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, user.NewDomainError(err, "id", input.ID)
+		}
+		return nil, fmt.Errorf("failed to get user for deactivation: %w", err)
+	}
+
+	dbUser.Deactivate()
+	if err := uc.userRepo.Update(ctx, dbUser); err != nil {
+		return nil, fmt.Errorf("failed to update user in repository: %w", err)
+	}
+
+	if err := uc.RevokeSessions(ctx, RevokeSessionsInput{UserID: dbUser.ID}); err != nil {
+		return nil, err
+	}
+
+	uc.publish(ctx, user.NewEvent(user.EventUserUpdated, dbUser))
+
+	return &UpdateUserOutput{User: dbUser}, nil
+}
@@ -0,0 +1,502 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/requestid"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestUseCase cria o caso de uso com mocks de repositório e JWT
+func newTestUseCase() (*usecase.UserUseCase, *mocks.UserRepository, *mocks.JWTService) {
+	repo := &mocks.UserRepository{}
+	jwtService := &mocks.JWTService{}
+	return usecase.NewUserUseCase(repo, jwtService), repo, jwtService
+}
+
+// newTestUser cria um usuário válido com a senha informada
+func newTestUser(t *testing.T, password string) *user.User {
+	t.Helper()
+	u, err := user.NewUser("test@example.com", password, "Test User", user.RoleUser)
+	require.NoError(t, err)
+	u.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+	return u
+}
+
+func TestCreateUser(t *testing.T) {
+	ctx := context.Background()
+	input := usecase.CreateUserInput{
+		Email:    "test@example.com",
+		Password: "password123",
+		Name:     "Test User",
+		Role:     user.RoleUser,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+		repo.On("Create", ctx, mock.AnythingOfType("*user.User")).Return(nil)
+
+		output, err := uc.CreateUser(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, input.Email, output.User.Email)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("email already exists", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("ExistsByEmail", ctx, input.Email).Return(true, nil)
+
+		_, err := uc.CreateUser(ctx, input)
+		assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		dbErr := errors.New("connection refused")
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, dbErr)
+
+		_, err := uc.CreateUser(ctx, input)
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestGetUserByID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("GetByID", ctx, "missing").Return(nil, user.ErrUserNotFound)
+
+		_, err := uc.GetUserByID(ctx, usecase.GetUserByIDInput{ID: "missing"})
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+		var domainErr *user.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "id", domainErr.Field)
+		assert.Equal(t, "missing", domainErr.Value)
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("GetByID", ctx, "missing").Return(nil, user.ErrUserNotFound)
+
+		_, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{ID: "missing"})
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("email taken by another user", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		existing := newTestUser(t, "password123")
+		newEmail := "taken@example.com"
+		repo.On("GetByID", ctx, existing.ID).Return(existing, nil)
+		repo.On("ExistsByEmail", ctx, newEmail).Return(true, nil)
+
+		_, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{ID: existing.ID, Email: &newEmail})
+		assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("Delete", ctx, "missing").Return(user.ErrUserNotFound)
+
+		err := uc.DeleteUser(ctx, usecase.DeleteUserInput{ID: "missing"})
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+}
+
+func TestAuthenticateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		u := newTestUser(t, "password123")
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+		assert.Equal(t, time.Hour, output.ExpiresIn)
+		jwtService.AssertExpectations(t)
+	})
+
+	t.Run("user not found maps to invalid password", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		repo.On("GetByEmail", ctx, "missing@example.com").Return(nil, user.ErrUserNotFound)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: "missing@example.com", Password: "password123"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		jwtService.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		u := newTestUser(t, "password123")
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "wrong123"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+	})
+
+	t.Run("deactivated user", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		u := newTestUser(t, "password123")
+		u.Deactivate()
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		assert.ErrorIs(t, err, user.ErrUserDeactivated)
+	})
+
+	t.Run("token generation failure", func(t *testing.T) {
+		uc, repo, jwtService := newTestUseCase()
+		u := newTestUser(t, "password123")
+		tokenErr := errors.New("signing failed")
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("", tokenErr)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+		assert.ErrorIs(t, err, tokenErr)
+	})
+}
+
+// TestAuthenticateUserUnknownEmailLooksLikeWrongPassword documenta a proteção
+// contra enumeração: email inexistente retorna o mesmo erro de senha errada. O
+// custo igual da comparação é verificado em user.SimulatePasswordCheck
+func TestAuthenticateUserUnknownEmailLooksLikeWrongPassword(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	existing := newTestUser(t, "password123")
+	repo.On("GetByEmail", ctx, "unknown@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("GetByEmail", ctx, existing.Email).Return(existing, nil)
+
+	_, unknownErr := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: "unknown@example.com", Password: "wrong-password"})
+	_, wrongErr := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: existing.Email, Password: "wrong-password"})
+
+	assert.ErrorIs(t, unknownErr, user.ErrInvalidPassword)
+	assert.Equal(t, wrongErr, unknownErr)
+}
+
+// TestAuthenticateUserDeactivatedWrongPasswordLooksLikeUnknown garante que a
+// desativação só é informada depois de a senha conferir: com a senha errada, uma
+// conta desativada responde como um email inexistente
+func TestAuthenticateUserDeactivatedWrongPasswordLooksLikeUnknown(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	deactivated := newTestUser(t, "password123")
+	deactivated.Deactivate()
+	repo.On("GetByEmail", ctx, "unknown@example.com").Return(nil, user.ErrUserNotFound)
+	repo.On("GetByEmail", ctx, deactivated.Email).Return(deactivated, nil)
+
+	_, unknownErr := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: "unknown@example.com", Password: "wrong-password"})
+	_, deactivatedErr := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: deactivated.Email, Password: "wrong-password"})
+
+	assert.ErrorIs(t, deactivatedErr, user.ErrInvalidPassword)
+	assert.Equal(t, unknownErr, deactivatedErr)
+}
+
+func TestAuthenticateUserRehashesToCurrentPepper(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { require.NoError(t, user.SetPasswordPeppers("", nil)) })
+
+	uc, repo, jwtService := newTestUseCase()
+	u := newTestUser(t, "password123")
+	require.NoError(t, user.SetPasswordPeppers("v1", map[string]string{"v1": "pepper-one"}))
+
+	repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+	legacyHash := u.Password
+	repo.On("ReplacePassword", ctx, mock.MatchedBy(func(updated *user.User) bool {
+		return updated.PasswordPepperVersion() == "v1"
+	}), legacyHash).Return(true, nil).Once()
+	jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+	repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+	jwtService.On("ExpiresIn").Return(time.Hour)
+
+	_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+	require.NoError(t, err)
+	assert.True(t, u.CheckPassword("password123"))
+
+	_, err = uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticateUserRehashSkipsConcurrentPasswordChange(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { require.NoError(t, user.SetPasswordPeppers("", nil)) })
+
+	uc, repo, jwtService := newTestUseCase()
+	u := newTestUser(t, "password123")
+	legacyHash := u.Password
+	require.NoError(t, user.SetPasswordPeppers("v1", map[string]string{"v1": "pepper-one"}))
+
+	repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+	// Outra requisição trocou a senha entre a leitura e a gravação
+	repo.On("ReplacePassword", ctx, u, legacyHash).Return(false, nil).Once()
+	jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+	repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+	jwtService.On("ExpiresIn").Return(time.Hour)
+
+	_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: u.Email, Password: "password123"})
+	require.NoError(t, err)
+	assert.Equal(t, legacyHash, u.Password, "the in-memory hash must match the stored one")
+	repo.AssertExpectations(t)
+}
+
+// stubAuthenticator simula um backend externo de credenciais
+type stubAuthenticator struct {
+	user *user.User
+	err  error
+}
+
+func (a stubAuthenticator) Authenticate(context.Context, string, string) (*user.User, error) {
+	return a.user, a.err
+}
+
+func TestAuthenticateUserWithExternalAuthenticator(t *testing.T) {
+	ctx := context.Background()
+	external, err := user.NewExternalUser("ana@example.com", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	external.ID = "8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60"
+
+	t.Run("delegates credential verification", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithAuthenticator(stubAuthenticator{user: external}))
+		jwtService.On("GenerateToken", external.ID, external.Email, string(external.Role)).Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, external.ID, mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: "Ana@Example.com", Password: "directory-secret"})
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("maps authenticator failures", func(t *testing.T) {
+		backendErr := errors.New("ldap unavailable")
+		for _, tt := range []struct {
+			err  error
+			want error
+		}{
+			{user.ErrInvalidPassword, user.ErrInvalidPassword},
+			{user.ErrUserNotFound, user.ErrInvalidPassword},
+			{user.ErrUserDeactivated, user.ErrUserDeactivated},
+			{backendErr, backendErr},
+		} {
+			uc := usecase.NewUserUseCase(&mocks.UserRepository{}, &mocks.JWTService{}, usecase.WithAuthenticator(stubAuthenticator{err: tt.err}))
+			_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Email: "ana@example.com", Password: "x"})
+			assert.ErrorIs(t, err, tt.want)
+		}
+	})
+}
+
+func TestRegisterUserAlwaysAssignsUserRole(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	repo.On("ExistsByEmail", ctx, "new@example.com").Return(false, nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+		return u.Role == user.RoleUser
+	})).Return(nil)
+
+	output, err := uc.RegisterUser(ctx, usecase.RegisterUserInput{
+		Email:    "new@example.com",
+		Password: "password123",
+		Name:     "New User",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user.RoleUser, output.User.Role)
+	repo.AssertExpectations(t)
+}
+
+// recordingPublisher guarda os eventos publicados
+type recordingPublisher struct {
+	events []user.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event user.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestUserLifecycleEventsPublished(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.UserRepository{}
+	publisher := &recordingPublisher{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEventPublisher(publisher))
+
+	repo.On("ExistsByEmail", ctx, "new@example.com").Return(false, nil)
+	repo.On("Create", ctx, mock.Anything).Return(nil)
+	repo.On("Delete", ctx, "42").Return(nil)
+
+	_, err := uc.CreateUser(ctx, usecase.CreateUserInput{
+		Email: "new@example.com", Password: "password123", Name: "New", Role: user.RoleUser,
+	})
+	require.NoError(t, err)
+	require.NoError(t, uc.DeleteUser(ctx, usecase.DeleteUserInput{ID: "42"}))
+
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, user.EventUserCreated, publisher.events[0].Type)
+	assert.Equal(t, "new@example.com", publisher.events[0].User.Email)
+	assert.Equal(t, user.EventUserDeleted, publisher.events[1].Type)
+	assert.Equal(t, "42", publisher.events[1].UserID)
+	assert.Nil(t, publisher.events[1].User)
+}
+
+func TestEventsCarryRequestID(t *testing.T) {
+	ctx := requestid.NewContext(context.Background(), "req-42")
+	repo := &mocks.UserRepository{}
+	publisher := &recordingPublisher{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithEventPublisher(publisher))
+
+	repo.On("Delete", ctx, "42").Return(nil)
+	require.NoError(t, uc.DeleteUser(ctx, usecase.DeleteUserInput{ID: "42"}))
+
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, "req-42", publisher.events[0].RequestID)
+}
+
+func TestRevokeSessions(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	repo.On("IncrementTokenVersion", ctx, "42").Return(3, nil)
+	repo.On("IncrementTokenVersion", ctx, "missing").Return(0, user.ErrUserNotFound)
+
+	require.NoError(t, uc.RevokeSessions(ctx, usecase.RevokeSessionsInput{UserID: "42"}))
+	assert.ErrorIs(t, uc.RevokeSessions(ctx, usecase.RevokeSessionsInput{UserID: "missing"}), user.ErrUserNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestChangePasswordRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success bumps token version", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		u := newTestUser(t, "oldpass123")
+		u.ID = "42"
+		repo.On("GetByID", ctx, "42").Return(u, nil)
+		repo.On("Update", ctx, u).Return(nil)
+		repo.On("IncrementTokenVersion", ctx, "42").Return(1, nil)
+
+		err := uc.ChangePassword(ctx, usecase.ChangePasswordInput{UserID: "42", CurrentPassword: "oldpass123", NewPassword: "newpass123"})
+		require.NoError(t, err)
+		assert.True(t, u.CheckPassword("newpass123"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("wrong current password keeps sessions", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		u := newTestUser(t, "oldpass123")
+		u.ID = "42"
+		repo.On("GetByID", ctx, "42").Return(u, nil)
+
+		err := uc.ChangePassword(ctx, usecase.ChangePasswordInput{UserID: "42", CurrentPassword: "wrong", NewPassword: "newpass123"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "IncrementTokenVersion", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeactivateUserRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	u := newTestUser(t, "password123")
+	u.ID = "42"
+	repo.On("GetByID", ctx, "42").Return(u, nil)
+	repo.On("Update", ctx, u).Return(nil)
+	repo.On("IncrementTokenVersion", ctx, "42").Return(1, nil)
+
+	output, err := uc.DeactivateUser(ctx, usecase.DeactivateUserInput{ID: "42"})
+	require.NoError(t, err)
+	assert.False(t, output.User.IsActive)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthenticateUserLogsOutcome(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	u := newTestUser(t, "password123")
+	repo.On("GetByEmail", ctx, "test@example.com").Return(u, nil)
+
+	_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{
+		Email:     "test@example.com",
+		Password:  "wrong-password",
+		ClientIP:  "203.0.113.7",
+		RequestID: "req-1",
+	})
+	require.ErrorIs(t, err, user.ErrInvalidPassword)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "auth.login", entry["msg"])
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, usecase.LoginResultInvalidPassword, entry["result"])
+	assert.Equal(t, usecase.HashIdentifier("test@example.com"), entry["email_hash"])
+	assert.Equal(t, "203.0.113.7", entry["ip"])
+	assert.Equal(t, "req-1", entry["request_id"])
+
+	assert.NotContains(t, buf.String(), "wrong-password")
+	assert.NotContains(t, buf.String(), "test@example.com")
+}
+
+func TestCreateUserChecksNormalizedEmail(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	repo.On("ExistsByEmail", ctx, "ana@example.com").Return(true, nil)
+
+	_, err := uc.CreateUser(ctx, usecase.CreateUserInput{
+		Email:    " Ana@Example.com",
+		Password: "password123",
+		Name:     "Ana",
+		Role:     user.RoleUser,
+	})
+	assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+	repo.AssertExpectations(t)
+}
+
+func TestPagedUsersAreNeverNil(t *testing.T) {
+	ctx := context.Background()
+	uc, repo, _ := newTestUseCase()
+	repo.On("ListActive", ctx, 0, 10).Return(nil, nil)
+	repo.On("CountActive", ctx).Return(int64(0), nil)
+	repo.On("Search", ctx, "nobody", false, 0, 10).Return(nil, nil)
+	repo.On("CountSearch", ctx, "nobody", false).Return(int64(0), nil)
+
+	listed, err := uc.ListUsers(ctx, usecase.ListUsersInput{Limit: 10})
+	require.NoError(t, err)
+	searched, err := uc.SearchUsers(ctx, usecase.SearchUsersInput{Query: "nobody", Limit: 10})
+	require.NoError(t, err)
+
+	for _, page := range []*usecase.PagedUsers{listed, searched} {
+		data, err := json.Marshal(page)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"users":[]`)
+	}
+}
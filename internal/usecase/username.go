@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-api-boilerplate/internal/domain/user"
+)
+
+// ErrUsernamesDisabled indica um nome de usuário enviado sem WithUsernames
+var ErrUsernamesDisabled = errors.New("usernames are not enabled")
+
+// WithUsernames habilita o nome de usuário opcional: cadastro e atualização
+// aceitam username e o login aceita username no lugar do email. Sem esta
+// opção, a coluna fica vazia e requisições com username são recusadas (exceto
+// a remoção, com username vazio). app.UseCaseOptions a aplica a partir de
+// users.usernames_enabled
+func WithUsernames() Option {
+	return func(uc *UserUseCase) {
+		uc.usernamesEnabled = true
+	}
+}
+
+// UsernamesEnabled informa se o nome de usuário foi habilitado
+func (uc *UserUseCase) UsernamesEnabled() bool {
+	return uc.usernamesEnabled
+}
+
+// CheckUsernameAvailable valida o formato do nome de usuário e verifica se ele
+// já está em uso, sem diferenciar maiúsculas
+func (uc *UserUseCase) CheckUsernameAvailable(ctx context.Context, username string) error {
+	if !uc.usernamesEnabled {
+		return ErrUsernamesDisabled
+	}
+
+	username = user.NormalizeUsername(username)
+	if err := user.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	exists, err := uc.userRepo.ExistsByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to check username existence: %w", err)
+	}
+	if exists {
+		return user.NewDomainError(user.ErrUserAlreadyExists, "username", username)
+	}
+
+	return nil
+}
+
+// updateUsername aplica um novo nome de usuário; mudar apenas a caixa do
+// próprio nome não conflita consigo mesmo. Um valor vazio remove o nome de
+// usuário, mesmo sem WithUsernames, para limpar contas antigas
+func (uc *UserUseCase) updateUsername(ctx context.Context, u *user.User, username string) error {
+	if user.NormalizeUsername(username) == "" {
+		u.ClearUsername()
+		return nil
+	}
+
+	if !strings.EqualFold(user.NormalizeUsername(username), u.Username) {
+		if err := uc.CheckUsernameAvailable(ctx, username); err != nil {
+			return err
+		}
+	} else if !uc.usernamesEnabled {
+		return ErrUsernamesDisabled
+	}
+
+	return u.SetUsername(username)
+}
+
+// resolveUsername troca o username do login pelo email da conta, para que o
+// Authenticator configurado verifique as credenciais como em um login por email
+func (uc *UserUseCase) resolveUsername(ctx context.Context, input *AuthenticateUserInput) error {
+	if !uc.usernamesEnabled {
+		return user.ErrUserNotFound
+	}
+
+	u, err := uc.userRepo.GetByUsername(ctx, user.NormalizeUsername(input.Username))
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to get user by username: %w", err)
+	}
+
+	input.Email = u.Email
+	return nil
+}
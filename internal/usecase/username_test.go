@@ -0,0 +1,146 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserWithUsername(t *testing.T) {
+	ctx := context.Background()
+	input := usecase.CreateUserInput{
+		Email:    "ana@example.com",
+		Password: "password123",
+		Name:     "Ana",
+		Role:     user.RoleUser,
+		Username: " Ana_Silva ",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithUsernames())
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+		repo.On("ExistsByUsername", ctx, "Ana_Silva").Return(false, nil)
+		repo.On("Create", ctx, mock.AnythingOfType("*user.User")).Return(nil)
+
+		output, err := uc.CreateUser(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, "Ana_Silva", output.User.Username)
+	})
+
+	t.Run("username taken", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithUsernames())
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+		repo.On("ExistsByUsername", ctx, "Ana_Silva").Return(true, nil)
+
+		_, err := uc.CreateUser(ctx, input)
+		assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid username", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithUsernames())
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+
+		invalid := input
+		invalid.Username = "ana.silva"
+		_, err := uc.CreateUser(ctx, invalid)
+		assert.ErrorIs(t, err, user.ErrInvalidUsername)
+	})
+
+	t.Run("usernames disabled", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+		repo.On("ExistsByEmail", ctx, input.Email).Return(false, nil)
+
+		_, err := uc.CreateUser(ctx, input)
+		assert.ErrorIs(t, err, usecase.ErrUsernamesDisabled)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateUsernameCaseOnly(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.UserRepository{}
+	uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithUsernames())
+	u := newTestUser(t, "password123")
+	require.NoError(t, u.SetUsername("ana_silva"))
+	repo.On("GetByID", ctx, u.ID).Return(u, nil)
+	repo.On("Update", ctx, u).Return(nil)
+
+	username := "Ana_Silva"
+	output, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{ID: u.ID, Username: &username})
+	require.NoError(t, err)
+	assert.Equal(t, "Ana_Silva", output.User.Username)
+	repo.AssertNotCalled(t, "ExistsByUsername", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUsernameClear(t *testing.T) {
+	ctx := context.Background()
+	for _, enabled := range []bool{true, false} {
+		repo := &mocks.UserRepository{}
+		var opts []usecase.Option
+		if enabled {
+			opts = append(opts, usecase.WithUsernames())
+		}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, opts...)
+		u := newTestUser(t, "password123")
+		require.NoError(t, u.SetUsername("ana_silva"))
+		repo.On("GetByID", ctx, u.ID).Return(u, nil)
+		repo.On("Update", ctx, u).Return(nil)
+
+		empty := " "
+		output, err := uc.UpdateUser(ctx, usecase.UpdateUserInput{ID: u.ID, Username: &empty})
+		require.NoError(t, err, "usernames enabled: %v", enabled)
+		assert.Empty(t, output.User.Username)
+		repo.AssertNotCalled(t, "ExistsByUsername", mock.Anything, mock.Anything)
+	}
+}
+
+func TestAuthenticateUserByUsername(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		jwtService := &mocks.JWTService{}
+		uc := usecase.NewUserUseCase(repo, jwtService, usecase.WithUsernames())
+		u := newTestUser(t, "password123")
+		require.NoError(t, u.SetUsername("test_user"))
+		repo.On("GetByUsername", ctx, "TEST_USER").Return(u, nil)
+		repo.On("GetByEmail", ctx, u.Email).Return(u, nil)
+		jwtService.On("GenerateToken", u.ID, u.Email, string(u.Role)).Return("signed-token", nil)
+		repo.On("RecordLogin", mock.Anything, u.ID, mock.Anything).Return(nil)
+		jwtService.On("ExpiresIn").Return(time.Hour)
+
+		output, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Username: "TEST_USER", Password: "password123"})
+		require.NoError(t, err)
+		assert.Equal(t, "signed-token", output.Token)
+	})
+
+	t.Run("unknown username maps to invalid password", func(t *testing.T) {
+		repo := &mocks.UserRepository{}
+		uc := usecase.NewUserUseCase(repo, &mocks.JWTService{}, usecase.WithUsernames())
+		repo.On("GetByUsername", ctx, "missing").Return(nil, user.ErrUserNotFound)
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Username: "missing", Password: "password123"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("usernames disabled", func(t *testing.T) {
+		uc, repo, _ := newTestUseCase()
+
+		_, err := uc.AuthenticateUser(ctx, usecase.AuthenticateUserInput{Username: "test_user", Password: "password123"})
+		assert.ErrorIs(t, err, user.ErrInvalidPassword)
+		repo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+	})
+}
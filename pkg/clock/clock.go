@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock é a fonte de tempo da aplicação. Injetá-lo no lugar de time.Now torna
+// determinístico o comportamento dependente de tempo (expiração, timestamps)
+type Clock interface {
+	Now() time.Time
+}
+
+// System é o relógio real, baseado em time.Now
+var System Clock = systemClock{}
+
+// systemClock implementa Clock com time.Now
+type systemClock struct{}
+
+// Now implementa Clock
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Fake é um relógio controlável para testes; só avança com Advance ou Set
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake cria um relógio parado em now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now implementa Clock
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance avança o relógio em d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Set posiciona o relógio em now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
@@ -2,6 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,6 +17,10 @@ type Config struct {
 	Database   DatabaseConfig   `mapstructure:"database"`
 	Logging    LoggingConfig    `mapstructure:"logging"`
 	Security   SecurityConfig   `mapstructure:"security"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	Webhooks   WebhooksConfig   `mapstructure:"webhooks"`
+	Users      UsersConfig      `mapstructure:"users"`
+	Auth       AuthConfig       `mapstructure:"auth"`
 	Environment string          `mapstructure:"environment"`
 }
 
@@ -23,8 +31,108 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+
+	// Timeouts não configurados (0) usam DefaultReadTimeout, DefaultWriteTimeout e
+	// DefaultIdleTimeout, para que o servidor nunca rode sem prazos.
+	// ReadHeaderTimeout é o prazo para receber os headers da requisição (0 usa
+	// ReadTimeout). ReadTimeout cobre headers e corpo: clientes que enviam o corpo
+	// devagar (slowloris) têm a conexão encerrada quando ele expira
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+
+	// MaxHeaderBytes limita o tamanho dos headers da requisição (0 usa http.DefaultMaxHeaderBytes, 1 MiB).
+	// Deve comportar o header Authorization com o maior token emitido (security.jwt_max_bytes)
+	MaxHeaderBytes int `mapstructure:"max_header_bytes"`
+
+	// ShutdownTimeout é quanto o desligamento aguarda requisições e workers de background terminarem
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+
+	// RequestTimeout é o prazo de cada requisição da API, propagado às consultas ao
+	// banco pelo contexto; ao expirar a resposta é 504. 0 desabilita. Deve ser menor
+	// que WriteTimeout, para que a resposta ainda chegue ao cliente
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+
+	// TimestampFormat define o formato das datas nas respostas: rfc3339nano (padrão), rfc3339 ou unix
+	TimestampFormat string `mapstructure:"timestamp_format"`
+
+	// JSONFieldNaming define os nomes dos campos JSON nas respostas: snake_case (padrão) ou camel_case
+	JSONFieldNaming string `mapstructure:"json_field_naming"`
+
+	// StrictJSON rejeita campos desconhecidos nos corpos JSON (UNKNOWN_FIELD) em vez de ignorá-los
+	StrictJSON bool `mapstructure:"strict_json"`
+
+	// Compression habilita gzip nas respostas; CompressionLevel (1-9, 0 = padrão balanceado)
+	// e CompressionTypes (vazio = tipos texto/JSON comuns) ajustam CPU vs. banda.
+	// text/event-stream e tipos já comprimidos nunca são comprimidos
+	Compression      bool     `mapstructure:"compression"`
+	CompressionLevel int      `mapstructure:"compression_level"`
+	CompressionTypes []string `mapstructure:"compression_types"`
+
+	// TrailingSlash define o tratamento de "/users/": strip (padrão) remove a barra
+	// sem redirecionar, redirect responde 301 (GET) ou 307 e strict responde 404
+	TrailingSlash string `mapstructure:"trailing_slash"`
+	// RedirectFixedPath redireciona caminhos com caixa ou barras erradas
+	// (ex.: /API/v1/Users) para a rota registrada; desabilitado por padrão
+	RedirectFixedPath bool `mapstructure:"redirect_fixed_path"`
+
+	// RequestIDHeader é o header do ID de correlação, lido primeiro e sempre
+	// devolvido na resposta (vazio usa X-Request-ID). RequestIDSources são headers
+	// alternativos lidos em ordem quando ele falta (ex.: X-Correlation-ID, traceparent)
+	RequestIDHeader  string   `mapstructure:"request_id_header"`
+	RequestIDSources []string `mapstructure:"request_id_sources"`
+
+	// CacheControl é o Cache-Control das leituras (GET) bem-sucedidas da API, ex.:
+	// "private, max-age=60" (vazio usa "private, no-cache"). Escritas, erros e rotas
+	// de autenticação e dados pessoais sempre recebem no-store
+	CacheControl string `mapstructure:"cache_control"`
+
+	// Deprecations marca rotas a serem aposentadas: as respostas delas recebem os
+	// headers Deprecation, Sunset (RFC 8594) e Link para a documentação de migração
+	Deprecations []DeprecationConfig `mapstructure:"deprecations"`
+}
+
+// DeprecationConfig marca uma rota obsoleta (server.deprecations)
+type DeprecationConfig struct {
+	// Method e Path identificam a rota registrada (ex.: GET e /api/v1/users/:id);
+	// Method vazio vale para todos os métodos da rota
+	Method string `mapstructure:"method"`
+	Path   string `mapstructure:"path"`
+	// Since é quando a rota ficou obsoleta (obrigatório) e Sunset quando deixará
+	// de responder (opcional), em RFC3339 ou AAAA-MM-DD (UTC)
+	Since  string `mapstructure:"since"`
+	Sunset string `mapstructure:"sunset"`
+	// Link é a URL da documentação de migração (opcional)
+	Link string `mapstructure:"link"`
+}
+
+// ParseDeprecationTime lê as datas de server.deprecations: RFC3339 ou
+// AAAA-MM-DD (meia-noite UTC). Vazio retorna o instante zero
+func ParseDeprecationTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
 }
 
+// Modos de tratamento da barra final (server.trailing_slash)
+const (
+	TrailingSlashStrip    = "strip"
+	TrailingSlashRedirect = "redirect"
+	TrailingSlashStrict   = "strict"
+)
+
+// Timeouts do servidor aplicados quando a configuração não os define
+const (
+	DefaultReadTimeout  = 30 * time.Second
+	DefaultWriteTimeout = 30 * time.Second
+	DefaultIdleTimeout  = 60 * time.Second
+)
+
+// headerNamePattern aceita nomes de header HTTP usuais (ex.: X-Correlation-ID)
+var headerNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
 // DatabaseConfig representa as configurações do banco de dados
 type DatabaseConfig struct {
 	Host            string        `mapstructure:"host"`
@@ -36,6 +144,68 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	// HealthCheckInterval é o intervalo do ping periódico ao banco; 0 desabilita
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"`
+	// PoolStatsInterval é o intervalo de amostragem do pool para as métricas db_*; 0 desabilita
+	PoolStatsInterval time.Duration `mapstructure:"pool_stats_interval"`
+	// WarmUp abre max_idle_conns conexões na inicialização (ignorado em testing)
+	WarmUp bool `mapstructure:"warmup"`
+	// SingleflightReads compartilha as leituras concorrentes de um mesmo usuário
+	// (por ID ou email) em uma única consulta
+	SingleflightReads bool `mapstructure:"singleflight_reads"`
+	// DisableMigrationLock desliga o advisory lock que serializa as migrações
+	// entre instâncias; MigrationLockTimeout limita a espera por ele (0 = sem limite)
+	DisableMigrationLock bool          `mapstructure:"disable_migration_lock"`
+	MigrationLockTimeout time.Duration `mapstructure:"migration_lock_timeout"`
+}
+
+// UsersConfig representa as regras de validação de usuários
+type UsersConfig struct {
+	// MaxNameLength é o tamanho máximo do nome em caracteres; 0 usa o padrão (255)
+	MaxNameLength int `mapstructure:"max_name_length"`
+	// ExportBatchSize é quantos usuários a exportação lê do banco por vez; 0 usa 1000
+	ExportBatchSize int `mapstructure:"export_batch_size"`
+	// ExportMaxRows limita o total de linhas de uma exportação; 0 usa 100000
+	ExportMaxRows int `mapstructure:"export_max_rows"`
+	// MaxBatchGetIDs limita os IDs distintos de POST /users/batch-get; 0 usa 100
+	MaxBatchGetIDs int `mapstructure:"max_batch_get_ids"`
+	// MaxSearchLimit é o maior limit (ou per_page) aceito por GET /users/search; 0 usa 100
+	MaxSearchLimit int `mapstructure:"max_search_limit"`
+	// DeletionPolicy define o que a exclusão faz: delete (remove a linha) ou anonymize (remove os dados pessoais)
+	DeletionPolicy string `mapstructure:"deletion_policy"`
+	// AllowedEmailDomains e BlockedEmailDomains restringem os domínios aceitos no cadastro
+	// ("example.com" ou "*.example.com" para subdomínios); vazios aceitam todos
+	AllowedEmailDomains []string `mapstructure:"allowed_email_domains"`
+	BlockedEmailDomains []string `mapstructure:"blocked_email_domains"`
+	// DisposableEmailDomains e DisposableEmailDomainsFile (um domínio por linha) listam
+	// provedores de email descartável rejeitados no cadastro, incluindo subdomínios
+	DisposableEmailDomains     []string `mapstructure:"disposable_email_domains"`
+	DisposableEmailDomainsFile string   `mapstructure:"disposable_email_domains_file"`
+	// UsernamesEnabled habilita o nome de usuário opcional e o login por username
+	// (usecase.WithUsernames)
+	UsernamesEnabled bool `mapstructure:"usernames_enabled"`
+	// AdminBootstrapEnabled habilita POST /auth/bootstrap-admin, que cria o
+	// primeiro admin enquanto não houver nenhum (usecase.WithAdminBootstrap)
+	AdminBootstrapEnabled bool `mapstructure:"admin_bootstrap_enabled"`
+	// UpdatePermissions substitui linhas da matriz de atualização (papel -> campo
+	// -> none, self ou any); papéis ausentes usam user.DefaultUpdatePolicy
+	UpdatePermissions map[string]map[string]string `mapstructure:"update_permissions"`
+	// AdminDowngrade rebaixa para user os admins sem login há muito tempo
+	// (usecase.WithAdminDowngrade)
+	AdminDowngrade AdminDowngradeConfig `mapstructure:"admin_downgrade"`
+}
+
+// AdminDowngradeConfig configura o job que rebaixa admins inativos
+type AdminDowngradeConfig struct {
+	// Enabled liga o job; desabilitado por padrão
+	Enabled bool `mapstructure:"enabled"`
+	// InactiveAfter é o tempo sem login (ou desde a criação, para quem nunca
+	// entrou) a partir do qual um admin é rebaixado
+	InactiveAfter time.Duration `mapstructure:"inactive_after"`
+	// Interval é o intervalo entre execuções do job
+	Interval time.Duration `mapstructure:"interval"`
+	// DryRun apenas registra em log quem seria rebaixado
+	DryRun bool `mapstructure:"dry_run"`
 }
 
 // LoggingConfig representa as configurações de logging
@@ -43,6 +213,9 @@ type LoggingConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
 	Output string `mapstructure:"output"`
+	// RedactFields são as chaves de atributos mascaradas em todos os logs;
+	// vazio usa email, password, token e authorization
+	RedactFields []string `mapstructure:"redact_fields"`
 }
 
 // SecurityConfig representa as configurações de segurança
@@ -50,6 +223,178 @@ type SecurityConfig struct {
 	BcryptCost    int           `mapstructure:"bcrypt_cost"`
 	JWTSecret     string        `mapstructure:"jwt_secret"`
 	JWTExpiration time.Duration `mapstructure:"jwt_expiration"`
+	// JWTAlgorithm é o algoritmo HMAC de assinatura (HS256, HS384 ou HS512); o único aceito na validação
+	JWTAlgorithm string `mapstructure:"jwt_algorithm"`
+	// JWTMaxBytes e JWTMaxPermissions limitam o tamanho dos tokens emitidos (0 usa os padrões)
+	JWTMaxBytes       int `mapstructure:"jwt_max_bytes"`
+	JWTMaxPermissions int `mapstructure:"jwt_max_permissions"`
+
+	// JWTActiveKID identifica a chave usada para assinar novos tokens
+	JWTActiveKID string `mapstructure:"jwt_active_kid"`
+	// JWTKeys mapeia kid -> segredo. Chaves anteriores devem permanecer aqui
+	// por pelo menos jwt_expiration após a rotação para não invalidar tokens vivos
+	JWTKeys map[string]string `mapstructure:"jwt_keys"`
+	// RequireStrongSecret exige segredos JWT fortes (auth.ValidateSecretStrength)
+	// também fora de production; em production a verificação é sempre feita
+	RequireStrongSecret bool `mapstructure:"require_strong_secret"`
+
+	// JWTAudience é a audiência deste serviço, exigida na claim aud dos tokens aceitos (vazio não valida)
+	JWTAudience string `mapstructure:"jwt_audience"`
+	// JWTIssuedAudiences são as audiências gravadas nos tokens emitidos (vazio usa jwt_audience)
+	JWTIssuedAudiences []string `mapstructure:"jwt_issued_audiences"`
+
+	// PasswordPepperVersion é a versão do pepper aplicada em novos hashes de senha (vazio desabilita)
+	PasswordPepperVersion string `mapstructure:"password_pepper_version"`
+	// PasswordPeppers mapeia versão -> segredo. Versões anteriores devem permanecer
+	// aqui enquanto houver senhas gravadas com elas; o hash migra no próximo login
+	PasswordPeppers map[string]string `mapstructure:"password_peppers"`
+
+	// PasswordExpirationEnabled exige a troca de senhas locais com PasswordMaxAge
+	// ou mais (usecase.WithPasswordMaxAge); desabilitado por padrão
+	PasswordExpirationEnabled bool          `mapstructure:"password_expiration_enabled"`
+	PasswordMaxAge            time.Duration `mapstructure:"password_max_age"`
+
+	// TokenVersionCacheTTL é a janela de consistência da revogação em massa (token_version):
+	// por até esse tempo, cada instância ainda pode aceitar tokens recém-revogados
+	TokenVersionCacheTTL time.Duration `mapstructure:"token_version_cache_ttl"`
+
+	// CheckAccountStatus rejeita, a cada requisição autenticada, tokens de usuários
+	// desativados ou excluídos
+	CheckAccountStatus bool `mapstructure:"check_account_status"`
+	// AccountStatusCacheTTL é por quanto tempo uma conta recém-desativada ainda pode
+	// ser aceita em cada instância quando CheckAccountStatus está habilitado
+	AccountStatusCacheTTL time.Duration `mapstructure:"account_status_cache_ttl"`
+
+	// TokenBlacklist define onde tokens revogados são armazenados: memory (uma instância) ou redis (várias réplicas)
+	TokenBlacklist string `mapstructure:"token_blacklist"`
+
+	// SessionStore habilita o controle de sessões ativas (jti de cada token emitido):
+	// memory (uma instância) ou redis (várias réplicas); vazio desabilita
+	SessionStore string `mapstructure:"session_store"`
+	// MaxSessionsPerUser limita as sessões ativas por usuário; um novo login além do
+	// limite revoga a sessão mais antiga. 0 não limita
+	MaxSessionsPerUser int `mapstructure:"max_sessions_per_user"`
+
+	// RateLimitBackend define onde os contadores de rate limiting ficam: memory ou redis
+	RateLimitBackend string `mapstructure:"rate_limit_backend"`
+	// RateLimitFailMode define a política quando o backend falha: open (permite) ou closed (429)
+	RateLimitFailMode string `mapstructure:"rate_limit_fail_mode"`
+	// RateLimitExemptRoles isenta do rate limiting tokens válidos destes roles (ex.: admin)
+	RateLimitExemptRoles []string `mapstructure:"rate_limit_exempt_roles"`
+	// RateLimitExemptAPIKeys isenta requisições com uma destas chaves no header X-API-Key
+	RateLimitExemptAPIKeys []string `mapstructure:"rate_limit_exempt_api_keys"`
+	// RateLimitWarningThreshold é a fração final do limite que recebe X-RateLimit-Warning (ex.: 0.1); 0 desabilita
+	RateLimitWarningThreshold float64 `mapstructure:"rate_limit_warning_threshold"`
+	// RateLimitIdleTTL é por quanto tempo o backend memory guarda a chave de um cliente inativo; 0 usa o padrão (10m)
+	RateLimitIdleTTL time.Duration `mapstructure:"rate_limit_idle_ttl"`
+	// RateLimitTenants dá limites próprios a clientes identificados pelo header X-API-Key (nome -> chave e limite)
+	RateLimitTenants map[string]RateLimitTenantConfig `mapstructure:"rate_limit_tenants"`
+	// LoginMaxBodyBytes limita o corpo de POST /auth/login (0 = 4 KiB); acima dele a resposta é 413
+	LoginMaxBodyBytes int64 `mapstructure:"login_max_body_bytes"`
+
+	// CORSOrigins são as origens permitidas por padrão em todos os grupos de rotas
+	CORSOrigins []string `mapstructure:"cors_origins"`
+	// CORSGroups sobrescreve as origens permitidas para grupos específicos (ex.: auth, admin)
+	CORSGroups map[string][]string `mapstructure:"cors_groups"`
+	// CORSAllowCredentials permite credenciais (cookies) cross-origin; com ele, "*" é ignorado
+	CORSAllowCredentials bool `mapstructure:"cors_allow_credentials"`
+	// CORSMaxAge é por quanto tempo o navegador pode cachear a resposta do preflight
+	CORSMaxAge time.Duration `mapstructure:"cors_max_age"`
+
+	// ResetTokenTTL é a validade dos tokens de redefinição de senha (0 usa 1h)
+	ResetTokenTTL time.Duration `mapstructure:"reset_token_ttl"`
+	// ResetTokenBytes é o tamanho aleatório dos tokens de redefinição (0 usa 32; mínimo 16)
+	ResetTokenBytes int `mapstructure:"reset_token_bytes"`
+	// ResetTokenMaxAttempts é quantas confirmações erradas invalidam um token de redefinição (0 usa 5)
+	ResetTokenMaxAttempts int `mapstructure:"reset_token_max_attempts"`
+	// PasswordResetLimit pedidos e confirmações de redefinição são aceitos por
+	// IP a cada PasswordResetWindow; pedidos também por email (0 usa 5 a cada 15min)
+	PasswordResetLimit  int           `mapstructure:"password_reset_limit"`
+	PasswordResetWindow time.Duration `mapstructure:"password_reset_window"`
+
+	// VerificationTokenTTL é a validade dos links de verificação de email (0 usa 1h)
+	VerificationTokenTTL time.Duration `mapstructure:"verification_token_ttl"`
+	// VerificationResendLimit reenvios do link são aceitos por email e por IP a
+	// cada VerificationResendWindow (0 usa 3 por hora)
+	VerificationResendLimit  int           `mapstructure:"verification_resend_limit"`
+	VerificationResendWindow time.Duration `mapstructure:"verification_resend_window"`
+
+	// CustomRoles estende os papéis embutidos (admin, user, guest)
+	CustomRoles []string `mapstructure:"custom_roles"`
+}
+
+// RateLimitTenantConfig é a cota de um tenant identificado pela chave de API
+type RateLimitTenantConfig struct {
+	// APIKey é o valor enviado no header X-API-Key
+	APIKey string `mapstructure:"api_key"`
+	// Limit é o limite do tenant em requisições por segundo
+	Limit int `mapstructure:"limit"`
+}
+
+// RedisConfig representa as configurações do Redis, usado para estado compartilhado entre réplicas
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+// WebhooksConfig representa as configurações de entrega de webhooks de eventos de usuário
+type WebhooksConfig struct {
+	URLs       []string      `mapstructure:"urls"`
+	Secret     string        `mapstructure:"secret"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	MaxRetries int           `mapstructure:"max_retries"`
+	QueueSize  int           `mapstructure:"queue_size"`
+}
+
+// Backends de verificação de credenciais do login (auth.backend)
+const (
+	AuthBackendLocal = "local"
+	AuthBackendLDAP  = "ldap"
+)
+
+// AuthConfig define onde as credenciais do login são verificadas
+type AuthConfig struct {
+	// Backend é local (senha bcrypt, padrão) ou ldap
+	Backend string     `mapstructure:"backend"`
+	LDAP    LDAPConfig `mapstructure:"ldap"`
+	// OIDC habilita o login via provedor externo, independente do backend
+	OIDC OIDCConfig `mapstructure:"oidc"`
+}
+
+// OIDCConfig representa o provedor OIDC (ex.: Google) do login externo
+type OIDCConfig struct {
+	// Issuer é a URL do provedor (ex.: https://accounts.google.com); vazio
+	// desabilita o login OIDC
+	Issuer       string `mapstructure:"issuer"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	// RedirectURL é a URL pública de /api/v1/auth/oidc/callback registrada no provedor
+	RedirectURL string `mapstructure:"redirect_url"`
+	// Scopes pedidos na autorização; vazio usa openid, email e profile
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// Enabled informa se o login OIDC está configurado
+func (o OIDCConfig) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// LDAPConfig representa a conexão com o diretório LDAP/AD usado quando auth.backend é ldap
+type LDAPConfig struct {
+	// URL do servidor, ex.: ldaps://ldap.example.com:636 (ldap:// ou ldaps://)
+	URL string `mapstructure:"url"`
+	// BindDN e BindPassword são a conta de serviço usada para localizar o
+	// usuário; vazios fazem a busca anônima
+	BindDN       string `mapstructure:"bind_dn"`
+	BindPassword string `mapstructure:"bind_password"`
+	BaseDN       string `mapstructure:"base_dn"`
+	// UserFilter localiza o usuário sob BaseDN; %s é o email escapado. Vazio usa (mail=%s)
+	UserFilter string `mapstructure:"user_filter"`
+	// RoleMapping associa grupos (CN, em minúsculas) a roles no primeiro login;
+	// sem grupo mapeado o usuário recebe DefaultRole (vazio usa user)
+	RoleMapping map[string]string `mapstructure:"role_mapping"`
+	DefaultRole string            `mapstructure:"default_role"`
 }
 
 // Load carrega a configuração do arquivo e variáveis de ambiente
@@ -81,7 +426,8 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// Validar configuração
+	// Validar configuração; regras do domínio (papéis, peppers, algoritmos JWT)
+	// são validadas e aplicadas por bootstrap.Configure
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -97,6 +443,20 @@ func setupEnvMappings() {
 	viper.BindEnv("server.read_timeout", "APP_SERVER_READ_TIMEOUT")
 	viper.BindEnv("server.write_timeout", "APP_SERVER_WRITE_TIMEOUT")
 	viper.BindEnv("server.idle_timeout", "APP_SERVER_IDLE_TIMEOUT")
+	viper.BindEnv("server.read_header_timeout", "APP_SERVER_READ_HEADER_TIMEOUT")
+	viper.BindEnv("server.max_header_bytes", "APP_SERVER_MAX_HEADER_BYTES")
+	viper.BindEnv("server.shutdown_timeout", "APP_SERVER_SHUTDOWN_TIMEOUT")
+	viper.BindEnv("server.request_timeout", "APP_SERVER_REQUEST_TIMEOUT")
+	viper.BindEnv("server.timestamp_format", "APP_SERVER_TIMESTAMP_FORMAT")
+	viper.BindEnv("server.json_field_naming", "APP_SERVER_JSON_FIELD_NAMING")
+	viper.BindEnv("server.strict_json", "APP_SERVER_STRICT_JSON")
+	viper.BindEnv("server.compression", "APP_SERVER_COMPRESSION")
+	viper.BindEnv("server.compression_level", "APP_SERVER_COMPRESSION_LEVEL")
+	viper.BindEnv("server.trailing_slash", "APP_SERVER_TRAILING_SLASH")
+	viper.BindEnv("server.redirect_fixed_path", "APP_SERVER_REDIRECT_FIXED_PATH")
+	viper.BindEnv("server.request_id_header", "APP_SERVER_REQUEST_ID_HEADER")
+	viper.BindEnv("server.request_id_sources", "APP_SERVER_REQUEST_ID_SOURCES")
+	viper.BindEnv("server.cache_control", "APP_SERVER_CACHE_CONTROL")
 
 	// Database
 	viper.BindEnv("database.host", "APP_DB_HOST")
@@ -108,16 +468,95 @@ func setupEnvMappings() {
 	viper.BindEnv("database.max_open_conns", "APP_DB_MAX_OPEN_CONNS")
 	viper.BindEnv("database.max_idle_conns", "APP_DB_MAX_IDLE_CONNS")
 	viper.BindEnv("database.conn_max_lifetime", "APP_DB_CONN_MAX_LIFETIME")
+	viper.BindEnv("database.warmup", "APP_DB_WARMUP")
+	viper.BindEnv("database.singleflight_reads", "APP_DB_SINGLEFLIGHT_READS")
+	viper.BindEnv("database.disable_migration_lock", "APP_DB_DISABLE_MIGRATION_LOCK")
+	viper.BindEnv("database.migration_lock_timeout", "APP_DB_MIGRATION_LOCK_TIMEOUT")
+	viper.BindEnv("database.health_check_interval", "APP_DB_HEALTH_CHECK_INTERVAL")
+	viper.BindEnv("database.pool_stats_interval", "APP_DB_POOL_STATS_INTERVAL")
 
 	// Logging
 	viper.BindEnv("logging.level", "APP_LOG_LEVEL")
 	viper.BindEnv("logging.format", "APP_LOG_FORMAT")
 	viper.BindEnv("logging.output", "APP_LOG_OUTPUT")
+	viper.BindEnv("logging.redact_fields", "APP_LOG_REDACT_FIELDS")
 
 	// Security
 	viper.BindEnv("security.bcrypt_cost", "APP_BCRYPT_COST")
 	viper.BindEnv("security.jwt_secret", "APP_JWT_SECRET")
+	viper.BindEnv("security.require_strong_secret", "APP_REQUIRE_STRONG_SECRET")
 	viper.BindEnv("security.jwt_expiration", "APP_JWT_EXPIRATION")
+	viper.BindEnv("security.jwt_active_kid", "APP_JWT_ACTIVE_KID")
+	viper.BindEnv("security.jwt_algorithm", "APP_JWT_ALGORITHM")
+	viper.BindEnv("security.jwt_audience", "APP_JWT_AUDIENCE")
+	viper.BindEnv("security.password_pepper_version", "APP_PASSWORD_PEPPER_VERSION")
+	viper.BindEnv("security.password_expiration_enabled", "APP_PASSWORD_EXPIRATION_ENABLED")
+	viper.BindEnv("security.password_max_age", "APP_PASSWORD_MAX_AGE")
+	viper.BindEnv("security.jwt_issued_audiences", "APP_JWT_ISSUED_AUDIENCES")
+	viper.BindEnv("security.cors_origins", "APP_CORS_ORIGINS")
+	viper.BindEnv("security.cors_allow_credentials", "APP_CORS_ALLOW_CREDENTIALS")
+	viper.BindEnv("security.cors_max_age", "APP_CORS_MAX_AGE")
+
+	viper.BindEnv("security.token_blacklist", "APP_TOKEN_BLACKLIST")
+	viper.BindEnv("security.session_store", "APP_SESSION_STORE")
+	viper.BindEnv("security.max_sessions_per_user", "APP_MAX_SESSIONS_PER_USER")
+	viper.BindEnv("security.token_version_cache_ttl", "APP_TOKEN_VERSION_CACHE_TTL")
+	viper.BindEnv("security.check_account_status", "APP_CHECK_ACCOUNT_STATUS")
+	viper.BindEnv("security.account_status_cache_ttl", "APP_ACCOUNT_STATUS_CACHE_TTL")
+	viper.BindEnv("security.custom_roles", "APP_CUSTOM_ROLES")
+	viper.BindEnv("security.reset_token_ttl", "APP_RESET_TOKEN_TTL")
+	viper.BindEnv("security.reset_token_bytes", "APP_RESET_TOKEN_BYTES")
+	viper.BindEnv("security.reset_token_max_attempts", "APP_RESET_TOKEN_MAX_ATTEMPTS")
+	viper.BindEnv("security.password_reset_limit", "APP_PASSWORD_RESET_LIMIT")
+	viper.BindEnv("security.password_reset_window", "APP_PASSWORD_RESET_WINDOW")
+	viper.BindEnv("security.verification_token_ttl", "APP_VERIFICATION_TOKEN_TTL")
+	viper.BindEnv("security.verification_resend_limit", "APP_VERIFICATION_RESEND_LIMIT")
+	viper.BindEnv("security.verification_resend_window", "APP_VERIFICATION_RESEND_WINDOW")
+	viper.BindEnv("security.rate_limit_backend", "APP_RATE_LIMIT_BACKEND")
+	viper.BindEnv("security.rate_limit_fail_mode", "APP_RATE_LIMIT_FAIL_MODE")
+	viper.BindEnv("security.rate_limit_exempt_roles", "APP_RATE_LIMIT_EXEMPT_ROLES")
+	viper.BindEnv("security.rate_limit_exempt_api_keys", "APP_RATE_LIMIT_EXEMPT_API_KEYS")
+	viper.BindEnv("security.rate_limit_warning_threshold", "APP_RATE_LIMIT_WARNING_THRESHOLD")
+	viper.BindEnv("security.rate_limit_idle_ttl", "APP_RATE_LIMIT_IDLE_TTL")
+
+	// Redis
+	viper.BindEnv("redis.addr", "APP_REDIS_ADDR")
+	viper.BindEnv("redis.password", "APP_REDIS_PASSWORD")
+	viper.BindEnv("redis.db", "APP_REDIS_DB")
+
+	// Webhooks
+	viper.BindEnv("webhooks.urls", "APP_WEBHOOKS_URLS")
+	viper.BindEnv("webhooks.secret", "APP_WEBHOOKS_SECRET")
+
+	// Auth
+	viper.BindEnv("auth.backend", "APP_AUTH_BACKEND")
+	viper.BindEnv("auth.ldap.url", "APP_LDAP_URL")
+	viper.BindEnv("auth.ldap.bind_dn", "APP_LDAP_BIND_DN")
+	viper.BindEnv("auth.ldap.bind_password", "APP_LDAP_BIND_PASSWORD")
+	viper.BindEnv("auth.ldap.base_dn", "APP_LDAP_BASE_DN")
+	viper.BindEnv("auth.ldap.user_filter", "APP_LDAP_USER_FILTER")
+	viper.BindEnv("auth.oidc.issuer", "APP_OIDC_ISSUER")
+	viper.BindEnv("auth.oidc.client_id", "APP_OIDC_CLIENT_ID")
+	viper.BindEnv("auth.oidc.client_secret", "APP_OIDC_CLIENT_SECRET")
+	viper.BindEnv("auth.oidc.redirect_url", "APP_OIDC_REDIRECT_URL")
+
+	// Users
+	viper.BindEnv("users.max_name_length", "APP_USERS_MAX_NAME_LENGTH")
+	viper.BindEnv("users.export_batch_size", "APP_USERS_EXPORT_BATCH_SIZE")
+	viper.BindEnv("users.export_max_rows", "APP_USERS_EXPORT_MAX_ROWS")
+	viper.BindEnv("users.max_batch_get_ids", "APP_USERS_MAX_BATCH_GET_IDS")
+	viper.BindEnv("users.max_search_limit", "APP_USERS_MAX_SEARCH_LIMIT")
+	viper.BindEnv("users.deletion_policy", "APP_USERS_DELETION_POLICY")
+	viper.BindEnv("users.allowed_email_domains", "APP_USERS_ALLOWED_EMAIL_DOMAINS")
+	viper.BindEnv("users.blocked_email_domains", "APP_USERS_BLOCKED_EMAIL_DOMAINS")
+	viper.BindEnv("users.disposable_email_domains", "APP_USERS_DISPOSABLE_EMAIL_DOMAINS")
+	viper.BindEnv("users.disposable_email_domains_file", "APP_USERS_DISPOSABLE_EMAIL_DOMAINS_FILE")
+	viper.BindEnv("users.usernames_enabled", "APP_USERS_USERNAMES_ENABLED")
+	viper.BindEnv("users.admin_bootstrap_enabled", "APP_USERS_ADMIN_BOOTSTRAP_ENABLED")
+	viper.BindEnv("users.admin_downgrade.enabled", "APP_USERS_ADMIN_DOWNGRADE_ENABLED")
+	viper.BindEnv("users.admin_downgrade.inactive_after", "APP_USERS_ADMIN_DOWNGRADE_INACTIVE_AFTER")
+	viper.BindEnv("users.admin_downgrade.interval", "APP_USERS_ADMIN_DOWNGRADE_INTERVAL")
+	viper.BindEnv("users.admin_downgrade.dry_run", "APP_USERS_ADMIN_DOWNGRADE_DRY_RUN")
 
 	// Environment
 	viper.BindEnv("environment", "APP_ENV")
@@ -130,6 +569,39 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server port is required")
 	}
 
+	switch c.Server.TimestampFormat {
+	case "", "rfc3339nano", "rfc3339", "unix":
+	default:
+		return fmt.Errorf("invalid timestamp format %q: must be rfc3339nano, rfc3339 or unix", c.Server.TimestampFormat)
+	}
+	switch c.Server.JSONFieldNaming {
+	case "", "snake_case", "camel_case":
+	default:
+		return fmt.Errorf("invalid json field naming %q: must be snake_case or camel_case", c.Server.JSONFieldNaming)
+	}
+
+	if c.Server.CompressionLevel < 0 || c.Server.CompressionLevel > 9 {
+		return fmt.Errorf("invalid compression level %d: must be between 1 and 9", c.Server.CompressionLevel)
+	}
+
+	switch c.Server.TrailingSlash {
+	case "", TrailingSlashStrip, TrailingSlashRedirect, TrailingSlashStrict:
+	default:
+		return fmt.Errorf("invalid trailing slash mode %q: must be strip, redirect or strict", c.Server.TrailingSlash)
+	}
+
+	if c.Server.RequestIDHeader != "" && !headerNamePattern.MatchString(c.Server.RequestIDHeader) {
+		return fmt.Errorf("invalid request id header %q", c.Server.RequestIDHeader)
+	}
+	for _, name := range c.Server.RequestIDSources {
+		if !headerNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid request id source header %q", name)
+		}
+	}
+	if err := c.Server.validateDeprecations(); err != nil {
+		return err
+	}
+
 	// Validar banco de dados
 	if c.Database.Host == "" {
 		return fmt.Errorf("database host is required")
@@ -144,10 +616,185 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("database name is required")
 	}
 
+	// Validar usuários
+	if c.Users.MaxNameLength < 0 {
+		return fmt.Errorf("invalid max name length %d: must not be negative", c.Users.MaxNameLength)
+	}
+	if c.Users.ExportBatchSize < 0 || c.Users.ExportMaxRows < 0 {
+		return fmt.Errorf("invalid export limits: batch size and max rows must not be negative")
+	}
+	if c.Users.MaxBatchGetIDs < 0 || c.Users.MaxSearchLimit < 0 {
+		return fmt.Errorf("invalid result limits: max batch-get IDs and max search limit must not be negative")
+	}
+	switch c.Users.DeletionPolicy {
+	case "", "delete", "anonymize":
+	default:
+		return fmt.Errorf("invalid deletion policy %q: must be delete or anonymize", c.Users.DeletionPolicy)
+	}
+	if downgrade := c.Users.AdminDowngrade; downgrade.Enabled {
+		// Abaixo de um dia, um admin em uso seria rebaixado entre dois logins
+		if downgrade.InactiveAfter < 24*time.Hour {
+			return fmt.Errorf("invalid admin downgrade inactive_after %s: must be at least 24h", downgrade.InactiveAfter)
+		}
+		if downgrade.Interval <= 0 {
+			return fmt.Errorf("invalid admin downgrade interval %s: must be positive", downgrade.Interval)
+		}
+	}
+
 	// Validar segurança
-	if c.Security.JWTSecret == "" {
+	if len(c.Security.JWTKeys) > 0 {
+		if _, ok := c.Security.JWTKeys[c.Security.JWTActiveKID]; !ok {
+			return fmt.Errorf("jwt active kid %q not found in jwt keys", c.Security.JWTActiveKID)
+		}
+	} else if c.Security.JWTSecret == "" {
 		return fmt.Errorf("jwt secret is required")
 	}
+	if c.Security.PasswordExpirationEnabled && c.Security.PasswordMaxAge < 24*time.Hour {
+		return fmt.Errorf("invalid password max age %s: must be at least 24h when password expiration is enabled", c.Security.PasswordMaxAge)
+	}
+
+	switch c.Security.RateLimitBackend {
+	case "", "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			return fmt.Errorf("redis addr is required when rate limit backend is redis")
+		}
+	default:
+		return fmt.Errorf("invalid rate limit backend %q: must be memory or redis", c.Security.RateLimitBackend)
+	}
+
+	switch c.Security.RateLimitFailMode {
+	case "", "open", "closed":
+	default:
+		return fmt.Errorf("invalid rate limit fail mode %q: must be open or closed", c.Security.RateLimitFailMode)
+	}
+	if c.Security.LoginMaxBodyBytes < 0 {
+		return fmt.Errorf("login max body bytes cannot be negative")
+	}
+	if c.Security.RateLimitWarningThreshold < 0 || c.Security.RateLimitWarningThreshold >= 1 {
+		return fmt.Errorf("invalid rate limit warning threshold %v: must be between 0 and 1", c.Security.RateLimitWarningThreshold)
+	}
+	if c.Security.RateLimitIdleTTL < 0 {
+		return fmt.Errorf("rate limit idle ttl cannot be negative")
+	}
+
+	for _, aud := range c.Security.JWTIssuedAudiences {
+		if strings.TrimSpace(aud) == "" {
+			return fmt.Errorf("jwt issued audiences cannot contain empty values")
+		}
+	}
+	if c.Security.JWTMaxBytes < 0 || c.Security.JWTMaxPermissions < 0 {
+		return fmt.Errorf("jwt token limits cannot be negative")
+	}
+
+	if c.Security.ResetTokenTTL < 0 {
+		return fmt.Errorf("invalid reset token ttl %s: must be positive", c.Security.ResetTokenTTL)
+	}
+	if c.Security.ResetTokenBytes < 0 {
+		return fmt.Errorf("invalid reset token length %d: must not be negative", c.Security.ResetTokenBytes)
+	}
+	if c.Security.ResetTokenMaxAttempts < 0 {
+		return fmt.Errorf("invalid reset token max attempts %d: must be positive", c.Security.ResetTokenMaxAttempts)
+	}
+	if c.Security.PasswordResetLimit < 0 || c.Security.PasswordResetWindow < 0 {
+		return fmt.Errorf("password reset limit and window cannot be negative")
+	}
+	if c.Security.VerificationTokenTTL < 0 {
+		return fmt.Errorf("invalid verification token ttl %s: must be positive", c.Security.VerificationTokenTTL)
+	}
+	if c.Security.VerificationResendLimit < 0 || c.Security.VerificationResendWindow < 0 {
+		return fmt.Errorf("verification resend limit and window cannot be negative")
+	}
+
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		return fmt.Errorf("server timeouts cannot be negative")
+	}
+	if c.Server.MaxHeaderBytes < 0 {
+		return fmt.Errorf("server max header bytes cannot be negative")
+	}
+	if c.Server.RequestTimeout < 0 {
+		return fmt.Errorf("invalid request timeout %s: must not be negative", c.Server.RequestTimeout)
+	}
+	if c.Server.RequestTimeout > 0 && c.Server.RequestTimeout >= c.Server.EffectiveWriteTimeout() {
+		return fmt.Errorf("request timeout (%s) must be smaller than write timeout (%s)", c.Server.RequestTimeout, c.Server.EffectiveWriteTimeout())
+	}
+	if c.Server.ShutdownTimeout < 0 {
+		return fmt.Errorf("server shutdown timeout cannot be negative")
+	}
+
+	switch c.Security.TokenBlacklist {
+	case "", "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			return fmt.Errorf("redis addr is required when token blacklist is redis")
+		}
+	default:
+		return fmt.Errorf("invalid token blacklist %q: must be memory or redis", c.Security.TokenBlacklist)
+	}
+
+	switch c.Security.SessionStore {
+	case "", "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			return fmt.Errorf("redis addr is required when session store is redis")
+		}
+	default:
+		return fmt.Errorf("invalid session store %q: must be memory or redis", c.Security.SessionStore)
+	}
+	if c.Security.MaxSessionsPerUser < 0 {
+		return fmt.Errorf("invalid max sessions per user %d: must not be negative", c.Security.MaxSessionsPerUser)
+	}
+	if c.Security.MaxSessionsPerUser > 0 && c.Security.SessionStore == "" {
+		return fmt.Errorf("max sessions per user requires a session store")
+	}
+
+	// Validar webhooks
+	if len(c.Webhooks.URLs) > 0 && c.Webhooks.Secret == "" {
+		return fmt.Errorf("webhooks secret is required when webhook urls are set")
+	}
+	for _, raw := range c.Webhooks.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url %q", raw)
+		}
+	}
+
+	switch c.Auth.Backend {
+	case "", AuthBackendLocal:
+	case AuthBackendLDAP:
+		if c.Auth.LDAP.URL == "" || c.Auth.LDAP.BaseDN == "" {
+			return fmt.Errorf("ldap url and base dn are required when auth backend is ldap")
+		}
+	default:
+		return fmt.Errorf("invalid auth backend %q: must be local or ldap", c.Auth.Backend)
+	}
+	if c.Auth.OIDC.Enabled() {
+		if c.Auth.OIDC.ClientID == "" || c.Auth.OIDC.ClientSecret == "" || c.Auth.OIDC.RedirectURL == "" {
+			return fmt.Errorf("oidc client id, client secret and redirect url are required when oidc issuer is set")
+		}
+		if !strings.HasPrefix(c.Auth.OIDC.Issuer, "https://") {
+			return fmt.Errorf("invalid oidc issuer %q: must be an https url", c.Auth.OIDC.Issuer)
+		}
+	}
+
+	for _, key := range c.Security.RateLimitExemptAPIKeys {
+		if len(key) < 16 {
+			return fmt.Errorf("rate limit exempt api keys must have at least 16 characters")
+		}
+	}
+	apiKeys := make(map[string]string, len(c.Security.RateLimitTenants))
+	for name, tenant := range c.Security.RateLimitTenants {
+		if len(tenant.APIKey) < 16 {
+			return fmt.Errorf("rate limit tenant %q: api key must have at least 16 characters", name)
+		}
+		if tenant.Limit <= 0 {
+			return fmt.Errorf("rate limit tenant %q: limit must be positive", name)
+		}
+		if other, ok := apiKeys[tenant.APIKey]; ok {
+			return fmt.Errorf("rate limit tenants %q and %q share the same api key", other, name)
+		}
+		apiKeys[tenant.APIKey] = name
+	}
 
 	return nil
 }
@@ -158,6 +805,103 @@ func (c *DatabaseConfig) GetDSN() string {
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
 
+// EffectiveMaxHeaderBytes retorna o limite de headers em vigor, aplicando o padrão do net/http
+func (s *ServerConfig) EffectiveMaxHeaderBytes() int {
+	if s.MaxHeaderBytes <= 0 {
+		return http.DefaultMaxHeaderBytes
+	}
+	return s.MaxHeaderBytes
+}
+
+// EffectiveReadTimeout retorna o prazo de leitura da requisição, com DefaultReadTimeout como padrão
+func (s *ServerConfig) EffectiveReadTimeout() time.Duration {
+	if s.ReadTimeout <= 0 {
+		return DefaultReadTimeout
+	}
+	return s.ReadTimeout
+}
+
+// EffectiveReadHeaderTimeout retorna o prazo de leitura dos headers, usando
+// o prazo de leitura da requisição quando não configurado
+func (s *ServerConfig) EffectiveReadHeaderTimeout() time.Duration {
+	if s.ReadHeaderTimeout <= 0 {
+		return s.EffectiveReadTimeout()
+	}
+	return s.ReadHeaderTimeout
+}
+
+// EffectiveWriteTimeout retorna o prazo da resposta, com DefaultWriteTimeout como padrão
+func (s *ServerConfig) EffectiveWriteTimeout() time.Duration {
+	if s.WriteTimeout <= 0 {
+		return DefaultWriteTimeout
+	}
+	return s.WriteTimeout
+}
+
+// EffectiveIdleTimeout retorna o prazo de conexões ociosas, com DefaultIdleTimeout como padrão
+func (s *ServerConfig) EffectiveIdleTimeout() time.Duration {
+	if s.IdleTimeout <= 0 {
+		return DefaultIdleTimeout
+	}
+	return s.IdleTimeout
+}
+
+// validateDeprecations verifica rotas, datas e links de server.deprecations
+func (s *ServerConfig) validateDeprecations() error {
+	seen := make(map[string]struct{}, len(s.Deprecations))
+	for _, d := range s.Deprecations {
+		if !strings.HasPrefix(d.Path, "/") {
+			return fmt.Errorf("deprecation: path %q must start with /", d.Path)
+		}
+		key := strings.ToUpper(d.Method) + " " + d.Path
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("deprecation: duplicate route %q", strings.TrimSpace(key))
+		}
+		seen[key] = struct{}{}
+
+		if d.Since == "" {
+			return fmt.Errorf("deprecation %s: since is required", d.Path)
+		}
+		since, err := ParseDeprecationTime(d.Since)
+		if err != nil {
+			return fmt.Errorf("deprecation %s: invalid since %q: use RFC3339 or YYYY-MM-DD", d.Path, d.Since)
+		}
+		sunset, err := ParseDeprecationTime(d.Sunset)
+		if err != nil {
+			return fmt.Errorf("deprecation %s: invalid sunset %q: use RFC3339 or YYYY-MM-DD", d.Path, d.Sunset)
+		}
+		if !sunset.IsZero() && !sunset.After(since) {
+			return fmt.Errorf("deprecation %s: sunset must be after since", d.Path)
+		}
+		if d.Link != "" {
+			if u, err := url.Parse(d.Link); err != nil || !u.IsAbs() {
+				return fmt.Errorf("deprecation %s: link %q must be an absolute URL", d.Path, d.Link)
+			}
+		}
+	}
+	return nil
+}
+
+// EffectiveTrailingSlash retorna o modo de barra final, com strip como padrão
+func (s *ServerConfig) EffectiveTrailingSlash() string {
+	if s.TrailingSlash == "" {
+		return TrailingSlashStrip
+	}
+	return s.TrailingSlash
+}
+
+// CORSOriginsFor retorna as origens CORS permitidas para um grupo de rotas,
+// usando as origens padrão quando o grupo não possui configuração própria
+func (s *SecurityConfig) CORSOriginsFor(group string) []string {
+	if origins, ok := s.CORSGroups[group]; ok {
+		return origins
+	}
+	if len(s.CORSOrigins) == 0 {
+		return []string{"*"}
+	}
+	return s.CORSOrigins
+}
+
 // IsDevelopment retorna true se o ambiente for development
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-api-boilerplate/pkg/config"
+)
+
+// connectTimeout limita o ping inicial e o aquecimento do pool
+const connectTimeout = 30 * time.Second
+
+// Open abre a conexão com o banco aplicando as configurações do pool.
+// Quando database.warmup está habilitado (e fora do modo de testes), pré-aquece o pool
+func Open(cfg *config.Config, logger *slog.Logger) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.Database.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	if cfg.Database.WarmUp && !cfg.IsTesting() {
+		if err := WarmUp(ctx, db, cfg.Database.MaxIdleConns, logger); err != nil {
+			// O aquecimento é uma otimização; a falha não impede a inicialização
+			logger.Warn("Database pool warmup failed", "error", err)
+		}
+	}
+
+	return db, nil
+}
+
+// WarmUp abre conns conexões simultâneas e executa SELECT 1 em cada uma,
+// devolvendo-as ao pool como ociosas. Evita que as primeiras requisições
+// após o deploy paguem o custo de estabelecer conexões
+func WarmUp(ctx context.Context, db *sql.DB, conns int, logger *slog.Logger) error {
+	if conns <= 0 {
+		return nil
+	}
+
+	start := time.Now()
+	opened := make([]*sql.Conn, 0, conns)
+	defer func() {
+		for _, conn := range opened {
+			conn.Close()
+		}
+	}()
+
+	// As conexões são mantidas abertas até o fim para forçar conexões distintas
+	for i := 0; i < conns; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open connection %d: %w", i+1, err)
+		}
+		opened = append(opened, conn)
+
+		if _, err := conn.ExecContext(ctx, "SELECT 1"); err != nil {
+			return fmt.Errorf("failed to prime connection %d: %w", i+1, err)
+		}
+	}
+
+	logger.Info("Database pool warmed up",
+		"connections", conns,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	return nil
+}
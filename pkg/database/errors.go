@@ -0,0 +1,55 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// transientCodes são SQLSTATEs de falhas passageiras, em que repetir a
+// operação inteira pode ter sucesso
+var transientCodes = map[pq.ErrorCode]struct{}{
+	"40001": {}, // serialization_failure
+	"40P01": {}, // deadlock_detected
+	"53300": {}, // too_many_connections
+	"55P03": {}, // lock_not_available
+	"57P01": {}, // admin_shutdown
+	"57P03": {}, // cannot_connect_now
+}
+
+// IsTransient informa se o erro do Postgres é passageiro: falhas de
+// serialização, deadlocks, excesso de conexões, reinício do servidor ou perda
+// da conexão (classe 08). Erros de dados e de constraints não são
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	if _, ok := transientCodes[pqErr.Code]; ok {
+		return true
+	}
+	return strings.HasPrefix(string(pqErr.Code), "08")
+}
+
+// IsQueryCanceled informa se a consulta foi cancelada no servidor (57014): o
+// driver pede o cancelamento quando o contexto da requisição expira ou é
+// cancelado no meio da consulta, e statement_timeout também produz esse erro
+func IsQueryCanceled(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "57014"
+}
+
+// IsUniqueViolation informa se o erro é uma violação de unicidade (23505)
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
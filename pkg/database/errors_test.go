@@ -0,0 +1,47 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"serialization failure", &pq.Error{Code: "40001"}, true},
+		{"wrapped deadlock", fmt.Errorf("failed to update user: %w", &pq.Error{Code: "40P01"}), true},
+		{"connection failure", &pq.Error{Code: "08006"}, true},
+		{"bad connection", fmt.Errorf("query: %w", driver.ErrBadConn), true},
+		{"unique violation", &pq.Error{Code: "23505"}, false},
+		{"other error", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	assert.True(t, IsUniqueViolation(fmt.Errorf("insert: %w", &pq.Error{Code: "23505"})))
+	assert.False(t, IsUniqueViolation(&pq.Error{Code: "23503"}))
+	assert.False(t, IsUniqueViolation(errors.New("boom")))
+	assert.False(t, IsUniqueViolation(nil))
+}
+
+func TestIsQueryCanceled(t *testing.T) {
+	assert.True(t, IsQueryCanceled(fmt.Errorf("get user: %w", &pq.Error{Code: "57014"})))
+	assert.False(t, IsQueryCanceled(&pq.Error{Code: "57P01"}))
+	assert.False(t, IsQueryCanceled(errors.New("boom")))
+	assert.False(t, IsQueryCanceled(nil))
+}
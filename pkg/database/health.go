@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// healthCheckTimeout limita cada verificação quando o contexto não tem prazo
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck verifica se o banco responde. Um ping bem-sucedido também
+// descarta do pool conexões mortas (ex.: após reinício do banco)
+func HealthCheck(ctx context.Context, db *sql.DB) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database health check failed: %w", err)
+	}
+	return nil
+}
+
+// HealthProbe executa HealthCheck periodicamente em background, registrando
+// falhas em log e repassando cada resultado a onResult (ex.: métricas).
+// Close deve ser chamado no desligamento
+type HealthProbe struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartHealthProbe inicia a verificação a cada interval. onResult é opcional.
+// Retorna nil quando interval <= 0 (verificação desabilitada)
+func StartHealthProbe(db *sql.DB, interval time.Duration, logger *slog.Logger, onResult func(err error)) *HealthProbe {
+	if interval <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &HealthProbe{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		RunHealthProbe(ctx, db, interval, logger, onResult)
+	}()
+
+	return p
+}
+
+// Close interrompe a verificação e aguarda o término da execução em andamento.
+// Seguro para receptor nil
+func (p *HealthProbe) Close() {
+	if p == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+}
+
+// RunHealthProbe verifica o banco a cada interval até ctx ser cancelado,
+// bloqueando enquanto isso. É a forma usada com worker.Manager; interval <= 0
+// retorna imediatamente. onResult é opcional
+func RunHealthProbe(ctx context.Context, db *sql.DB, interval time.Duration, logger *slog.Logger, onResult func(err error)) {
+	if interval <= 0 {
+		return
+	}
+	if onResult == nil {
+		onResult = func(error) {}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			healthy = checkHealth(ctx, db, logger, onResult, healthy)
+		}
+	}
+}
+
+// checkHealth executa uma verificação e registra mudanças de estado em log.
+// Cancelamentos do desligamento não são reportados como falha
+func checkHealth(ctx context.Context, db *sql.DB, logger *slog.Logger, onResult func(err error), wasHealthy bool) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	err := HealthCheck(ctx, db)
+	if err != nil && errors.Is(err, context.Canceled) {
+		return wasHealthy
+	}
+	onResult(err)
+
+	switch {
+	case err != nil:
+		logger.Error("Database health probe failed", "error", err)
+		return false
+	case !wasHealthy:
+		logger.Info("Database health probe recovered")
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/lib/pq"
+)
+
+// unreachableDB abre um pool apontando para uma porta sem servidor
+func unreachableDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=postgres dbname=none sslmode=disable connect_timeout=1")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestHealthCheckFailsForUnreachableDatabase(t *testing.T) {
+	err := HealthCheck(context.Background(), unreachableDB(t))
+	assert.ErrorContains(t, err, "database health check failed")
+}
+
+func TestHealthProbeReportsFailuresAndStops(t *testing.T) {
+	results := make(chan error, 10)
+	probe := StartHealthProbe(unreachableDB(t), 10*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)), func(err error) {
+		select {
+		case results <- err:
+		default:
+		}
+	})
+	require.NotNil(t, probe)
+
+	select {
+	case err := <-results:
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("probe did not run")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		probe.Close()
+		probe.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Close did not stop the probe")
+	}
+}
+
+func TestHealthProbeDisabled(t *testing.T) {
+	probe := StartHealthProbe(nil, 0, nil, nil)
+	assert.Nil(t, probe)
+	probe.Close()
+}
+
+func TestRunHealthProbeStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		RunHealthProbe(ctx, unreachableDB(t), 10*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("RunHealthProbe did not stop on cancel")
+	}
+}
@@ -1,54 +1,148 @@
 package database
 
 import (
+	"bufio"
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
-	"github.com/pressly/goose/v3"
 	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
 )
 
+// migrationLockKey identifica o advisory lock das migrações (pg_advisory_lock(hashtext(key)))
+const migrationLockKey = "goose:migrations"
+
+// ErrMigrationLockTimeout indica que outra instância manteve o lock das
+// migrações além do prazo configurado
+var ErrMigrationLockTimeout = errors.New("timed out waiting for the migration lock")
+
 // Migrator gerencia migrações do banco de dados
 type Migrator struct {
 	db     *sql.DB
 	logger *slog.Logger
+
+	lock        bool
+	lockTimeout time.Duration
+}
+
+// MigratorOption configura comportamentos opcionais do Migrator
+type MigratorOption func(*Migrator)
+
+// WithMigrationLock habilita ou desabilita o advisory lock das migrações
+// (habilitado por padrão)
+func WithMigrationLock(enabled bool) MigratorOption {
+	return func(m *Migrator) {
+		m.lock = enabled
+	}
+}
+
+// WithMigrationLockTimeout limita a espera pelo lock das migrações; 0 espera
+// indefinidamente
+func WithMigrationLockTimeout(timeout time.Duration) MigratorOption {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
 }
 
 // NewMigrator cria uma nova instância de Migrator
-func NewMigrator(db *sql.DB, logger *slog.Logger) *Migrator {
-	return &Migrator{
+func NewMigrator(db *sql.DB, logger *slog.Logger, opts ...MigratorOption) *Migrator {
+	m := &Migrator{
 		db:     db,
 		logger: logger,
+		lock:   true,
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
 
-// RunMigrations executa todas as migrações pendentes
+// RunMigrations executa todas as migrações pendentes. Com o lock habilitado,
+// instâncias concorrentes aguardam a primeira terminar e então não encontram
+// nada pendente
 func (m *Migrator) RunMigrations(migrationsDir string) error {
 	m.logger.Info("Starting database migrations", "dir", migrationsDir)
-	
+
 	// Configurar goose
 	goose.SetLogger(m.createGooseLogger())
-	
+
 	// Executar migrações
-	if err := goose.Up(m.db, migrationsDir); err != nil {
+	err := m.withLock(context.Background(), func() error {
+		return goose.Up(m.db, migrationsDir)
+	})
+	if err != nil {
 		m.logger.Error("Failed to run migrations", "error", err)
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
-	
+
 	m.logger.Info("Database migrations completed successfully")
 	return nil
 }
 
+// withLock executa fn segurando o advisory lock das migrações em uma conexão
+// dedicada. O lock é de sessão: é liberado ao fim, inclusive em erro ou pânico
+// de fn, e o Postgres o libera se a conexão cair
+func (m *Migrator) withLock(ctx context.Context, fn func() error) (err error) {
+	if !m.lock {
+		return fn()
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	lockCtx := ctx
+	if m.lockTimeout > 0 {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, m.lockTimeout)
+		defer cancel()
+	}
+
+	m.logger.Info("Waiting for migration lock")
+	if _, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockKey); err != nil {
+		if errors.Is(lockCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s", ErrMigrationLockTimeout, m.lockTimeout)
+		}
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	m.logger.Info("Migration lock acquired")
+
+	defer func() {
+		// Contexto próprio: o lock precisa ser liberado mesmo com ctx cancelado
+		if _, unlockErr := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", migrationLockKey); unlockErr != nil {
+			m.logger.Error("Failed to release migration lock", "error", unlockErr)
+			// Descarta a conexão para que o Postgres libere o lock da sessão
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+			if err == nil {
+				err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+			}
+		}
+	}()
+
+	return fn()
+}
+
 // CreateMigrationsTable cria a tabela de controle de migrações se não existir
 func (m *Migrator) CreateMigrationsTable() error {
 	m.logger.Info("Creating migrations table")
-	
+
 	if err := goose.Up(m.db, "."); err != nil {
 		m.logger.Error("Failed to create migrations table", "error", err)
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
-	
+
 	m.logger.Info("Migrations table created successfully")
 	return nil
 }
@@ -56,28 +150,300 @@ func (m *Migrator) CreateMigrationsTable() error {
 // GetMigrationStatus retorna o status das migrações
 func (m *Migrator) GetMigrationStatus(migrationsDir string) error {
 	m.logger.Info("Checking migration status", "dir", migrationsDir)
-	
+
 	if err := goose.Status(m.db, migrationsDir); err != nil {
 		m.logger.Error("Failed to get migration status", "error", err)
 		return fmt.Errorf("failed to get migration status: %w", err)
 	}
-	
+
 	return nil
 }
 
-// Rollback executa rollback da última migração
+// PlannedMigration é uma migração pendente que RunMigrations aplicaria
+type PlannedMigration struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+}
+
+// ErrMissingMigrations indica migrações pendentes com versão anterior à atual
+// do banco, que RunMigrations recusa aplicar fora de ordem
+var ErrMissingMigrations = errors.New("found pending migrations older than the current version")
+
+// Plan retorna, em ordem, as migrações pendentes que RunMigrations aplicaria,
+// sem executá-las nem alterar o banco (nem mesmo criar a tabela de versões).
+// Migrações pendentes anteriores à versão atual retornam ErrMissingMigrations,
+// como RunMigrations faria
+func (m *Migrator) Plan(migrationsDir string) ([]PlannedMigration, error) {
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+
+	applied, err := m.appliedVersions(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	var current int64
+	for version := range applied {
+		current = max(current, version)
+	}
+
+	plan := []PlannedMigration{}
+	var missing []int64
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if migration.Version < current {
+			missing = append(missing, migration.Version)
+		}
+		plan = append(plan, PlannedMigration{
+			Version: migration.Version,
+			Name:    filepath.Base(migration.Source),
+			Path:    migration.Source,
+		})
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %v (current %d)", ErrMissingMigrations, missing, current)
+	}
+
+	return plan, nil
+}
+
+// appliedVersions lê as versões aplicadas da tabela do goose, sem criá-la. Em
+// tabelas antigas, linhas com is_applied = false desfazem a versão
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]struct{}, error) {
+	applied := make(map[int64]struct{})
+
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", goose.TableName()).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check migration table: %w", err)
+	}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, fmt.Sprintf("SELECT version_id, is_applied FROM %s ORDER BY id", goose.TableName()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		if err := rows.Scan(&version, &isApplied); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		if isApplied {
+			applied[version] = struct{}{}
+		} else {
+			delete(applied, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+
+	// A versão 0 é a linha inicial criada pelo goose, não uma migração
+	delete(applied, 0)
+	return applied, nil
+}
+
+// CurrentVersion retorna a versão atual do schema aplicada no banco
+func (m *Migrator) CurrentVersion(ctx context.Context) (int64, error) {
+	version, err := goose.GetDBVersionContext(ctx, m.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Rollback executa rollback da última migração. Como DownTo, recusa com
+// ErrIrreversibleMigration uma migração sem seção Down
 func (m *Migrator) Rollback(migrationsDir string) error {
 	m.logger.Info("Rolling back last migration", "dir", migrationsDir)
-	
-	if err := goose.Down(m.db, migrationsDir); err != nil {
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	version, err := m.CurrentVersion(context.Background())
+	if err != nil {
+		return err
+	}
+	if err := m.checkReversible(migrations, version-1); err != nil {
+		return err
+	}
+
+	err = m.withLock(context.Background(), func() error {
+		return goose.Down(m.db, migrationsDir)
+	})
+	if err != nil {
 		m.logger.Error("Failed to rollback migration", "error", err)
 		return fmt.Errorf("failed to rollback migration: %w", err)
 	}
-	
+
 	m.logger.Info("Migration rollback completed successfully")
 	return nil
 }
 
+// ErrUnknownMigrationVersion indica uma versão alvo sem migração correspondente
+var ErrUnknownMigrationVersion = errors.New("unknown migration version")
+
+// UpTo aplica as migrações pendentes até version, inclusive. version precisa
+// ser a versão de uma migração de migrationsDir
+func (m *Migrator) UpTo(migrationsDir string, version int64) error {
+	m.logger.Info("Migrating up to version", "dir", migrationsDir, "version", version)
+
+	if err := m.validateVersion(migrationsDir, version, false); err != nil {
+		return err
+	}
+
+	goose.SetLogger(m.createGooseLogger())
+	err := m.withLock(context.Background(), func() error {
+		return goose.UpTo(m.db, migrationsDir, version)
+	})
+	if err != nil {
+		m.logger.Error("Failed to migrate up to version", "version", version, "error", err)
+		return fmt.Errorf("failed to migrate up to version %d: %w", version, err)
+	}
+
+	m.logger.Info("Migrated up to version", "version", version)
+	return nil
+}
+
+// DownTo desfaz as migrações aplicadas acima de version, que permanece
+// aplicada. version precisa ser a versão de uma migração de migrationsDir, ou
+// 0 para desfazer todas. Se alguma das migrações desfeitas não tiver seção
+// Down, o retorno é ErrIrreversibleMigration e nada é executado
+func (m *Migrator) DownTo(migrationsDir string, version int64) error {
+	m.logger.Info("Migrating down to version", "dir", migrationsDir, "version", version)
+
+	if err := m.validateVersion(migrationsDir, version, true); err != nil {
+		return err
+	}
+
+	goose.SetLogger(m.createGooseLogger())
+	err := m.withLock(context.Background(), func() error {
+		return goose.DownTo(m.db, migrationsDir, version)
+	})
+	if err != nil {
+		m.logger.Error("Failed to migrate down to version", "version", version, "error", err)
+		return fmt.Errorf("failed to migrate down to version %d: %w", version, err)
+	}
+
+	m.logger.Info("Migrated down to version", "version", version)
+	return nil
+}
+
+// ErrIrreversibleMigration indica um rollback que passaria por uma migração
+// sem seção Down. O goose removeria a versão da tabela sem desfazer o schema
+var ErrIrreversibleMigration = errors.New("migration has no down section")
+
+// validateVersion verifica se version corresponde a uma migração de
+// migrationsDir. down indica um rollback até version: aceita 0 (nenhuma
+// migração aplicada) e exige seção Down em todas as migrações aplicadas acima
+// de version
+func (m *Migrator) validateVersion(migrationsDir string, version int64, down bool) error {
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return fmt.Errorf("failed to collect migrations: %w", err)
+	}
+
+	known := version == 0 && down
+	for _, migration := range migrations {
+		if migration.Version == version {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("%w: %d", ErrUnknownMigrationVersion, version)
+	}
+	if !down {
+		return nil
+	}
+
+	return m.checkReversible(migrations, version)
+}
+
+// checkReversible retorna ErrIrreversibleMigration se alguma migração aplicada
+// acima de version não tiver seção Down
+func (m *Migrator) checkReversible(migrations goose.Migrations, version int64) error {
+	applied, err := m.appliedVersions(context.Background())
+	if err != nil {
+		return err
+	}
+	var reverted []*goose.Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			reverted = append(reverted, migration)
+		}
+	}
+	irreversible, err := migrationsWithoutDown(reverted)
+	if err != nil {
+		return err
+	}
+	if len(irreversible) > 0 {
+		return fmt.Errorf("%w: %v", ErrIrreversibleMigration, irreversible)
+	}
+	return nil
+}
+
+// migrationsWithoutDown retorna, em ordem, as versões das migrações SQL sem
+// comandos na seção Down. Migrações em Go não são verificadas
+func migrationsWithoutDown(migrations []*goose.Migration) ([]int64, error) {
+	var versions []int64
+	for _, migration := range migrations {
+		if filepath.Ext(migration.Source) != ".sql" {
+			continue
+		}
+		ok, err := hasDownSection(migration.Source)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			versions = append(versions, migration.Version)
+		}
+	}
+	return versions, nil
+}
+
+// hasDownSection informa se o arquivo tem a anotação "-- +goose Down" seguida
+// de ao menos um comando (linhas que não são comentários)
+func hasDownSection(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read migration: %w", err)
+	}
+	defer file.Close()
+
+	inDown := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if annotation, ok := strings.CutPrefix(line, "-- +goose "); ok {
+			// StatementBegin/End e outras anotações não mudam a seção
+			switch strings.ToLower(strings.TrimSpace(annotation)) {
+			case "up":
+				inDown = false
+			case "down":
+				inDown = true
+			}
+			continue
+		}
+		if inDown && line != "" && !strings.HasPrefix(line, "--") {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read migration: %w", err)
+	}
+	return false, nil
+}
+
 // createGooseLogger cria um logger compatível com goose
 func (m *Migrator) createGooseLogger() goose.Logger {
 	return &gooseLogger{logger: m.logger}
@@ -106,4 +472,4 @@ func (l *gooseLogger) Println(v ...interface{}) {
 
 func (l *gooseLogger) Printf(format string, v ...interface{}) {
 	l.logger.Info("Goose info", "message", fmt.Sprintf(format, v...))
-} 
\ No newline at end of file
+}
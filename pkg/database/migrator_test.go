@@ -0,0 +1,49 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMigrationsWithoutDown garante que, das migrações do repositório, apenas
+// a criação da tabela users não pode ser desfeita por DownTo e Rollback
+func TestMigrationsWithoutDown(t *testing.T) {
+	migrations, err := goose.CollectMigrations("../../sql/migrations", 0, goose.MaxVersion)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	irreversible, err := migrationsWithoutDown(migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, irreversible)
+}
+
+func TestHasDownSection(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{"up only", "-- +goose Up\nCREATE TABLE t (id INT);\n", false},
+		{"empty down", "-- +goose Up\nCREATE TABLE t (id INT);\n\n-- +goose Down\n", false},
+		{"down with comments only", "-- +goose Up\nCREATE TABLE t (id INT);\n\n-- +goose Down\n-- nada a desfazer\n", false},
+		{"down", "-- +goose Up\nCREATE TABLE t (id INT);\n\n-- +goose Down\nDROP TABLE t;\n", true},
+		{"down with statement block", "-- +goose Up\nCREATE TABLE t (id INT);\n\n-- +goose Down\n-- +goose StatementBegin\nDROP TABLE t;\n-- +goose StatementEnd\n", true},
+		{"statement block in up", "-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE t (id INT);\n-- +goose StatementEnd\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "00001_test.sql")
+			require.NoError(t, os.WriteFile(path, []byte(tt.source), 0o600))
+
+			got, err := hasDownSection(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RunPoolStatsSampler repassa db.Stats() a onSample imediatamente e a cada
+// interval até ctx ser cancelado, bloqueando enquanto isso. É a forma usada
+// com worker.Manager; interval <= 0 ou onSample nil retornam imediatamente
+func RunPoolStatsSampler(ctx context.Context, db *sql.DB, interval time.Duration, onSample func(sql.DBStats)) {
+	if interval <= 0 || onSample == nil {
+		return
+	}
+
+	onSample(db.Stats())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			onSample(db.Stats())
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolStatsSamplerRunsUntilCanceled(t *testing.T) {
+	db := unreachableDB(t)
+	db.SetMaxOpenConns(7)
+
+	samples := make(chan sql.DBStats, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunPoolStatsSampler(ctx, db, 10*time.Millisecond, func(stats sql.DBStats) {
+			select {
+			case samples <- stats:
+			default:
+			}
+		})
+	}()
+
+	// A primeira amostra é imediata, sem esperar o intervalo
+	select {
+	case stats := <-samples:
+		assert.Equal(t, 7, stats.MaxOpenConnections)
+	case <-time.After(time.Second):
+		t.Fatal("sampler did not report")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sampler did not stop on cancel")
+	}
+}
+
+func TestPoolStatsSamplerDisabled(t *testing.T) {
+	called := false
+	RunPoolStatsSampler(context.Background(), unreachableDB(t), 0, func(sql.DBStats) { called = true })
+	assert.False(t, called)
+}
@@ -1,30 +1,55 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
+
+	"go-api-boilerplate/pkg/config"
 )
 
-// New cria uma nova instância do logger configurado
+// New cria uma nova instância do logger configurado, mascarando DefaultRedactFields
 func New(level string) *slog.Logger {
-	var logLevel slog.Level
+	return NewFromConfig(config.LoggingConfig{Level: level})
+}
+
+// NewFromConfig cria o logger com nível, formato e campos mascarados da configuração
+func NewFromConfig(cfg config.LoggingConfig) *slog.Logger {
+	return slog.New(newHandler(os.Stdout, cfg))
+}
+
+// newHandler monta o handler de saída envolvido pelo RequestIDHandler e pelo RedactHandler
+func newHandler(w io.Writer, cfg config.LoggingConfig) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level: parseLevel(cfg.Level),
+	}
+
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	fields := cfg.RedactFields
+	if len(fields) == 0 {
+		fields = DefaultRedactFields
+	}
+	return NewRedactHandler(NewRequestIDHandler(handler), fields)
+}
+
+// parseLevel converte o nível configurado; valores desconhecidos usam info
+func parseLevel(level string) slog.Level {
 	switch level {
 	case "debug":
-		logLevel = slog.LevelDebug
+		return slog.LevelDebug
 	case "info":
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	case "warn":
-		logLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		logLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	}
-
-	opts := &slog.HandlerOptions{
-		Level: logLevel,
-	}
-
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	return slog.New(handler)
 }
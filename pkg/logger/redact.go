@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// RedactedValue substitui o valor de atributos sensíveis
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactFields são as chaves mascaradas quando nenhuma é configurada
+var DefaultRedactFields = []string{"email", "password", "token", "authorization"}
+
+// RedactHandler mascara atributos sensíveis antes de repassá-los ao handler
+// seguinte. As chaves são comparadas sem diferenciar maiúsculas, inclusive
+// dentro de grupos e em atributos adicionados via With
+type RedactHandler struct {
+	next   slog.Handler
+	fields map[string]struct{}
+}
+
+// NewRedactHandler envolve next mascarando os campos informados
+func NewRedactHandler(next slog.Handler, fields []string) *RedactHandler {
+	set := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(strings.TrimSpace(field))] = struct{}{}
+	}
+	return &RedactHandler{next: next, fields: set}
+}
+
+// Enabled implementa slog.Handler
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implementa slog.Handler
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redact(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs implementa slog.Handler
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(a)
+	}
+	return &RedactHandler{next: h.next.WithAttrs(redacted), fields: h.fields}
+}
+
+// WithGroup implementa slog.Handler
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{next: h.next.WithGroup(name), fields: h.fields}
+}
+
+// redact mascara o atributo se a chave for sensível, percorrendo grupos
+func (h *RedactHandler) redact(a slog.Attr) slog.Attr {
+	if _, ok := h.fields[strings.ToLower(a.Key)]; ok {
+		return slog.String(a.Key, RedactedValue)
+	}
+
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	group := a.Value.Group()
+	redacted := make([]slog.Attr, len(group))
+	for i, ga := range group {
+		redacted[i] = h.redact(ga)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
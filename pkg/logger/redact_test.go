@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go-api-boilerplate/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHandlerMasksSensitiveAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(newHandler(&buf, config.LoggingConfig{}))
+
+	log.With("Authorization", "Bearer abc").
+		WithGroup("req").
+		Info("login", "email", "ana@example.com", "ip", "203.0.113.7",
+			slog.Group("user", "email", "ana@example.com", "id", "42"))
+
+	assert.NotContains(t, buf.String(), "ana@example.com")
+	assert.NotContains(t, buf.String(), "Bearer abc")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, RedactedValue, entry["Authorization"])
+
+	req := entry["req"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, req["email"])
+	assert.Equal(t, "203.0.113.7", req["ip"])
+	nested := req["user"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, nested["email"])
+	assert.Equal(t, "42", nested["id"])
+}
+
+func TestRedactHandlerUsesConfiguredFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(newHandler(&buf, config.LoggingConfig{RedactFields: []string{"user_agent"}}))
+
+	log.Info("request", "user_agent", "curl/8.0", "email", "ana@example.com")
+
+	assert.NotContains(t, buf.String(), "curl/8.0")
+	assert.Contains(t, buf.String(), "ana@example.com", "only configured fields are redacted")
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go-api-boilerplate/pkg/requestid"
+)
+
+// RequestIDKey é a chave do ID de correlação nos logs
+const RequestIDKey = "request_id"
+
+// RequestIDHandler acrescenta o ID de correlação (requestid.FromContext) aos
+// registros feitos com o contexto da requisição (InfoContext, ErrorContext...).
+// Registros que já trazem request_id, no próprio registro ou via With, não
+// são alterados
+type RequestIDHandler struct {
+	next         slog.Handler
+	hasRequestID bool
+}
+
+// NewRequestIDHandler envolve next acrescentando o ID de correlação
+func NewRequestIDHandler(next slog.Handler) *RequestIDHandler {
+	return &RequestIDHandler{next: next}
+}
+
+// Enabled implementa slog.Handler
+func (h *RequestIDHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implementa slog.Handler
+func (h *RequestIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	id := requestid.FromContext(ctx)
+	if id == "" || h.hasRequestID || recordHasKey(r, RequestIDKey) {
+		return h.next.Handle(ctx, r)
+	}
+
+	r = r.Clone()
+	r.AddAttrs(slog.String(RequestIDKey, id))
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implementa slog.Handler
+func (h *RequestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	has := h.hasRequestID
+	for _, a := range attrs {
+		if a.Key == RequestIDKey {
+			has = true
+		}
+	}
+	return &RequestIDHandler{next: h.next.WithAttrs(attrs), hasRequestID: has}
+}
+
+// WithGroup implementa slog.Handler
+func (h *RequestIDHandler) WithGroup(name string) slog.Handler {
+	return &RequestIDHandler{next: h.next.WithGroup(name), hasRequestID: h.hasRequestID}
+}
+
+// recordHasKey informa se o registro tem um atributo de primeiro nível com key
+func recordHasKey(r slog.Record, key string) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == key
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go-api-boilerplate/pkg/config"
+	"go-api-boilerplate/pkg/requestid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDHandlerAddsIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(newHandler(&buf, config.LoggingConfig{}))
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	entries := func() []map[string]interface{} {
+		var out []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			out = append(out, entry)
+		}
+		buf.Reset()
+		return out
+	}
+
+	log.InfoContext(ctx, "with context")
+	log.Info("without context")
+	got := entries()
+	assert.Equal(t, "req-1", got[0][RequestIDKey])
+	assert.NotContains(t, got[1], RequestIDKey)
+
+	// Um request_id explícito prevalece e não é duplicado
+	log.InfoContext(ctx, "explicit", RequestIDKey, "req-2")
+	log.With(RequestIDKey, "req-3").InfoContext(ctx, "via with")
+	assert.Equal(t, 1, strings.Count(buf.String(), `"request_id":"req-2"`))
+	assert.Equal(t, 1, strings.Count(buf.String(), `"request_id":"req-3"`))
+	assert.NotContains(t, buf.String(), "req-1")
+}
@@ -0,0 +1,27 @@
+// Package requestid carrega o ID de correlação da requisição no
+// context.Context, para que camadas sem acesso ao HTTP (casos de uso, eventos,
+// webhooks e logs) o repassem aos efeitos colaterais da requisição
+package requestid
+
+import "context"
+
+// contextKey é a chave privada do ID no contexto
+type contextKey struct{}
+
+// NewContext retorna uma cópia de ctx com o ID de correlação; ID vazio
+// retorna ctx inalterado
+func NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext retorna o ID de correlação de ctx, ou vazio se não houver
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,22 @@
+package requestid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, FromContext(ctx))
+	assert.Equal(t, ctx, NewContext(ctx, ""))
+
+	ctx = NewContext(ctx, "req-1")
+	assert.Equal(t, "req-1", FromContext(ctx))
+
+	// Contextos derivados mantêm o ID
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	assert.Equal(t, "req-1", FromContext(child))
+}
@@ -10,19 +10,36 @@ import (
 // CustomValidator implementa validação customizada
 type CustomValidator struct {
 	validator *validator.Validate
+	roles     func() []string
+}
+
+// Option configura o CustomValidator
+type Option func(*CustomValidator)
+
+// WithRoles informa os papéis aceitos pela tag role. A função é consultada a
+// cada validação, então acompanha papéis registrados depois da criação. Sem
+// ela, a tag role recusa qualquer valor
+func WithRoles(roles func() []string) Option {
+	return func(cv *CustomValidator) {
+		cv.roles = roles
+	}
 }
 
 // NewCustomValidator cria uma nova instância de CustomValidator
-func NewCustomValidator() *CustomValidator {
-	v := validator.New()
+func NewCustomValidator(opts ...Option) *CustomValidator {
+	cv := &CustomValidator{
+		validator: validator.New(),
+		roles:     func() []string { return nil },
+	}
+	for _, opt := range opts {
+		opt(cv)
+	}
 
 	// Registra validações customizadas
-	v.RegisterValidation("password", validatePassword)
-	v.RegisterValidation("role", validateRole)
+	cv.validator.RegisterValidation("password", validatePassword)
+	cv.validator.RegisterValidation("role", cv.validateRole)
 
-	return &CustomValidator{
-		validator: v,
-	}
+	return cv
 }
 
 // Validate valida uma struct
@@ -41,7 +58,7 @@ func (cv *CustomValidator) GetValidationErrors(err error) []string {
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, e := range validationErrors {
-			errors = append(errors, formatValidationError(e))
+			errors = append(errors, cv.formatValidationError(e))
 		}
 	} else {
 		errors = append(errors, err.Error())
@@ -75,22 +92,19 @@ func validatePassword(fl validator.FieldLevel) bool {
 	return hasLetter && hasNumber
 }
 
-// validateRole valida se o role é válido
-func validateRole(fl validator.FieldLevel) bool {
+// validateRole valida se o role está entre os papéis informados em WithRoles
+func (cv *CustomValidator) validateRole(fl validator.FieldLevel) bool {
 	role := fl.Field().String()
-	validRoles := []string{"admin", "user", "guest"}
-
-	for _, validRole := range validRoles {
-		if role == validRole {
+	for _, valid := range cv.roles() {
+		if role == valid {
 			return true
 		}
 	}
-
 	return false
 }
 
 // formatValidationError formata um erro de validação
-func formatValidationError(e validator.FieldError) string {
+func (cv *CustomValidator) formatValidationError(e validator.FieldError) string {
 	field := strings.ToLower(e.Field())
 
 	switch e.Tag() {
@@ -105,7 +119,7 @@ func formatValidationError(e validator.FieldError) string {
 	case "password":
 		return fmt.Sprintf("%s must be at least 6 characters long and contain both letters and numbers", field)
 	case "role":
-		return fmt.Sprintf("%s must be one of: admin, user, guest", field)
+		return fmt.Sprintf("%s must be one of: %s", field, strings.Join(cv.roles(), ", "))
 	default:
 		return fmt.Sprintf("%s failed validation: %s", field, e.Tag())
 	}
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+// Manager acompanha as goroutines de background da aplicação (probes,
+// limpezas periódicas, relays) para que terminem de forma ordenada no
+// desligamento: Shutdown cancela o contexto de todas e aguarda até um prazo
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *slog.Logger
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	closed  bool
+	running map[string]int
+	closers []closer
+}
+
+// closer é uma finalização registrada com OnShutdown
+type closer struct {
+	name string
+	fn   func() error
+}
+
+// NewManager cria um manager sem workers
+func NewManager(logger *slog.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:     ctx,
+		cancel:  cancel,
+		logger:  logger,
+		running: make(map[string]int),
+	}
+}
+
+// Go executa fn em uma goroutine com o contexto do manager, que é cancelado
+// no Shutdown; fn deve retornar ao observar ctx.Done(). Pânicos são
+// recuperados e registrados. Após o Shutdown, novos workers são ignorados
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		m.logger.Warn("worker not started: manager is shutting down", "worker", name)
+		return
+	}
+
+	m.running[name]++
+	m.wg.Add(1)
+	go m.run(name, fn)
+}
+
+// OnShutdown registra fn para ser executada uma única vez por Shutdown,
+// depois que os workers terminarem (ou o prazo expirar), em ordem inversa de
+// registro. Serve para liberar recursos compartilhados, como conexões com o
+// Redis; falhas são registradas em log pelo nome
+func (m *Manager) OnShutdown(name string, fn func() error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closers = append(m.closers, closer{name: name, fn: fn})
+}
+
+// runClosers executa as finalizações pendentes, da última para a primeira
+func (m *Manager) runClosers() {
+	m.mu.Lock()
+	closers := m.closers
+	m.closers = nil
+	m.mu.Unlock()
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].fn(); err != nil {
+			m.logger.Error("shutdown hook failed", "hook", closers[i].name, "error", err)
+		}
+	}
+}
+
+// run executa o worker e o remove da lista de ativos ao terminar
+func (m *Manager) run(name string, fn func(ctx context.Context)) {
+	defer m.wg.Done()
+	defer m.finish(name)
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("worker panicked", "worker", name, "panic", fmt.Sprint(r))
+		}
+	}()
+
+	fn(m.ctx)
+}
+
+// finish decrementa a contagem de execuções do worker
+func (m *Manager) finish(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running[name]--; m.running[name] <= 0 {
+		delete(m.running, name)
+	}
+}
+
+// Running retorna, em ordem alfabética, os nomes dos workers ainda em execução
+func (m *Manager) Running() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.running))
+	for name := range m.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown cancela o contexto dos workers e aguarda que terminem ou que ctx
+// expire. No segundo caso, registra em log os que não terminaram e retorna o
+// erro do contexto. Em seguida executa as finalizações de OnShutdown. Pode ser
+// chamado mais de uma vez
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		m.runClosers()
+		return nil
+	case <-ctx.Done():
+		m.logger.Error("workers did not stop before shutdown deadline", "workers", m.Running())
+		m.runClosers()
+		return ctx.Err()
+	}
+}
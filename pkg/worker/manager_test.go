@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager() *Manager {
+	return NewManager(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestManagerCancelsWorkersOnShutdown(t *testing.T) {
+	m := newTestManager()
+
+	started := make(chan struct{})
+	observed := make(chan struct{})
+	m.Go("cleaner", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(observed)
+	})
+	<-started
+	assert.Equal(t, []string{"cleaner"}, m.Running())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, m.Shutdown(ctx))
+
+	select {
+	case <-observed:
+	default:
+		t.Fatal("worker did not observe cancellation")
+	}
+	assert.Empty(t, m.Running())
+}
+
+func TestManagerShutdownTimesOutOnStuckWorker(t *testing.T) {
+	m := newTestManager()
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	m.Go("stuck", func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, m.Shutdown(ctx), context.DeadlineExceeded)
+	assert.Equal(t, []string{"stuck"}, m.Running())
+}
+
+func TestManagerRecoversPanicsAndRejectsAfterShutdown(t *testing.T) {
+	m := newTestManager()
+	m.Go("panicky", func(context.Context) { panic("boom") })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, m.Shutdown(ctx))
+
+	ran := false
+	m.Go("late", func(context.Context) { ran = true })
+	require.NoError(t, m.Shutdown(ctx))
+	assert.False(t, ran)
+	assert.Empty(t, m.Running())
+}
+
+func TestManagerRunsShutdownHooksOnceAfterWorkers(t *testing.T) {
+	m := newTestManager()
+
+	var order []string
+	stopped := false
+	m.Go("probe", func(ctx context.Context) {
+		<-ctx.Done()
+		stopped = true
+	})
+	m.OnShutdown("first", func() error {
+		order = append(order, "first")
+		return nil
+	})
+	m.OnShutdown("redis", func() error {
+		assert.True(t, stopped, "hooks run after the workers stop")
+		order = append(order, "redis")
+		return assert.AnError
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, m.Shutdown(ctx))
+	require.NoError(t, m.Shutdown(ctx))
+	assert.Equal(t, []string{"redis", "first"}, order)
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- O conjunto de papéis válidos agora é definido pela aplicação (user.ValidRoles),
+-- podendo ser estendido via security.custom_roles
+ALTER TABLE users DROP CONSTRAINT check_valid_role;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Falha se existirem usuários com papéis customizados (security.custom_roles)
+ALTER TABLE users ADD CONSTRAINT check_valid_role
+    CHECK (role IN ('admin', 'user', 'guest'));
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Versão dos tokens do usuário: incrementá-la invalida todos os JWTs já emitidos
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN token_version;
+-- +goose StatementEnd
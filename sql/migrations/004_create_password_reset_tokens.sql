@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tokens de redefinição de senha: apenas o hash SHA-256 é persistido e cada
+-- usuário tem no máximo um token pendente. attempts conta as confirmações e é
+-- incrementado no próprio UPDATE, para que palpites concorrentes não
+-- compartilhem a contagem; além de security.reset_token_max_attempts o token
+-- não é mais aceito
+CREATE TABLE password_reset_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE password_reset_tokens;
+-- +goose StatementEnd
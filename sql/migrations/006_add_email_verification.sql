@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Momento em que o email do usuário foi verificado (NULL = não verificado).
+-- Contas existentes antes da verificação de email são consideradas verificadas
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+UPDATE users SET email_verified_at = created_at;
+
+-- Tokens de verificação de email: apenas o hash SHA-256 é persistido. email é o
+-- endereço para o qual o link foi enviado
+CREATE TABLE email_verification_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE email_verification_tokens;
+
+ALTER TABLE users DROP COLUMN email_verified_at;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Troca de email: o token verifica o novo endereço (email) e só é aplicado se o
+-- email do usuário ainda for replaces_email. NULL é a verificação do email atual
+ALTER TABLE email_verification_tokens ADD COLUMN replaces_email VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE email_verification_tokens DROP COLUMN replaces_email;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Nome de usuário opcional (users.usernames_enabled); NULL quando não definido.
+-- A unicidade ignora maiúsculas, e a caixa informada no cadastro é mantida
+ALTER TABLE users ADD COLUMN username VARCHAR(30);
+
+CREATE UNIQUE INDEX idx_users_username_lower ON users (LOWER(username)) WHERE username IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_users_username_lower;
+
+ALTER TABLE users DROP COLUMN username;
+-- +goose StatementEnd
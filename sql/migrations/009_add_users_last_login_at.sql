@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Momento do último login bem-sucedido; NULL para quem nunca entrou.
+-- O índice atende a listagem de contas inativas (GET /users/dormant)
+ALTER TABLE users ADD COLUMN last_login_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_last_login_at ON users (last_login_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_users_last_login_at;
+
+ALTER TABLE users DROP COLUMN last_login_at;
+-- +goose StatementEnd
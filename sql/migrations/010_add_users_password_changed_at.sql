@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Momento da última troca de senha, usado pela expiração de senhas
+-- (security.password_expiration_enabled). Contas existentes começam a contar
+-- a partir desta migração; NULL nunca expira
+ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE users SET password_changed_at = NOW();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN password_changed_at;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Emails são gravados e buscados em minúsculas; linhas antigas com outra caixa
+-- são normalizadas. Se duas contas diferirem apenas na caixa do email a
+-- migração falha na unicidade e o conflito precisa ser resolvido antes
+UPDATE users SET email = LOWER(email) WHERE email <> LOWER(email);
+
+-- A unicidade ignora maiúsculas e atende às buscas por LOWER(email)
+CREATE UNIQUE INDEX idx_users_email_lower ON users (LOWER(email));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- A caixa original dos emails não é restaurada
+DROP INDEX idx_users_email_lower;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO email_verification_tokens (token_hash, user_id, email, replaces_email, expires_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: DeleteEmailVerificationTokensByUser :execrows
+DELETE FROM email_verification_tokens WHERE user_id = $1;
+
+-- name: ConsumeEmailVerificationToken :one
+DELETE FROM email_verification_tokens WHERE token_hash = $1
+RETURNING *;
+
+-- name: MarkUserEmailVerified :execrows
+UPDATE users SET
+    email_verified_at = sqlc.arg(verified_at),
+    updated_at = sqlc.arg(verified_at)
+WHERE id = sqlc.arg(id) AND email = sqlc.arg(email);
+
+-- name: ChangeUserEmail :execrows
+UPDATE users SET
+    email = sqlc.arg(new_email),
+    email_verified_at = sqlc.arg(verified_at),
+    updated_at = sqlc.arg(verified_at)
+WHERE id = sqlc.arg(id) AND email = sqlc.arg(old_email);
@@ -0,0 +1,17 @@
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (token_hash, user_id, expires_at, created_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeletePasswordResetTokensByUser :execrows
+DELETE FROM password_reset_tokens WHERE user_id = $1;
+
+-- name: GetPasswordResetTokenByUser :one
+SELECT * FROM password_reset_tokens WHERE user_id = $1;
+
+-- name: IncrementPasswordResetAttempts :one
+UPDATE password_reset_tokens SET attempts = attempts + 1
+WHERE token_hash = $1
+RETURNING attempts;
+
+-- name: ConsumePasswordResetToken :execrows
+DELETE FROM password_reset_tokens WHERE token_hash = $1;
@@ -1,40 +1,190 @@
 -- name: CreateUser :one
 INSERT INTO users (
-    email, password, name, role, is_active, created_at, updated_at
+    email, password, name, role, is_active, created_at, updated_at, email_verified_at, username, password_changed_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: GetUserByID :one
 SELECT * FROM users WHERE id = $1;
 
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = $1;
+SELECT * FROM users WHERE LOWER(email) = LOWER($1);
+
+-- name: GetUserByUsername :one
+SELECT * FROM users WHERE LOWER(username) = LOWER($1);
 
 -- name: UpdateUser :one
 UPDATE users SET
     email = COALESCE($2, email),
+    -- Um novo email precisa ser verificado novamente
+    email_verified_at = CASE WHEN COALESCE($2, email) = email THEN email_verified_at END,
     password = COALESCE($3, password),
     name = COALESCE($4, name),
     role = COALESCE($5, role),
+    -- Uma troca de role invalida os tokens emitidos com o role anterior
+    token_version = CASE WHEN COALESCE($5, role) = role THEN token_version ELSE token_version + 1 END,
     is_active = COALESCE($6, is_active),
-    updated_at = $7
+    updated_at = $7,
+    username = $8,
+    password_changed_at = $9
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteUser :exec
+-- name: DeleteUser :execrows
 DELETE FROM users WHERE id = $1;
 
+-- name: AnonymizeUser :one
+UPDATE users SET
+    email = sqlc.arg(email),
+    password = sqlc.arg(password),
+    name = sqlc.arg(name),
+    username = NULL,
+    is_active = FALSE,
+    token_version = token_version + 1,
+    updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: ListUsers :many
 SELECT * FROM users 
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
+-- name: ListActiveUsers :many
+SELECT * FROM users 
+WHERE is_active = true
+ORDER BY created_at DESC
+LIMIT $1 OFFSET $2;
+
 -- name: CountUsers :one
 SELECT COUNT(*) FROM users;
 
+-- name: CountUsersByRole :many
+SELECT role, COUNT(*) AS count FROM users
+GROUP BY role;
+
+-- name: CountActiveUsers :one
+SELECT COUNT(*) FROM users WHERE is_active = true;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE (name ILIKE sqlc.arg(pattern) OR email ILIKE sqlc.arg(pattern))
+  AND (is_active = true OR sqlc.arg(include_inactive)::boolean)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE (name ILIKE sqlc.arg(pattern) OR email ILIKE sqlc.arg(pattern))
+  AND (is_active = true OR sqlc.arg(include_inactive)::boolean);
+
 -- name: ExistsByEmail :one
-SELECT EXISTS(SELECT 1 FROM users WHERE email = $1);
+SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(email) = LOWER($1));
+
+-- name: ExistsByUsername :one
+SELECT EXISTS(SELECT 1 FROM users WHERE LOWER(username) = LOWER($1));
+
+-- name: ExistsByRole :one
+SELECT EXISTS(SELECT 1 FROM users WHERE role = $1);
+
+-- name: LockFirstAdminBootstrap :exec
+-- Serializa a criação do primeiro admin até o fim da transação
+SELECT pg_advisory_xact_lock(hashtext('users:first_admin_bootstrap'));
 
 -- name: ExistsByID :one
-SELECT EXISTS(SELECT 1 FROM users WHERE id = $1); 
\ No newline at end of file
+SELECT EXISTS(SELECT 1 FROM users WHERE id = $1); 
+
+-- name: IncrementTokenVersion :one
+UPDATE users SET
+    token_version = token_version + 1,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING token_version;
+
+-- name: GetTokenVersion :one
+SELECT token_version FROM users
+WHERE id = $1;
+
+-- name: GetUserActiveStatus :one
+SELECT is_active FROM users
+WHERE id = $1;
+
+-- name: ListExistingEmails :many
+SELECT LOWER(email)::text FROM users
+WHERE LOWER(email) = ANY(sqlc.arg(emails)::text[]);
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: GetUserRolesForUpdate :many
+SELECT id, role FROM users
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+FOR UPDATE;
+
+-- name: UpdateUserRoles :execrows
+UPDATE users SET
+    role = sqlc.arg(role),
+    token_version = token_version + 1,
+    updated_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: CountUsersCreatedBetween :one
+SELECT COUNT(*) FROM users
+WHERE created_at >= sqlc.arg(from_time) AND created_at <= sqlc.arg(to_time);
+
+-- name: ListUsersCreatedBetween :many
+SELECT * FROM users
+WHERE created_at >= sqlc.arg(from_time) AND created_at <= sqlc.arg(to_time)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListUsersAfterID :many
+SELECT * FROM users
+WHERE id > sqlc.arg(id)
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ReplaceUserPassword :execrows
+-- Grava apenas se o hash ainda for o lido, sem sobrescrever uma troca concorrente
+UPDATE users SET
+    password = sqlc.arg(password),
+    password_changed_at = sqlc.arg(password_changed_at)
+WHERE id = sqlc.arg(id) AND password = sqlc.arg(previous_password);
+
+-- name: UpdateLastLogin :exec
+UPDATE users SET last_login_at = sqlc.arg(last_login_at)
+WHERE id = sqlc.arg(id);
+
+-- name: CountDormantUsers :one
+SELECT COUNT(*) FROM users
+WHERE last_login_at IS NULL OR last_login_at < sqlc.arg(since);
+
+-- name: ListDormantUsers :many
+SELECT * FROM users
+WHERE last_login_at IS NULL OR last_login_at < sqlc.arg(since)
+ORDER BY last_login_at ASC NULLS FIRST, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListInactiveUsersByRole :many
+-- Sem login, a atividade é a criação da conta: um admin recém-criado não é inativo
+SELECT * FROM users
+WHERE role = sqlc.arg(role) AND is_active AND COALESCE(last_login_at, created_at) < sqlc.arg(since)
+ORDER BY COALESCE(last_login_at, created_at), id;
+
+-- name: CountActiveUsersByRole :one
+SELECT COUNT(*) FROM users
+WHERE role = sqlc.arg(role) AND is_active;
+
+-- name: DowngradeInactiveUsers :many
+-- Condicional: quem entrou ou mudou de papel depois da listagem fica de fora
+UPDATE users SET
+    role = sqlc.arg(new_role),
+    token_version = token_version + 1,
+    updated_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+    AND role = sqlc.arg(role)
+    AND is_active
+    AND COALESCE(last_login_at, created_at) < sqlc.arg(since)
+RETURNING id;
@@ -0,0 +1,11 @@
+-- name: GetUserByIdentity :one
+SELECT users.* FROM users
+JOIN user_identities ON user_identities.user_id = users.id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (issuer, subject, user_id, created_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteUserIdentitiesByUser :execrows
+DELETE FROM user_identities WHERE user_id = $1;
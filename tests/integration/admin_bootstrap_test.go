@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFirstAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	newAdmin := func(i int) *user.User {
+		u, err := user.NewUser(fmt.Sprintf("admin%d@example.com", i), "password123", "Admin", user.RoleAdmin)
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("first call succeeds, second is rejected", func(t *testing.T) {
+		testutil.ResetDB(t, db)
+
+		first := newAdmin(1)
+		require.NoError(t, userRepo.CreateFirstAdmin(ctx, first))
+		assert.ErrorIs(t, userRepo.CreateFirstAdmin(ctx, newAdmin(2)), user.ErrAdminAlreadyExists)
+
+		counts, err := userRepo.CountByRole(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), counts[user.RoleAdmin])
+	})
+
+	t.Run("closed by an admin created elsewhere", func(t *testing.T) {
+		testutil.ResetDB(t, db)
+
+		require.NoError(t, userRepo.Create(ctx, newAdmin(1)))
+		assert.ErrorIs(t, userRepo.CreateFirstAdmin(ctx, newAdmin(2)), user.ErrAdminAlreadyExists)
+	})
+
+	t.Run("concurrent calls create a single admin", func(t *testing.T) {
+		testutil.ResetDB(t, db)
+
+		const calls = 8
+		admins := make([]*user.User, calls)
+		for i := range admins {
+			admins[i] = newAdmin(i)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, calls)
+		for i := 0; i < calls; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = userRepo.CreateFirstAdmin(ctx, admins[i])
+			}(i)
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			if err == nil {
+				created++
+				continue
+			}
+			assert.True(t, errors.Is(err, user.ErrAdminAlreadyExists), err)
+		}
+		assert.Equal(t, 1, created)
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"go-api-boilerplate/internal/infrastructure/http/ctxkeys"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -61,9 +63,9 @@ func TestAuthMiddleware(t *testing.T) {
 		router.Use(func(c *gin.Context) {
 			authHeader := c.GetHeader("Authorization")
 			if authHeader == "Bearer valid-token" {
-				c.Set("userID", "123")
-				c.Set("userEmail", "test@example.com")
-				c.Set("userRole", "user")
+				ctxkeys.SetUserID(c, "123")
+				ctxkeys.SetUserEmail(c, "test@example.com")
+				ctxkeys.SetUserRole(c, "user")
 				c.Next()
 				return
 			}
@@ -76,7 +78,7 @@ func TestAuthMiddleware(t *testing.T) {
 
 		// Rota protegida
 		router.GET("/protected", func(c *gin.Context) {
-			userID, _ := c.Get("userID")
+			userID, _ := ctxkeys.UserID(c)
 			c.JSON(http.StatusOK, gin.H{
 				"message": "Access granted",
 				"userID":  userID,
@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateRolesClassifiesIDs garante que a troca em lote altera apenas quem
+// precisa e reporta IDs inexistentes
+func TestUpdateRolesClassifiesIDs(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	alice, err := user.NewUser("alice@example.com", "password123", "Alice", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, alice))
+	bob, err := user.NewUser("bob@example.com", "password123", "Bob", user.RoleAdmin)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, bob))
+
+	// A simulação reporta o mesmo resultado sem gravar
+	preview, err := userRepo.PreviewUpdateRoles(ctx, []string{alice.ID}, user.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice.ID}, preview.Updated)
+	unchanged, err := userRepo.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.RoleUser, unchanged.Role)
+
+	missing := uuid.NewString()
+	result, err := userRepo.UpdateRoles(ctx, []string{alice.ID, bob.ID, missing, "not-a-uuid"}, user.RoleAdmin)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{alice.ID}, result.Updated)
+	assert.Equal(t, []string{bob.ID}, result.Unchanged)
+	assert.Equal(t, []string{missing, "not-a-uuid"}, result.NotFound)
+
+	updated, err := userRepo.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.RoleAdmin, updated.Role)
+	assert.Equal(t, alice.TokenVersion+1, updated.TokenVersion, "role change must revoke existing tokens")
+
+	// A simulação e os usuários inalterados mantêm a versão
+	stillAdmin, err := userRepo.GetByID(ctx, bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, bob.TokenVersion, stillAdmin.TokenVersion)
+}
+
+// TestUpdateRoleIncrementsTokenVersion garante que a troca de role por Update
+// (PUT /users/:id) invalida os tokens, e que outras alterações não
+func TestUpdateRoleIncrementsTokenVersion(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	alice, err := user.NewUser("alice@example.com", "password123", "Alice", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, alice))
+	version := alice.TokenVersion
+
+	require.NoError(t, alice.UpdateName("Alice Doe"))
+	require.NoError(t, userRepo.Update(ctx, alice))
+	assert.Equal(t, version, alice.TokenVersion)
+
+	require.NoError(t, alice.UpdateRole(user.RoleAdmin))
+	require.NoError(t, userRepo.Update(ctx, alice))
+	assert.Equal(t, version+1, alice.TokenVersion)
+
+	stored, err := userRepo.GetTokenVersion(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, version+1, stored)
+}
+
+// TestGetByIDsSkipsMissing garante que a busca em lote ignora IDs inexistentes ou inválidos
+func TestGetByIDsSkipsMissing(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	alice, err := user.NewUser("alice@example.com", "password123", "Alice", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, alice))
+
+	users, err := userRepo.GetByIDs(ctx, []string{alice.ID, uuid.NewString(), "not-a-uuid"})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, alice.Email, users[0].Email)
+
+	users, err = userRepo.GetByIDs(ctx, []string{"not-a-uuid"})
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	// Acima de MaxIDsPerQuery a busca é feita em partes, sem perder resultados
+	ids := make([]string, repository.MaxIDsPerQuery)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	ids = append(ids, alice.ID)
+	users, err = userRepo.GetByIDs(ctx, ids)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, alice.ID, users[0].ID)
+}
+
+// TestExistingEmails garante que a pré-validação de importação encontra os
+// emails já cadastrados em uma consulta
+func TestExistingEmails(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	alice, err := user.NewUser("alice@example.com", "password123", "Alice", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, alice))
+
+	existing, err := userRepo.ExistingEmails(ctx, []string{"alice@example.com", "new@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, existing)
+
+	existing, err = userRepo.ExistingEmails(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+}
+
+// TestEmailLookupIgnoresCase garante que contas gravadas com outra caixa antes
+// da normalização continuam encontradas pelo email normalizado
+func TestEmailLookupIgnoresCase(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	alice, err := user.NewUser("alice@example.com", "password123", "Alice", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, alice))
+	_, err = db.ExecContext(ctx, "UPDATE users SET email = 'Alice@Example.com' WHERE id = $1", alice.ID)
+	require.NoError(t, err)
+
+	found, err := userRepo.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, found.ID)
+
+	exists, err := userRepo.ExistsByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	existing, err := userRepo.ExistingEmails(ctx, []string{"alice@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com"}, existing)
+
+	// O índice único em LOWER(email) impede outra conta com a mesma caixa ignorada
+	_, err = db.ExecContext(ctx, `INSERT INTO users (email, password, name) VALUES ('ALICE@example.com', 'x', 'Other')`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/infrastructure/http/handlers"
+	"go-api-boilerplate/internal/infrastructure/http/middleware"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContextCancellation garante que um contexto cancelado aborta o trabalho no banco
+func TestContextCancellation(t *testing.T) {
+	db := setupTestDB(t)
+	userRepo := repository.NewPostgresUserRepository(db)
+	userUseCase := usecase.NewUserUseCase(userRepo, auth.NewJWTService("test-secret", time.Hour))
+
+	t.Run("Canceled Context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		_, err := userUseCase.ListUsers(ctx, usecase.ListUsersInput{Limit: 10})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Deadline Exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		start := time.Now()
+		_, err := userUseCase.GetUserByEmail(ctx, usecase.GetUserByEmailInput{Email: "test@example.com"})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+// TestRequestTimeoutCancelsQuery garante que o prazo do TimeoutMiddleware chega
+// ao banco: a consulta em andamento é cancelada no servidor, a conexão volta ao
+// pool e o cliente recebe 504
+func TestRequestTimeoutCancelsQuery(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+	gin.SetMode(gin.TestMode)
+
+	userRepo := repository.NewPostgresUserRepository(db)
+	userHandler := handlers.NewUserHandler(usecase.NewUserUseCase(userRepo, auth.NewJWTService(testJWTSecret, time.Hour)))
+
+	router := gin.New()
+	router.Use(middleware.TimeoutMiddleware(200 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		// Consulta deliberadamente lenta com o contexto da requisição
+		if _, err := db.ExecContext(c.Request.Context(), "SELECT pg_sleep(5)"); err != nil {
+			_ = c.Error(err)
+		}
+	})
+	router.GET("/users/:id", userHandler.GetUserByID)
+
+	serve := func(path string) (*httptest.ResponseRecorder, time.Duration) {
+		start := time.Now()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w, time.Since(start)
+	}
+
+	// sleeping conta as consultas pg_sleep ainda em execução no servidor
+	sleeping := func() int {
+		var n int
+		require.NoError(t, db.QueryRow(
+			"SELECT count(*) FROM pg_stat_activity WHERE state = 'active' AND query = 'SELECT pg_sleep(5)'",
+		).Scan(&n))
+		return n
+	}
+
+	t.Run("slow query", func(t *testing.T) {
+		w, elapsed := serve("/slow")
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Less(t, elapsed, 2*time.Second)
+		assert.Eventually(t, func() bool { return sleeping() == 0 }, 2*time.Second, 50*time.Millisecond,
+			"the query is cancelled on the server, not left running")
+		assert.Zero(t, db.Stats().InUse, "no connection is leaked")
+	})
+
+	t.Run("repository query", func(t *testing.T) {
+		// Um lock exclusivo faz a leitura do repositório esperar até o prazo
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		defer tx.Rollback()
+		_, err = tx.Exec("LOCK TABLE users IN ACCESS EXCLUSIVE MODE")
+		require.NoError(t, err)
+
+		w, elapsed := serve("/users/8a3c1f5e-2b4d-4c6e-9f1a-0b2c3d4e5f60")
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		assert.Contains(t, w.Body.String(), "Request timed out")
+		assert.Less(t, elapsed, 2*time.Second)
+	})
+}
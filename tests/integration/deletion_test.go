@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAccountDeletion cobre as duas políticas de exclusão no banco
+func TestAccountDeletion(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	t.Run("delete removes the row", func(t *testing.T) {
+		u, err := user.NewUser("delete@example.com", "password123", "Delete Me", user.RoleUser)
+		require.NoError(t, err)
+		require.NoError(t, userRepo.Create(ctx, u))
+
+		require.NoError(t, userRepo.Delete(ctx, u.ID))
+		_, err = userRepo.GetByID(ctx, u.ID)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+		assert.ErrorIs(t, userRepo.Delete(ctx, u.ID), user.ErrUserNotFound)
+	})
+
+	t.Run("anonymize keeps the row without personal data", func(t *testing.T) {
+		u, err := user.NewUser("anonymize@example.com", "password123", "Anonymize Me", user.RoleUser)
+		require.NoError(t, err)
+		require.NoError(t, u.SetUsername("anonymize_me"))
+		require.NoError(t, userRepo.Create(ctx, u))
+		version := u.TokenVersion
+
+		// Os tokens de verificação guardam o email atual e o novo
+		require.NoError(t, userRepo.ReplaceVerificationToken(ctx, &user.VerificationToken{
+			Hash:          "anonymize-hash",
+			UserID:        u.ID,
+			Email:         "anonymize-new@example.com",
+			ReplacesEmail: u.Email,
+			ExpiresAt:     time.Now().Add(time.Hour),
+		}))
+
+		issuer := "https://accounts.example.com"
+		require.NoError(t, userRepo.LinkIdentity(ctx, u.ID, issuer, "sub-anonymize"))
+
+		u.Anonymize()
+		require.NoError(t, userRepo.Anonymize(ctx, u))
+
+		stored, err := userRepo.GetByID(ctx, u.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.IsAnonymized())
+		assert.Equal(t, user.AnonymizedName, stored.Name)
+		assert.False(t, stored.IsActive)
+		assert.False(t, stored.CheckPassword("password123"))
+		assert.Equal(t, version+1, stored.TokenVersion)
+		assert.Empty(t, stored.Username)
+
+		exists, err := userRepo.ExistsByEmail(ctx, "anonymize@example.com")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		exists, err = userRepo.ExistsByUsername(ctx, "anonymize_me")
+		require.NoError(t, err)
+		assert.False(t, exists, "the username is released")
+
+		var tokens int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM email_verification_tokens WHERE user_id = $1", u.ID).Scan(&tokens))
+		assert.Zero(t, tokens, "pending verification tokens keep former addresses")
+
+		_, err = userRepo.GetByIdentity(ctx, issuer, "sub-anonymize")
+		assert.ErrorIs(t, err, user.ErrUserNotFound, "the external identity is unlinked")
+	})
+}
@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIdentities(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+	issuer := "https://accounts.example.com"
+
+	alice, err := user.NewExternalUser("alice@example.com", "Alice", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, alice))
+
+	_, err = userRepo.GetByIdentity(ctx, issuer, "sub-alice")
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+	require.NoError(t, userRepo.LinkIdentity(ctx, alice.ID, issuer, "sub-alice"))
+
+	found, err := userRepo.GetByIdentity(ctx, issuer, "sub-alice")
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, found.ID)
+
+	// O mesmo subject em outro provedor é outra identidade
+	_, err = userRepo.GetByIdentity(ctx, "https://other.example.com", "sub-alice")
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+	bob, err := user.NewExternalUser("bob@example.com", "Bob", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, bob))
+	err = userRepo.LinkIdentity(ctx, bob.ID, issuer, "sub-alice")
+	assert.ErrorIs(t, err, user.ErrIdentityAlreadyLinked)
+
+	// Excluir o usuário remove os vínculos
+	require.NoError(t, userRepo.Delete(ctx, alice.ID))
+	_, err = userRepo.GetByIdentity(ctx, issuer, "sub-alice")
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+}
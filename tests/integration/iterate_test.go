@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIterate garante que a iteração por keyset visita todos os usuários uma
+// única vez, em ordem de ID, em lotes do tamanho pedido
+func TestIterate(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		u, err := user.NewUser(fmt.Sprintf("iterate%d@example.com", i), "password123", "Iterate", user.RoleUser)
+		require.NoError(t, err)
+		require.NoError(t, userRepo.Create(ctx, u))
+		ids = append(ids, u.ID)
+	}
+	sort.Strings(ids)
+
+	var seen []string
+	var sizes []int
+	err := userRepo.Iterate(ctx, 2, func(batch []*user.User) error {
+		sizes = append(sizes, len(batch))
+		for _, u := range batch {
+			seen = append(seen, u.ID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, ids, seen)
+
+	t.Run("stops on callback error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		err := userRepo.Iterate(ctx, 2, func([]*user.User) error {
+			calls++
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("rejects non-positive batch size", func(t *testing.T) {
+		err := userRepo.Iterate(ctx, 0, func([]*user.User) error { return nil })
+		assert.Error(t, err)
+	})
+}
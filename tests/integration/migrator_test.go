@@ -0,0 +1,292 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/pkg/database"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockTestMigration demora o suficiente para que os migradores concorrentes
+// se sobreponham; aplicada duas vezes, o CREATE TABLE falharia
+const lockTestMigration = `-- +goose Up
+SELECT pg_sleep(0.3);
+CREATE TABLE migrator_lock_test (id INT);
+INSERT INTO migrator_lock_test VALUES (1);
+
+-- +goose Down
+DROP TABLE migrator_lock_test;
+`
+
+// isolatedMigrations cria um diretório com as migrações de teste (nome do
+// arquivo -> conteúdo) e aponta o goose para uma tabela de versões própria,
+// removida e restaurada ao fim do teste
+func isolatedMigrations(t *testing.T, db *sql.DB, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	goose.SetTableName("goose_test_version")
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS goose_test_version")
+		goose.SetTableName("goose_db_version")
+	})
+
+	return dir
+}
+
+// TestConcurrentMigratorsApplyOnce garante que migradores concorrentes aplicam
+// cada migração uma única vez: um aplica e os demais aguardam o lock
+func TestConcurrentMigratorsApplyOnce(t *testing.T) {
+	db := setupTestDB(t)
+	dir := isolatedMigrations(t, db, map[string]string{"00001_lock_test.sql": lockTestMigration})
+	t.Cleanup(func() { db.Exec("DROP TABLE IF EXISTS migrator_lock_test") })
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	const instances = 3
+	errs := make([]error, instances)
+	var wg sync.WaitGroup
+	for i := 0; i < instances; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = database.NewMigrator(db, log).RunMigrations(dir)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	var rows, applied int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM migrator_lock_test").Scan(&rows))
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM goose_test_version WHERE version_id = 1").Scan(&applied))
+	assert.Equal(t, 1, rows)
+	assert.Equal(t, 1, applied)
+}
+
+// TestMigratorLockTimeout garante que a espera pelo lock respeita o prazo
+func TestMigratorLockTimeout(t *testing.T) {
+	db := setupTestDB(t)
+	dir := isolatedMigrations(t, db, map[string]string{"00001_lock_test.sql": lockTestMigration})
+
+	// Outra "instância" segura o lock das migrações (mesma chave do Migrator)
+	ctx := context.Background()
+	holder, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer holder.Close()
+	_, err = holder.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext('goose:migrations'))")
+	require.NoError(t, err)
+	defer holder.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext('goose:migrations'))")
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	migrator := database.NewMigrator(db, log, database.WithMigrationLockTimeout(200*time.Millisecond))
+
+	start := time.Now()
+	err = migrator.RunMigrations(dir)
+	assert.ErrorIs(t, err, database.ErrMigrationLockTimeout)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	var exists bool
+	require.NoError(t, db.QueryRow("SELECT to_regclass('migrator_lock_test') IS NOT NULL").Scan(&exists))
+	assert.False(t, exists)
+}
+
+// planTestMigration cria e remove uma tabela identificada pelo nome
+func planTestMigration(table string) string {
+	return "-- +goose Up\nCREATE TABLE " + table + " (id INT);\n\n-- +goose Down\nDROP TABLE " + table + ";\n"
+}
+
+// TestMigratorPlan garante que o plano lista apenas as migrações pendentes,
+// sem aplicá-las nem criar a tabela de versões
+func TestMigratorPlan(t *testing.T) {
+	db := setupTestDB(t)
+	dir := isolatedMigrations(t, db, map[string]string{
+		"00001_plan_first.sql": planTestMigration("migrator_plan_first"),
+	})
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS migrator_plan_first")
+		db.Exec("DROP TABLE IF EXISTS migrator_plan_second")
+	})
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	migrator := database.NewMigrator(db, log)
+
+	plan, err := migrator.Plan(dir)
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	assert.Equal(t, int64(1), plan[0].Version)
+	assert.Equal(t, "00001_plan_first.sql", plan[0].Name)
+
+	var exists bool
+	require.NoError(t, db.QueryRow("SELECT to_regclass('goose_test_version') IS NOT NULL").Scan(&exists))
+	assert.False(t, exists, "plan must not create the version table")
+
+	require.NoError(t, migrator.RunMigrations(dir))
+	plan, err = migrator.Plan(dir)
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+
+	// Uma migração nova aparece no plano e não é aplicada
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00002_plan_second.sql"), []byte(planTestMigration("migrator_plan_second")), 0o600))
+	plan, err = migrator.Plan(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []database.PlannedMigration{
+		{Version: 2, Name: "00002_plan_second.sql", Path: filepath.Join(dir, "00002_plan_second.sql")},
+	}, plan)
+	require.NoError(t, db.QueryRow("SELECT to_regclass('migrator_plan_second') IS NOT NULL").Scan(&exists))
+	assert.False(t, exists)
+}
+
+// tableExists informa se a tabela existe no banco
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var exists bool
+	require.NoError(t, db.QueryRow("SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists))
+	return exists
+}
+
+// TestMigratorUpToDownTo garante a migração até uma versão intermediária e a
+// volta a uma versão anterior
+func TestMigratorUpToDownTo(t *testing.T) {
+	db := setupTestDB(t)
+	tables := []string{"migrator_target_one", "migrator_target_two", "migrator_target_three"}
+	dir := isolatedMigrations(t, db, map[string]string{
+		"00001_target_one.sql":   planTestMigration(tables[0]),
+		"00002_target_two.sql":   planTestMigration(tables[1]),
+		"00003_target_three.sql": planTestMigration(tables[2]),
+	})
+	t.Cleanup(func() {
+		for _, table := range tables {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+	})
+
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	migrator := database.NewMigrator(db, log)
+
+	require.NoError(t, migrator.UpTo(dir, 2))
+	version, err := migrator.CurrentVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	assert.True(t, tableExists(t, db, tables[0]))
+	assert.True(t, tableExists(t, db, tables[1]))
+	assert.False(t, tableExists(t, db, tables[2]))
+
+	require.NoError(t, migrator.DownTo(dir, 1))
+	version, err = migrator.CurrentVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+	assert.True(t, tableExists(t, db, tables[0]))
+	assert.False(t, tableExists(t, db, tables[1]))
+
+	t.Run("unknown versions are rejected", func(t *testing.T) {
+		assert.ErrorIs(t, migrator.UpTo(dir, 4), database.ErrUnknownMigrationVersion)
+		assert.ErrorIs(t, migrator.UpTo(dir, 0), database.ErrUnknownMigrationVersion)
+		assert.ErrorIs(t, migrator.DownTo(dir, 7), database.ErrUnknownMigrationVersion)
+
+		version, err := migrator.CurrentVersion(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), version)
+	})
+
+	require.NoError(t, migrator.DownTo(dir, 0))
+	assert.False(t, tableExists(t, db, tables[0]))
+}
+
+// TestMigratorRefusesIrreversibleRollback garante que DownTo e Rollback não
+// removem da tabela de versões uma migração sem seção Down
+func TestMigratorRefusesIrreversibleRollback(t *testing.T) {
+	db := setupTestDB(t)
+	dir := isolatedMigrations(t, db, map[string]string{
+		"00001_reversible.sql":   planTestMigration("migrator_reversible"),
+		"00002_irreversible.sql": "-- +goose Up\nCREATE TABLE migrator_irreversible (id INT);\n",
+	})
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS migrator_reversible")
+		db.Exec("DROP TABLE IF EXISTS migrator_irreversible")
+	})
+
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	migrator := database.NewMigrator(db, log)
+	require.NoError(t, migrator.RunMigrations(dir))
+
+	assert.ErrorIs(t, migrator.Rollback(dir), database.ErrIrreversibleMigration)
+	assert.ErrorIs(t, migrator.DownTo(dir, 1), database.ErrIrreversibleMigration)
+	assert.ErrorIs(t, migrator.DownTo(dir, 0), database.ErrIrreversibleMigration)
+
+	version, err := migrator.CurrentVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	assert.True(t, tableExists(t, db, "migrator_irreversible"))
+}
+
+// columnExists informa se a tabela tem a coluna
+func columnExists(t *testing.T, db *sql.DB, table, column string) bool {
+	t.Helper()
+	var exists bool
+	require.NoError(t, db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = 'public' AND table_name = $1 AND column_name = $2
+		)`, table, column).Scan(&exists))
+	return exists
+}
+
+// TestMigratorTargetsRealMigrations garante que as migrações do projeto podem
+// ser desfeitas e reaplicadas até versões intermediárias. O schema volta à
+// versão mais recente ao fim do teste
+func TestMigratorTargetsRealMigrations(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	dir := testutil.MigrationsDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	migrator := database.NewMigrator(db, log)
+	t.Cleanup(func() {
+		require.NoError(t, migrator.RunMigrations(dir))
+	})
+
+	// O banco de testes já está na versão mais recente
+	require.NoError(t, migrator.DownTo(dir, 2))
+	require.NoError(t, migrator.UpTo(dir, 6))
+	version, err := migrator.CurrentVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), version)
+	assert.True(t, tableExists(t, db, "password_reset_tokens"))
+	assert.True(t, tableExists(t, db, "user_identities"))
+	assert.True(t, tableExists(t, db, "email_verification_tokens"))
+	assert.False(t, columnExists(t, db, "email_verification_tokens", "replaces_email"))
+	assert.False(t, columnExists(t, db, "users", "username"))
+
+	require.NoError(t, migrator.DownTo(dir, 2))
+	version, err = migrator.CurrentVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	assert.False(t, tableExists(t, db, "password_reset_tokens"))
+	assert.False(t, tableExists(t, db, "user_identities"))
+	assert.False(t, tableExists(t, db, "email_verification_tokens"))
+	assert.False(t, columnExists(t, db, "users", "token_version"))
+	assert.False(t, columnExists(t, db, "users", "email_verified_at"))
+	assert.True(t, tableExists(t, db, "users"))
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPasswordChangedAtRoundTrip garante que a data da última troca de senha é
+// gravada na criação e atualizada pelo Update, e que contas externas ficam sem ela
+func TestPasswordChangedAtRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	u, err := user.NewUser("expiring@example.com", "password123", "Expiring", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	stored, err := userRepo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.PasswordChangedAt)
+	assert.WithinDuration(t, *u.PasswordChangedAt, *stored.PasswordChangedAt, time.Millisecond)
+
+	changed := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	stored.PasswordChangedAt = &changed
+	require.NoError(t, userRepo.Update(ctx, stored))
+
+	stored, err = userRepo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.PasswordChangedAt)
+	assert.True(t, stored.PasswordChangedAt.Equal(changed))
+
+	external, err := user.NewExternalUser("external@example.com", "External", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, external))
+
+	stored, err = userRepo.GetByID(ctx, external.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.PasswordChangedAt)
+}
+
+// TestReplacePasswordIsConditional garante que ReplacePassword grava apenas a
+// senha e só quando o hash persistido ainda é o lido
+func TestReplacePasswordIsConditional(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	u, err := user.NewUser("replace@example.com", "password123", "Replace", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	// Uma leitura anterior à troca concorrente
+	stale, err := userRepo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	staleHash := stale.Password
+
+	require.NoError(t, u.UpdateName("Renamed"))
+	previous := u.Password
+	require.NoError(t, u.ChangePassword("newpassword123"))
+	replaced, err := userRepo.ReplacePassword(ctx, u, previous)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+
+	stored, err := userRepo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.CheckPassword("newpassword123"))
+	assert.Equal(t, "Replace", stored.Name, "only the password is written")
+
+	require.NoError(t, stale.SetPassword("password123"))
+	replaced, err = userRepo.ReplacePassword(ctx, stale, staleHash)
+	require.NoError(t, err)
+	assert.False(t, replaced, "a stale hash must not overwrite the newer password")
+
+	stored, err = userRepo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.CheckPassword("newpassword123"))
+}
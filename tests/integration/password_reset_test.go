@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetTokens(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	u, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	_, err = userRepo.GetPasswordResetToken(ctx, u.ID)
+	assert.ErrorIs(t, err, user.ErrPasswordResetTokenNotFound)
+
+	expiresAt := time.Now().Add(time.Hour)
+	first := &user.PasswordResetToken{Hash: "hash-1", UserID: u.ID, ExpiresAt: expiresAt, CreatedAt: time.Now()}
+	second := &user.PasswordResetToken{Hash: "hash-2", UserID: u.ID, ExpiresAt: expiresAt, CreatedAt: time.Now()}
+	require.NoError(t, userRepo.ReplacePasswordResetToken(ctx, first))
+	require.NoError(t, userRepo.ReplacePasswordResetToken(ctx, second))
+
+	// Um novo pedido substitui o token pendente
+	_, err = userRepo.IncrementResetAttempts(ctx, "hash-1")
+	assert.ErrorIs(t, err, user.ErrPasswordResetTokenNotFound)
+
+	stored, err := userRepo.GetPasswordResetToken(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hash-2", stored.Hash)
+	assert.Zero(t, stored.Attempts)
+	assert.WithinDuration(t, expiresAt, stored.ExpiresAt, time.Millisecond)
+
+	// As tentativas ficam no registro do token
+	for want := 1; want <= 3; want++ {
+		n, err := userRepo.IncrementResetAttempts(ctx, "hash-2")
+		require.NoError(t, err)
+		assert.Equal(t, want, n)
+	}
+	stored, err = userRepo.GetPasswordResetToken(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Attempts)
+
+	// Uso único
+	require.NoError(t, userRepo.ConsumePasswordResetToken(ctx, "hash-2"))
+	assert.ErrorIs(t, userRepo.ConsumePasswordResetToken(ctx, "hash-2"), user.ErrPasswordResetTokenNotFound)
+	_, err = userRepo.GetPasswordResetToken(ctx, u.ID)
+	assert.ErrorIs(t, err, user.ErrPasswordResetTokenNotFound)
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/infrastructure/events"
+	"go-api-boilerplate/internal/infrastructure/http/handlers"
+	"go-api-boilerplate/internal/infrastructure/http/router"
+	"go-api-boilerplate/internal/infrastructure/http/server"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/pkg/config"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMethodNotAllowed verifica o 405 com header Allow no router da aplicação
+func TestMethodNotAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTestDB(t)
+	cfg := &config.Config{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService(testJWTSecret, 24*time.Hour)
+	userHandler := handlers.NewUserHandler(usecase.NewUserUseCase(repository.NewPostgresUserRepository(db), jwtService))
+	engine := router.SetupRouter(userHandler, handlers.NewDiagnosticsHandler(db, cfg, nil, nil),
+		handlers.NewEventsHandler(events.NewStream(0)), jwtService, cfg, log)
+
+	t.Run("wrong method on existing route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/auth/login", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		allow := strings.Split(w.Header().Get("Allow"), ", ")
+		assert.ElementsMatch(t, []string{http.MethodPost, http.MethodOptions}, allow)
+
+		var resp handlers.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, handlers.CodeMethodNotAllowed, resp.Code)
+	})
+
+	t.Run("unknown path in a CORS group", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Allow"))
+	})
+}
+
+// TestTrailingSlashIsStripped verifica que, pelo servidor montado com server.New,
+// "/users/" responde como "/users" e o corpo de um POST chega ao handler
+func TestTrailingSlashIsStripped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	cfg := &config.Config{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService(testJWTSecret, 24*time.Hour)
+	userRepo := repository.NewPostgresUserRepository(db)
+	userHandler := handlers.NewUserHandler(usecase.NewUserUseCase(userRepo, jwtService))
+	engine := router.SetupRouter(userHandler, handlers.NewDiagnosticsHandler(db, cfg, nil, nil),
+		handlers.NewEventsHandler(events.NewStream(0)), jwtService, cfg, log)
+	handler := server.New(cfg.Server, engine, log).Handler
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/auth/register/",
+		strings.NewReader(`{"email":"slash@example.com","password":"Password123!","name":"Slash"}`)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	created, err := userRepo.GetByEmail(context.Background(), "slash@example.com")
+	require.NoError(t, err)
+	token, err := jwtService.GenerateToken(created.ID, created.Email, string(created.Role))
+	require.NoError(t, err)
+
+	list := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	plain, slash := list("/api/v1/users"), list("/api/v1/users/")
+	assert.Equal(t, http.StatusOK, plain.Code)
+	assert.Equal(t, plain.Code, slash.Code)
+	assert.JSONEq(t, plain.Body.String(), slash.Body.String())
+}
@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRevokeSessionsRejectsOldTokens garante que o incremento de token_version invalida tokens anteriores
+func TestRevokeSessionsRejectsOldTokens(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+	jwtService := auth.NewJWTService("test-secret", time.Hour, auth.WithTokenVersions(userRepo))
+	userUseCase := usecase.NewUserUseCase(userRepo, jwtService)
+
+	created, err := userUseCase.CreateUser(ctx, usecase.CreateUserInput{
+		Email:    "sessions@example.com",
+		Password: "password123",
+		Name:     "Sessions",
+		Role:     user.RoleUser,
+	})
+	require.NoError(t, err)
+
+	login, err := userUseCase.AuthenticateUser(ctx, usecase.AuthenticateUserInput{
+		Email:    "sessions@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	_, err = jwtService.ValidateToken(login.Token)
+	require.NoError(t, err)
+
+	require.NoError(t, userUseCase.RevokeSessions(ctx, usecase.RevokeSessionsInput{UserID: created.User.ID}))
+
+	_, err = jwtService.ValidateToken(login.Token)
+	assert.ErrorIs(t, err, auth.ErrRevokedToken)
+
+	// Um novo login volta a funcionar com a versão atual
+	login, err = userUseCase.AuthenticateUser(ctx, usecase.AuthenticateUserInput{
+		Email:    "sessions@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+	_, err = jwtService.ValidateToken(login.Token)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,191 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreatedBetween garante que contagem e listagem respeitam o intervalo inclusivo
+func TestCreatedBetween(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	for i, email := range []string{"jan9@example.com", "jan10@example.com", "jan11@example.com"} {
+		u, err := user.NewUser(email, "password123", "Range", user.RoleUser)
+		require.NoError(t, err)
+		u.CreatedAt = base.AddDate(0, 0, i-1)
+		u.UpdatedAt = u.CreatedAt
+		require.NoError(t, userRepo.Create(ctx, u))
+	}
+
+	count, err := userRepo.CountCreatedBetween(ctx, base, base.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	users, err := userRepo.ListCreatedBetween(ctx, base, base.AddDate(0, 0, 1), 0, 1)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "jan11@example.com", users[0].Email)
+}
+
+// TestCountByRole garante a contagem por papel em uma consulta, com zeros para papéis sem usuários
+func TestCountByRole(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	for email, role := range map[string]user.Role{
+		"admin@example.com": user.RoleAdmin,
+		"ana@example.com":   user.RoleUser,
+		"bia@example.com":   user.RoleUser,
+		"caio@example.com":  user.RoleUser,
+	} {
+		u, err := user.NewUser(email, "password123", "Role", role)
+		require.NoError(t, err)
+		require.NoError(t, userRepo.Create(ctx, u))
+	}
+
+	counts, err := userRepo.CountByRole(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[user.Role]int64{
+		user.RoleAdmin: 1,
+		user.RoleUser:  3,
+		user.RoleGuest: 0,
+	}, counts)
+}
+
+// TestListDormant garante que contas sem login no período, ou que nunca
+// entraram, são listadas antes das de login mais antigo
+func TestListDormant(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	logins := map[string]time.Duration{
+		"recent@example.com": -24 * time.Hour,
+		"old@example.com":    -120 * 24 * time.Hour,
+		"older@example.com":  -365 * 24 * time.Hour,
+	}
+	for _, email := range []string{"recent@example.com", "old@example.com", "older@example.com", "never@example.com"} {
+		u, err := user.NewUser(email, "password123", "Dormant", user.RoleUser)
+		require.NoError(t, err)
+		require.NoError(t, userRepo.Create(ctx, u))
+		if ago, ok := logins[email]; ok {
+			require.NoError(t, userRepo.RecordLogin(ctx, u.ID, now.Add(ago)))
+		}
+	}
+
+	since := now.AddDate(0, 0, -90)
+	count, err := userRepo.CountDormant(ctx, since)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	users, err := userRepo.ListDormant(ctx, since, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, "never@example.com", users[0].Email)
+	assert.Nil(t, users[0].LastLoginAt)
+	assert.Equal(t, "older@example.com", users[1].Email)
+	assert.Equal(t, "old@example.com", users[2].Email)
+	require.NotNil(t, users[2].LastLoginAt)
+	assert.True(t, users[2].LastLoginAt.Equal(now.Add(-120*24*time.Hour)))
+
+	page, err := userRepo.ListDormant(ctx, since, 2, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "old@example.com", page[0].Email)
+}
+
+// TestListInactiveByRole garante que quem nunca entrou conta desde a criação
+// da conta e que apenas o papel pedido é listado
+func TestListInactiveByRole(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	seed := func(email string, role user.Role, createdAt time.Time, lastLogin *time.Time) *user.User {
+		u, err := user.NewUser(email, "password123", "Inactive", role)
+		require.NoError(t, err)
+		u.CreatedAt = createdAt
+		u.UpdatedAt = createdAt
+		require.NoError(t, userRepo.Create(ctx, u))
+		if lastLogin != nil {
+			require.NoError(t, userRepo.RecordLogin(ctx, u.ID, *lastLogin))
+		}
+		return u
+	}
+	longAgo := now.AddDate(-1, 0, 0)
+	recent := now.AddDate(0, 0, -1)
+	stale := now.AddDate(0, -4, 0)
+	seed("stale-admin@example.com", user.RoleAdmin, longAgo, &stale)
+	seed("never-admin@example.com", user.RoleAdmin, longAgo, nil)
+	seed("new-admin@example.com", user.RoleAdmin, recent, nil)
+	seed("active-admin@example.com", user.RoleAdmin, longAgo, &recent)
+	seed("stale-user@example.com", user.RoleUser, longAgo, &stale)
+	disabled := seed("disabled-admin@example.com", user.RoleAdmin, longAgo, &stale)
+	disabled.Deactivate()
+	require.NoError(t, userRepo.Update(ctx, disabled))
+
+	since := now.AddDate(0, 0, -90)
+	inactive, err := userRepo.ListInactiveByRole(ctx, user.RoleAdmin, since)
+	require.NoError(t, err)
+	require.Len(t, inactive, 2)
+	assert.Equal(t, "never-admin@example.com", inactive[0].Email)
+	assert.Equal(t, "stale-admin@example.com", inactive[1].Email)
+
+	admins, err := userRepo.CountActiveByRole(ctx, user.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), admins)
+
+	t.Run("downgrade skips admins active since the listing", func(t *testing.T) {
+		staleAdmin, neverAdmin := inactive[1], inactive[0]
+		require.NoError(t, userRepo.RecordLogin(ctx, neverAdmin.ID, now))
+
+		updated, err := userRepo.DowngradeInactive(ctx, []string{staleAdmin.ID, neverAdmin.ID}, user.RoleAdmin, user.RoleUser, since)
+		require.NoError(t, err)
+		assert.Equal(t, []string{staleAdmin.ID}, updated)
+
+		version, err := userRepo.GetTokenVersion(ctx, staleAdmin.ID)
+		require.NoError(t, err)
+		assert.Equal(t, staleAdmin.TokenVersion+1, version)
+
+		kept, err := userRepo.GetByID(ctx, neverAdmin.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleAdmin, kept.Role)
+
+		// Uma segunda execução não encontra mais o admin rebaixado
+		updated, err = userRepo.DowngradeInactive(ctx, []string{staleAdmin.ID}, user.RoleAdmin, user.RoleUser, since)
+		require.NoError(t, err)
+		assert.Empty(t, updated)
+	})
+
+	t.Run("downgrade skips deactivated admins", func(t *testing.T) {
+		updated, err := userRepo.DowngradeInactive(ctx, []string{disabled.ID}, user.RoleAdmin, user.RoleUser, since)
+		require.NoError(t, err)
+		assert.Empty(t, updated)
+
+		kept, err := userRepo.GetByID(ctx, disabled.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleAdmin, kept.Role)
+	})
+}
@@ -10,21 +10,28 @@ import (
 	"time"
 
 	"go-api-boilerplate/internal/domain/auth"
-	"go-api-boilerplate/internal/domain/user"
 	"go-api-boilerplate/internal/infrastructure/http/handlers"
 	"go-api-boilerplate/internal/infrastructure/repository"
 	"go-api-boilerplate/internal/usecase"
+	"go-api-boilerplate/tests/testutil"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	_ "github.com/lib/pq"
 )
 
+// testJWTSecret assina os tokens do router de teste, permitindo validá-los nos testes
+const testJWTSecret = "test-secret"
+
 // setupTestDB cria uma conexão de teste com o banco
 func setupTestDB(t *testing.T) *sql.DB {
+	// Com TEST_USE_CONTAINERS=1 usa um Postgres efêmero via testcontainers
+	if testutil.UseContainers() {
+		return testutil.StartPostgres(t)
+	}
+
 	// Usar banco de teste separado
-	dsn := "host=localhost port=5433 user=postgres password=secret dbname=boilerplate_test sslmode=disable"
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", testutil.TestDSN())
 	require.NoError(t, err)
 	
 	// Testar conexão
@@ -39,12 +46,11 @@ func setupTestRouter(t *testing.T) *gin.Engine {
 	db := setupTestDB(t)
 	
 	// Limpar banco antes de cada teste
-	_, err := db.Exec("DELETE FROM users")
-	require.NoError(t, err)
+	testutil.ResetDB(t, db)
 	
 	// Inicializar dependências
 	userRepo := repository.NewPostgresUserRepository(db)
-	jwtService := auth.NewJWTService("test-secret", 24*time.Hour)
+	jwtService := auth.NewJWTService(testJWTSecret, 24*time.Hour)
 	userUseCase := usecase.NewUserUseCase(userRepo, jwtService)
 	userHandler := handlers.NewUserHandler(userUseCase)
 	
@@ -58,6 +64,7 @@ func setupTestRouter(t *testing.T) *gin.Engine {
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", userHandler.Login)
+			auth.POST("/register", userHandler.Register)
 		}
 		
 		users := api.Group("/users")
@@ -65,6 +72,7 @@ func setupTestRouter(t *testing.T) *gin.Engine {
 			users.POST("", userHandler.CreateUser)
 			users.GET("", userHandler.ListUsers)
 			users.GET("/email", userHandler.GetUserByEmail)
+			users.GET("/search", userHandler.SearchUsers)
 			users.GET("/:id", userHandler.GetUserByID)
 			users.PUT("/:id", userHandler.UpdateUser)
 			users.DELETE("/:id", userHandler.DeleteUser)
@@ -103,7 +111,7 @@ func TestUserCRUD(t *testing.T) {
 		
 		assert.Equal(t, http.StatusCreated, w.Code)
 		
-		var response user.User
+		var response handlers.UserResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		
@@ -122,7 +130,7 @@ func TestUserCRUD(t *testing.T) {
 		
 		assert.Equal(t, http.StatusOK, w.Code)
 		
-		var response user.User
+		var response handlers.UserResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		
@@ -145,13 +153,15 @@ func TestUserCRUD(t *testing.T) {
 		
 		assert.Equal(t, http.StatusOK, w.Code)
 		
-		var response map[string]interface{}
+		var response handlers.LoginResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
 		
-		// Verificar se retorna token
-		assert.Contains(t, response, "token")
-		assert.NotEmpty(t, response["token"])
+		// Verificar se retorna token, tipo, validade e usuário
+		assert.NotEmpty(t, response.Token)
+		assert.Equal(t, handlers.TokenTypeBearer, response.TokenType)
+		assert.Equal(t, int64((24 * time.Hour).Seconds()), response.ExpiresIn)
+		assert.Equal(t, "test@example.com", response.User.Email)
 	})
 	
 	// Teste 4: Listar usuários
@@ -264,7 +274,17 @@ func TestAuthentication(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 		
-		assert.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response handlers.LoginResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotEmpty(t, response.Token)
+
+		// O token retornado deve ser aceito pelo mesmo JWTService
+		claims, err := auth.NewJWTService(testJWTSecret, 24*time.Hour).ValidateToken(response.Token)
+		require.NoError(t, err)
+		assert.Equal(t, response.User.ID, claims.UserID)
+		assert.Equal(t, "auth@example.com", claims.Email)
 	})
 	
 	t.Run("Invalid Password", func(t *testing.T) {
@@ -298,4 +318,55 @@ func TestAuthentication(t *testing.T) {
 		
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
-} 
\ No newline at end of file
+} 
+// TestRegisterPrivilegeEscalation garante que o registro público ignora o role enviado
+func TestRegisterPrivilegeEscalation(t *testing.T) {
+	router := setupTestRouter(t)
+
+	payload := map[string]string{
+		"email":    "intruder@example.com",
+		"password": "password123",
+		"name":     "Intruder",
+		"role":     "admin",
+	}
+
+	jsonData, _ := json.Marshal(payload)
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var response handlers.UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "user", string(response.Role))
+
+	// Confirma o papel persistido
+	req = httptest.NewRequest("GET", "/api/v1/users/email?email=intruder@example.com", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "user", string(response.Role))
+	assert.False(t, response.IsAdmin)
+}
+
+// TestEmptyListsSerializeAsArrays garante que listagem e busca sem resultados
+// retornam "users": [] e não null
+func TestEmptyListsSerializeAsArrays(t *testing.T) {
+	router := setupTestRouter(t)
+
+	for _, path := range []string{"/api/v1/users", "/api/v1/users/search?q=nobody"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var body map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.JSONEq(t, `[]`, string(body["users"]))
+		})
+	}
+}
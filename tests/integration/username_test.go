@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsernameUniqueness(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	ana, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, ana.SetUsername("Ana_Silva"))
+	require.NoError(t, userRepo.Create(ctx, ana))
+
+	// Buscas não diferenciam maiúsculas e preservam a caixa cadastrada
+	found, err := userRepo.GetByUsername(ctx, "ana_silva")
+	require.NoError(t, err)
+	assert.Equal(t, ana.ID, found.ID)
+	assert.Equal(t, "Ana_Silva", found.Username)
+
+	exists, err := userRepo.ExistsByUsername(ctx, "ANA_SILVA")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = userRepo.GetByUsername(ctx, "bob")
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+	// O índice único também não diferencia maiúsculas
+	bob, err := user.NewUser("bob@example.com", "password123", "Bob", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, bob.SetUsername("ana_silva"))
+	assert.ErrorIs(t, userRepo.Create(ctx, bob), user.ErrUserAlreadyExists)
+
+	// Usuários sem username não conflitam entre si
+	bob.Username = ""
+	require.NoError(t, userRepo.Create(ctx, bob))
+	carol, err := user.NewUser("carol@example.com", "password123", "Carol", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, carol))
+
+	require.NoError(t, bob.SetUsername("ANA_silva"))
+	assert.ErrorIs(t, userRepo.Update(ctx, bob), user.ErrUserAlreadyExists)
+}
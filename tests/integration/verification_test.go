@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-boilerplate/internal/domain/user"
+	"go-api-boilerplate/internal/infrastructure/repository"
+	"go-api-boilerplate/tests/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailVerificationTokens(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	u, err := user.NewUser("ana@example.com", "password123", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, u))
+	assert.False(t, u.IsEmailVerified())
+
+	expiresAt := time.Now().Add(time.Hour)
+	first := &user.VerificationToken{Hash: "hash-1", UserID: u.ID, Email: u.Email, ExpiresAt: expiresAt}
+	second := &user.VerificationToken{Hash: "hash-2", UserID: u.ID, Email: u.Email, ExpiresAt: expiresAt}
+	require.NoError(t, userRepo.ReplaceVerificationToken(ctx, first))
+	require.NoError(t, userRepo.ReplaceVerificationToken(ctx, second))
+
+	// O reenvio invalida o token anterior
+	_, err = userRepo.ConsumeVerificationToken(ctx, "hash-1")
+	assert.ErrorIs(t, err, user.ErrVerificationTokenInvalid)
+
+	stored, err := userRepo.ConsumeVerificationToken(ctx, "hash-2")
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, stored.UserID)
+	assert.Equal(t, u.Email, stored.Email)
+	assert.WithinDuration(t, expiresAt, stored.ExpiresAt, time.Millisecond)
+
+	// Uso único
+	_, err = userRepo.ConsumeVerificationToken(ctx, "hash-2")
+	assert.ErrorIs(t, err, user.ErrVerificationTokenInvalid)
+
+	// A verificação só vale para o email atual
+	err = userRepo.MarkEmailVerified(ctx, u.ID, "old@example.com", time.Now())
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+	require.NoError(t, userRepo.MarkEmailVerified(ctx, u.ID, u.Email, time.Now()))
+	found, err := userRepo.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+	assert.True(t, found.IsEmailVerified())
+}
+
+func TestExternalUsersAreCreatedVerified(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	u, err := user.NewExternalUser("bob@example.com", "Bob", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	found, err := userRepo.GetByEmail(ctx, "bob@example.com")
+	require.NoError(t, err)
+	assert.True(t, found.IsEmailVerified())
+}
+
+func TestEmailChange(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	ana, err := user.NewExternalUser("ana@example.com", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, ana))
+	bob, err := user.NewExternalUser("bob@example.com", "Bob", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, bob))
+
+	pending := &user.VerificationToken{Hash: "change", UserID: ana.ID, Email: "ana.souza@example.com", ReplacesEmail: ana.Email, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, userRepo.ReplaceVerificationToken(ctx, pending))
+
+	// Até a confirmação o email atual continua valendo
+	found, err := userRepo.GetByEmail(ctx, "ana@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, ana.ID, found.ID)
+
+	stored, err := userRepo.ConsumeVerificationToken(ctx, "change")
+	require.NoError(t, err)
+	assert.True(t, stored.IsEmailChange())
+	assert.Equal(t, "ana@example.com", stored.ReplacesEmail)
+
+	err = userRepo.ChangeEmail(ctx, ana.ID, ana.Email, bob.Email, time.Now())
+	assert.ErrorIs(t, err, user.ErrUserAlreadyExists)
+
+	require.NoError(t, userRepo.ChangeEmail(ctx, ana.ID, stored.ReplacesEmail, stored.Email, time.Now()))
+	found, err = userRepo.GetByID(ctx, ana.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ana.souza@example.com", found.Email)
+	assert.True(t, found.IsEmailVerified())
+
+	// O token vale apenas para o email que ele substitui
+	err = userRepo.ChangeEmail(ctx, ana.ID, "ana@example.com", "other@example.com", time.Now())
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+}
+
+func TestUpdateWithNewEmailResetsVerification(t *testing.T) {
+	db := setupTestDB(t)
+	testutil.ResetDB(t, db)
+
+	ctx := context.Background()
+	userRepo := repository.NewPostgresUserRepository(db)
+
+	u, err := user.NewExternalUser("ana@example.com", "Ana", user.RoleUser)
+	require.NoError(t, err)
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	require.NoError(t, u.UpdateName("Ana Souza"))
+	require.NoError(t, userRepo.Update(ctx, u))
+	assert.True(t, u.IsEmailVerified())
+
+	u.Email = "ana.souza@example.com"
+	require.NoError(t, userRepo.Update(ctx, u))
+	assert.False(t, u.IsEmailVerified())
+}
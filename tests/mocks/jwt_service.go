@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"go-api-boilerplate/internal/domain/auth"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// JWTService é um mock de auth.JWTService baseado em testify/mock
+type JWTService struct {
+	mock.Mock
+}
+
+var _ auth.JWTService = (*JWTService)(nil)
+
+// GenerateToken implementa auth.JWTService
+func (m *JWTService) GenerateToken(userID, email, role string, opts ...auth.TokenOption) (string, error) {
+	if len(opts) > 0 {
+		args := m.Called(userID, email, role, opts)
+		return args.String(0), args.Error(1)
+	}
+	args := m.Called(userID, email, role)
+	return args.String(0), args.Error(1)
+}
+
+// ValidateToken implementa auth.JWTService
+func (m *JWTService) ValidateToken(tokenString string) (*auth.Claims, error) {
+	args := m.Called(tokenString)
+	claims, _ := args.Get(0).(*auth.Claims)
+	return claims, args.Error(1)
+}
+
+// ExpiresIn implementa auth.JWTService
+func (m *JWTService) ExpiresIn() time.Duration {
+	args := m.Called()
+	d, _ := args.Get(0).(time.Duration)
+	return d
+}
+
+// RevokeToken implementa auth.JWTService
+func (m *JWTService) RevokeToken(ctx context.Context, tokenString string) error {
+	args := m.Called(ctx, tokenString)
+	return args.Error(0)
+}
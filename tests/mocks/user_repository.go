@@ -0,0 +1,335 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"go-api-boilerplate/internal/domain/repository"
+	"go-api-boilerplate/internal/domain/user"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// UserRepository é um mock de repository.UserRepository baseado em testify/mock
+type UserRepository struct {
+	mock.Mock
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// Create implementa repository.UserRepository
+func (m *UserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+// CreateFirstAdmin implementa repository.UserRepository
+func (m *UserRepository) CreateFirstAdmin(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+// GetByID implementa repository.UserRepository
+func (m *UserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	args := m.Called(ctx, id)
+	return userArg(args, 0), args.Error(1)
+}
+
+// GetByEmail implementa repository.UserRepository
+func (m *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	return userArg(args, 0), args.Error(1)
+}
+
+// GetByUsername implementa repository.UserRepository
+func (m *UserRepository) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	return userArg(args, 0), args.Error(1)
+}
+
+// Update implementa repository.UserRepository
+func (m *UserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+// Delete implementa repository.UserRepository
+func (m *UserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// List implementa repository.UserRepository
+func (m *UserRepository) List(ctx context.Context, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, offset, limit)
+	users, _ := args.Get(0).([]*user.User)
+	return users, args.Error(1)
+}
+
+// ListActive implementa repository.UserRepository
+func (m *UserRepository) ListActive(ctx context.Context, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, offset, limit)
+	users, _ := args.Get(0).([]*user.User)
+	return users, args.Error(1)
+}
+
+// Search implementa repository.UserRepository
+func (m *UserRepository) Search(ctx context.Context, query string, includeInactive bool, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, query, includeInactive, offset, limit)
+	users, _ := args.Get(0).([]*user.User)
+	return users, args.Error(1)
+}
+
+// CountSearch implementa repository.UserRepository
+func (m *UserRepository) CountSearch(ctx context.Context, query string, includeInactive bool) (int64, error) {
+	args := m.Called(ctx, query, includeInactive)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// GetByIDs implementa repository.UserRepository
+func (m *UserRepository) GetByIDs(ctx context.Context, ids []string) ([]*user.User, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// ListAfterID implementa repository.UserRepository
+func (m *UserRepository) ListAfterID(ctx context.Context, afterID string, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// Iterate implementa repository.UserRepository
+func (m *UserRepository) Iterate(ctx context.Context, batchSize int, fn func([]*user.User) error) error {
+	args := m.Called(ctx, batchSize, fn)
+	return args.Error(0)
+}
+
+// CountByRole implementa repository.UserRepository
+func (m *UserRepository) CountByRole(ctx context.Context) (map[user.Role]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[user.Role]int64), args.Error(1)
+}
+
+// CountCreatedBetween implementa repository.UserRepository
+func (m *UserRepository) CountCreatedBetween(ctx context.Context, from, to time.Time) (int64, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ListCreatedBetween implementa repository.UserRepository
+func (m *UserRepository) ListCreatedBetween(ctx context.Context, from, to time.Time, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, from, to, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// ReplacePassword implementa repository.UserRepository
+func (m *UserRepository) ReplacePassword(ctx context.Context, u *user.User, previousHash string) (bool, error) {
+	args := m.Called(ctx, u, previousHash)
+	return args.Bool(0), args.Error(1)
+}
+
+// RecordLogin implementa repository.UserRepository
+func (m *UserRepository) RecordLogin(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// CountDormant implementa repository.UserRepository
+func (m *UserRepository) CountDormant(ctx context.Context, since time.Time) (int64, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ListDormant implementa repository.UserRepository
+func (m *UserRepository) ListDormant(ctx context.Context, since time.Time, offset, limit int) ([]*user.User, error) {
+	args := m.Called(ctx, since, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// ListInactiveByRole implementa repository.UserRepository
+func (m *UserRepository) ListInactiveByRole(ctx context.Context, role user.Role, since time.Time) ([]*user.User, error) {
+	args := m.Called(ctx, role, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+// CountActiveByRole implementa repository.UserRepository
+func (m *UserRepository) CountActiveByRole(ctx context.Context, role user.Role) (int64, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// DowngradeInactive implementa repository.UserRepository
+func (m *UserRepository) DowngradeInactive(ctx context.Context, ids []string, from, to user.Role, since time.Time) ([]string, error) {
+	args := m.Called(ctx, ids, from, to, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// Anonymize implementa repository.UserRepository
+func (m *UserRepository) Anonymize(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+// IncrementTokenVersion implementa repository.UserRepository
+func (m *UserRepository) IncrementTokenVersion(ctx context.Context, id string) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+// GetTokenVersion implementa repository.UserRepository
+func (m *UserRepository) GetTokenVersion(ctx context.Context, id string) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+// UpdateRoles implementa repository.UserRepository
+func (m *UserRepository) UpdateRoles(ctx context.Context, ids []string, role user.Role) (*repository.RoleUpdateResult, error) {
+	args := m.Called(ctx, ids, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.RoleUpdateResult), args.Error(1)
+}
+
+// PreviewUpdateRoles implementa repository.UserRepository
+func (m *UserRepository) PreviewUpdateRoles(ctx context.Context, ids []string, role user.Role) (*repository.RoleUpdateResult, error) {
+	args := m.Called(ctx, ids, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.RoleUpdateResult), args.Error(1)
+}
+
+// IsActive implementa repository.UserRepository
+func (m *UserRepository) IsActive(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// Count implementa repository.UserRepository
+func (m *UserRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// CountActive implementa repository.UserRepository
+func (m *UserRepository) CountActive(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ExistsByEmail implementa repository.UserRepository
+func (m *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+// ExistsByUsername implementa repository.UserRepository
+func (m *UserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	args := m.Called(ctx, username)
+	return args.Bool(0), args.Error(1)
+}
+
+// ExistingEmails implementa repository.UserRepository
+func (m *UserRepository) ExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	args := m.Called(ctx, emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// ExistsByID implementa repository.UserRepository
+func (m *UserRepository) ExistsByID(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// GetByIdentity implementa repository.UserRepository
+func (m *UserRepository) GetByIdentity(ctx context.Context, issuer, subject string) (*user.User, error) {
+	args := m.Called(ctx, issuer, subject)
+	return userArg(args, 0), args.Error(1)
+}
+
+// LinkIdentity implementa repository.UserRepository
+func (m *UserRepository) LinkIdentity(ctx context.Context, userID, issuer, subject string) error {
+	args := m.Called(ctx, userID, issuer, subject)
+	return args.Error(0)
+}
+
+// ReplaceVerificationToken implementa repository.UserRepository
+func (m *UserRepository) ReplaceVerificationToken(ctx context.Context, token *user.VerificationToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+// ConsumeVerificationToken implementa repository.UserRepository
+func (m *UserRepository) ConsumeVerificationToken(ctx context.Context, hash string) (*user.VerificationToken, error) {
+	args := m.Called(ctx, hash)
+	token, _ := args.Get(0).(*user.VerificationToken)
+	return token, args.Error(1)
+}
+
+// MarkEmailVerified implementa repository.UserRepository
+func (m *UserRepository) MarkEmailVerified(ctx context.Context, userID, email string, verifiedAt time.Time) error {
+	args := m.Called(ctx, userID, email, verifiedAt)
+	return args.Error(0)
+}
+
+// ChangeEmail implementa repository.UserRepository
+func (m *UserRepository) ChangeEmail(ctx context.Context, userID, oldEmail, newEmail string, verifiedAt time.Time) error {
+	args := m.Called(ctx, userID, oldEmail, newEmail, verifiedAt)
+	return args.Error(0)
+}
+
+// ReplacePasswordResetToken implementa repository.UserRepository
+func (m *UserRepository) ReplacePasswordResetToken(ctx context.Context, token *user.PasswordResetToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+// GetPasswordResetToken implementa repository.UserRepository
+func (m *UserRepository) GetPasswordResetToken(ctx context.Context, userID string) (*user.PasswordResetToken, error) {
+	args := m.Called(ctx, userID)
+	token, _ := args.Get(0).(*user.PasswordResetToken)
+	return token, args.Error(1)
+}
+
+// IncrementResetAttempts implementa repository.UserRepository
+func (m *UserRepository) IncrementResetAttempts(ctx context.Context, hash string) (int, error) {
+	args := m.Called(ctx, hash)
+	return args.Int(0), args.Error(1)
+}
+
+// ConsumePasswordResetToken implementa repository.UserRepository
+func (m *UserRepository) ConsumePasswordResetToken(ctx context.Context, hash string) error {
+	args := m.Called(ctx, hash)
+	return args.Error(0)
+}
+
+// userArg extrai um *user.User dos argumentos, aceitando nil
+func userArg(args mock.Arguments, index int) *user.User {
+	u, _ := args.Get(index).(*user.User)
+	return u
+}
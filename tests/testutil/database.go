@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultTestDSN é a conexão padrão com o banco de testes de integração
+const DefaultTestDSN = "host=localhost port=5433 user=postgres password=secret dbname=boilerplate_test sslmode=disable"
+
+// migrationsTable é a tabela de controle do goose, que nunca deve ser truncada
+const migrationsTable = "goose_db_version"
+
+// AppTables descobre as tabelas da aplicação no schema public.
+// As tabelas são lidas do catálogo do banco, então novas migrações são
+// contempladas automaticamente sem alterar os testes
+func AppTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = 'public' AND tablename <> $1
+		ORDER BY tablename`, migrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// ResetDatabase trunca todas as tabelas da aplicação dentro de uma transação,
+// reiniciando as sequences. O TRUNCATE único com CASCADE resolve a ordem
+// de dependência entre chaves estrangeiras
+func ResetDatabase(ctx context.Context, db *sql.DB) error {
+	tables, err := AppTables(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = fmt.Sprintf("%q", table)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", strings.Join(quoted, ", "))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reset: %w", err)
+	}
+
+	return nil
+}
+
+// ResetDB limpa o banco de testes, falhando o teste em caso de erro
+func ResetDB(t testing.TB, db *sql.DB) {
+	t.Helper()
+	require.NoError(t, ResetDatabase(context.Background(), db))
+}
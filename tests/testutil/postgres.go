@@ -0,0 +1,108 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"go-api-boilerplate/pkg/database"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	_ "github.com/lib/pq"
+)
+
+// Variáveis de ambiente que controlam o banco usado nos testes de integração
+const (
+	// EnvUseContainers habilita o Postgres efêmero via testcontainers quando igual a "1" ou "true"
+	EnvUseContainers = "TEST_USE_CONTAINERS"
+	// EnvTestDSN sobrescreve a DSN fixa do banco de testes
+	EnvTestDSN = "TEST_DATABASE_DSN"
+)
+
+// postgresImage é a imagem usada pelo container de testes, alinhada ao docker-compose
+const postgresImage = "postgres:15-alpine"
+
+var (
+	containerOnce sync.Once
+	containerDSN  string
+	containerErr  error
+)
+
+// UseContainers indica se os testes devem subir um Postgres via testcontainers
+func UseContainers() bool {
+	v := os.Getenv(EnvUseContainers)
+	return v == "1" || v == "true"
+}
+
+// TestDSN retorna a DSN fixa do banco de testes, respeitando TEST_DATABASE_DSN
+func TestDSN() string {
+	if dsn := os.Getenv(EnvTestDSN); dsn != "" {
+		return dsn
+	}
+	return DefaultTestDSN
+}
+
+// MigrationsDir retorna o caminho absoluto do diretório de migrações do projeto
+func MigrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "sql", "migrations")
+}
+
+// StartPostgres sobe (uma única vez por processo) um Postgres efêmero,
+// aplica as migrações com o Migrator e retorna uma conexão pronta para uso.
+// O container é removido pelo reaper do testcontainers ao fim do processo
+func StartPostgres(t testing.TB) *sql.DB {
+	t.Helper()
+
+	containerOnce.Do(func() {
+		containerDSN, containerErr = startPostgresContainer(context.Background())
+	})
+	require.NoError(t, containerErr)
+
+	db, err := sql.Open("postgres", containerDSN)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, db.Ping())
+	return db
+}
+
+// startPostgresContainer inicia o container e executa as migrações
+func startPostgresContainer(ctx context.Context) (string, error) {
+	container, err := postgres.Run(ctx, postgresImage,
+		postgres.WithDatabase("boilerplate_test"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("secret"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to start postgres container: %w", err)
+	}
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		return "", fmt.Errorf("failed to get container connection string: %w", err)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return "", fmt.Errorf("failed to open container database: %w", err)
+	}
+	defer db.Close()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if err := database.NewMigrator(db, log).RunMigrations(MigrationsDir()); err != nil {
+		return "", err
+	}
+
+	return dsn, nil
+}
@@ -0,0 +1,27 @@
+// Comando resetdb limpa o banco de testes de integração.
+// Uso: make test-db-reset (ou TEST_DATABASE_DSN=... go run ./tests/testutil/resetdb)
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"go-api-boilerplate/tests/testutil"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	db, err := sql.Open("postgres", testutil.TestDSN())
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := testutil.ResetDatabase(context.Background(), db); err != nil {
+		log.Fatalf("failed to reset database: %v", err)
+	}
+
+	log.Println("test database reset")
+}